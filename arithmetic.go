@@ -0,0 +1,187 @@
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// number is the intermediate result of evaluating an arithmetic operand.
+// Integers are kept exact until they are combined with a float or divided.
+type number struct {
+	isFloat bool
+	i       int64
+	f       float64
+}
+
+func (n number) float() float64 {
+	if n.isFloat {
+		return n.f
+	}
+	return float64(n.i)
+}
+
+func evaluateComparison(node *grammar.ComparisonExpression, datum interface{}, opts *options) (bool, error) {
+	left, err := evaluateOperand(node.Left, datum, opts)
+	if err != nil {
+		return false, err
+	}
+	right, err := evaluateOperand(node.Right, datum, opts)
+	if err != nil {
+		return false, err
+	}
+
+	var cmp int
+	if left.isFloat || right.isFloat {
+		l, r := left.float(), right.float()
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	} else {
+		switch {
+		case left.i < right.i:
+			cmp = -1
+		case left.i > right.i:
+			cmp = 1
+		}
+	}
+
+	switch node.Operator {
+	case grammar.MatchEqual:
+		return cmp == 0, nil
+	case grammar.MatchNotEqual:
+		return cmp != 0, nil
+	case grammar.MatchLessThan:
+		return cmp < 0, nil
+	case grammar.MatchLessThanOrEqual:
+		return cmp <= 0, nil
+	case grammar.MatchGreaterThan:
+		return cmp > 0, nil
+	case grammar.MatchGreaterThanOrEqual:
+		return cmp >= 0, nil
+	default:
+		return false, fmt.Errorf("Invalid comparison operator %s", node.Operator)
+	}
+}
+
+func evaluateOperand(operand *grammar.Operand, datum interface{}, opts *options) (number, error) {
+	switch {
+	case operand.Arithmetic != nil:
+		return evaluateArithmetic(operand.Arithmetic, datum, opts)
+	case operand.Value != nil:
+		return parseNumber(operand.Value.Raw)
+	}
+
+	val, err := getSelectorValue(operand.Selector, datum, opts)
+	if err != nil {
+		return number{}, err
+	}
+	val, err = jsonNumberValue(val)
+	if err != nil {
+		return number{}, err
+	}
+
+	rvalue := reflect.Indirect(reflect.ValueOf(val))
+	switch rvalue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return number{i: rvalue.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rvalue.Uint(); u <= math.MaxInt64 {
+			return number{i: int64(u)}, nil
+		}
+		return number{isFloat: true, f: float64(rvalue.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return number{isFloat: true, f: rvalue.Float()}, nil
+	case reflect.Invalid:
+		return number{}, fmt.Errorf("Cannot perform arithmetic operations on a nil value for selector: %q", operand.Selector)
+	default:
+		return number{}, fmt.Errorf("Cannot perform arithmetic operations on type %s for selector: %q", rvalue.Type(), operand.Selector)
+	}
+}
+
+// parseNumber converts a number or duration literal, durations being their
+// integer nanoseconds as with the other operators
+func parseNumber(raw string) (number, error) {
+	if i, err := strconv.ParseInt(raw, 0, 64); err == nil {
+		return number{i: i}, nil
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return number{isFloat: true, f: f}, nil
+	}
+	if d, err := time.ParseDuration(raw); err == nil {
+		return number{i: int64(d)}, nil
+	}
+	return number{}, fmt.Errorf("invalid number %q", raw)
+}
+
+func evaluateArithmetic(expr *grammar.ArithmeticExpression, datum interface{}, opts *options) (number, error) {
+	left, err := evaluateOperand(expr.Left, datum, opts)
+	if err != nil {
+		return number{}, err
+	}
+	right, err := evaluateOperand(expr.Right, datum, opts)
+	if err != nil {
+		return number{}, err
+	}
+
+	switch expr.Operator {
+	case grammar.ArithmeticDivide:
+		// division is always done in floating point so that ratios such as
+		// Used / Total are not truncated to zero
+		if right.float() == 0 {
+			return number{}, errors.New("division by zero")
+		}
+		return number{isFloat: true, f: left.float() / right.float()}, nil
+	case grammar.ArithmeticModulo:
+		if left.isFloat || right.isFloat {
+			return number{}, errors.New("Cannot perform modulo operations on floating point values")
+		}
+		if right.i == 0 {
+			return number{}, errors.New("division by zero")
+		}
+		return number{i: left.i % right.i}, nil
+	}
+
+	if left.isFloat || right.isFloat {
+		l, r := left.float(), right.float()
+		switch expr.Operator {
+		case grammar.ArithmeticAdd:
+			return number{isFloat: true, f: l + r}, nil
+		case grammar.ArithmeticSubtract:
+			return number{isFloat: true, f: l - r}, nil
+		case grammar.ArithmeticMultiply:
+			return number{isFloat: true, f: l * r}, nil
+		}
+	} else {
+		l, r := left.i, right.i
+		var result int64
+		var ok bool
+		switch expr.Operator {
+		case grammar.ArithmeticAdd:
+			result = l + r
+			ok = (r > 0) == (result > l)
+		case grammar.ArithmeticSubtract:
+			result = l - r
+			ok = (r > 0) == (result < l)
+		case grammar.ArithmeticMultiply:
+			result = l * r
+			ok = l == 0 || (result/l == r && !(l == -1 && r == math.MinInt64))
+		default:
+			return number{}, fmt.Errorf("Invalid arithmetic operator %s", expr.Operator)
+		}
+		if !ok {
+			// rather than wrapping around and comparing the wrong way
+			return number{}, fmt.Errorf("integer overflow in %d %s %d", l, expr.Operator, r)
+		}
+		return number{i: result}, nil
+	}
+	return number{}, fmt.Errorf("Invalid arithmetic operator %s", expr.Operator)
+}
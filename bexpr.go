@@ -8,33 +8,467 @@ package bexpr
 //go:generate goimports -w grammar/grammar.go
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sync"
+	"time"
+
 	"github.com/hashicorp/go-bexpr/grammar"
+	"github.com/mitchellh/pointerstructure"
 )
 
+// Evaluator evaluates a parsed expression against data. Evaluations do not
+// modify the Evaluator so one Evaluator may be shared by any number of
+// goroutines, such as request handlers, calling Evaluate and the other
+// evaluation methods concurrently, along with SetValueSet and
+// DeleteValueSet. Only UnmarshalJSON and UnmarshalText, which replace the
+// expression, must not run concurrently with other methods.
 type Evaluator struct {
 	// The syntax tree
 	ast grammar.Expression
+
+	// The options used during evaluation
+	opts options
+
+	// The names of the placeholders which have not been bound yet
+	unbound []string
+
+	// The evaluators this one was combined from by And, Or or Not
+	operands []*Evaluator
 }
 
 func CreateEvaluator(expression string, opts ...Option) (*Evaluator, error) {
 	parsedOpts := getOpts(opts...)
+	if max := parsedOpts.withMaxExpressionLength; max != 0 && uint64(len(expression)) > max {
+		return nil, &LimitError{Max: max, Err: fmt.Errorf("%w of %d bytes", ErrExpressionTooLong, max)}
+	}
+
+	return createEvaluator([]byte(expression), parsedOpts)
+}
+
+// CreateEvaluatorFromReader is like CreateEvaluator but reads the expression
+// from r. When a maximum expression length is set with WithMaxExpressionLength
+// no more than that many bytes will be read from r before failing, so callers
+// can pass untrusted input such as request bodies without buffering it first.
+func CreateEvaluatorFromReader(r io.Reader, opts ...Option) (*Evaluator, error) {
+	parsedOpts := getOpts(opts...)
+
+	max := parsedOpts.withMaxExpressionLength
+	if max != 0 && max < math.MaxInt64 {
+		// read one byte past the limit to detect overly long expressions,
+		// which no reader can go past for larger limits
+		r = io.LimitReader(r, int64(max)+1)
+	}
+
+	expression, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading expression: %w", err)
+	}
+	if max != 0 && uint64(len(expression)) > max {
+		return nil, &LimitError{Max: max, Err: fmt.Errorf("%w of %d bytes", ErrExpressionTooLong, max)}
+	}
+
+	return createEvaluator(expression, parsedOpts)
+}
+
+// CreateEvaluatorFromJSON creates an Evaluator from the JSON form of a parsed
+// expression, as produced by Evaluator.MarshalJSON, without parsing the
+// expression again. It accepts the same options as CreateEvaluator, except
+// for those that only apply while parsing. The limits encoded with the
+// expression are applied before the given options, which override them,
+// and the expression is checked against them as when it was created. The
+// maximum expression length of the given options limits the length of the
+// JSON, which is checked before it is decoded, while the maximum depth is
+// checked as the expression is decoded.
+func CreateEvaluatorFromJSON(data []byte, opts ...Option) (*Evaluator, error) {
+	if max := getOpts(opts...).withMaxExpressionLength; max != 0 && uint64(len(data)) > max {
+		return nil, &LimitError{Max: max, Err: fmt.Errorf("%w of %d bytes", ErrExpressionTooLong, max)}
+	}
+
+	ast, parsedOpts, err := decodeEvaluator(data, opts)
+	if errors.Is(err, grammar.ErrExpressionTooDeep) {
+		return nil, &LimitError{Max: parsedOpts.withMaxExpressionDepth, Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding expression: %w", err)
+	}
+	return newEvaluator(ast, parsedOpts)
+}
+
+func createEvaluator(expression []byte, parsedOpts options) (*Evaluator, error) {
 	var parserOpts []grammar.Option
 	if parsedOpts.withMaxExpressions != 0 {
 		parserOpts = append(parserOpts, grammar.MaxExpressions(parsedOpts.withMaxExpressions))
 	}
 
-	ast, err := grammar.Parse("", []byte(expression), parserOpts...)
+	if parsedOpts.withErrorRecovery {
+		parserOpts = append(parserOpts, grammar.ErrorRecovery(true))
+	}
+
+	if parsedOpts.withCommaAnd {
+		parserOpts = append(parserOpts, grammar.CommaAnd(true))
+	}
+
+	ast, err := grammar.ParseExpression(expression, parserOpts...)
 	if err != nil {
+		if parsedOpts.withErrorRecovery {
+			return nil, Diagnostics(grammar.Errors(err))
+		}
 		return nil, err
 	}
 
+	return newEvaluator(ast, parsedOpts)
+}
+
+// newEvaluator validates the AST and prepares it for evaluation
+func newEvaluator(ast grammar.Expression, parsedOpts options) (*Evaluator, error) {
+	if err := validate(ast, &parsedOpts); err != nil {
+		return nil, err
+	}
+
+	// Key the default values by the JSON Pointer form of their selectors so
+	// that either syntax can be used to reference the same value
+	if len(parsedOpts.withDefaultValues) > 0 {
+		defaults := make(map[string]interface{}, len(parsedOpts.withDefaultValues))
+		for selector, value := range parsedOpts.withDefaultValues {
+			sel, err := grammar.ParseSelector(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector %q for default value: %w", selector, err)
+			}
+			defaults[pointerKey(sel.Path)] = value
+		}
+		parsedOpts.withDefaultValues = defaults
+	}
+
+	if len(parsedOpts.withCaseInsensitive) > 0 {
+		fold := make(map[string]struct{}, len(parsedOpts.withCaseInsensitive))
+		for selector := range parsedOpts.withCaseInsensitive {
+			sel, err := grammar.ParseSelector(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid case insensitive selector %q: %w", selector, err)
+			}
+			fold[pointerKey(sel.Path)] = struct{}{}
+		}
+		parsedOpts.withCaseInsensitive = fold
+	}
+
+	parsedOpts.valueSets = new(valueSets)
+	for name, values := range parsedOpts.withValueSets {
+		parsedOpts.valueSets.set(name, values)
+	}
+
+	parsedOpts.listSets = newListSets(ast)
+
+	// the values of fields with a type are checked once the options are
+	// ready to evaluate against, after which their aliases can be replaced
+	if len(parsedOpts.withFields) > 0 {
+		if err := validateFields(ast, &parsedOpts); err != nil {
+			return nil, err
+		}
+		ast = aliasSelectors(ast, parsedOpts.withFields)
+		parsedOpts.listSets = newListSets(ast)
+	}
+
 	eval := &Evaluator{
-		ast: ast.(grammar.Expression),
+		ast:     ast,
+		opts:    parsedOpts,
+		unbound: unboundParameters(ast),
 	}
 
 	return eval, nil
 }
 
+// newListSets coerces the values of list literals once rather than for
+// every evaluation
+func newListSets(ast grammar.Expression) map[*grammar.MatchExpression]*valueSet {
+	sets := make(map[*grammar.MatchExpression]*valueSet)
+	walkMatchExpressions(ast, func(node *grammar.MatchExpression) error {
+		if node.Values != nil && (node.Operator == grammar.MatchInSet || node.Operator == grammar.MatchNotInSet) {
+			raw := make([]string, 0, len(node.Values))
+			quoted := make([]bool, 0, len(node.Values))
+			for _, value := range node.Values {
+				raw = append(raw, value.Raw)
+				quoted = append(quoted, value.Quoted)
+			}
+			sets[node] = newValueSet(raw, quoted)
+		}
+		return nil
+	})
+	return sets
+}
+
 func (eval *Evaluator) Evaluate(datum interface{}) (bool, error) {
-	return evaluate(eval.ast, datum)
+	return eval.evaluate(nil, datum)
+}
+
+// EvaluateContext evaluates the expression against the datum like Evaluate
+// but stops early with the error of the context once it is done. The
+// context is checked before each element iterated by a quantifier, each
+// value a wildcard expands to and each function call so that evaluations
+// over large values can be cancelled.
+func (eval *Evaluator) EvaluateContext(ctx context.Context, datum interface{}) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return eval.evaluate(ctx, datum)
+}
+
+// evaluate evaluates the expression with the state of a single evaluation,
+// which is only needed for a context or the evaluation limits
+func (eval *Evaluator) evaluate(ctx context.Context, datum interface{}) (bool, error) {
+	if len(eval.unbound) > 0 {
+		return false, fmt.Errorf("parameter $%s is not bound", eval.unbound[0])
+	}
+
+	opts := &eval.opts
+	if b := newBudget(opts); ctx != nil || b != nil {
+		scratch := scratchOptions.Get().(*options)
+		defer func() {
+			*scratch = options{}
+			scratchOptions.Put(scratch)
+		}()
+		*scratch = eval.opts
+		scratch.ctx = ctx
+		scratch.budget = b
+		opts = scratch
+	}
+	return evaluate(eval.ast, datum, opts)
+}
+
+// scratchOptions pools the copies of the options that hold the state of
+// single evaluations, so that evaluations sharing an Evaluator each get
+// their own without allocating one every time
+var scratchOptions = sync.Pool{
+	New: func() interface{} { return new(options) },
+}
+
+// checkContext returns the error of the context of the evaluation, if it
+// has one and it is done
+func checkContext(opts *options) error {
+	if opts.ctx == nil {
+		return nil
+	}
+	return opts.ctx.Err()
+}
+
+// String returns the expression in canonical form, such as for logging or
+// to compare expressions written in different ways. It returns the empty
+// string for expressions that cannot be formatted.
+func (eval *Evaluator) String() string {
+	stringer, ok := eval.ast.(fmt.Stringer)
+	if !ok {
+		return ""
+	}
+	return stringer.String()
+}
+
+// Selectors returns the path of every selector the expression uses to
+// look up values within the datum, such as to load only those values from
+// a database before evaluating. Each path is listed once in the order it
+// first appears. Selectors relative to the element bound to a quantifier
+// variable are not listed but the selector of the collection iterated is.
+func (eval *Evaluator) Selectors() [][]string {
+	var paths [][]string
+	seen := make(map[string]struct{})
+	walkSelectors(eval.ast, func(sel grammar.Selector, _ *grammar.MatchExpression) error {
+		key := pointerKey(sel.Path)
+		if _, found := seen[key]; found {
+			return nil
+		}
+		seen[key] = struct{}{}
+		paths = append(paths, append([]string(nil), sel.Path...))
+		return nil
+	})
+	return paths
+}
+
+// MarshalJSON encodes the parsed expression so that it can be stored or sent
+// elsewhere and turned back into an Evaluator with CreateEvaluatorFromJSON
+// or UnmarshalJSON. When the evaluator has any of the limits set by
+// WithUntrustedInputLimits and the options it lists, the expression is
+// encoded as the "expression" field of an object whose "limits" field holds
+// them. Other options, such as functions, are not included.
+func (eval *Evaluator) MarshalJSON() ([]byte, error) {
+	limits := limitsToJSON(&eval.opts)
+	if limits.empty() {
+		return json.Marshal(eval.ast)
+	}
+	expression, err := json.Marshal(eval.ast)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonEvaluator{Expression: expression, Limits: &limits})
+}
+
+// UnmarshalJSON decodes an expression encoded by MarshalJSON into the
+// evaluator without parsing it again, such as to share compiled filters
+// between processes through a datastore. A JSON string is parsed as the
+// expression syntax as by UnmarshalText. The evaluator keeps the limits
+// that were encoded and fails to decode an expression exceeding them, while
+// its other options are the defaults. Use CreateEvaluatorFromJSON instead
+// to apply options such as functions.
+func (eval *Evaluator) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		var expression string
+		if err := json.Unmarshal(trimmed, &expression); err != nil {
+			return err
+		}
+		return eval.UnmarshalText([]byte(expression))
+	}
+
+	decoded, err := CreateEvaluatorFromJSON(data)
+	if err != nil {
+		return err
+	}
+	*eval = *decoded
+	return nil
+}
+
+// jsonEvaluator is the form of an Evaluator encoded by MarshalJSON when it
+// has limits
+type jsonEvaluator struct {
+	Expression json.RawMessage `json:"expression"`
+	Limits     *jsonLimits     `json:"limits"`
+}
+
+// jsonLimits holds the limits of an Evaluator, with the denied operators
+// given by their symbols as listed by Field.String
+type jsonLimits struct {
+	MaxExpressions        uint64   `json:"maxExpressions,omitempty"`
+	MaxExpressionLength   uint64   `json:"maxExpressionLength,omitempty"`
+	MaxSelectorDepth      uint64   `json:"maxSelectorDepth,omitempty"`
+	MaxExpressionDepth    uint64   `json:"maxExpressionDepth,omitempty"`
+	MaxMatchExpressions   uint64   `json:"maxMatchExpressions,omitempty"`
+	MaxEvaluationSteps    uint64   `json:"maxEvaluationSteps,omitempty"`
+	MaxEvaluationDuration string   `json:"maxEvaluationDuration,omitempty"`
+	DeniedOperators       []string `json:"deniedOperators,omitempty"`
+}
+
+func limitsToJSON(opts *options) jsonLimits {
+	limits := jsonLimits{
+		MaxExpressions:      opts.withMaxExpressions,
+		MaxExpressionLength: opts.withMaxExpressionLength,
+		MaxSelectorDepth:    opts.withMaxSelectorDepth,
+		MaxExpressionDepth:  opts.withMaxExpressionDepth,
+		MaxMatchExpressions: opts.withMaxMatchExpressions,
+		MaxEvaluationSteps:  opts.withMaxEvaluationSteps,
+	}
+	if opts.withMaxEvalDuration != 0 {
+		limits.MaxEvaluationDuration = opts.withMaxEvalDuration.String()
+	}
+	for _, op := range allOperators() {
+		if _, denied := opts.withDeniedOperators[op]; denied {
+			limits.DeniedOperators = append(limits.DeniedOperators, op.Symbol())
+		}
+	}
+	return limits
+}
+
+func (limits *jsonLimits) empty() bool {
+	return limits.MaxExpressions == 0 &&
+		limits.MaxExpressionLength == 0 &&
+		limits.MaxSelectorDepth == 0 &&
+		limits.MaxExpressionDepth == 0 &&
+		limits.MaxMatchExpressions == 0 &&
+		limits.MaxEvaluationSteps == 0 &&
+		limits.MaxEvaluationDuration == "" &&
+		len(limits.DeniedOperators) == 0
+}
+
+// options returns the options which set the limits
+func (limits *jsonLimits) options() ([]Option, error) {
+	opts := []Option{
+		WithMaxExpressions(limits.MaxExpressions),
+		WithMaxExpressionLength(limits.MaxExpressionLength),
+		WithMaxSelectorDepth(limits.MaxSelectorDepth),
+		WithMaxExpressionDepth(limits.MaxExpressionDepth),
+		WithMaxMatchExpressions(limits.MaxMatchExpressions),
+		WithMaxEvaluationSteps(limits.MaxEvaluationSteps),
+	}
+	if limits.MaxEvaluationDuration != "" {
+		d, err := time.ParseDuration(limits.MaxEvaluationDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maximum evaluation duration: %w", err)
+		}
+		opts = append(opts, WithMaxEvaluationDuration(d))
+	}
+	for _, symbol := range limits.DeniedOperators {
+		op, ok := lookupOperatorSymbol(symbol)
+		if !ok {
+			return nil, fmt.Errorf("unknown denied operator %q", symbol)
+		}
+		opts = append(opts, WithDeniedOperators(op))
+	}
+	return opts, nil
+}
+
+// decodeEvaluator decodes the expression encoded by MarshalJSON, accepting
+// expressions encoded without limits as well, and returns it along with the
+// options made of its limits followed by opts. The expression is decoded
+// within the maximum depth of those options.
+func decodeEvaluator(data []byte, opts []Option) (grammar.Expression, options, error) {
+	var encoded jsonEvaluator
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, options{}, err
+	}
+	expression := data
+	if encoded.Limits != nil {
+		limits, err := encoded.Limits.options()
+		if err != nil {
+			return nil, options{}, err
+		}
+		opts = append(limits, opts...)
+		expression = encoded.Expression
+	}
+
+	parsedOpts := getOpts(opts...)
+	ast, err := grammar.UnmarshalExpressionMaxDepth(expression, parsedOpts.withMaxExpressionDepth)
+	return ast, parsedOpts, err
+}
+
+// MarshalText returns the expression in canonical form
+func (eval *Evaluator) MarshalText() ([]byte, error) {
+	return []byte(eval.String()), nil
+}
+
+// UnmarshalText parses the expression into the evaluator with the default
+// options, such as to compile a filter given as a field of a configuration
+// file when it is loaded so that invalid syntax is reported up front.
+func (eval *Evaluator) UnmarshalText(text []byte) error {
+	decoded, err := CreateEvaluator(string(text))
+	if err != nil {
+		return err
+	}
+	*eval = *decoded
+	return nil
+}
+
+// SetValueSet adds or replaces the named set of values referenced in the
+// expression as @name. This is safe to call while evaluations are running.
+func (eval *Evaluator) SetValueSet(name string, values []string) {
+	eval.opts.valueSets.set(name, values)
+	for _, operand := range eval.operands {
+		operand.SetValueSet(name, values)
+	}
+}
+
+// DeleteValueSet removes the named set of values. Subsequent evaluations
+// of expressions referencing the set will fail.
+func (eval *Evaluator) DeleteValueSet(name string) {
+	eval.opts.valueSets.delete(name)
+	for _, operand := range eval.operands {
+		operand.DeleteValueSet(name)
+	}
+}
+
+// pointerKey returns the canonical JSON Pointer string for a selector path
+func pointerKey(path []string) string {
+	ptr := pointerstructure.Pointer{Parts: path}
+	return ptr.String()
 }
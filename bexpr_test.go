@@ -1,8 +1,21 @@
 package bexpr
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-bexpr/grammar"
 	"github.com/stretchr/testify/require"
 )
 
@@ -11,6 +24,7 @@ func TestCreateEvaluator(t *testing.T) {
 
 	type testCase struct {
 		expression string
+		opts       []Option
 		err        string
 	}
 
@@ -18,6 +32,73 @@ func TestCreateEvaluator(t *testing.T) {
 		"basic": {
 			expression: "foo == 3",
 		},
+		"invalid default value selector": {
+			expression: "foo == 3",
+			opts:       []Option{WithDefaultValue("foo[", 3)},
+			err:        "invalid selector \"foo[\" for default value: 1:5 (4): rule \"index\": Invalid index",
+		},
+		"max expression length": {
+			expression: "foo == 3",
+			opts:       []Option{WithMaxExpressionLength(8)},
+		},
+		"max expression length exceeded": {
+			expression: "foo == 30",
+			opts:       []Option{WithMaxExpressionLength(8)},
+			err:        "expression exceeds the maximum length of 8 bytes",
+		},
+		"max selector depth": {
+			expression: "foo.bar.baz == 3",
+			opts:       []Option{WithMaxSelectorDepth(2)},
+			err:        `selector "foo.bar.baz" exceeds the maximum depth of 2`,
+		},
+		"max selector depth function": {
+			expression: "fn(foo.bar.baz)",
+			opts:       []Option{WithMaxSelectorDepth(2), WithFunction("fn", func(args ...interface{}) (bool, error) { return true, nil })},
+			err:        `selector "foo.bar.baz" exceeds the maximum depth of 2`,
+		},
+		"max selector depth comparison": {
+			expression: "foo.bar + 1 > a.b.c",
+			opts:       []Option{WithMaxSelectorDepth(2)},
+			err:        `selector "a.b.c" exceeds the maximum depth of 2`,
+		},
+		"max selector depth quantifier": {
+			expression: "any(foo.bar.baz, x -> x == 1)",
+			opts:       []Option{WithMaxSelectorDepth(2)},
+			err:        `selector "foo.bar.baz" exceeds the maximum depth of 2`,
+		},
+		"max expression depth": {
+			expression: "a == 1 and not (b == 2 or c == 3)",
+			opts:       []Option{WithMaxExpressionDepth(4)},
+		},
+		"max expression depth exceeded": {
+			expression: "a == 1 and not (b == 2 or all(c, x -> x == 3))",
+			opts:       []Option{WithMaxExpressionDepth(4)},
+			err:        "expression exceeds the maximum depth of 4",
+		},
+		"max match expressions": {
+			expression: "foo == 3 or (bar == 4 and not baz == 5)",
+			opts:       []Option{WithMaxMatchExpressions(2)},
+			err:        "expression contains 3 match expressions which exceeds the maximum of 2",
+		},
+		"denied operator": {
+			expression: "foo == 3 or bar matches `.*`",
+			opts:       []Option{WithDeniedOperators(grammar.MatchMatches)},
+			err:        `match operator "Matches" is not allowed for selector: "bar"`,
+		},
+		"untrusted input": {
+			expression: "foo == 3 and bar.baz contains 4",
+			opts:       []Option{WithUntrustedInputLimits()},
+		},
+		"untrusted input regex": {
+			expression: "foo not matches `.*`",
+			opts:       []Option{WithUntrustedInputLimits()},
+			err:        `match operator "Not Matches" is not allowed for selector: "foo"`,
+		},
+		"untrusted input overridden": {
+			expression: "foo == 3 or bar == 4",
+			opts:       []Option{WithUntrustedInputLimits(), WithMaxMatchExpressions(1)},
+			err:        "expression contains 2 match expressions which exceeds the maximum of 1",
+		},
 	}
 
 	for name, tcase := range tests {
@@ -26,11 +107,1401 @@ func TestCreateEvaluator(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			expr, err := CreateEvaluator(tcase.expression)
+			expr, err := CreateEvaluator(tcase.expression, tcase.opts...)
 			if tcase.err == "" {
 				require.NoError(t, err)
 				require.NotNil(t, expr)
+			} else {
+				require.EqualError(t, err, tcase.err)
+				require.Nil(t, expr)
+			}
+		})
+	}
+}
+
+// countingReader tracks how many bytes have been read from the underlying reader
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += n
+	return n, err
+}
+
+func TestCreateEvaluatorFromReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		expr, err := CreateEvaluatorFromReader(strings.NewReader("foo == 3"), WithMaxExpressionLength(8))
+		require.NoError(t, err)
+
+		match, err := expr.Evaluate(map[string]int{"foo": 3})
+		require.NoError(t, err)
+		require.True(t, match)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		t.Parallel()
+		expr, err := CreateEvaluatorFromReader(strings.NewReader("foo =="))
+		require.Error(t, err)
+		require.Nil(t, expr)
+	})
+
+	t.Run("bounded read", func(t *testing.T) {
+		t.Parallel()
+		r := &countingReader{r: strings.NewReader("foo == 3 or " + strings.Repeat("foo == 3 or ", 100000) + "foo == 3")}
+		expr, err := CreateEvaluatorFromReader(r, WithMaxExpressionLength(1024))
+		require.EqualError(t, err, "expression exceeds the maximum length of 1024 bytes")
+		require.True(t, errors.Is(err, ErrExpressionTooLong))
+		require.Nil(t, expr)
+		require.Equal(t, 1025, r.read)
+
+		_, err = CreateEvaluator("foo == 30", WithMaxExpressionLength(8))
+		require.True(t, errors.Is(err, ErrExpressionTooLong))
+	})
+
+	t.Run("largest limits", func(t *testing.T) {
+		t.Parallel()
+		for _, max := range []uint64{math.MaxInt64 - 1, math.MaxInt64, math.MaxUint64} {
+			expr, err := CreateEvaluatorFromReader(strings.NewReader("foo == 3"), WithMaxExpressionLength(max))
+			require.NoError(t, err)
+			require.Equal(t, "foo == 3", expr.String())
+		}
+	})
+}
+
+func TestCreateEvaluator_ErrorRecovery(t *testing.T) {
+	t.Parallel()
+
+	expr, err := CreateEvaluator("foo = 3 and bar == 3 or baz is emtpy", WithErrorRecovery())
+	require.Nil(t, expr)
+
+	var diags Diagnostics
+	require.True(t, errors.As(err, &diags))
+	require.Len(t, diags, 2)
+	require.EqualError(t, diags[0], "1:1 (0): rule \"clause\": Invalid expression \"foo = 3\"")
+	require.EqualError(t, diags[1], "1:25 (24): rule \"clause\": Invalid expression \"baz is emtpy\"")
+
+	var perr *grammar.ParseError
+	require.True(t, errors.As(diags[1], &perr))
+	require.Equal(t, "baz", perr.Token)
+
+	// without error recovery only the first error is reported
+	_, err = CreateEvaluator("foo = 3 and bar == 3 or baz is emtpy")
+	require.False(t, errors.As(err, &diags))
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, 4, perr.Offset)
+
+	expr, err = CreateEvaluator("foo == 3", WithErrorRecovery())
+	require.NoError(t, err)
+	match, err := expr.Evaluate(map[string]int{"foo": 3})
+	require.NoError(t, err)
+	require.True(t, match)
+}
+
+func TestCreateEvaluator_CommaAnd(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"Status": "passing",
+		"Meta":   map[string]string{"env": "prod"},
+	}
+
+	expr, err := CreateEvaluator("Status==passing,Meta.env==prod", WithCommaAnd())
+	require.NoError(t, err)
+	match, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	expr, err = CreateEvaluator("Status==passing, Meta.env==dev", WithCommaAnd())
+	require.NoError(t, err)
+	match, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, match)
+
+	_, err = CreateEvaluator("Status==passing,Meta.env==prod")
+	require.Error(t, err)
+}
+
+func TestCreateEvaluator_SelectorAccess(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		err        string
+	}
+
+	allowed := WithAllowedSelectors("Name", "Meta.*.public", "Nodes.*.Name", `"/Extra/a~1b"`)
+	denied := WithDeniedSelectors("Secrets", "Meta.*.private")
+
+	tests := map[string]testCase{
+		"Allowed":                {expression: "Name == web and Meta.a.public == x", opts: []Option{allowed}},
+		"Allowed Quantifier":     {expression: "all(Nodes, n -> n.Name == web)", opts: []Option{WithAllowedSelectors("Nodes")}},
+		"Not Allowed":            {expression: "Name == web and Port == 80", opts: []Option{allowed}, err: `selector "Port" is not allowed`},
+		"Not Allowed Quantifier": {expression: "any(Nodes, n -> n.Name == web)", opts: []Option{allowed}, err: `selector "Nodes" is not allowed`},
+		"Allowed Pointer":        {expression: `"/Extra/a~1b" == x`, opts: []Option{allowed}},
+		"Allowed Wildcard":       {expression: "Meta.*.public == x", opts: []Option{allowed}},
+		"Wildcard Not Allowed":   {expression: "Name.* == x", opts: []Option{WithAllowedSelectors("Name.a")}, err: `selector "Name.*" is not allowed`},
+		"Denied":                 {expression: "Name == web and Secrets.token == x", opts: []Option{denied}, err: `selector "Secrets.token" is not allowed`},
+		"Denied Function":        {expression: "isSet(Secrets)", opts: []Option{denied, WithFunction("isSet", func(args ...interface{}) (bool, error) { return true, nil })}, err: `selector "Secrets" is not allowed`},
+		"Denied Quantifier":      {expression: "any(Meta, m -> m.private == x)", opts: []Option{denied}, err: `selector "Meta.*.private" is not allowed`},
+		"Denied Wildcard":        {expression: "Meta.a.* == x", opts: []Option{denied}, err: `selector "Meta.a.*" is not allowed`},
+		"Denied Over Allowed":    {expression: "Secrets.token == x", opts: []Option{denied, WithAllowedSelectors("Secrets")}, err: `selector "Secrets.token" is not allowed`},
+		"Not Denied":             {expression: "Meta.a.public == x and Secret == x", opts: []Option{denied}},
+		"Invalid":                {expression: "Name == x", opts: []Option{WithDeniedSelectors("a..b")}, err: `invalid denied selector "a..b": 1:3 (2): no match found, expected: "*", "\"", "` + "`" + `", [0-9] or [a-zA-Z]`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateEvaluatorFromJSON(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"Status": "passing",
+		"Meta":   map[string]string{"env": "prod"},
+		"Port":   8080,
+	}
+
+	expr, err := CreateEvaluator(`Status == passing and Meta.env in [prod, staging] and not Port < $min`)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(expr)
+	require.NoError(t, err)
+
+	decoded, err := CreateEvaluatorFromJSON(data)
+	require.NoError(t, err)
+
+	// placeholders stay unbound
+	_, err = decoded.Evaluate(value)
+	require.EqualError(t, err, "parameter $min is not bound")
+
+	decoded, err = decoded.Bind(map[string]interface{}{"min": 1024})
+	require.NoError(t, err)
+	match, err := decoded.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// options are applied to the decoded expression
+	_, err = CreateEvaluatorFromJSON(data, WithMaxMatchExpressions(2))
+	require.EqualError(t, err, "expression contains 3 match expressions which exceeds the maximum of 2")
+
+	_, err = CreateEvaluatorFromJSON([]byte(`{"type": "match"`))
+	require.EqualError(t, err, "error decoding expression: unexpected end of JSON input")
+
+	// the length limit is checked before decoding
+	_, err = CreateEvaluatorFromJSON(append(data, bytes.Repeat([]byte(" "), 4096)...), WithUntrustedInputLimits())
+	require.True(t, errors.Is(err, ErrExpressionTooLong))
+	require.EqualError(t, err, "expression exceeds the maximum length of 4096 bytes")
+	_, err = CreateEvaluatorFromJSON(data, WithUntrustedInputLimits())
+	require.NoError(t, err)
+
+	// the depth limit is checked while decoding
+	deep := []byte(strings.Repeat(`{"type": "unary", "operator": "Not", "operand": `, 200) + string(data) + strings.Repeat(`}`, 200))
+	_, err = CreateEvaluatorFromJSON(deep, WithMaxExpressionDepth(128))
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, uint64(128), limitErr.Max)
+	require.EqualError(t, err, "expression exceeds the maximum depth of 128")
+
+	// the limits are kept through the encoding
+	limited, err := CreateEvaluator(`Status == passing and Port > 1024`, WithUntrustedInputLimits(), WithMaxEvaluationSteps(2))
+	require.NoError(t, err)
+	limitedData, err := json.Marshal(limited)
+	require.NoError(t, err)
+
+	var restoredLimited Evaluator
+	require.NoError(t, json.Unmarshal(limitedData, &restoredLimited))
+	require.Equal(t, limited.String(), restoredLimited.String())
+	_, err = restoredLimited.Evaluate(value)
+	var budgetErr *BudgetExceededError
+	require.True(t, errors.As(err, &budgetErr))
+	reencoded, err := json.Marshal(&restoredLimited)
+	require.NoError(t, err)
+	require.JSONEq(t, string(limitedData), string(reencoded))
+
+	// options given when decoding override the encoded limits
+	decoded, err = CreateEvaluatorFromJSON(limitedData, WithMaxEvaluationSteps(0))
+	require.NoError(t, err)
+	match, err = decoded.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// the expression is checked against the encoded limits
+	pattern, err := CreateEvaluator(`Status matches "^pass"`)
+	require.NoError(t, err)
+	patternData, err := json.Marshal(pattern)
+	require.NoError(t, err)
+	_, err = CreateEvaluatorFromJSON([]byte(`{"expression": ` + string(patternData) + `, "limits": {"deniedOperators": ["matches"]}}`))
+	var opErr *UnsupportedOperatorError
+	require.True(t, errors.As(err, &opErr))
+	require.Equal(t, grammar.MatchMatches, opErr.Operator)
+	err = json.Unmarshal([]byte(`{"expression": `+string(data)+`, "limits": {"maxMatchExpressions": 2}}`), &restoredLimited)
+	require.EqualError(t, err, "expression contains 3 match expressions which exceeds the maximum of 2")
+	_, err = CreateEvaluatorFromJSON([]byte(`{"expression": ` + string(data) + `, "limits": {"deniedOperators": ["bogus"]}}`))
+	require.EqualError(t, err, `error decoding expression: unknown denied operator "bogus"`)
+
+	// evaluators can be stored within other values
+	type stored struct {
+		Filter *Evaluator
+	}
+	encoded, err := json.Marshal(stored{Filter: expr})
+	require.NoError(t, err)
+
+	var restored stored
+	require.NoError(t, json.Unmarshal(encoded, &restored))
+	require.Equal(t, expr.String(), restored.Filter.String())
+	restored.Filter, err = restored.Filter.Bind(map[string]interface{}{"min": 1024})
+	require.NoError(t, err)
+	match, err = restored.Filter.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	err = json.Unmarshal([]byte(`{"Filter": {"type": "bogus"}}`), &restored)
+	require.Error(t, err)
+}
+
+func TestEvaluator_Text(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Filter *Evaluator
+	}
+
+	var cfg config
+	require.NoError(t, json.Unmarshal([]byte(`{"Filter": "Name == web and Port > 80"}`), &cfg))
+	match, err := cfg.Filter.Evaluate(map[string]interface{}{"Name": "web", "Port": 443})
+	require.NoError(t, err)
+	require.True(t, match)
+
+	text, err := cfg.Filter.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "Name == web and Port > 80", string(text))
+
+	var eval Evaluator
+	require.NoError(t, eval.UnmarshalText(text))
+	require.Equal(t, cfg.Filter.String(), eval.String())
+
+	err = json.Unmarshal([]byte(`{"Filter": "Name =="}`), &cfg)
+	var syntaxErr *SyntaxError
+	require.True(t, errors.As(err, &syntaxErr))
+}
+
+func TestEvaluator_Optimize(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		optimized  string
+	}
+
+	tests := map[string]testCase{
+		"Unchanged":           {"a == 1 and (b == 2 or c == 3)", "a == 1 and (b == 2 or c == 3)"},
+		"True And":            {"true and a == 1 and true", "a == 1"},
+		"False And":           {"a == 1 and (b == 2 and false)", "false"},
+		"True Or":             {"a == 1 or true", "true"},
+		"False Or":            {"false or a == 1 or false", "a == 1"},
+		"Only Constants":      {"true and true", "true"},
+		"Xor Constants":       {"(a == 1 xor true) and (b == 2 xor false)", "not a == 1 and b == 2"},
+		"Xor Self":            {"a == 1 xor a == 1", "false"},
+		"Xor Negation":        {"a == 1 xor not a == 1", "true"},
+		"Repeated Clause":     {"a == 1 and b == 2 and a == 1", "a == 1 and b == 2"},
+		"Repeated Nested":     {"(a == 1 and b == 2) and (b == 2 and a == 1)", "a == 1 and b == 2"},
+		"Repeated Spelling":   {"a==1 or a eq 1", "a == 1"},
+		"Contradiction":       {"a == 1 and b == 2 and not a == 1", "false"},
+		"Tautology":           {"a is empty or not a is empty or b == 1", "true"},
+		"Within Quantifier":   {"any(items, i -> i.x == 1 and true)", "any(items, i -> i.x == 1)"},
+		"Repeated Quantifier": {"any(items, i -> i.x == 1) or any(items, i -> i.x == 1)", "any(items, i -> i.x == 1)"},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+			original := expr.String()
+
+			optimized := expr.Optimize()
+			require.Equal(t, tcase.optimized, optimized.String())
+			// the original evaluator is not modified
+			require.Equal(t, original, expr.String())
+		})
+	}
+
+	// the parser already removes double negations but built expressions
+	// may contain them
+	expr, err := CreateEvaluatorFromExpr(Match("a").Equals(1).Not().Not().And(Match("b").Equals(2).Not().Not().Not()))
+	require.NoError(t, err)
+	require.Equal(t, "a == 1 and not b == 2", expr.Optimize().String())
+
+	// optimized evaluators give the same results
+	expr, err = CreateEvaluator("(not not (a == 1) and a == $a and b in [x, y]) or true and c != 3")
+	require.NoError(t, err)
+	expr, err = expr.Bind(map[string]interface{}{"a": 1})
+	require.NoError(t, err)
+	expr = expr.Optimize()
+	require.Equal(t, `a == 1 and b in [x, y] or c != 3`, expr.String())
+
+	match, err := expr.Evaluate(map[string]interface{}{"a": 1, "b": "y", "c": 3})
+	require.NoError(t, err)
+	require.True(t, match)
+}
+
+func TestEvaluator_PartialEvaluate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		residual   string
+		err        string
+	}
+
+	partial := map[string]interface{}{
+		"Tenant": "acme",
+		"Region": "eu",
+		"Tags":   []string{"a", "b"},
+		"Nodes":  []map[string]int{{"Port": 80}, {"Port": 8080}},
+	}
+
+	tests := map[string]testCase{
+		"Residual":          {expression: "Tenant == acme and Status == passing", residual: "Status == passing"},
+		"Decided False":     {expression: "Tenant == other and Status == passing", residual: "false"},
+		"Decided True":      {expression: "Region in [eu, us] or Status == passing", residual: "true"},
+		"Nothing Known":     {expression: "Status == passing or Port > 80", residual: "Status == passing or Port > 80"},
+		"Everything Known":  {expression: "Tenant == acme and b in Tags", residual: "true"},
+		"Not":               {expression: "not (Tenant == acme) or not Status == passing", residual: "not Status == passing"},
+		"Mixed Clause":      {expression: "hasTag(Tags, Meta) and Tenant == acme", residual: "hasTag(Tags, Meta)"},
+		"Quantifier":        {expression: "any(Nodes, n -> n.Port == 8080) and Healthy", residual: "Healthy"},
+		"Wildcard":          {expression: "Nodes.*.Port == 80 and Healthy", residual: "Healthy"},
+		"Missing Is Null":   {expression: "Owner is null and Tenant == acme", residual: "Owner is null"},
+		"Evaluation Errors": {expression: "Tags > 3 and Status == passing", err: `Cannot perform ordered comparisons on type slice for selector: "Tags"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression, WithFunction("hasTag", func(args ...interface{}) (bool, error) {
+				return true, nil
+			}))
+			require.NoError(t, err)
+
+			residual, err := expr.PartialEvaluate(partial)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.residual, residual.String())
+		})
+	}
+}
+
+func TestCombineEvaluators(t *testing.T) {
+	t.Parallel()
+
+	tenant, err := CreateEvaluator("Tenant == acme and Owner in @admins", WithValueSet("admins", []string{"alice"}))
+	require.NoError(t, err)
+
+	user, err := CreateEvaluator("Name == WEB and isHealthy(Status) and Region in [eu, us]",
+		WithCaseInsensitive("Name"),
+		WithFunction("isHealthy", func(args ...interface{}) (bool, error) {
+			return args[0] == "passing", nil
+		}))
+	require.NoError(t, err)
+
+	other, err := CreateEvaluator("Region == $region")
+	require.NoError(t, err)
+
+	value := map[string]string{
+		"Tenant": "acme",
+		"Owner":  "alice",
+		"Name":   "web",
+		"Status": "passing",
+		"Region": "eu",
+	}
+
+	combined := And(tenant, user)
+	require.Equal(t, "(Tenant == acme and Owner in @admins) and Name == WEB and isHealthy(Status) and Region in [eu, us]", combined.String())
+	match, err := combined.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// the value sets of the combined evaluator are independent
+	tenant.SetValueSet("admins", []string{"bob"})
+	match, err = combined.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+	match, err = tenant.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, match)
+
+	combined = Or(tenant, Not(user))
+	require.Equal(t, "Tenant == acme and Owner in @admins or not (Name == WEB and isHealthy(Status) and Region in [eu, us])", combined.String())
+	match, err = combined.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, match)
+
+	// placeholders of the evaluators must still be bound
+	combined = And(user, other)
+	_, err = combined.Evaluate(value)
+	require.EqualError(t, err, "parameter $region is not bound")
+	combined, err = combined.Bind(map[string]interface{}{"region": "eu"})
+	require.NoError(t, err)
+	match, err = combined.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// each expression is evaluated with the options of its own evaluator
+	strict, err := CreateEvaluator("Name == Web and Zone == west", WithMaxEvaluationSteps(10))
+	require.NoError(t, err)
+	lenient, err := CreateEvaluator("Name == WEB and Zone == west",
+		WithCaseInsensitive("Name"), WithMissingResult(true))
+	require.NoError(t, err)
+
+	match, err = And(lenient, Not(strict)).Evaluate(map[string]string{"Name": "web", "Zone": "west"})
+	require.NoError(t, err)
+	require.True(t, match)
+	match, err = lenient.Evaluate(map[string]string{"Name": "web"})
+	require.NoError(t, err)
+	require.True(t, match)
+	_, err = Or(strict, lenient).Evaluate(map[string]string{"Name": "Web"})
+	require.EqualError(t, err, `error finding value in datum: /Zone at part 0: couldn't find key "Zone"`)
+	match, err = Or(lenient, strict).Evaluate(map[string]string{"Name": "Web"})
+	require.NoError(t, err)
+	require.True(t, match)
+	_, err = And(lenient, strict).Evaluate(map[string]string{"Name": "Web"})
+	require.EqualError(t, err, `error finding value in datum: /Zone at part 0: couldn't find key "Zone"`)
+	truth, err := And(lenient, strict).EvaluateTruth(map[string]string{"Name": "Web"})
+	require.NoError(t, err)
+	require.Equal(t, TruthUnknown, truth)
+	explanation, err := And(lenient, Not(strict)).Explain(map[string]string{"Name": "web", "Zone": "west"})
+	require.NoError(t, err)
+	require.True(t, explanation.Result)
+
+	// evaluators created from the same expression keep their own options
+	expr := Match("Name").Equals("WEB")
+	exact, err := CreateEvaluatorFromExpr(expr)
+	require.NoError(t, err)
+	folded, err := CreateEvaluatorFromExpr(expr, WithCaseInsensitive("Name"))
+	require.NoError(t, err)
+	for _, eval := range []*Evaluator{And(exact, folded), And(folded, exact)} {
+		match, err = eval.Evaluate(map[string]string{"Name": "web"})
+		require.NoError(t, err)
+		require.False(t, match)
+	}
+	for _, eval := range []*Evaluator{Or(exact, folded), Or(folded, exact)} {
+		match, err = eval.Evaluate(map[string]string{"Name": "web"})
+		require.NoError(t, err)
+		require.True(t, match)
+	}
+
+	// optimizing does not merge clauses evaluated with different options
+	combined = Or(strict, lenient).Optimize()
+	require.Equal(t, "Name == Web and Zone == west or Name == WEB and Zone == west", combined.String())
+	match, err = combined.Evaluate(map[string]string{"Name": "WEB", "Zone": "west"})
+	require.NoError(t, err)
+	require.True(t, match)
+
+	combined, err = And(strict, lenient).PartialEvaluate(map[string]string{"Name": "web"})
+	require.NoError(t, err)
+	require.Equal(t, "false", combined.String())
+	combined, err = And(lenient, strict).PartialEvaluate(map[string]string{"Name": "Web"})
+	require.NoError(t, err)
+	require.Equal(t, "Zone == west and Zone == west", combined.String())
+	match, err = combined.Evaluate(map[string]string{})
+	require.Error(t, err)
+
+	// the combined evaluation is limited by the smallest budget
+	many, err := CreateEvaluator("all(Values, v -> v >= 0)")
+	require.NoError(t, err)
+	_, err = And(many, strict).Evaluate(map[string]interface{}{"Values": make([]int, 20), "Name": "Web", "Zone": "west"})
+	require.EqualError(t, err, "evaluation exceeded the maximum of 10 steps")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name    string
+		Port    int
+		Tags    []string
+		Meta    map[string]string
+		Checks  []struct{ Status string }
+		Extra   interface{}
+		private string
+	}
+
+	type testCase struct {
+		expression string
+		dataType   interface{}
+		errs       []string
+	}
+
+	tests := map[string]testCase{
+		"Valid": {
+			expression: `Name == web and Port > 80 and prod in Tags and Meta.env == prod and Extra.anything == 1`,
+			dataType:   service{},
+		},
+		"Pointer": {
+			expression: `Name matches "^web" and all(Checks, c -> c.Status == passing)`,
+			dataType:   &service{},
+		},
+		"Syntax Only": {
+			expression: `Unknown == 1`,
+		},
+		"Syntax Errors": {
+			expression: `Name == web and (Port > ) and Tags ==`,
+			dataType:   service{},
+			errs: []string{
+				`1:18 (17): rule "clause": Invalid expression "Port >"`,
+				`1:31 (30): rule "clause": Invalid expression "Tags =="`,
+			},
+		},
+		"Unknown Fields": {
+			expression: `Nme == web and Port.Number == 1 and private == x`,
+			dataType:   service{},
+			errs: []string{
+				`invalid selector "Nme": type bexpr.service has no field "Nme", did you mean "Name"?`,
+				`invalid selector "Port.Number": cannot select "Number" within type int`,
+				`invalid selector "private": type bexpr.service has no field "private"`,
+			},
+		},
+		"Operators And Values": {
+			expression: `Port matches "8.*" and Port == abc and Port is empty and len(Name) > x and Tags contains 1`,
+			dataType:   service{},
+			errs: []string{
+				`Value of type int is not convertible to []byte`,
+				`error getting match value in expression: strconv.ParseInt: parsing "abc": invalid syntax`,
+				`Cannot perform is empty operations on type int for selector: "Port"`,
+				`error getting match value in expression: strconv.ParseInt: parsing "x": invalid syntax`,
+			},
+		},
+		"Map Keys": {
+			expression: `Shards.3.State == ready and Shards.abc.State == ready and Shards.*.State == ready and Ports.80 == http and Ports["-1"] == x`,
+			dataType: struct {
+				Shards map[int]struct{ State string }
+				Ports  map[uint16]string
+			}{},
+			errs: []string{
+				`invalid selector "Shards.abc.State": cannot convert "abc" to key type int`,
+				`invalid selector "Ports.-1": cannot convert "-1" to key type uint16`,
+			},
+		},
+		"Quantifier Fields": {
+			// selectors of the bound variable are checked during evaluation
+			expression: `any(Chekcs, c -> c.State == passing)`,
+			dataType:   service{},
+			errs: []string{
+				`invalid selector "Chekcs": type bexpr.service has no field "Chekcs", did you mean "Checks"?`,
+			},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var errs []string
+			for _, err := range Validate(tcase.expression, tcase.dataType) {
+				errs = append(errs, err.Error())
+			}
+			require.Equal(t, tcase.errs, errs)
+		})
+	}
+}
+
+func TestEvaluator_Selectors(t *testing.T) {
+	t.Parallel()
+
+	expr, err := CreateEvaluator(`Name == web and "/Meta/a~1b" == x and (Port > 80 or Name != db) and `+
+		`len(Tags) > 1 and any(Nodes, n -> n.Port == 80) and isUp(Status, "x") and Limit + 1 > Count and Nodes.*.Port == 1`,
+		WithFunction("isUp", func(args ...interface{}) (bool, error) { return true, nil }))
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"Name"},
+		{"Meta", "a/b"},
+		{"Port"},
+		{"Tags"},
+		{"Nodes"},
+		{"Status"},
+		{"Limit"},
+		{"Count"},
+		{"Nodes", "*", "Port"},
+	}, expr.Selectors())
+
+	expr, err = CreateEvaluator("true")
+	require.NoError(t, err)
+	require.Empty(t, expr.Selectors())
+}
+
+func TestEvaluator_EvaluateContext(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"Nodes": []map[string]int{{"Port": 80}, {"Port": 443}, {"Port": 8080}},
+	}
+
+	expr, err := CreateEvaluator("all(Nodes, n -> n.Port > 0)")
+	require.NoError(t, err)
+
+	match, err := expr.EvaluateContext(context.Background(), value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = expr.EvaluateContext(ctx, value)
+	require.True(t, errors.Is(err, context.Canceled))
+
+	// cancelling during the evaluation stops it before the next element
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	expr, err = CreateEvaluator("all(Nodes, n -> slow(n.Port))", WithFunction("slow", func(args ...interface{}) (bool, error) {
+		calls++
+		cancel()
+		return true, nil
+	}))
+	require.NoError(t, err)
+	_, err = expr.EvaluateContext(ctx, value)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Equal(t, 1, calls)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = expr.FilterSliceContext(ctx, []interface{}{value})
+	require.True(t, errors.Is(err, context.Canceled))
+	_, err = expr.FilterMapContext(ctx, map[string]interface{}{"a": value})
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestEvaluator_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	// run with the race detector, as by make test-race, to check that
+	// evaluations sharing an evaluator do not interfere
+	expr, err := CreateEvaluator(`Name in @names and any(Nodes, n -> n.Port > $port) and Name matches "^w"`,
+		WithValueSet("names", []string{"web"}), WithMaxEvaluationSteps(100))
+	require.NoError(t, err)
+	expr, err = expr.Bind(map[string]interface{}{"port": 100})
+	require.NoError(t, err)
+
+	value := map[string]interface{}{
+		"Name":  "web",
+		"Nodes": []map[string]int{{"Port": 80}, {"Port": 443}},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				var match bool
+				var err error
+				switch i % 4 {
+				case 0:
+					match, err = expr.Evaluate(value)
+				case 1:
+					match, err = expr.EvaluateContext(context.Background(), value)
+				case 2:
+					var explanation *Explanation
+					explanation, err = expr.Explain(value)
+					if err == nil {
+						match = explanation.Result
+					}
+				case 3:
+					expr.SetValueSet("names", []string{"web", "db"})
+					match = true
+				}
+				if err == nil && !match {
+					err = errors.New("expression did not match")
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestEvaluator_EvaluateJSON(t *testing.T) {
+	t.Parallel()
+
+	document := []byte(`{
+		"skipped": {"deep": [1, {"a": "b"}], "text": "}]"},
+		"name": "web",
+		"port": 8080,
+		"weight": 0.5,
+		"tags": ["a", "b"],
+		"nodes": [{"id": 1, "ok": false}, {"id": 2, "ok": true}],
+		"meta": {"env": "prod", "owner": null}
+	}`)
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"String":           {expression: "name == web", result: true},
+		"Numbers":          {expression: "port > 8000 and weight < 1 and port == 8080", result: true},
+		"Nested":           {expression: "meta.env == prod and meta.owner is null", result: true},
+		"Index":            {expression: "tags.1 == b and nodes[-1].id == 2 and nodes[0].ok == false", result: true},
+		"Contains":         {expression: "tags contains a and len(nodes) == 2", result: true},
+		"Quantifier":       {expression: "any(nodes, n -> n.ok and n.id == 2)", result: true},
+		"Wildcard":         {expression: "nodes.*.id == 1", result: true},
+		"Pointer":          {expression: `"/meta/env" == prod`, result: true},
+		"Missing":          {expression: "missing == x", err: `error finding value in datum: /missing at part 0: couldn't find key`},
+		"Missing Index":    {expression: "tags.5 == x", err: `error finding value in datum: /tags/5 at part 0: couldn't find key`},
+		"Within Scalar":    {expression: "name.first is null", result: true},
+		"Missing Is Null":  {expression: "meta.missing is null", result: true},
+		"Missing Not Null": {expression: "tags.9 is not null", result: false},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			result, err := expr.EvaluateJSON(document)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+
+			result, err = expr.EvaluateReader(bytes.NewReader(document))
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+
+	expr, err := CreateEvaluator("name == web")
+	require.NoError(t, err)
+	_, err = expr.EvaluateJSON([]byte(`{"name" 1}`))
+	require.EqualError(t, err, "error finding value in datum: /name at part 0: invalid character '1' after object key")
+}
+
+func TestEvaluator_EvaluationLimits(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"Nodes": []map[string]int{{"Port": 80}, {"Port": 443}, {"Port": 8080}},
+	}
+
+	// the quantifier and its body for each of the three nodes
+	expr, err := CreateEvaluator("all(Nodes, n -> n.Port > 0)", WithMaxEvaluationSteps(4))
+	require.NoError(t, err)
+	match, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// the steps are counted for each evaluation separately
+	match, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	expr, err = CreateEvaluator("all(Nodes, n -> n.Port > 0)", WithMaxEvaluationSteps(3))
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	require.EqualError(t, err, "evaluation exceeded the maximum of 3 steps")
+	var budgetErr *BudgetExceededError
+	require.True(t, errors.As(err, &budgetErr))
+	require.Equal(t, uint64(3), budgetErr.MaxSteps)
+
+	// each value a wildcard expands to is a step
+	expr, err = CreateEvaluator("Nodes.*.Port == 8080", WithMaxEvaluationSteps(3))
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	require.EqualError(t, err, "evaluation exceeded the maximum of 3 steps")
+
+	expr, err = CreateEvaluator("all(Nodes, n -> slow(n.Port))", WithMaxEvaluationDuration(time.Millisecond),
+		WithFunction("slow", func(args ...interface{}) (bool, error) {
+			time.Sleep(2 * time.Millisecond)
+			return true, nil
+		}))
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	require.EqualError(t, err, "evaluation exceeded the maximum duration of 1ms")
+	require.True(t, errors.As(err, &budgetErr))
+	require.Equal(t, time.Millisecond, budgetErr.MaxDuration)
+}
+
+func TestEvaluator_UntrustedInputEvaluationLimits(t *testing.T) {
+	t.Parallel()
+
+	values := make([]int, 200000)
+	value := map[string]interface{}{"Values": values}
+
+	for _, expression := range []string{
+		"any(Values, v -> v == 1)",
+		"all(Values, v -> v == 0)",
+		"Values.* == 1",
+	} {
+		expr, err := CreateEvaluator(expression, WithUntrustedInputLimits())
+		require.NoError(t, err)
+		_, err = expr.Evaluate(value)
+		// whichever of the step and duration budgets runs out first
+		var budgetErr *BudgetExceededError
+		require.True(t, errors.As(err, &budgetErr), expression)
+	}
+
+	// nested wildcards are expanded lazily within the budget, here over 60^4
+	// paths sharing the same maps
+	nested := interface{}("y")
+	for depth := 0; depth < 4; depth++ {
+		level := make(map[string]interface{}, 60)
+		for i := 0; i < 60; i++ {
+			level[strconv.Itoa(i)] = nested
+		}
+		nested = level
+	}
+	expr, err := CreateEvaluator("a.*.*.*.* == x", WithUntrustedInputLimits())
+	require.NoError(t, err)
+	_, err = expr.Evaluate(map[string]interface{}{"a": nested})
+	var budgetErr *BudgetExceededError
+	require.True(t, errors.As(err, &budgetErr))
+
+	expr, err = CreateEvaluator("a.*.*.*.* == x")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = expr.EvaluateContext(ctx, map[string]interface{}{"a": nested})
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// the collection is small enough to evaluate within the budget
+	expr, err = CreateEvaluator("any(Values, v -> v == 1)", WithUntrustedInputLimits())
+	require.NoError(t, err)
+	match, err := expr.Evaluate(map[string]interface{}{"Values": values[:1000]})
+	require.NoError(t, err)
+	require.False(t, match)
+
+	// the budgets can be lifted by the options that set them
+	expr, err = CreateEvaluator("any(Values, v -> v == 1)", WithUntrustedInputLimits(),
+		WithMaxEvaluationSteps(0), WithMaxEvaluationDuration(0))
+	require.NoError(t, err)
+	match, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, match)
+}
+
+func TestErrorTypes(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Name string
+		Port int
+		Meta map[string]string
+	}
+	value := node{Name: "web", Port: 80, Meta: map[string]string{}}
+
+	_, err := CreateEvaluator("Name ==")
+	var syntaxErr *SyntaxError
+	require.True(t, errors.As(err, &syntaxErr))
+	require.Equal(t, 1, syntaxErr.Line)
+	require.Equal(t, 8, syntaxErr.Column)
+
+	_, err = CreateEvaluator("Name == (", WithErrorRecovery())
+	require.True(t, errors.As(err, &syntaxErr))
+
+	_, err = CreateEvaluator("Meta.a.b == x", WithMaxSelectorDepth(2))
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, uint64(2), limitErr.Max)
+	require.Equal(t, []string{"Meta", "a", "b"}, limitErr.Selector.Path)
+
+	_, err = CreateEvaluator("Name == web", WithMaxExpressionLength(4))
+	require.True(t, errors.As(err, &limitErr))
+	require.True(t, errors.Is(err, ErrExpressionTooLong))
+
+	evaluate := func(expression string) error {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		_, err = expr.Evaluate(value)
+		return err
+	}
+
+	var selectorErr *UnknownSelectorError
+	require.True(t, errors.As(evaluate("Nmae == web"), &selectorErr))
+	require.Equal(t, []string{"Nmae"}, selectorErr.Selector.Path)
+	require.True(t, errors.As(evaluate("Meta.env == prod"), &selectorErr))
+	require.False(t, errors.As(evaluate("Name.x == y"), &selectorErr))
+	errs := Validate("Nmae == web", node{})
+	require.Len(t, errs, 1)
+	require.True(t, errors.As(errs[0], &selectorErr))
+
+	var operatorErr *UnsupportedOperatorError
+	require.True(t, errors.As(evaluate("Port matches `8`"), &operatorErr))
+	require.Equal(t, grammar.MatchMatches, operatorErr.Operator)
+	require.True(t, errors.As(evaluate("Meta < 3"), &operatorErr))
+	require.Equal(t, []string{"Meta"}, operatorErr.Selector.Path)
+	_, err = CreateEvaluator("Name matches x", WithDeniedOperators(grammar.MatchMatches))
+	require.True(t, errors.As(err, &operatorErr))
+
+	var coercionErr *CoercionError
+	err = evaluate("Port == eighty")
+	require.EqualError(t, err, `error getting match value in expression: strconv.ParseInt: parsing "eighty": invalid syntax`)
+	require.True(t, errors.As(err, &coercionErr))
+	require.Equal(t, "eighty", coercionErr.Value)
+	require.Equal(t, []string{"Port"}, coercionErr.Selector.Path)
+	require.True(t, errors.As(evaluate("Port between 1 and x"), &coercionErr))
+	require.Equal(t, "x", coercionErr.Value)
+}
+
+func TestEvaluator_Explain(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"Name":  "web",
+		"Port":  80,
+		"Nodes": []map[string]interface{}{{"Port": 80}, {"Port": 8080}},
+	}
+
+	expr, err := CreateEvaluator("Name == web and not (Port > 80 or Port + 1 == 81) or any(Nodes, n -> n.Port > 1000)")
+	require.NoError(t, err)
+
+	explanation, err := expr.Explain(value)
+	require.NoError(t, err)
+	require.True(t, explanation.Result)
+	require.Equal(t, strings.Join([]string{
+		`true: Name == web and not (Port > 80 or Port + 1 == 81) or any(Nodes, n -> n.Port > 1000)`,
+		`  false: Name == web and not (Port > 80 or Port + 1 == 81)`,
+		`    true: Name == web (Name = "web")`,
+		`    false: not (Port > 80 or Port + 1 == 81)`,
+		`      true: Port > 80 or Port + 1 == 81`,
+		`        false: Port > 80 (Port = 80)`,
+		`        true: Port + 1 == 81 (Port = 80)`,
+		`  true: any(Nodes, n -> n.Port > 1000)`,
+		`    false: n.Port > 1000 (n = map[Port:80], n.Port = 80)`,
+		`    true: n.Port > 1000 (n = map[Port:8080], n.Port = 8080)`,
+	}, "\n"), explanation.String())
+
+	match, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.Equal(t, match, explanation.Result)
+
+	// the right operand is skipped as in evaluation
+	expr, err = CreateEvaluator("Name == db and Missing == 1")
+	require.NoError(t, err)
+	explanation, err = expr.Explain(value)
+	require.NoError(t, err)
+	require.False(t, explanation.Result)
+	require.Len(t, explanation.Clauses, 1)
+
+	expr, err = CreateEvaluator("Name == web and Missing == 1")
+	require.NoError(t, err)
+	_, err = expr.Explain(value)
+	require.EqualError(t, err, `error finding value in datum: /Missing at part 0: couldn't find key "Missing"`)
+
+	expr, err = CreateEvaluator("String == web")
+	require.NoError(t, err)
+	explanation, err = expr.Explain(testFlatStructAlt{String: "web"})
+	require.NoError(t, err)
+	require.Equal(t, `true: String == web (String = "web")`, explanation.String())
+}
+
+func TestEvaluator_EvaluateCapture(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"Name":  "web",
+		"Port":  80,
+		"Nodes": []map[string]interface{}{{"Port": 80}, {"Port": 8080}},
+	}
+
+	type testCase struct {
+		expression string
+		result     bool
+		captured   []string
+	}
+	for _, tc := range []testCase{
+		{"Name == web and Port == 80", true, []string{"Name == web", "Port == 80"}},
+		{"Name == web and Port == 81", false, []string{"Port == 81"}},
+		{"Name == db and Port == 80", false, []string{"Name == db"}},
+		{"Name == db or Port == 80", true, []string{"Port == 80"}},
+		{"Name == web or Port == 81", true, []string{"Name == web"}},
+		{"Name == db or Port == 81", false, []string{"Name == db", "Port == 81"}},
+		{"not (Name == db or Port == 81)", true, []string{"Name == db", "Port == 81"}},
+		{"Name == web xor Port == 81", true, []string{"Name == web", "Port == 81"}},
+		{"any(Nodes, n -> n.Port > 1000)", true, []string{"n.Port > 1000"}},
+		{"all(Nodes, n -> n.Port > 0)", true, []string{"n.Port > 0", "n.Port > 0"}},
+		{"all(Nodes, n -> n.Port < 1000)", false, []string{"n.Port < 1000"}},
+	} {
+		expr, err := CreateEvaluator(tc.expression)
+		require.NoError(t, err)
+		result, captures, err := expr.EvaluateCapture(value)
+		require.NoError(t, err)
+		require.Equal(t, tc.result, result, tc.expression)
+
+		var captured []string
+		for _, capture := range captures {
+			captured = append(captured, capture.Expression)
+		}
+		require.Equal(t, tc.captured, captured, tc.expression)
+	}
+
+	expr, err := CreateEvaluator("Name == web and any(Nodes, n -> n.Port > 1000 and Port < 100)")
+	require.NoError(t, err)
+	_, captures, err := expr.EvaluateCapture(value)
+	require.NoError(t, err)
+	require.Equal(t, []Capture{
+		{Expression: "Name == web", Result: true, Values: map[string]interface{}{"Name": "web"}},
+		{Expression: "n.Port > 1000", Result: true, Values: map[string]interface{}{"n.Port": 8080, "n": map[string]interface{}{"Port": 8080}}},
+		{Expression: "Port < 100", Result: true, Values: map[string]interface{}{"Port": 80, "n": map[string]interface{}{"Port": 8080}}},
+	}, captures)
+}
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Port int
+		Next *node
+	}
+
+	type service struct {
+		Name    string `bexpr:"name"`
+		Enabled bool
+		Meta    map[string]int
+		Addrs   []net.IP
+		Nodes   []node
+		Extra   interface{}
+		Skipped string `bexpr:"-"`
+		private string
+	}
+
+	var listing []string
+	for _, field := range Fields(service{}) {
+		listing = append(listing, field.String())
+	}
+	require.Equal(t, []string{
+		"/name: ==, !=, contains, not contains, is empty, is not empty, matches, not matches, <, <=, >, >=, in, not in, " +
+			"like, not like, starts with, not starts with, ends with, not ends with, between, not between, ==i, !=i, " +
+			"in cidr, not in cidr, contains any, not contains any, contains all, not contains all",
+		"/Enabled: ==, !=, in, not in",
+		"/Meta: contains, not contains, is empty, is not empty, is null, is not null, contains any, not contains any, contains all, not contains all",
+		"/Meta/<any>: ==, !=, <, <=, >, >=, in, not in, between, not between",
+		"/Addrs: is empty, is not empty, is null, is not null, in cidr, not in cidr",
+		"/Addrs/<any>: is null, is not null, in cidr, not in cidr",
+		"/Nodes: is empty, is not empty, is null, is not null",
+		"/Nodes/<any>/Port: ==, !=, <, <=, >, >=, in, not in, between, not between",
+		"/Nodes/<any>/Next: is null, is not null",
+		"/Extra: ==, !=, contains, not contains, is empty, is not empty, matches, not matches, <, <=, >, >=, in, not in, " +
+			"like, not like, starts with, not starts with, ends with, not ends with, between, not between, ==i, !=i, " +
+			"is null, is not null, in cidr, not in cidr, contains any, not contains any, contains all, not contains all",
+	}, listing)
+
+	// every listed operator can be used with the field
+	fields := Fields(service{})
+	require.Equal(t, []string{"Nodes", FieldAny, "Port"}, fields[7].Path)
+	require.Equal(t, reflect.TypeOf(0), fields[7].Type)
+	errs := Validate(`Meta.a between 1 and 2 and Nodes.0.Port in [1, 2] and Addrs in cidr "10.0.0.0/8"`, service{})
+	require.Empty(t, errs)
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Port   uint16
+		Weight float64
+		Parent *node
+	}
+
+	type service struct {
+		Name   string `bexpr:"name"`
+		Up     bool
+		Labels map[string]string
+		Nodes  []node
+		Addr   net.IP
+		Extra  interface{}
+	}
+
+	schema, err := JSONSchema(&service{})
+	require.NoError(t, err)
+
+	// the operators of strings are the same wherever they are
+	stringOps := `["==", "!=", "contains", "not contains", "is empty", "is not empty", "matches", "not matches", "<", "<=", ">", ">=", ` +
+		`"in", "not in", "like", "not like", "starts with", "not starts with", "ends with", "not ends with", "between", "not between", ` +
+		`"==i", "!=i", "in cidr", "not in cidr", "contains any", "not contains any", "contains all", "not contains all"]`
+	numberOps := `["==", "!=", "<", "<=", ">", ">=", "in", "not in", "between", "not between"]`
+
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "x-bexpr-operators": `+stringOps+`},
+			"Up": {"type": "boolean", "x-bexpr-operators": ["==", "!=", "in", "not in"]},
+			"Labels": {
+				"type": "object",
+				"additionalProperties": {"type": "string", "x-bexpr-operators": `+stringOps+`},
+				"x-bexpr-operators": ["contains", "not contains", "is empty", "is not empty", "is null", "is not null", "contains any", "not contains any", "contains all", "not contains all"]
+			},
+			"Nodes": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"additionalProperties": false,
+					"properties": {
+						"Port": {"type": "integer", "x-bexpr-operators": `+numberOps+`},
+						"Weight": {"type": "number", "x-bexpr-operators": `+numberOps+`},
+						"Parent": {"type": "object", "x-bexpr-operators": ["is null", "is not null"]}
+					}
+				},
+				"x-bexpr-operators": ["is empty", "is not empty", "is null", "is not null"]
+			},
+			"Addr": {"type": "string", "format": "ip", "x-bexpr-operators": ["is null", "is not null", "in cidr", "not in cidr"]},
+			"Extra": {"x-bexpr-operators": ["==", "!=", "contains", "not contains", "is empty", "is not empty", "matches", "not matches", "<", "<=", ">", ">=", `+
+		`"in", "not in", "like", "not like", "starts with", "not starts with", "ends with", "not ends with", "between", "not between", `+
+		`"==i", "!=i", "is null", "is not null", "in cidr", "not in cidr", "contains any", "not contains any", "contains all", "not contains all"]}
+		}
+	}`, string(schema))
+}
+
+func TestLoadFields(t *testing.T) {
+	t.Parallel()
+
+	fields, err := LoadFields(strings.NewReader(`[
+		{"selector": "Status", "type": "string", "operators": ["==", "!=", "in"]},
+		{"selector": "Meta.*", "aliases": ["Labels.*"], "type": "string"},
+		{"selector": "Port", "type": "integer"},
+		{"selector": "Nodes"},
+		{"selector": "Nodes.*.Name", "type": "string"}
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, "/Status: ==, !=, in", fields[0].String())
+	require.Equal(t, [][]string{{"Labels", FieldAny}}, fields[1].Aliases)
+	require.Equal(t, reflect.TypeOf(int64(0)), fields[2].Type)
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	value := map[string]interface{}{
+		"Status": "passing",
+		"Meta":   map[string]string{"env": "prod"},
+		"Port":   8080,
+		"Nodes":  []map[string]string{{"Name": "web"}},
+	}
+
+	tests := map[string]testCase{
+		"Allowed":          {expression: "Status in [passing, warning] and Port > 80", result: true},
+		"Alias":            {expression: "Labels.env == prod and Meta.env == prod", result: true},
+		"Quantifier":       {expression: "any(Nodes, n -> n.Name == web)", result: true},
+		"Unknown Field":    {expression: "Stauts == passing", err: `invalid selector "Stauts": not a known field, did you mean "Status"?`},
+		"Hidden Subfield":  {expression: "any(Nodes, n -> n.Secret == x)", err: `invalid selector "Nodes.*.Secret": not a known field`},
+		"Operator":         {expression: `Status matches "pass"`, err: `match operator "Matches" is not supported for selector: "Status"`},
+		"Length":           {expression: "len(Port) > 1", err: `match operator "Greater Than" is not supported for the length of selector: "Port"`},
+		"Coercion":         {expression: "Port == abc", err: `error getting match value in expression: strconv.ParseInt: parsing "abc": invalid syntax`},
+		"Untyped Operator": {expression: "Nodes is not empty", result: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression, WithFields(fields...))
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
 			}
+			require.NoError(t, err)
+
+			result, err := expr.Evaluate(value)
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
 		})
 	}
+
+	expr, err := CreateEvaluator("Labels.env == prod", WithFields(fields...))
+	require.NoError(t, err)
+	require.Equal(t, "Meta.env == prod", expr.String())
+
+	for input, expected := range map[string]string{
+		`[{"selector": "A", "operators": ["=="], "extra": 1}]`: `error decoding fields: json: unknown field "extra"`,
+		`[{"selector": "A", "type": "date"}]`:                  `unknown type "date" for field "A"`,
+		`[{"selector": "A", "operators": ["~"]}]`:              `unknown operator "~" for field "A"`,
+		`[{"selector": "A.*", "aliases": ["B"]}]`:              `alias "B" for field "A.*" must have the same number of wildcards`,
+	} {
+		_, err := LoadFields(strings.NewReader(input))
+		require.EqualError(t, err, expected)
+	}
+}
+
+func TestMergeFields(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name    string
+		Meta    map[string]string
+		Secrets map[string]string
+		Extra   interface{}
+	}
+
+	generated := Fields(service{})
+	fields := MergeFields(generated,
+		Field{Path: []string{"Name"}, Operators: []grammar.MatchOperator{grammar.MatchEqual, grammar.MatchNotEqual}},
+		Field{Path: []string{"Meta"}, Aliases: [][]string{{"Labels"}}, Hidden: true},
+		Field{Path: []string{"Secrets"}, Hidden: true},
+		Field{Path: []string{"Extra", "Port"}, Type: reflect.TypeOf(0), Operators: []grammar.MatchOperator{grammar.MatchEqual}},
+	)
+
+	var listing []string
+	for _, field := range fields {
+		listing = append(listing, field.String())
+	}
+	require.Equal(t, []string{
+		"/Name: ==, !=",
+		"/Meta: contains, not contains, is empty, is not empty, is null, is not null, contains any, not contains any, contains all, not contains all",
+		"/Meta/<any>: ==, !=, contains, not contains, is empty, is not empty, matches, not matches, <, <=, >, >=, in, not in, " +
+			"like, not like, starts with, not starts with, ends with, not ends with, between, not between, ==i, !=i, " +
+			"in cidr, not in cidr, contains any, not contains any, contains all, not contains all",
+		"/Extra: ==, !=, contains, not contains, is empty, is not empty, matches, not matches, <, <=, >, >=, in, not in, " +
+			"like, not like, starts with, not starts with, ends with, not ends with, between, not between, ==i, !=i, " +
+			"is null, is not null, in cidr, not in cidr, contains any, not contains any, contains all, not contains all",
+		"/Extra/Port: ==",
+	}, listing)
+	require.True(t, fields[2].Hidden)
+	require.Equal(t, [][]string{{"Labels", FieldAny}}, fields[2].Aliases)
+	require.Nil(t, generated[1].Aliases)
+
+	value := service{
+		Name:  "web",
+		Meta:  map[string]string{"env": "prod"},
+		Extra: map[string]int{"Port": 80},
+	}
+
+	for expression, expected := range map[string]string{
+		"Name == web and Labels.env == prod and Extra.Port == 80": "",
+		"Labels contains env": "",
+		"Name matches web":    `match operator "Matches" is not supported for selector: "Name"`,
+		"Meta.env == prod":    `invalid selector "Meta.env": not a known field`,
+		"Secrets.key == x":    `invalid selector "Secrets.key": not a known field`,
+		"Extra.Port == http":  `error getting match value in expression: strconv.ParseInt: parsing "http": invalid syntax`,
+		"Lables.env == prod":  `invalid selector "Lables.env": not a known field, did you mean "Labels.env"?`,
+	} {
+		expr, err := CreateEvaluator(expression, WithFields(fields...))
+		if expected != "" {
+			require.EqualError(t, err, expected, expression)
+			continue
+		}
+		require.NoError(t, err, expression)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err)
+		require.True(t, result, expression)
+	}
+}
+
+func TestEvaluator_Bind(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"meta": map[string]string{"env": "prod", "node": "web-01"},
+		"port": 8080,
+	}
+
+	expr, err := CreateEvaluator("meta.env == $env and meta.node like $pattern and port in [$port, 443]")
+	require.NoError(t, err)
+
+	_, err = expr.Evaluate(value)
+	require.EqualError(t, err, "parameter $env is not bound")
+
+	partial, err := expr.Bind(map[string]interface{}{"env": "prod", "port": 8080})
+	require.NoError(t, err)
+	_, err = partial.Evaluate(value)
+	require.EqualError(t, err, "parameter $pattern is not bound")
+
+	bound, err := partial.Bind(map[string]interface{}{"pattern": "web-*"})
+	require.NoError(t, err)
+	match, err := bound.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// binding does not modify the original evaluator
+	other, err := expr.Bind(map[string]interface{}{"env": "dev", "pattern": "*", "port": 1})
+	require.NoError(t, err)
+	match, err = other.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, match)
+
+	match, err = bound.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// bound values are never parsed as expression syntax
+	injected, err := expr.Bind(map[string]interface{}{"env": "prod or port == 8080", "pattern": "*", "port": 1})
+	require.NoError(t, err)
+	match, err = injected.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, match)
+
+	_, err = expr.Bind(map[string]interface{}{"pattern": "web-[a"})
+	require.EqualError(t, err, `error binding value for selector "meta.node": Invalid glob pattern "web-[a": unclosed character class`)
+
+	// times and durations are bound in the forms expressions write them in
+	type event struct {
+		Created time.Time
+		Timeout time.Duration
+	}
+	created := time.Date(2023, 6, 15, 12, 30, 0, 500, time.FixedZone("EST", -5*60*60))
+	expr, err = CreateEvaluator("Created == $created and Timeout == $timeout and Created >= $after")
+	require.NoError(t, err)
+	bound, err = expr.Bind(map[string]interface{}{"created": created, "timeout": 90 * time.Minute, "after": &created})
+	require.NoError(t, err)
+	match, err = bound.Evaluate(event{Created: created.UTC(), Timeout: 90 * time.Minute})
+	require.NoError(t, err)
+	require.True(t, match)
+	match, err = bound.Evaluate(event{Created: created.Add(time.Nanosecond), Timeout: 90 * time.Minute})
+	require.NoError(t, err)
+	require.False(t, match)
+
+	// bound values are checked against the types of the fields
+	expr, err = CreateEvaluator("port == $port", WithFields(Field{Path: []string{"port"}, Type: reflect.TypeOf(0), Operators: []grammar.MatchOperator{grammar.MatchEqual}}))
+	require.NoError(t, err)
+	_, err = expr.Bind(map[string]interface{}{"port": "http"})
+	require.EqualError(t, err, `error getting match value in expression: strconv.ParseInt: parsing "http": invalid syntax`)
+	bound, err = expr.Bind(map[string]interface{}{"port": 8080})
+	require.NoError(t, err)
+	match, err = bound.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
 }
@@ -0,0 +1,75 @@
+package bexpr
+
+import (
+	"math/big"
+	"reflect"
+)
+
+// bigPrec is the precision in bits that values of expressions are parsed
+// with for comparison against big.Int and big.Float values
+const bigPrec = 1024
+
+var (
+	bigIntTyp   = reflect.TypeOf(big.Int{})
+	bigFloatTyp = reflect.TypeOf(big.Float{})
+)
+
+// bigValue is the form that big.Int and big.Float values are compared in.
+// Integers are converted exactly, while values of expressions compared
+// against floats are rounded to the precision of the float so that, for
+// example, a float holding 0.1 equals 0.1.
+type bigValue struct {
+	f     *big.Float
+	exact bool
+}
+
+var bigValueTyp = reflect.TypeOf(bigValue{})
+
+func isBigType(rtype reflect.Type) bool {
+	return rtype == bigIntTyp || rtype == bigFloatTyp
+}
+
+// bigMatchValue returns a big.Int or big.Float, or a non-nil pointer to
+// one, as a bigValue and any other value as is
+func bigMatchValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case *big.Int:
+		if v != nil {
+			return bigValue{f: new(big.Float).SetInt(v), exact: true}
+		}
+	case big.Int:
+		return bigValue{f: new(big.Float).SetInt(&v), exact: true}
+	case *big.Float:
+		if v != nil {
+			return bigValue{f: v}
+		}
+	case big.Float:
+		return bigValue{f: &v}
+	}
+	return val
+}
+
+func coerceBigValue(value string) (interface{}, error) {
+	f, _, err := big.ParseFloat(value, 0, bigPrec, big.ToNearestEven)
+	if err != nil {
+		return nil, err
+	}
+	return bigValue{f: f, exact: true}, nil
+}
+
+// cmp compares the value against that of an expression
+func (v bigValue) cmp(other bigValue) int {
+	expected := other.f
+	if !v.exact && v.f.Prec() > 0 {
+		expected = new(big.Float).SetPrec(v.f.Prec()).SetMode(v.f.Mode()).Set(expected)
+	}
+	return v.f.Cmp(expected)
+}
+
+func doEqualBig(first interface{}, second reflect.Value) bool {
+	return doCompareBig(first, second) == 0
+}
+
+func doCompareBig(first interface{}, second reflect.Value) int {
+	return second.Interface().(bigValue).cmp(first.(bigValue))
+}
@@ -0,0 +1,165 @@
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Bind returns a copy of the evaluator with the $name placeholders in the
+// expression replaced by the given values. The expression only has to be
+// parsed once and can then be bound to different user supplied values
+// without interpolating them into the expression text. Values are bound as
+// the literals they would be written as, with times in RFC 3339 form, and
+// are checked against the types of any fields given by WithFields.
+//
+// Placeholders without a value are left unbound so that they may be bound
+// by a later call, but an evaluator can only be evaluated once all of its
+// placeholders are bound. Named value sets are shared with the original
+// evaluator.
+func (eval *Evaluator) Bind(values map[string]interface{}) (*Evaluator, error) {
+	if eval.operands != nil {
+		return eval.mapOperands(func(operand *Evaluator) (*Evaluator, error) {
+			return operand.Bind(values)
+		})
+	}
+
+	ast, err := bindExpression(eval.ast, values)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := eval.opts
+	if len(opts.withFields) > 0 {
+		// the bound values are checked against the types of the fields as
+		// the values written in the expression were
+		if err := validateFieldTypes(ast, &opts); err != nil {
+			return nil, err
+		}
+	}
+	opts.listSets = newListSets(ast)
+
+	return &Evaluator{
+		ast:     ast,
+		opts:    opts,
+		unbound: unboundParameters(ast),
+	}, nil
+}
+
+// bindExpression copies the expression, substituting bound values for
+// placeholders. The original expression is never modified as it may be in
+// use by concurrent evaluations.
+func bindExpression(ast grammar.Expression, values map[string]interface{}) (grammar.Expression, error) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand, err := bindExpression(node.Operand, values)
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: operand}, nil
+
+	case *grammar.BinaryExpression:
+		left, err := bindExpression(node.Left, values)
+		if err != nil {
+			return nil, err
+		}
+		right, err := bindExpression(node.Right, values)
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.BinaryExpression{Operator: node.Operator, Left: left, Right: right}, nil
+
+	case *grammar.QuantifierExpression:
+		body, err := bindExpression(node.Expression, values)
+		if err != nil {
+			return nil, err
+		}
+		bound := *node
+		bound.Expression = body
+		return &bound, nil
+
+	case *grammar.FunctionExpression:
+		bound := &grammar.FunctionExpression{Name: node.Name, Args: make([]*grammar.FunctionArgument, len(node.Args))}
+		for i, arg := range node.Args {
+			bound.Args[i] = &grammar.FunctionArgument{Selector: arg.Selector, Value: bindValue(arg.Value, values)}
+		}
+		return bound, nil
+
+	case *grammar.MatchExpression:
+		bound := *node
+		bound.Value = bindValue(node.Value, values)
+		if node.Values != nil {
+			bound.Values = make([]*grammar.MatchValue, len(node.Values))
+			for i, value := range node.Values {
+				bound.Values[i] = bindValue(value, values)
+			}
+		}
+		if bound.Value != node.Value {
+			if err := bound.ConvertValue(); err != nil {
+				return nil, fmt.Errorf("error binding value for selector %q: %w", node.Selector, err)
+			}
+		}
+		return &bound, nil
+	}
+	return ast, nil
+}
+
+func bindValue(value *grammar.MatchValue, values map[string]interface{}) *grammar.MatchValue {
+	if value == nil || value.Parameter == "" {
+		return value
+	}
+	bound, ok := values[value.Parameter]
+	if !ok {
+		return value
+	}
+	return boundValue(bound)
+}
+
+// boundValue returns the bound value as the literal it would be written as,
+// which is quoted for strings so that they are compared as quoted values are
+func boundValue(value interface{}) *grammar.MatchValue {
+	return &grammar.MatchValue{Raw: boundLiteral(value), Quoted: reflect.ValueOf(value).Kind() == reflect.String}
+}
+
+// boundLiteral returns the bound value as it would be written in an
+// expression, so that it is coerced as the same value would be
+func boundLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *time.Time:
+		if v != nil {
+			return v.Format(time.RFC3339Nano)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// unboundParameters returns the names of all the placeholders in the
+// expression which have not been bound to a value
+func unboundParameters(ast grammar.Expression) []string {
+	var unbound []string
+	walkExpressions(ast, func(expr grammar.Expression) error {
+		switch node := expr.(type) {
+		case *grammar.FunctionExpression:
+			for _, arg := range node.Args {
+				if arg.Value != nil && arg.Value.Parameter != "" {
+					unbound = append(unbound, arg.Value.Parameter)
+				}
+			}
+		case *grammar.MatchExpression:
+			if node.Value != nil && node.Value.Parameter != "" {
+				unbound = append(unbound, node.Value.Parameter)
+			}
+			for _, value := range node.Values {
+				if value.Parameter != "" {
+					unbound = append(unbound, value.Parameter)
+				}
+			}
+		}
+		return nil
+	})
+	return unbound
+}
@@ -0,0 +1,42 @@
+package bexpr
+
+import (
+	"time"
+)
+
+// budget tracks the use of the evaluation limits by a single evaluation
+type budget struct {
+	steps    uint64
+	maxSteps uint64
+
+	deadline    time.Time
+	maxDuration time.Duration
+}
+
+func newBudget(opts *options) *budget {
+	if opts.withMaxEvaluationSteps == 0 && opts.withMaxEvalDuration == 0 {
+		return nil
+	}
+	b := &budget{maxSteps: opts.withMaxEvaluationSteps, maxDuration: opts.withMaxEvalDuration}
+	if b.maxDuration != 0 {
+		b.deadline = time.Now().Add(b.maxDuration)
+	}
+	return b
+}
+
+// step counts a step of the evaluation and returns an error once the
+// evaluation has exceeded its limits
+func step(opts *options) error {
+	b := opts.budget
+	if b == nil {
+		return nil
+	}
+	b.steps++
+	if b.maxSteps != 0 && b.steps > b.maxSteps {
+		return &BudgetExceededError{MaxSteps: b.maxSteps}
+	}
+	if b.maxDuration != 0 && time.Now().After(b.deadline) {
+		return &BudgetExceededError{MaxDuration: b.maxDuration}
+	}
+	return nil
+}
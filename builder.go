@@ -0,0 +1,260 @@
+package bexpr
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Expr is an expression constructed in code rather than parsed from a
+// string, such as:
+//
+//	Match("Status").Equals("passing").And(Match("Meta.env").In("prod", "staging"))
+//
+// Values are used as is so they never need quoting or escaping. Any error
+// made while building the expression, such as an invalid selector or
+// regular expression, is returned by CreateEvaluatorFromExpr.
+type Expr struct {
+	ast grammar.Expression
+	err error
+}
+
+// CreateEvaluatorFromExpr creates an Evaluator for the built expression.
+// It accepts the same options as CreateEvaluator, except for those that
+// only apply while parsing.
+func CreateEvaluatorFromExpr(expr Expr, opts ...Option) (*Evaluator, error) {
+	if expr.err != nil {
+		return nil, expr.err
+	}
+	if expr.ast == nil {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	return newEvaluator(expr.ast, getOpts(opts...))
+}
+
+// String returns the expression in the canonical expression syntax or the
+// empty string if there was an error building it
+func (e Expr) String() string {
+	stringer, ok := e.ast.(fmt.Stringer)
+	if e.err != nil || !ok {
+		return ""
+	}
+	return stringer.String()
+}
+
+// And returns an expression matching when this and all the others match
+func (e Expr) And(others ...Expr) Expr {
+	return e.binary(grammar.BinaryOpAnd, others)
+}
+
+// Or returns an expression matching when this or any of the others match
+func (e Expr) Or(others ...Expr) Expr {
+	return e.binary(grammar.BinaryOpOr, others)
+}
+
+// Not returns an expression matching when this one does not
+func (e Expr) Not() Expr {
+	if e.err != nil {
+		return e
+	}
+	if e.ast == nil {
+		return Expr{err: fmt.Errorf("operand of %q is empty", grammar.UnaryOpNot)}
+	}
+	return Expr{ast: &grammar.UnaryExpression{Operator: grammar.UnaryOpNot, Operand: e.ast}}
+}
+
+func (e Expr) binary(op grammar.BinaryOperator, others []Expr) Expr {
+	result := e
+	for _, other := range others {
+		if result.err != nil {
+			return result
+		}
+		if other.err != nil {
+			return other
+		}
+		if result.ast == nil || other.ast == nil {
+			return Expr{err: fmt.Errorf("operand of %q is empty", op)}
+		}
+		result = Expr{ast: &grammar.BinaryExpression{Left: result.ast, Operator: op, Right: other.ast}}
+	}
+	return result
+}
+
+// MatchBuilder creates the match expressions for a single selector
+type MatchBuilder struct {
+	selector grammar.Selector
+	err      error
+}
+
+// Match starts building a match expression for the selector, which may use
+// either the dotted bexpr syntax or the JSON Pointer syntax.
+func Match(selector string) *MatchBuilder {
+	sel, err := grammar.ParseSelector(selector)
+	if err != nil {
+		err = fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+	return &MatchBuilder{selector: sel, err: err}
+}
+
+// Equals matches when the selected value equals the value
+func (b *MatchBuilder) Equals(value interface{}) Expr {
+	return b.match(grammar.MatchEqual, value)
+}
+
+// NotEquals matches when the selected value does not equal the value
+func (b *MatchBuilder) NotEquals(value interface{}) Expr {
+	return b.match(grammar.MatchNotEqual, value)
+}
+
+// EqualsFold matches when the selected string equals the value ignoring case
+func (b *MatchBuilder) EqualsFold(value interface{}) Expr {
+	return b.match(grammar.MatchEqualFold, value)
+}
+
+// Contains matches when the selected collection or string contains the value
+func (b *MatchBuilder) Contains(value interface{}) Expr {
+	return b.match(grammar.MatchIn, value)
+}
+
+// NotContains matches when the selected collection or string does not
+// contain the value
+func (b *MatchBuilder) NotContains(value interface{}) Expr {
+	return b.match(grammar.MatchNotIn, value)
+}
+
+// In matches when the selected value is one of the values
+func (b *MatchBuilder) In(values ...interface{}) Expr {
+	return b.list(grammar.MatchInSet, values)
+}
+
+// NotIn matches when the selected value is none of the values
+func (b *MatchBuilder) NotIn(values ...interface{}) Expr {
+	return b.list(grammar.MatchNotInSet, values)
+}
+
+// ContainsAny matches when the selected collection contains any of the values
+func (b *MatchBuilder) ContainsAny(values ...interface{}) Expr {
+	return b.list(grammar.MatchContainsAny, values)
+}
+
+// ContainsAll matches when the selected collection contains all of the values
+func (b *MatchBuilder) ContainsAll(values ...interface{}) Expr {
+	return b.list(grammar.MatchContainsAll, values)
+}
+
+// IsEmpty matches when the selected value is empty
+func (b *MatchBuilder) IsEmpty() Expr {
+	return b.match(grammar.MatchIsEmpty, nil)
+}
+
+// IsNotEmpty matches when the selected value is not empty
+func (b *MatchBuilder) IsNotEmpty() Expr {
+	return b.match(grammar.MatchIsNotEmpty, nil)
+}
+
+// IsNull matches when the selected value is nil or missing
+func (b *MatchBuilder) IsNull() Expr {
+	return b.match(grammar.MatchIsNull, nil)
+}
+
+// IsNotNull matches when the selected value is neither nil nor missing
+func (b *MatchBuilder) IsNotNull() Expr {
+	return b.match(grammar.MatchIsNotNull, nil)
+}
+
+// Matches matches when the selected string matches the regular expression
+func (b *MatchBuilder) Matches(pattern string) Expr {
+	return b.match(grammar.MatchMatches, pattern)
+}
+
+// NotMatches matches when the selected string does not match the regular
+// expression
+func (b *MatchBuilder) NotMatches(pattern string) Expr {
+	return b.match(grammar.MatchNotMatches, pattern)
+}
+
+// Like matches when the selected string matches the glob pattern
+func (b *MatchBuilder) Like(pattern string) Expr {
+	return b.match(grammar.MatchLike, pattern)
+}
+
+// StartsWith matches when the selected string starts with the prefix
+func (b *MatchBuilder) StartsWith(prefix string) Expr {
+	return b.match(grammar.MatchPrefix, prefix)
+}
+
+// EndsWith matches when the selected string ends with the suffix
+func (b *MatchBuilder) EndsWith(suffix string) Expr {
+	return b.match(grammar.MatchSuffix, suffix)
+}
+
+// InCIDR matches when the selected IP address is within the network
+func (b *MatchBuilder) InCIDR(cidr string) Expr {
+	return b.match(grammar.MatchInCIDR, cidr)
+}
+
+// LessThan matches when the selected value is less than the value
+func (b *MatchBuilder) LessThan(value interface{}) Expr {
+	return b.match(grammar.MatchLessThan, value)
+}
+
+// LessThanOrEqual matches when the selected value is at most the value
+func (b *MatchBuilder) LessThanOrEqual(value interface{}) Expr {
+	return b.match(grammar.MatchLessThanOrEqual, value)
+}
+
+// GreaterThan matches when the selected value is greater than the value
+func (b *MatchBuilder) GreaterThan(value interface{}) Expr {
+	return b.match(grammar.MatchGreaterThan, value)
+}
+
+// GreaterThanOrEqual matches when the selected value is at least the value
+func (b *MatchBuilder) GreaterThanOrEqual(value interface{}) Expr {
+	return b.match(grammar.MatchGreaterThanOrEqual, value)
+}
+
+// Between matches when the selected value is within the inclusive bounds
+func (b *MatchBuilder) Between(low, high interface{}) Expr {
+	return b.list(grammar.MatchBetween, []interface{}{low, high})
+}
+
+// Operator matches using an operator registered with RegisterOperator
+func (b *MatchBuilder) Operator(op grammar.MatchOperator, value interface{}) Expr {
+	return b.match(op, value)
+}
+
+func (b *MatchBuilder) match(op grammar.MatchOperator, value interface{}) Expr {
+	if b.err != nil {
+		return Expr{err: b.err}
+	}
+
+	node := &grammar.MatchExpression{Selector: b.selector, Operator: op}
+	switch op {
+	case grammar.MatchIsEmpty, grammar.MatchIsNotEmpty, grammar.MatchIsNull, grammar.MatchIsNotNull:
+	default:
+		if value == nil {
+			return Expr{err: fmt.Errorf("match operator %q requires a value for selector %q", op, b.selector)}
+		}
+		node.Value = boundValue(value)
+	}
+	if err := node.ConvertValue(); err != nil {
+		return Expr{err: fmt.Errorf("invalid value for selector %q: %w", b.selector, err)}
+	}
+	return Expr{ast: node}
+}
+
+func (b *MatchBuilder) list(op grammar.MatchOperator, values []interface{}) Expr {
+	if b.err != nil {
+		return Expr{err: b.err}
+	}
+
+	// a nil list would refer to a named value set
+	node := &grammar.MatchExpression{Selector: b.selector, Operator: op, Values: make([]*grammar.MatchValue, 0, len(values))}
+	for _, value := range values {
+		if value == nil {
+			return Expr{err: fmt.Errorf("match operator %q requires non-nil values for selector %q", op, b.selector)}
+		}
+		node.Values = append(node.Values, boundValue(value))
+	}
+	return Expr{ast: node}
+}
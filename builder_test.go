@@ -0,0 +1,93 @@
+package bexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEvaluatorFromExpr(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expr   Expr
+		result bool
+		err    string
+	}
+
+	value := map[string]interface{}{
+		"Status": "passing",
+		"Port":   8080,
+		"Node":   `web "01"`,
+		"Tags":   []string{"primary", "v2"},
+		"Meta":   map[string]string{"env": "prod"},
+		"Start":  time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+	}
+
+	tests := map[string]testCase{
+		"Equals":             {expr: Match("Status").Equals("passing"), result: true},
+		"Quoting":            {expr: Match("Node").Equals(`web "01"`), result: true},
+		"JSON Pointer":       {expr: Match(`"/Meta/env"`).In("prod", "staging"), result: true},
+		"Not In":             {expr: Match("Meta.env").NotIn("prod", "staging"), result: false},
+		"Empty In":           {expr: Match("Meta.env").In(), result: false},
+		"Numbers":            {expr: Match("Port").Between(1024, 9000).And(Match("Port").NotEquals(80)), result: true},
+		"Contains":           {expr: Match("Tags").Contains("v2").And(Match("Tags").ContainsAll("v2", "primary")), result: true},
+		"Or":                 {expr: Match("Status").Equals("critical").Or(Match("Port").GreaterThan(8000)), result: true},
+		"Not":                {expr: Match("Status").Matches("^pass").Not(), result: false},
+		"Empty":              {expr: Match("Meta.missing").IsNull().And(Match("Tags").IsNotEmpty()), result: true},
+		"Invalid Selector":   {expr: Match("Meta.").Equals("x"), err: `invalid selector "Meta.": 1:6 (5): no match found, expected: "*", "\"", "` + "`" + `", [0-9] or [a-zA-Z]`},
+		"Invalid Pattern":    {expr: Match("Status").Equals("x").Or(Match("Node").Matches("[a-")), err: "invalid value for selector \"Node\": Invalid regular expression \"[a-\": error parsing regexp: missing closing ]: `[a-`"},
+		"Invalid Within":     {expr: Match("Status").Like("web-[a").Not().And(Match("Port").Equals(1)), err: `invalid value for selector "Status": Invalid glob pattern "web-[a": unclosed character class`},
+		"Time":               {expr: Match("Start").Equals(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)), result: true},
+		"Time Bounds":        {expr: Match("Start").Between(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)), result: true},
+		"Nil Value":          {expr: Match("Status").Equals(nil), err: `match operator "Equal" requires a value for selector "Status"`},
+		"Nil List Value":     {expr: Match("Status").In("passing", nil), err: `match operator "In Set" requires non-nil values for selector "Status"`},
+		"Uninitialized Expr": {expr: Expr{}, err: "expression is empty"},
+		"Empty Operand":      {expr: Expr{}.And(Match("Status").Equals("passing")), err: `operand of "And" is empty`},
+		"Empty Right":        {expr: Match("Status").Equals("passing").Or(Expr{}), err: `operand of "Or" is empty`},
+		"Empty Not":          {expr: Expr{}.Not(), err: `operand of "Not" is empty`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluatorFromExpr(tcase.expr)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+
+			match, err := eval.Evaluate(value)
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+}
+
+func TestCreateEvaluatorFromExpr_Options(t *testing.T) {
+	t.Parallel()
+
+	_, err := CreateEvaluatorFromExpr(Match("Status").Matches(".*"), WithDeniedOperators(grammar.MatchMatches))
+	require.EqualError(t, err, `match operator "Matches" is not allowed for selector: "Status"`)
+}
+
+func TestExpr_String(t *testing.T) {
+	t.Parallel()
+
+	expr := Match("Meta.env").In("prod", "a b").Or(Match("Node").Equals(`web "01"`).And(Match("Port").LessThan(80).Not()))
+	require.Equal(t, `Meta.env in ["prod", "a b"] or Node == "web \"01\"" and not Port < 80`, expr.String())
+
+	eval, err := CreateEvaluator(expr.String())
+	require.NoError(t, err)
+	require.Equal(t, expr.String(), eval.String())
+
+	require.Equal(t, "", Match("Meta.").Equals(1).String())
+	require.Equal(t, "", Expr{}.String())
+	require.Equal(t, "", new(Evaluator).String())
+}
@@ -1,22 +1,225 @@
 package bexpr
 
 import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// FieldValueCoercionFn converts the raw string value of an expression into
+// the value that the selected values are compared against
+type FieldValueCoercionFn func(value string) (interface{}, error)
+
+// coercions holds the FieldValueCoercionFn registered for each type
+var coercions sync.Map
+
+// RegisterCoercion sets the function converting the values of expressions
+// which are compared against values of the type, in place of the one used
+// for its kind. This gives named types such as IDs, enums and addresses
+// their own syntax, for example to compare a Level int against: warn
+//
+// The function may return a value of the type or of any other type of the
+// same kind. Only types of the kinds that expressions compare, booleans,
+// numbers and strings, can have a coercion. Coercions should be registered
+// before any expressions are evaluated, such as from an init function.
+func RegisterCoercion(rtype reflect.Type, fn FieldValueCoercionFn) error {
+	if rtype == nil || fn == nil {
+		return fmt.Errorf("a coercion requires a type and a function")
+	}
+	if _, _, ok := primitiveValue(reflect.Zero(rtype)); !ok {
+		return fmt.Errorf("cannot register a coercion for type %s of kind %s", rtype, rtype.Kind())
+	}
+	coercions.Store(rtype, fn)
+	return nil
+}
+
+func getCoercion(rtype reflect.Type) (FieldValueCoercionFn, bool) {
+	switch rtype {
+	case timeValueTyp:
+		return coerceTimeValue, true
+	case uuidValueTyp:
+		return coerceUUIDValue, true
+	case bigValueTyp:
+		return coerceBigValue, true
+	case durationTyp:
+		return coerceDurationValue, true
+	}
+	fn, ok := coercions.Load(rtype)
+	if !ok {
+		if isTextType(rtype) {
+			return textCoercion(rtype), true
+		}
+		return nil, false
+	}
+	return fn.(FieldValueCoercionFn), true
+}
+
+var textUnmarshalerTyp = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isTextType reports whether values of expressions are unmarshaled into
+// values of the type with its UnmarshalText method, for types without a
+// registered coercion. IP addresses are matched as such instead, and
+// values implementing Comparer compare themselves.
+func isTextType(rtype reflect.Type) bool {
+	switch rtype.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return false
+	}
+	return rtype != ipTyp && !isComparerType(rtype) && reflect.PtrTo(rtype).Implements(textUnmarshalerTyp)
+}
+
+// textCoercion returns the coercion that unmarshals values of expressions
+// into values of the type. Values that the type rejects are converted as
+// for its kind if it is a boolean, number or string, so that enums can be
+// compared against both their names and their values.
+func textCoercion(rtype reflect.Type) FieldValueCoercionFn {
+	return func(value string) (interface{}, error) {
+		ptr := reflect.New(rtype)
+		err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+		if err == nil {
+			return ptr.Elem().Interface(), nil
+		}
+		if _, _, ok := primitiveValue(ptr.Elem()); ok {
+			if coerced, kindErr := getMatchValue(value, rtype.Kind()); kindErr == nil {
+				return coerced, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// textEqual reports whether the selected value of a type for which
+// isTextType holds equals the unmarshaled value of an expression
+func textEqual(first interface{}, second reflect.Value) bool {
+	if second.Type().Comparable() {
+		return first == second.Interface()
+	}
+	return reflect.DeepEqual(first, second.Interface())
+}
+
+// coerceValue converts the raw value of an expression for comparison
+// against values of the type, using the coercion registered for the type if
+// there is one, by unmarshaling it for types implementing
+// encoding.TextUnmarshaler and otherwise the one for its kind
+func coerceValue(raw string, rtype reflect.Type) (interface{}, error) {
+	fn, ok := getCoercion(rtype)
+	if !ok {
+		return getMatchValue(raw, rtype.Kind())
+	}
+
+	coerced, err := fn(raw)
+	if err != nil {
+		return nil, err
+	}
+	want, _, ok := primitiveValue(reflect.Zero(rtype))
+	if !ok {
+		// values of types which unmarshal themselves are compared whole
+		return coerced, nil
+	}
+	kind, value, ok := primitiveValue(reflect.ValueOf(coerced))
+	if !ok || kind != want {
+		return nil, fmt.Errorf("coercion for type %s returned a value of type %T", rtype, coerced)
+	}
+	return value, nil
+}
+
+// coerceMatchValue converts the raw value of an expression as coerceValue
+// does, except that numbers which values of integer kinds cannot hold, such
+// as 1.5, 1e30 or -1 for unsigned integers, are returned as float64. These
+// equal no integer and are ordered against integers by their exact values,
+// rather than failing the evaluation.
+func coerceMatchValue(raw string, rtype reflect.Type) (interface{}, error) {
+	value, err := coerceValue(raw, rtype)
+	if err == nil {
+		return value, nil
+	}
+	if _, ok := getCoercion(rtype); ok {
+		return nil, err
+	}
+	switch rtype.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// literals beyond the range of float64 are infinite
+		f, ferr := strconv.ParseFloat(raw, 64)
+		if (ferr == nil || errors.Is(ferr, strconv.ErrRange)) && !math.IsNaN(f) {
+			return f, nil
+		}
+	}
+	return nil, err
+}
+
+// primitiveValue returns the value as the type that values of its kind are
+// compared as, such as int64 for every signed integer, along with the kind
+// of that type. It returns false for values of the other kinds.
+func primitiveValue(value reflect.Value) (reflect.Kind, interface{}, bool) {
+	switch value.Kind() {
+	case reflect.Bool:
+		return reflect.Bool, value.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int64, value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Uint64, value.Uint(), true
+	case reflect.Float32:
+		return reflect.Float32, float32(value.Float()), true
+	case reflect.Float64:
+		return reflect.Float64, value.Float(), true
+	case reflect.String:
+		return reflect.String, value.String(), true
+	default:
+		return reflect.Invalid, nil, false
+	}
+}
+
 // CoerceInt64 conforms to the FieldValueCoercionFn signature
 // and can be used to convert the raw string value of
 // an expression into an `int64`
 func CoerceInt64(value string) (interface{}, error) {
 	i, err := strconv.ParseInt(value, 0, 64)
+	if err != nil {
+		// allow integral values written in scientific notation such as 1e3
+		if f, ok := parseIntegralFloat(value); ok && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f), nil
+		}
+	}
 	return int64(i), err
 }
 
+// CoerceDuration conforms to the FieldValueCoercionFn signature
+// and can be used to convert the raw string value of
+// an expression into a `time.Duration`
+func CoerceDuration(value string) (interface{}, error) {
+	return time.ParseDuration(value)
+}
+
+// coerceDurationValue converts values of expressions compared against
+// durations, and integers tagged as durations, which may be written either
+// as durations such as 5m or as integer nanoseconds
+func coerceDurationValue(value string) (interface{}, error) {
+	d, err := CoerceDuration(value)
+	if err != nil {
+		if i, ierr := CoerceInt64(value); ierr == nil {
+			return time.Duration(i.(int64)), nil
+		}
+	}
+	return d, err
+}
+
 // CoerceUint64 conforms to the FieldValueCoercionFn signature
 // and can be used to convert the raw string value of
 // an expression into an `int64`
 func CoerceUint64(value string) (interface{}, error) {
 	i, err := strconv.ParseUint(value, 0, 64)
+	if err != nil {
+		if f, ok := parseIntegralFloat(value); ok && f >= 0 && f < math.MaxUint64 {
+			return uint64(f), nil
+		}
+	}
 	return uint64(i), err
 }
 
@@ -35,6 +238,12 @@ func CoerceFloat32(value string) (interface{}, error) {
 	// it can be converted to a float32 without changing
 	// its value
 	f, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		// hex and octal integers are valid float values too
+		if i, ierr := strconv.ParseInt(value, 0, 64); ierr == nil {
+			return float32(i), nil
+		}
+	}
 	return float32(f), err
 }
 
@@ -42,5 +251,120 @@ func CoerceFloat32(value string) (interface{}, error) {
 // and can be used to convert the raw string value of
 // an expression into an `float64`
 func CoerceFloat64(value string) (interface{}, error) {
-	return strconv.ParseFloat(value, 64)
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		if i, ierr := strconv.ParseInt(value, 0, 64); ierr == nil {
+			return float64(i), nil
+		}
+	}
+	return f, err
+}
+
+// parseIntegralFloat parses a float such as 1.5e6 that has no fractional
+// part so that it can be used as an integer value
+func parseIntegralFloat(value string) (float64, bool) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f != math.Trunc(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// CoerceTime conforms to the FieldValueCoercionFn signature
+// and can be used to convert the raw string value of
+// an expression into a `time.Time`. Values may be RFC3339
+// timestamps, dates, seconds since the Unix epoch or times
+// relative to now such as now-1h
+func CoerceTime(value string) (interface{}, error) {
+	if strings.HasPrefix(value, "now") {
+		now := time.Now()
+		if value == "now" {
+			return now, nil
+		}
+		offset, err := time.ParseDuration(strings.TrimPrefix(value[len("now"):], "+"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative time %q: %w", value, err)
+		}
+		return now.Add(offset), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// wellKnownTimestamp and wellKnownDuration are implemented by the messages
+// generated for the well-known google.protobuf.Timestamp and
+// google.protobuf.Duration types
+type wellKnownTimestamp interface {
+	AsTime() time.Time
+	CheckValid() error
+}
+
+type wellKnownDuration interface {
+	AsDuration() time.Duration
+	CheckValid() error
+}
+
+// wellKnownValue returns a protobuf Timestamp or Duration message as the
+// time.Time or time.Duration that it holds, so that it compares against the
+// values of expressions as times and durations do, and any other value as is
+func wellKnownValue(val interface{}) interface{} {
+	if rvalue := reflect.ValueOf(val); rvalue.Kind() == reflect.Ptr && rvalue.IsNil() {
+		return val
+	}
+	switch v := val.(type) {
+	case wellKnownTimestamp:
+		return v.AsTime()
+	case wellKnownDuration:
+		return v.AsDuration()
+	}
+	return val
+}
+
+// timeValue is the form that time.Time values are compared in, as the
+// seconds since the Unix epoch and the nanoseconds within the second, which
+// hold any time exactly regardless of its location. Values of the
+// expression are coerced by CoerceTime.
+type timeValue struct {
+	sec  int64
+	nsec int64
+}
+
+var timeValueTyp = reflect.TypeOf(timeValue{})
+
+func newTimeValue(t time.Time) timeValue {
+	return timeValue{sec: t.Unix(), nsec: int64(t.Nanosecond())}
+}
+
+func coerceTimeValue(value string) (interface{}, error) {
+	t, err := CoerceTime(value)
+	if err != nil {
+		return nil, err
+	}
+	return newTimeValue(t.(time.Time)), nil
+}
+
+// cmp compares the value against that of an expression
+func (v timeValue) cmp(other timeValue) int {
+	switch {
+	case v.sec < other.sec || (v.sec == other.sec && v.nsec < other.nsec):
+		return -1
+	case v == other:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func doEqualTime(first interface{}, second reflect.Value) bool {
+	return second.Interface().(timeValue) == first.(timeValue)
+}
+
+func doCompareTime(first interface{}, second reflect.Value) int {
+	return second.Interface().(timeValue).cmp(first.(timeValue))
 }
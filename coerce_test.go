@@ -0,0 +1,119 @@
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerceTime(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		value    string
+		expected time.Time
+		err      string
+	}
+
+	tests := map[string]testCase{
+		"RFC3339":      {value: "2023-01-01T00:00:00Z", expected: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"Fractional":   {value: "2023-01-01T10:00:00.5+02:00", expected: time.Date(2023, 1, 1, 8, 0, 0, 500000000, time.UTC)},
+		"Date":         {value: "2023-06-15", expected: time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)},
+		"Unix":         {value: "1672531200", expected: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"Invalid":      {value: "yesterday", err: `parsing time "yesterday" as "2006-01-02": cannot parse "yesterday" as "2006"`},
+		"Bad Relative": {value: "now-1x", err: `invalid relative time "now-1x": time: unknown unit "x" in duration "-1x"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := CoerceTime(tcase.value)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tcase.expected.Equal(value.(time.Time)), "expected %v got %v", tcase.expected, value)
+		})
+	}
+
+	before := time.Now()
+	value, err := CoerceTime("now-1h")
+	require.NoError(t, err)
+	require.WithinDuration(t, before.Add(-time.Hour), value.(time.Time), time.Minute)
+
+	value, err = CoerceTime("now+1h30m")
+	require.NoError(t, err)
+	require.True(t, value.(time.Time).After(before.Add(time.Hour)))
+}
+
+type testLevel int
+
+const (
+	testLevelDebug testLevel = iota
+	testLevelInfo
+	testLevelWarn
+)
+
+func coerceTestLevel(value string) (interface{}, error) {
+	for level, name := range []string{"debug", "info", "warn"} {
+		if value == name {
+			return testLevel(level), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown level %q", value)
+}
+
+func TestRegisterCoercion(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, RegisterCoercion(reflect.TypeOf(testLevelDebug), coerceTestLevel))
+	require.EqualError(t, RegisterCoercion(reflect.TypeOf(struct{}{}), coerceTestLevel), "cannot register a coercion for type struct {} of kind struct")
+	require.EqualError(t, RegisterCoercion(reflect.TypeOf(0), nil), "a coercion requires a type and a function")
+
+	type entry struct {
+		Level  testLevel
+		Levels []testLevel
+		Count  int
+	}
+	value := entry{Level: testLevelWarn, Levels: []testLevel{testLevelInfo}, Count: 2}
+
+	tests := map[string]struct {
+		expression string
+		result     bool
+		err        string
+	}{
+		"Equal":         {expression: "Level == warn", result: true},
+		"Ordered":       {expression: "Level > info", result: true},
+		"Between":       {expression: "Level between debug and info", result: false},
+		"Contains":      {expression: "Levels contains info", result: true},
+		"In List":       {expression: "Level in [info, warn]", result: true},
+		"Other Types":   {expression: "Count == 2", result: true},
+		"Invalid Value": {expression: "Level == fatal", err: `error getting match value in expression: unknown level "fatal"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+			result, err := expr.Evaluate(value)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+
+	require.Empty(t, Validate("Level >= info", entry{}))
+	require.Len(t, Validate("Level >= trace", entry{}), 1)
+}
@@ -0,0 +1,167 @@
+package bexpr
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// And combines the evaluators into one which matches when all of them match,
+// such as to apply a mandatory filter on top of one supplied by a user
+// without joining the expression strings.
+//
+// Each evaluator's expression is evaluated with the options it was created
+// with, so the functions, default values, case insensitive selectors and
+// other options of one never apply to the expressions of the others. The
+// combined evaluation is limited by the smallest evaluation step and
+// duration budgets of the evaluators, while FilterSlice and FilterMap use
+// the parallelism and map key selector of the first one. The named value
+// sets are copied so changing the sets of the original evaluators does not
+// affect the combined one and vice versa. Changing a set of the combined
+// evaluator changes it for each of the evaluators it was combined from.
+func And(first *Evaluator, rest ...*Evaluator) *Evaluator {
+	return combine(grammar.BinaryOpAnd, first, rest)
+}
+
+// Or combines the evaluators into one which matches when any of them match.
+// The options apply in the same way as for And.
+func Or(first *Evaluator, rest ...*Evaluator) *Evaluator {
+	return combine(grammar.BinaryOpOr, first, rest)
+}
+
+// Not returns an evaluator which matches when the given one does not
+func Not(eval *Evaluator) *Evaluator {
+	operand := eval.copy()
+	return newCombinedEvaluator(&grammar.UnaryExpression{Operator: grammar.UnaryOpNot, Operand: operand.ast}, []*Evaluator{operand})
+}
+
+func combine(op grammar.BinaryOperator, first *Evaluator, rest []*Evaluator) *Evaluator {
+	if len(rest) == 0 {
+		return first.copy()
+	}
+
+	evals := make([]*Evaluator, 0, len(rest)+1)
+	for _, eval := range append([]*Evaluator{first}, rest...) {
+		evals = append(evals, eval.copy())
+	}
+
+	// each copy has its own expression, by which its scope is found
+	ast := evals[len(evals)-1].ast
+	for i := len(evals) - 2; i >= 0; i-- {
+		ast = &grammar.BinaryExpression{Left: evals[i].ast, Operator: op, Right: ast}
+	}
+	return newCombinedEvaluator(ast, evals)
+}
+
+func newCombinedEvaluator(ast grammar.Expression, evals []*Evaluator) *Evaluator {
+	opts := getDefaultOptions()
+	opts.withParallelism = evals[0].opts.withParallelism
+	opts.withMapKeySelector = evals[0].opts.withMapKeySelector
+	opts.valueSets = new(valueSets)
+	opts.scopes = make(map[grammar.Expression]*Evaluator, len(evals))
+	for _, eval := range evals {
+		opts.withMaxEvaluationSteps = minLimit(opts.withMaxEvaluationSteps, eval.opts.withMaxEvaluationSteps)
+		opts.withMaxEvalDuration = time.Duration(minLimit(uint64(opts.withMaxEvalDuration), uint64(eval.opts.withMaxEvalDuration)))
+		opts.scopes[eval.ast] = eval
+	}
+
+	return &Evaluator{
+		ast:      ast,
+		opts:     opts,
+		unbound:  unboundParameters(ast),
+		operands: evals,
+	}
+}
+
+// minLimit returns the smaller of two limits, where zero is no limit
+func minLimit(a, b uint64) uint64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// copy returns a copy of the evaluator with its own copies of the named
+// value sets, which combined evaluators are made from. The expression is
+// copied as well so that the operands of a combined evaluator are told
+// apart by their expressions even when they were created from the same one.
+func (eval *Evaluator) copy() *Evaluator {
+	if eval.operands != nil {
+		return eval.recombine(eval.operands)
+	}
+	copied := *eval
+	copied.ast = rewriteSelectors(eval.ast, func(sel grammar.Selector) grammar.Selector { return sel }, nil)
+	copied.opts.valueSets = eval.opts.valueSets.copy()
+	copied.opts.listSets = newListSets(copied.ast)
+	return &copied
+}
+
+// recombine returns the combination of the operands in the same way as the
+// combined evaluator, folding operands which have become constants such as
+// by PartialEvaluate
+func (eval *Evaluator) recombine(operands []*Evaluator) *Evaluator {
+	root, ok := eval.ast.(*grammar.BinaryExpression)
+	if !ok {
+		// combined by Not
+		if constant, ok := operands[0].ast.(*grammar.ConstantExpression); ok {
+			negated := operands[0].copy()
+			negated.ast = &grammar.ConstantExpression{Value: !constant.Value}
+			return negated
+		}
+		return Not(operands[0])
+	}
+
+	// true operands of and and false operands of or do not change the
+	// result while false and true ones decide it
+	decisive := root.Operator == grammar.BinaryOpOr
+	var kept []*Evaluator
+	for _, operand := range operands {
+		constant, ok := operand.ast.(*grammar.ConstantExpression)
+		if !ok {
+			kept = append(kept, operand)
+			continue
+		}
+		if constant.Value == decisive {
+			return operand.copy()
+		}
+	}
+	switch len(kept) {
+	case 0:
+		return operands[0].copy()
+	case 1:
+		return kept[0].copy()
+	}
+	return combine(root.Operator, kept[0], kept[1:])
+}
+
+// mapOperands applies fn to each operand of the combined evaluator and
+// combines the results in the same way
+func (eval *Evaluator) mapOperands(fn func(*Evaluator) (*Evaluator, error)) (*Evaluator, error) {
+	operands := make([]*Evaluator, len(eval.operands))
+	for i, operand := range eval.operands {
+		mapped, err := fn(operand)
+		if err != nil {
+			return nil, err
+		}
+		operands[i] = mapped
+	}
+	return eval.recombine(operands), nil
+}
+
+// scope returns the options to evaluate the expression with. The operands
+// of combined evaluators are evaluated with the options of the evaluators
+// they came from, sharing the state of the evaluation in progress.
+func scope(ast grammar.Expression, opts *options) *options {
+	if opts.scopes == nil {
+		return opts
+	}
+	operand, ok := opts.scopes[ast]
+	if !ok {
+		return opts
+	}
+	scoped := operand.opts
+	scoped.ctx = opts.ctx
+	scoped.budget = opts.budget
+	scoped.unknown = opts.unknown
+	return &scoped
+}
@@ -0,0 +1,118 @@
+package bexpr
+
+import (
+	"reflect"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Comparer is implemented by values which compare themselves against the
+// values of expressions, such as decimal types. Implementing it opts the
+// type into the equality and ordering operators, along with between and
+// in for lists and value sets, without bexpr depending on the package
+// defining the type. A shopspring/decimal field could be wrapped as:
+//
+//	type Amount struct{ decimal.Decimal }
+//
+//	func (a Amount) CompareValue(value string) (int, error) {
+//		d, err := decimal.NewFromString(value)
+//		if err != nil {
+//			return 0, err
+//		}
+//		return a.Cmp(d), nil
+//	}
+//
+// CompareValue returns a negative number, zero or a positive number when
+// the value is less than, equal to or greater than the raw value of the
+// expression. Its errors are returned as a CoercionError. Nil pointers are
+// null rather than compared.
+type Comparer interface {
+	CompareValue(value string) (int, error)
+}
+
+var comparerTyp = reflect.TypeOf((*Comparer)(nil)).Elem()
+
+// isComparerType reports whether values of the type, or pointers to them,
+// implement Comparer
+func isComparerType(rtype reflect.Type) bool {
+	return rtype.Implements(comparerTyp) || (rtype.Kind() != reflect.Ptr && reflect.PtrTo(rtype).Implements(comparerTyp))
+}
+
+// asComparer returns the value as a Comparer if it or a pointer to it is
+// one, other than nil pointers
+func asComparer(val interface{}) (Comparer, bool) {
+	rvalue := reflect.ValueOf(val)
+	if !rvalue.IsValid() || !isComparerType(rvalue.Type()) {
+		return nil, false
+	}
+	if rvalue.Kind() == reflect.Ptr && rvalue.IsNil() {
+		return nil, false
+	}
+	if comparer, ok := val.(Comparer); ok {
+		return comparer, true
+	}
+
+	// a copy is addressable, so that CompareValue can have a pointer
+	// receiver
+	ptr := reflect.New(rvalue.Type())
+	ptr.Elem().Set(rvalue)
+	return ptr.Interface().(Comparer), true
+}
+
+func doMatchComparer(expression *grammar.MatchExpression, comparer Comparer, opts *options) (bool, error) {
+	compare := func(raw string) (int, error) {
+		result, err := comparer.CompareValue(raw)
+		if err != nil {
+			return 0, coercionError(expression, raw, err)
+		}
+		return result, nil
+	}
+
+	switch expression.Operator {
+	case grammar.MatchEqual, grammar.MatchNotEqual:
+		result, err := compare(expression.Value.Raw)
+		return err == nil && (result == 0) == (expression.Operator == grammar.MatchEqual), err
+	case grammar.MatchLessThan:
+		result, err := compare(expression.Value.Raw)
+		return err == nil && result < 0, err
+	case grammar.MatchLessThanOrEqual:
+		result, err := compare(expression.Value.Raw)
+		return err == nil && result <= 0, err
+	case grammar.MatchGreaterThan:
+		result, err := compare(expression.Value.Raw)
+		return err == nil && result > 0, err
+	case grammar.MatchGreaterThanOrEqual:
+		result, err := compare(expression.Value.Raw)
+		return err == nil && result >= 0, err
+	case grammar.MatchBetween, grammar.MatchNotBetween:
+		low, err := compare(expression.Values[0].Raw)
+		if err != nil {
+			return false, err
+		}
+		high, err := compare(expression.Values[1].Raw)
+		if err != nil {
+			return false, err
+		}
+		return (low >= 0 && high <= 0) == (expression.Operator == grammar.MatchBetween), nil
+	case grammar.MatchInSet, grammar.MatchNotInSet:
+		set, err := matchSet(expression, opts)
+		if err != nil {
+			return false, err
+		}
+		// values of the set that cannot be compared are not members
+		found := false
+		for _, raw := range set.values {
+			if result, err := comparer.CompareValue(raw); err == nil && result == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (expression.Operator == grammar.MatchInSet), nil
+	case grammar.MatchIsNull:
+		return false, nil
+	case grammar.MatchIsNotNull:
+		return true, nil
+	default:
+		return false, operatorError(expression, "Cannot perform %s operations on type %T", expression.Operator.Symbol(), comparer)
+	}
+}
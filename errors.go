@@ -0,0 +1,117 @@
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// ErrExpressionTooLong is wrapped by the errors of CreateEvaluator and
+// CreateEvaluatorFromReader for expressions longer than the maximum set by
+// WithMaxExpressionLength, such as to respond with a distinct status.
+var ErrExpressionTooLong = errors.New("expression exceeds the maximum length")
+
+// ErrNilValue is wrapped by the errors of evaluations whose selectors pass
+// through a nil pointer, map or interface to reach the values within it,
+// unless other options such as WithNilTraversal give the match a result.
+var ErrNilValue = errors.New("nil value cannot be traversed")
+
+// Diagnostics is the error returned by CreateEvaluator when error recovery
+// is enabled with WithErrorRecovery and the expression contains syntax
+// errors. It holds every error found, ordered by position.
+type Diagnostics []error
+
+func (d Diagnostics) Error() string {
+	msgs := make([]string, 0, len(d))
+	for _, err := range d {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// As lets errors.As find the first of the errors matching target, such as
+// the *SyntaxError of the first syntax error.
+func (d Diagnostics) As(target interface{}) bool {
+	for _, err := range d {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// The errors of CreateEvaluator, Validate and Evaluate are returned as, or
+// wrap, one of the following types where they fall into one of their
+// categories, so that callers can tell them apart with errors.As, such as to
+// respond with different statuses. Their messages are unchanged by this and
+// the error they describe is available with errors.Unwrap.
+
+// SyntaxError is an expression that could not be parsed, with the position
+// of the error within it
+type SyntaxError = grammar.ParseError
+
+// UnknownSelectorError is a selector referring to a value that does not
+// exist, such as a struct field the type does not have or, when evaluating,
+// a map key missing from the datum.
+type UnknownSelectorError struct {
+	Selector grammar.Selector
+	Err      error
+}
+
+func (e *UnknownSelectorError) Error() string { return e.Err.Error() }
+func (e *UnknownSelectorError) Unwrap() error { return e.Err }
+
+// UnsupportedOperatorError is a match operator that cannot be applied to the
+// value of its selector, either because of the type of the value or because
+// the operator is denied by the options or the tags of a struct field.
+// Selector is empty for the operators of comparisons.
+type UnsupportedOperatorError struct {
+	Operator grammar.MatchOperator
+	Selector grammar.Selector
+	Err      error
+}
+
+func (e *UnsupportedOperatorError) Error() string { return e.Err.Error() }
+func (e *UnsupportedOperatorError) Unwrap() error { return e.Err }
+
+// CoercionError is a value within the expression that cannot be converted to
+// the type of the value of its selector, such as a word compared to an int.
+type CoercionError struct {
+	Selector grammar.Selector
+	Value    string
+	Err      error
+}
+
+func (e *CoercionError) Error() string { return e.Err.Error() }
+func (e *CoercionError) Unwrap() error { return e.Err }
+
+// LimitError is an expression exceeding one of the limits set by the
+// options, such as WithMaxExpressionLength or WithMaxSelectorDepth. Max is
+// the limit exceeded and Selector is set for the limits on selectors.
+// Evaluations exceeding their limits fail with a *BudgetExceededError.
+type LimitError struct {
+	Max      uint64
+	Selector grammar.Selector
+	Err      error
+}
+
+func (e *LimitError) Error() string { return e.Err.Error() }
+func (e *LimitError) Unwrap() error { return e.Err }
+
+// BudgetExceededError is returned by evaluations that exceed the limits set
+// with WithMaxEvaluationSteps or WithMaxEvaluationDuration. Only the limit
+// which was exceeded is set.
+type BudgetExceededError struct {
+	MaxSteps    uint64
+	MaxDuration time.Duration
+}
+
+func (e *BudgetExceededError) Error() string {
+	if e.MaxSteps != 0 {
+		return fmt.Sprintf("evaluation exceeded the maximum of %d steps", e.MaxSteps)
+	}
+	return fmt.Sprintf("evaluation exceeded the maximum duration of %s", e.MaxDuration)
+}
@@ -2,10 +2,17 @@ package bexpr
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/hashicorp/go-bexpr/grammar"
 	"github.com/mitchellh/pointerstructure"
@@ -32,16 +39,38 @@ func primitiveEqualityFn(kind reflect.Kind) func(first interface{}, second refle
 	}
 }
 
+// valueEqualityFn returns the equality function for the value, which is
+// that of its kind unless the value is of a type compared in its own way
+func valueEqualityFn(value reflect.Value) func(first interface{}, second reflect.Value) bool {
+	if eqFn := primitiveEqualityFn(value.Kind()); eqFn != nil || !value.IsValid() {
+		return eqFn
+	}
+	switch {
+	case value.Type() == bigValueTyp:
+		return doEqualBig
+	case value.Type() == timeValueTyp:
+		return doEqualTime
+	case isTextType(value.Type()):
+		return textEqual
+	}
+	return nil
+}
+
 func doEqualBool(first interface{}, second reflect.Value) bool {
 	return first.(bool) == second.Bool()
 }
 
+// integers equal none of the numbers they cannot hold, which are coerced
+// to float64
+
 func doEqualInt64(first interface{}, second reflect.Value) bool {
-	return first.(int64) == second.Int()
+	b, ok := first.(int64)
+	return ok && b == second.Int()
 }
 
 func doEqualUint64(first interface{}, second reflect.Value) bool {
-	return first.(uint64) == second.Uint()
+	b, ok := first.(uint64)
+	return ok && b == second.Uint()
 }
 
 func doEqualFloat32(first interface{}, second reflect.Value) bool {
@@ -56,6 +85,132 @@ func doEqualString(first interface{}, second reflect.Value) bool {
 	return first.(string) == second.String()
 }
 
+func doEqualFoldString(first interface{}, second reflect.Value) bool {
+	return strings.EqualFold(first.(string), second.String())
+}
+
+func primitiveCompareFn(kind reflect.Kind) func(first interface{}, second reflect.Value) int {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return doCompareInt64
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return doCompareUint64
+	case reflect.Float32:
+		return doCompareFloat32
+	case reflect.Float64:
+		return doCompareFloat64
+	case reflect.String:
+		return doCompareString
+	default:
+		return nil
+	}
+}
+
+// valueCompareFn returns the compare function for the value, which is that
+// of its kind for all but big numbers and times
+func valueCompareFn(value reflect.Value) func(first interface{}, second reflect.Value) int {
+	switch {
+	case value.IsValid() && value.Type() == bigValueTyp:
+		return doCompareBig
+	case value.IsValid() && value.Type() == timeValueTyp:
+		return doCompareTime
+	}
+	return primitiveCompareFn(value.Kind())
+}
+
+// The compare functions return -1, 0 or 1 when the datum value (second)
+// is less than, equal to or greater than the expressions value (first)
+
+func doCompareInt64(first interface{}, second reflect.Value) int {
+	b, ok := first.(int64)
+	if !ok {
+		return compareInexactInt(second.Int(), first.(float64))
+	}
+	a := second.Int()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func doCompareUint64(first interface{}, second reflect.Value) int {
+	b, ok := first.(uint64)
+	if !ok {
+		return compareInexactUint(second.Uint(), first.(float64))
+	}
+	a := second.Uint()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareInexactInt compares an integer against a number that no integer
+// equals, such as 1.5 or 1e30
+func compareInexactInt(a int64, b float64) int {
+	switch {
+	case b >= math.MaxInt64:
+		return -1
+	case b <= math.MinInt64:
+		return 1
+	case a <= int64(math.Floor(b)):
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareInexactUint compares an unsigned integer against a number that no
+// unsigned integer equals, such as 1.5 or -1
+func compareInexactUint(a uint64, b float64) int {
+	switch {
+	case b < 0:
+		return 1
+	case b >= math.MaxUint64:
+		return -1
+	case a <= uint64(math.Floor(b)):
+		return -1
+	default:
+		return 1
+	}
+}
+
+func doCompareFloat32(first interface{}, second reflect.Value) int {
+	a, b := float32(second.Float()), first.(float32)
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func doCompareFloat64(first interface{}, second reflect.Value) int {
+	a, b := second.Float(), first.(float64)
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func doCompareString(first interface{}, second reflect.Value) int {
+	return strings.Compare(second.String(), first.(string))
+}
+
 // Get rid of 0 to many levels of pointers to get at the real type
 func derefType(rtype reflect.Type) reflect.Type {
 	for rtype.Kind() == reflect.Ptr {
@@ -66,56 +221,234 @@ func derefType(rtype reflect.Type) reflect.Type {
 
 func doMatchMatches(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
 	if !value.Type().ConvertibleTo(byteSliceTyp) {
-		return false, fmt.Errorf("Value of type %s is not convertible to []byte", value.Type())
+		return false, &UnsupportedOperatorError{
+			Operator: expression.Operator,
+			Selector: expression.Selector,
+			Err:      fmt.Errorf("Value of type %s is not convertible to []byte", value.Type()),
+		}
 	}
 
-	var re *regexp.Regexp
-	var ok bool
-	if expression.Value.Converted != nil {
-		re, ok = expression.Value.Converted.(*regexp.Regexp)
-	}
+	// The parser compiles the regular expression once so that evaluations
+	// do not have to. ASTs built by other means may not have it yet, in which
+	// case it is compiled here without caching to keep evaluation free of
+	// mutations to the shared AST.
+	re, ok := expression.Value.Converted.(*regexp.Regexp)
 	if !ok || re == nil {
 		var err error
 		re, err = regexp.Compile(expression.Value.Raw)
 		if err != nil {
-			return false, fmt.Errorf("Failed to compile regular expression %q: %v", expression.Value.Raw, err)
+			return false, coercionError(expression, expression.Value.Raw, fmt.Errorf("Failed to compile regular expression %q: %v", expression.Value.Raw, err))
 		}
-		expression.Value.Converted = re
 	}
 
 	return re.Match(value.Convert(byteSliceTyp).Interface().([]byte)), nil
 }
 
-func doMatchEqual(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
+func doMatchLike(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
+	re, ok := expression.Value.Converted.(*regexp.Regexp)
+	if !ok || re == nil {
+		return false, fmt.Errorf("Glob pattern %q was not compiled", expression.Value.Raw)
+	}
+	return doMatchStrings(expression, value, "like", re.MatchString)
+}
+
+func doMatchPrefix(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
+	return doMatchStrings(expression, value, "starts with", func(s string) bool {
+		return strings.HasPrefix(s, expression.Value.Raw)
+	})
+}
+
+func doMatchSuffix(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
+	return doMatchStrings(expression, value, "ends with", func(s string) bool {
+		return strings.HasSuffix(s, expression.Value.Raw)
+	})
+}
+
+// doMatchStrings applies the match function to a string value or to each element
+// of a slice of strings, where any matching element is sufficient.
+func doMatchStrings(expression *grammar.MatchExpression, value reflect.Value, opName string, match func(string) bool) (bool, error) {
+	switch kind := value.Kind(); kind {
+	case reflect.String:
+		return match(value.String()), nil
+	case reflect.Slice, reflect.Array:
+		if derefType(value.Type().Elem()).Kind() != reflect.String {
+			return false, operatorError(expression, "Cannot perform %s operations on type %s", opName, value.Type())
+		}
+		for i := 0; i < value.Len(); i++ {
+			if match(reflect.Indirect(value.Index(i)).String()) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, operatorError(expression, "Cannot perform %s operations on type %s", opName, kind)
+	}
+}
+
+var ipTyp reflect.Type = reflect.TypeOf(net.IP{})
+
+var timeTyp reflect.Type = reflect.TypeOf(time.Time{})
+
+var durationTyp reflect.Type = reflect.TypeOf(time.Duration(0))
+
+var jsonNumberTyp reflect.Type = reflect.TypeOf(json.Number(""))
+
+// doMatchInCIDR checks whether the IP address value, or any of the
+// addresses within a slice, is within the network of the expression.
+// Strings which are not IP addresses and nil values are never contained.
+func doMatchInCIDR(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
+	network, ok := expression.Value.Converted.(*net.IPNet)
+	if !ok {
+		_, parsed, err := net.ParseCIDR(expression.Value.Raw)
+		if err != nil {
+			return false, fmt.Errorf("error getting match value in expression: %w", coercionError(expression, expression.Value.Raw, err))
+		}
+		network = parsed
+	}
+
+	value = addressValue(value)
+	if !value.IsValid() {
+		return false, nil
+	}
+	if value.Type() == ipTyp {
+		return network.Contains(value.Interface().(net.IP)), nil
+	}
+
+	switch kind := value.Kind(); kind {
+	case reflect.String:
+		ip := net.ParseIP(value.String())
+		return ip != nil && network.Contains(ip), nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			item := addressValue(value.Index(i))
+			if !item.IsValid() {
+				continue
+			}
+			if item.Type() != ipTyp && item.Kind() != reflect.String {
+				return false, operatorError(expression, "Cannot perform in cidr operations on type %s", value.Type())
+			}
+			found, err := doMatchInCIDR(expression, item)
+			if err != nil || found {
+				return found, err
+			}
+		}
+		return false, nil
+	default:
+		return false, operatorError(expression, "Cannot perform in cidr operations on type %s", kind)
+	}
+}
+
+// addressValue returns the value that an interface, such as an element of a
+// decoded JSON array, or a pointer holds. It returns the zero Value for nil.
+func addressValue(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func doMatchEqual(expression *grammar.MatchExpression, value reflect.Value, fold bool) (bool, error) {
 	// NOTE: see preconditions in evaluategrammar.MatchExpressionRecurse
-	eqFn := primitiveEqualityFn(value.Kind())
-	matchValue, err := getMatchExprValue(expression, value.Kind())
+	if _, ok := expression.Value.Converted.(bool); ok && value.Kind() != reflect.Bool {
+		// bare selectors may only reference boolean values
+		return false, operatorError(expression, "Cannot perform boolean operations on type %s", value.Kind())
+	}
+	eqFn := valueEqualityFn(value)
+	if eqFn == nil {
+		return false, operatorError(expression, "Cannot perform equality operations on type %s", value.Kind())
+	}
+	matchValue, err := getMatchExprValue(expression, value.Type())
 	if err != nil {
 		return false, fmt.Errorf("error getting match value in expression: %w", err)
 	}
+	if fold && value.Kind() == reflect.String {
+		return strings.EqualFold(matchValue.(string), value.String()), nil
+	}
 	return eqFn(matchValue, value), nil
 }
 
-func doMatchIn(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
-	matchValue, err := getMatchExprValue(expression, value.Kind())
+func doMatchCompare(expression *grammar.MatchExpression, value reflect.Value) (int, error) {
+	cmpFn := valueCompareFn(value)
+	if cmpFn == nil {
+		return 0, operatorError(expression, "Cannot perform ordered comparisons on type %s", value.Kind())
+	}
+	matchValue, err := getMatchExprValue(expression, value.Type())
 	if err != nil {
-		return false, fmt.Errorf("error getting match value in expression: %w", err)
+		return 0, fmt.Errorf("error getting match value in expression: %w", err)
+	}
+	return cmpFn(matchValue, value), nil
+}
+
+func doMatchBetween(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
+	cmpFn := valueCompareFn(value)
+	if cmpFn == nil {
+		return false, operatorError(expression, "Cannot perform between operations on type %s", value.Kind())
+	}
+	if len(expression.Values) != 2 {
+		return false, fmt.Errorf("between operations require a lower and upper bound for selector: %q", expression.Selector)
 	}
 
+	low, err := literalValue(expression.Values[0], value.Type())
+	if err != nil {
+		return false, fmt.Errorf("error getting lower bound in expression: %w", coercionError(expression, expression.Values[0].Raw, err))
+	}
+	high, err := literalValue(expression.Values[1], value.Type())
+	if err != nil {
+		return false, fmt.Errorf("error getting upper bound in expression: %w", coercionError(expression, expression.Values[1].Raw, err))
+	}
+
+	return cmpFn(low, value) >= 0 && cmpFn(high, value) <= 0, nil
+}
+
+// doMatchIn checks whether the collection or string value contains the value
+// of the expression. For maps the value is converted to the type of the map
+// keys, so that maps with keys other than strings, such as map[int]string,
+// are matched by their keys.
+func doMatchIn(expression *grammar.MatchExpression, value reflect.Value, fold bool) (bool, error) {
 	switch kind := value.Kind(); kind {
 	case reflect.Map:
-		found := value.MapIndex(reflect.ValueOf(matchValue))
+		keyType := value.Type().Key()
+		key, err := getMatchExprValue(expression, keyType)
+		if err != nil {
+			return false, fmt.Errorf("error getting match value in expression: %w", err)
+		}
+		if fold && keyType.Kind() == reflect.String {
+			for _, mapKey := range value.MapKeys() {
+				if strings.EqualFold(mapKey.String(), key.(string)) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		rkey := reflect.ValueOf(key)
+		if _, inexact := key.(float64); inexact && rkey.Kind() != keyType.Kind() {
+			// no integer key equals a number integers cannot hold
+			return false, nil
+		}
+		if !rkey.Type().ConvertibleTo(keyType) {
+			return false, operatorError(expression, "Cannot perform in/contains operations on map keys of type %s", keyType)
+		}
+		found := value.MapIndex(rkey.Convert(keyType))
 		return found.IsValid(), nil
 
 	case reflect.Slice, reflect.Array:
 		itemType := derefType(value.Type().Elem())
+		if itemType.Kind() == reflect.Interface || itemType == jsonNumberTyp {
+			return doMatchInDynamic(expression, value, fold)
+		}
 		// Once we know the item type, we need to re-derive the match value for
 		// equality assertion
-		matchValue, err = getMatchExprValue(expression, itemType.Kind())
+		matchValue, err := getMatchExprValue(expression, itemType)
 		if err != nil {
 			return false, fmt.Errorf("error getting match value in expression: %w", err)
 		}
 		eqFn := primitiveEqualityFn(itemType.Kind())
+		if fold && itemType.Kind() == reflect.String {
+			eqFn = doEqualFoldString
+		}
 
 		for i := 0; i < value.Len(); i++ {
 			item := value.Index(i)
@@ -129,84 +462,759 @@ func doMatchIn(expression *grammar.MatchExpression, value reflect.Value) (bool,
 		return false, nil
 
 	case reflect.String:
+		matchValue, err := getMatchExprValue(expression, value.Type())
+		if err != nil {
+			return false, fmt.Errorf("error getting match value in expression: %w", err)
+		}
+		if fold {
+			return strings.Contains(strings.ToLower(value.String()), strings.ToLower(matchValue.(string))), nil
+		}
 		return strings.Contains(value.String(), matchValue.(string)), nil
 
 	default:
-		return false, fmt.Errorf("Cannot perform in/contains operations on type %s for selector: %q", kind, expression.Selector)
+		return false, operatorError(expression, "Cannot perform in/contains operations on type %s", kind)
+	}
+}
+
+// doMatchInDynamic checks whether any element of the slice or array, whose
+// elements are interfaces such as those decoded from JSON, equals the value
+// of the match. Each element is compared according to its own type and the
+// elements of types the value cannot be converted to are not equal to it.
+func doMatchInDynamic(expression *grammar.MatchExpression, value reflect.Value, fold bool) (bool, error) {
+	for i := 0; i < value.Len(); i++ {
+		item, err := jsonNumberValue(value.Index(i).Interface())
+		if err != nil {
+			continue
+		}
+		ritem := reflect.Indirect(reflect.ValueOf(item))
+		if primitiveEqualityFn(ritem.Kind()) == nil {
+			continue
+		}
+		if found, err := doMatchEqual(expression, ritem, fold); err == nil && found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// doMatchContainsList checks the value contains any, or when all is set
+// every one, of the list values in the same way as the contains operator
+func doMatchContainsList(expression *grammar.MatchExpression, value reflect.Value, fold bool, all bool) (bool, error) {
+	for _, listValue := range expression.Values {
+		item := *expression
+		item.Value = listValue
+		found, err := doMatchIn(&item, value, fold)
+		if err != nil {
+			return false, err
+		}
+		if found != all {
+			return found, nil
+		}
 	}
+	return all, nil
 }
 
+// matchSet returns the set of values that the in set operators of the
+// match expression test membership of, either its list or a named set
+func matchSet(expression *grammar.MatchExpression, opts *options) (*valueSet, error) {
+	if expression.Values != nil {
+		set := opts.listSets[expression]
+		if set == nil {
+			return nil, fmt.Errorf("list values for selector %q were not coerced", expression.Selector)
+		}
+		return set, nil
+	}
+	set := opts.valueSets.get(expression.Value.Raw)
+	if set == nil {
+		return nil, fmt.Errorf("value set %q is not defined", "@"+expression.Value.Raw)
+	}
+	return set, nil
+}
+
+func doMatchInSet(expression *grammar.MatchExpression, value reflect.Value, opts *options) (bool, error) {
+	set, err := matchSet(expression, opts)
+	if err != nil {
+		return false, err
+	}
+
+	switch kind := value.Kind(); kind {
+	case reflect.Slice, reflect.Array:
+		// any item within the set is sufficient
+		for i := 0; i < value.Len(); i++ {
+			item := value.Index(i)
+			dynamic := item.Kind() == reflect.Interface || derefType(item.Type()) == jsonNumberTyp
+			if dynamic {
+				// elements such as those decoded from JSON are compared by
+				// their own types, skipping those that cannot be
+				elem, err := jsonNumberValue(item.Interface())
+				if err != nil {
+					continue
+				}
+				item = reflect.ValueOf(elem)
+			}
+			found, ok := set.contains(reflect.Indirect(item))
+			if !ok {
+				if dynamic {
+					continue
+				}
+				return false, operatorError(expression, "Cannot perform in set operations on type %s", item.Kind())
+			}
+			if found {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		found, ok := set.contains(value)
+		if !ok {
+			return false, operatorError(expression, "Cannot perform in set operations on type %s", kind)
+		}
+		return found, nil
+	}
+}
+
+// doMatchIsEmpty checks whether the map, slice, array or string value, which
+// nil values are treated as, has no elements. Values of any other kind have
+// no length and fail the match with an UnsupportedOperatorError.
 func doMatchIsEmpty(matcher *grammar.MatchExpression, value reflect.Value) (bool, error) {
 	// NOTE: see preconditions in evaluategrammar.MatchExpressionRecurse
-	return value.Len() == 0, nil
+	switch kind := value.Kind(); kind {
+	case reflect.Invalid:
+		// nil values have nothing in them
+		return true, nil
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		return value.Len() == 0, nil
+	default:
+		return false, operatorError(matcher, "Cannot perform is empty operations on type %s", kind)
+	}
 }
 
-func getMatchExprValue(expression *grammar.MatchExpression, rvalue reflect.Kind) (interface{}, error) {
+func getMatchExprValue(expression *grammar.MatchExpression, rtype reflect.Type) (interface{}, error) {
 	if expression.Value == nil {
 		return nil, nil
 	}
 
+	value, err := literalValue(expression.Value, rtype)
+	if err != nil {
+		return nil, coercionError(expression, expression.Value.Raw, err)
+	}
+	return value, nil
+}
+
+// operatorError returns an *UnsupportedOperatorError for the operator of the
+// match expression which reads: <message> for selector: "<selector>"
+func operatorError(expression *grammar.MatchExpression, format string, args ...interface{}) error {
+	return &UnsupportedOperatorError{
+		Operator: expression.Operator,
+		Selector: expression.Selector,
+		Err:      fmt.Errorf(format+" for selector: %q", append(args, expression.Selector)...),
+	}
+}
+
+// coercionError returns a *CoercionError for the raw value of the match
+// expression
+func coercionError(expression *grammar.MatchExpression, raw string, err error) error {
+	return &CoercionError{Selector: expression.Selector, Value: raw, Err: err}
+}
+
+// selectorError returns the error finding the value of the selector within
+// the datum, as an *UnknownSelectorError when the value does not exist
+func selectorError(sel grammar.Selector, err error) error {
+	if errors.Is(err, errNoStructField) || errors.Is(err, errIgnoredField) || errors.Is(err, pointerstructure.ErrNotFound) {
+		return &UnknownSelectorError{Selector: sel, Err: err}
+	}
+	return err
+}
+
+func getMatchValue(raw string, rvalue reflect.Kind) (interface{}, error) {
 	switch rvalue {
 	case reflect.Bool:
-		return CoerceBool(expression.Value.Raw)
+		return CoerceBool(raw)
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return CoerceInt64(expression.Value.Raw)
+		return CoerceInt64(raw)
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return CoerceUint64(expression.Value.Raw)
+		return CoerceUint64(raw)
 
 	case reflect.Float32:
-		return CoerceFloat32(expression.Value.Raw)
+		return CoerceFloat32(raw)
 
 	case reflect.Float64:
-		return CoerceFloat64(expression.Value.Raw)
+		return CoerceFloat64(raw)
+
+	default:
+		return raw, nil
+	}
+}
+
+// characterValue returns the code point of a value which is a single
+// character as the value that runes, such as: Delim == ",", and bytes, when
+// the character is ASCII, are compared against. It returns false for values
+// of other kinds.
+func characterValue(raw string, kind reflect.Kind) (interface{}, bool) {
+	r, size := utf8.DecodeRuneInString(raw)
+	if size == 0 || size != len(raw) || r == utf8.RuneError {
+		return nil, false
+	}
+	switch {
+	case kind == reflect.Int32:
+		return int64(r), true
+	case kind == reflect.Uint8 && r < utf8.RuneSelf:
+		return uint64(r), true
+	}
+	return nil, false
+}
+
+// literalValue converts the value of an expression for comparison against
+// values of the type. Quoted values of a single character are compared
+// against runes and bytes as characters, while any other value, including
+// unquoted digits, is compared against them as a number.
+func literalValue(value *grammar.MatchValue, rtype reflect.Type) (interface{}, error) {
+	if value.Quoted {
+		if _, ok := getCoercion(rtype); !ok {
+			if character, ok := characterValue(value.Raw, rtype.Kind()); ok {
+				return character, nil
+			}
+		}
+	}
+	return coerceMatchValue(value.Raw, rtype)
+}
+
+// isMissingValue reports whether the value lookup failed because the value
+// is not present in the datum, either due to an absent map key or a nil
+// pointer, map or interface somewhere along the path.
+func isMissingValue(err error) bool {
+	return errors.Is(err, pointerstructure.ErrNotFound) || errors.Is(err, ErrNilValue)
+}
+
+// valueLength returns the length of the map, slice, array or string value
+// for len() selectors. Nil values have a length of zero.
+func valueLength(expression *grammar.MatchExpression, val interface{}) (int, error) {
+	rvalue := reflect.Indirect(reflect.ValueOf(val))
+	switch rvalue.Kind() {
+	case reflect.Invalid:
+		return 0, nil
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		return rvalue.Len(), nil
+	default:
+		return 0, operatorError(expression, "Cannot perform len operations on type %s", rvalue.Kind())
+	}
+}
+
+// isNilValue reports whether val is nil or a nil pointer, map, slice or interface
+func isNilValue(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+	rvalue := reflect.ValueOf(val)
+	switch rvalue.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rvalue.IsNil()
+	default:
+		return false
+	}
+}
+
+// resolveRelativeIndexes rewrites the negative and "last" indexes of slice
+// elements within the pointer path into absolute indexes. Map keys are left
+// untouched so that they still match keys such as "-1". The selector path
+// is never modified as it is shared by all evaluations.
+func resolveRelativeIndexes(ptr *pointerstructure.Pointer, datum interface{}, opts *options) ([]string, error) {
+	parts := ptr.Parts
+	for i, part := range parts {
+		if part != "last" && !strings.HasPrefix(part, "-") {
+			continue
+		}
+
+		parent := pointerstructure.Pointer{Parts: parts[:i]}
+		val, err := getValue(&parent, datum, opts)
+		if err != nil {
+			// let the full lookup report the error
+			return parts, nil
+		}
+
+		rvalue := reflect.Indirect(reflect.ValueOf(val))
+		if rvalue.Kind() != reflect.Slice && rvalue.Kind() != reflect.Array {
+			continue
+		}
+
+		idx := -1
+		if part != "last" {
+			idx, err = strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("%s at part %d: invalid index %q", parent.String(), i, part)
+			}
+		}
+		if rvalue.Len()+idx < 0 {
+			return nil, fmt.Errorf("%s at part %d: index %s is out of range (length = %d)", parent.String(), i, part, rvalue.Len())
+		}
+
+		resolved := make([]string, len(parts))
+		copy(resolved, parts)
+		resolved[i] = strconv.Itoa(rvalue.Len() + idx)
+		parts = resolved
+	}
+	return parts, nil
+}
 
+// expandWildcards expands the wildcard segments of the pointer path, at the
+// given indexes in increasing order, into the paths of all the map values or
+// slice elements at those levels, calling fn with each path in turn until it
+// returns true or an error. Segments which are not wildcards are kept as
+// they are, even when they are "*" map keys. Each value a wildcard expands
+// to is a step of the evaluation, so that the paths of nested wildcards are
+// not all expanded before the evaluation limits or context are checked.
+// Errors finding the values are reported for the selector sel.
+func expandWildcards(sel grammar.Selector, ptr *pointerstructure.Pointer, wildcards []int, datum interface{}, opts *options, fn func(path []string) (bool, error)) (bool, error) {
+	if len(wildcards) == 0 {
+		return fn(ptr.Parts)
+	}
+	wildcard := wildcards[0]
+	lookupError := func(err error) error {
+		return fmt.Errorf("error finding value in datum: %w", selectorError(sel, err))
+	}
+
+	parent := pointerstructure.Pointer{Parts: ptr.Parts[:wildcard]}
+	prefix, err := resolveRelativeIndexes(&parent, datum, opts)
+	if err != nil {
+		return false, lookupError(err)
+	}
+	parent.Parts = prefix
+
+	val, err := getValue(&parent, datum, opts)
+	if err != nil {
+		return false, lookupError(err)
+	}
+
+	var segments []string
+	rvalue := reflect.Indirect(reflect.ValueOf(val))
+	switch rvalue.Kind() {
+	case reflect.Map:
+		for _, key := range rvalue.MapKeys() {
+			segments = append(segments, fmt.Sprint(key.Interface()))
+		}
+		// keep the evaluation order and so any errors deterministic
+		sort.Strings(segments)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rvalue.Len(); i++ {
+			segments = append(segments, strconv.Itoa(i))
+		}
 	default:
-		return expression.Value.Raw, nil
+		return false, lookupError(fmt.Errorf("%s at part %d: wildcard cannot be used with value kind: %s", parent.String(), wildcard, rvalue.Kind()))
+	}
+
+	parts := make([]string, 0, len(ptr.Parts))
+	parts = append(parts, prefix...)
+	parts = append(parts, "")
+	parts = append(parts, ptr.Parts[wildcard+1:]...)
+	for _, segment := range segments {
+		if err := step(opts); err != nil {
+			return false, err
+		}
+		if err := checkContext(opts); err != nil {
+			return false, err
+		}
+
+		// only the wildcards after the substituted segment remain, so a
+		// key which is itself "*" is not expanded again
+		parts[wildcard] = segment
+		done, err := expandWildcards(sel, &pointerstructure.Pointer{Parts: parts}, wildcards[1:], datum, opts, fn)
+		if err != nil || done {
+			return done, err
+		}
+	}
+	return false, nil
+}
+
+// binding is the datum used while evaluating the body of a quantifier. It
+// binds the quantifier variable to the current element while all other
+// selectors still resolve against the enclosing datum.
+type binding struct {
+	variable string
+	value    interface{}
+	parent   interface{}
+}
+
+// resolveBinding returns the datum the selector path refers to along with
+// the remainder of the path within that datum.
+func resolveBinding(datum interface{}, path []string) (interface{}, []string) {
+	for {
+		b, ok := datum.(*binding)
+		if !ok {
+			return datum, path
+		}
+		if len(path) > 0 && path[0] == b.variable {
+			return b.value, path[1:]
+		}
+		datum = b.parent
 	}
 }
 
-func evaluateMatchExpression(expression *grammar.MatchExpression, datum interface{}) (bool, error) {
+func evaluateQuantifier(node *grammar.QuantifierExpression, datum interface{}, opts *options) (bool, error) {
+	target, path := resolveBinding(datum, node.Selector.Path)
 	ptr := pointerstructure.Pointer{
-		Parts: expression.Selector.Path,
-		Config: pointerstructure.Config{
-			TagName: "bexpr",
-		},
+		Parts: path,
 	}
-	val, err := ptr.Get(datum)
+	parts, err := resolveRelativeIndexes(&ptr, target, opts)
 	if err != nil {
 		return false, fmt.Errorf("error finding value in datum: %w", err)
 	}
+	ptr.Parts = parts
 
-	if jn, ok := val.(json.Number); ok {
-		if jni, err := jn.Int64(); err == nil {
-			val = jni
-		} else if jnf, err := jn.Float64(); err == nil {
-			val = jnf
-		} else {
-			return false, fmt.Errorf("unable to convert json number %s to int or float", jn)
+	val, err := getValue(&ptr, target, opts)
+	if err != nil {
+		return false, fmt.Errorf("error finding value in datum: %w%s", selectorError(node.Selector, err), selectorSuggestion(&ptr, target, opts))
+	}
+
+	elements, err := quantifierElements(node, val)
+	if err != nil {
+		return false, err
+	}
+
+	for _, element := range elements {
+		if err := checkContext(opts); err != nil {
+			return false, err
+		}
+		result, err := evaluate(node.Expression, &binding{variable: node.Variable, value: element.Interface(), parent: datum}, opts)
+		if err != nil {
+			return false, err
+		}
+		// any stops at the first match and all at the first mismatch
+		if result == (node.Quantifier == grammar.QuantifierAny) {
+			return result, nil
 		}
 	}
+	return node.Quantifier == grammar.QuantifierAll, nil
+}
+
+// quantifierElements returns the elements of the collection the quantifier
+// iterates, with the values of maps ordered by their keys
+func quantifierElements(node *grammar.QuantifierExpression, val interface{}) ([]reflect.Value, error) {
+	var elements []reflect.Value
+	rvalue := reflect.Indirect(reflect.ValueOf(val))
+	switch rvalue.Kind() {
+	case reflect.Map:
+		keys := rvalue.MapKeys()
+		// keep the evaluation order and so any errors deterministic
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			elements = append(elements, rvalue.MapIndex(key))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rvalue.Len(); i++ {
+			elements = append(elements, rvalue.Index(i))
+		}
+	default:
+		return nil, fmt.Errorf("Cannot perform %s quantification on type %s for selector: %q", strings.ToLower(node.Quantifier.String()), rvalue.Kind(), node.Selector)
+	}
+	return elements, nil
+}
+
+// getSelectorValue returns the value that the selector refers to within the
+// datum or within the element bound to a quantifier variable.
+func getSelectorValue(sel grammar.Selector, datum interface{}, opts *options) (interface{}, error) {
+	target, path := resolveBinding(datum, sel.Path)
+	ptr := pointerstructure.Pointer{
+		Parts: path,
+	}
+	parts, err := resolveRelativeIndexes(&ptr, target, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding value in datum: %w", err)
+	}
+	ptr.Parts = parts
+
+	val, err := getValue(&ptr, target, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding value in datum: %w%s", selectorError(sel, err), selectorSuggestion(&ptr, target, opts))
+	}
+	return val, nil
+}
+
+func evaluateFunction(node *grammar.FunctionExpression, datum interface{}, opts *options) (bool, error) {
+	fn, ok := opts.withFunctions[node.Name]
+	if !ok {
+		return false, fmt.Errorf("function %q is not defined", node.Name)
+	}
+	if err := checkContext(opts); err != nil {
+		return false, err
+	}
+
+	args := make([]interface{}, 0, len(node.Args))
+	for _, arg := range node.Args {
+		if arg.Value != nil {
+			args = append(args, arg.Value.Raw)
+			continue
+		}
+
+		val, err := getSelectorValue(arg.Selector, datum, opts)
+		if err != nil {
+			return false, err
+		}
+		args = append(args, val)
+	}
+
+	result, err := fn(args...)
+	if err != nil {
+		return false, fmt.Errorf("error calling function %q: %w", node.Name, err)
+	}
+	return result, nil
+}
+
+func evaluateMatchExpression(expression *grammar.MatchExpression, datum interface{}, opts *options) (bool, error) {
+	datum, path := resolveBinding(datum, expression.Selector.Path)
+	ptr := pointerstructure.Pointer{
+		Parts: path,
+	}
+
+	if !expression.Selector.HasWildcard() {
+		return evaluateMatchPath(expression, &ptr, datum, opts)
+	}
+
+	// the indexes of the wildcards within the path relative to the binding
+	var wildcards []int
+	offset := len(expression.Selector.Path) - len(path)
+	for _, wildcard := range expression.Selector.Wildcards {
+		if wildcard >= offset {
+			wildcards = append(wildcards, wildcard-offset)
+		}
+	}
+	// wildcard selectors match if any of the values they expand to match
+	return expandWildcards(expression.Selector, &ptr, wildcards, datum, opts, func(path []string) (bool, error) {
+		return evaluateMatchPath(expression, &pointerstructure.Pointer{Parts: path}, datum, opts)
+	})
+}
+
+func evaluateMatchPath(expression *grammar.MatchExpression, ptr *pointerstructure.Pointer, datum interface{}, opts *options) (bool, error) {
+	parts, err := resolveRelativeIndexes(ptr, datum, opts)
+	if err != nil {
+		return false, fmt.Errorf("error finding value in datum: %w", err)
+	}
+	resolved := pointerstructure.Pointer{Parts: parts}
+
+	val, field, err := lookupValue(&resolved, datum, opts, !expression.Length)
+	if delegated, ok := val.(*delegatedMatch); ok && err == nil {
+		return delegated.evaluate(expression, opts)
+	}
+	if err == nil {
+		err = field.checkOperator(expression)
+		if err != nil {
+			return false, err
+		}
+		val = sqlNullValue(dynamicValue(val))
+	}
+	if err != nil {
+		if !isMissingValue(err) {
+			return false, fmt.Errorf("error finding value in datum: %w%s", selectorError(expression.Selector, err), selectorSuggestion(&resolved, datum, opts))
+		}
+		defaultVal, ok := opts.withDefaultValues[pointerKey(expression.Selector.Path)]
+		if !ok {
+			if result, ok := opts.withMissingResults[expression.Operator]; ok {
+				return result, nil
+			}
+			// a value behind a nil pointer or missing key is null
+			switch expression.Operator {
+			case grammar.MatchIsNull:
+				return true, nil
+			case grammar.MatchIsNotNull:
+				return false, nil
+			}
+			if errors.Is(err, ErrNilValue) {
+				switch opts.withNilTraversal {
+				case NilTraversalFalse:
+					return false, nil
+				case NilTraversalNegated:
+					return negatedOperator(expression.Operator), nil
+				}
+			}
+			if opts.unknown {
+				switch expression.Operator {
+				case grammar.MatchIsEmpty, grammar.MatchIsNotEmpty:
+					// missing values have nothing in them
+					return expression.Operator == grammar.MatchIsEmpty, nil
+				}
+				return false, errUnknown
+			}
+			return false, fmt.Errorf("error finding value in datum: %w", selectorError(expression.Selector, err))
+		}
+		val = defaultVal
+	} else if reflect.Indirect(reflect.ValueOf(val)).Kind() == reflect.Invalid {
+		if defaultVal, ok := opts.withDefaultValues[pointerKey(expression.Selector.Path)]; ok {
+			val = defaultVal
+		}
+	}
+
+	if opts.unknown && !expression.Length && reflect.Indirect(reflect.ValueOf(val)).Kind() == reflect.Invalid {
+		switch expression.Operator {
+		case grammar.MatchIsNull, grammar.MatchIsNotNull, grammar.MatchIsEmpty, grammar.MatchIsNotEmpty:
+		default:
+			return false, errUnknown
+		}
+	}
+
+	val, err = jsonNumberValue(val)
+	if err != nil {
+		return false, err
+	}
+
+	if expression.Length {
+		val, err = valueLength(expression, val)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return evaluateMatchValue(expression, val, field, opts)
+}
+
+// dynamicValue returns the value held by the interface that a pointer, such
+// as a *interface{} field, refers to so that the value is compared by its
+// own type as the values of interface{} fields are. It returns any other
+// value as is.
+func dynamicValue(val interface{}) interface{} {
+	rvalue := reflect.ValueOf(val)
+	if rvalue.Kind() != reflect.Ptr || rvalue.IsNil() || rvalue.Elem().Kind() != reflect.Interface {
+		return val
+	}
+	for rvalue.Kind() == reflect.Ptr && !rvalue.IsNil() && rvalue.Elem().Kind() == reflect.Interface {
+		rvalue = rvalue.Elem().Elem()
+	}
+	if !rvalue.IsValid() {
+		return nil
+	}
+	return rvalue.Interface()
+}
+
+// jsonNumberValue converts a json.Number, as decoded by encoding/json with
+// UseNumber, or a pointer to one to an int64 or float64 so that it compares
+// as the numeric literals of expressions do. It returns any other value as
+// is.
+func jsonNumberValue(val interface{}) (interface{}, error) {
+	if ptr, ok := val.(*json.Number); ok && ptr != nil {
+		val = *ptr
+	}
+	jn, ok := val.(json.Number)
+	if !ok {
+		return val, nil
+	}
+	if jni, err := jn.Int64(); err == nil {
+		return jni, nil
+	} else if jnf, err := jn.Float64(); err == nil {
+		return jnf, nil
+	}
+	return nil, fmt.Errorf("unable to convert json number %s to int or float", jn)
+}
+
+// evaluateMatchValue applies the operator of the match expression to the
+// value found at its selector, within the struct field if there is one.
+// With WithDynamicTypes a value of a type that the operator or the value of
+// the match cannot be applied to does not match.
+func evaluateMatchValue(expression *grammar.MatchExpression, val interface{}, field *structField, opts *options) (bool, error) {
+	if comparer, ok := asComparer(val); ok {
+		result, err := doMatchComparer(expression, comparer, opts)
+		return dynamicTypeResult(expression, result, err, opts)
+	}
+	result, err := applyMatchOperator(expression, comparedValue(val, field, opts), field, opts)
+	return dynamicTypeResult(expression, result, err, opts)
+}
+
+// comparedValue returns the selected value in the form it is compared in,
+// which differs from the value itself for timestamps, UUIDs, durations, big
+// numbers, protobuf Timestamp and Duration messages and with WithStringers
+// structs implementing fmt.Stringer
+func comparedValue(val interface{}, field *structField, opts *options) interface{} {
+	return stringerMatchValue(bigMatchValue(uuidMatchValue(timeMatchValue(wellKnownValue(field.durationValue(val))))), opts)
+}
+
+// timeMatchValue returns a time.Time, or a pointer to one, as a timeValue
+// and any other value as is
+func timeMatchValue(val interface{}) interface{} {
+	rvalue := reflect.Indirect(reflect.ValueOf(val))
+	if !rvalue.IsValid() || rvalue.Type() != timeTyp {
+		return val
+	}
+	return newTimeValue(rvalue.Interface().(time.Time))
+}
+
+// dynamicTypeResult returns the result of the match expression, or with
+// WithDynamicTypes the result of a mismatch if the error is due to the type
+// of the selected value
+func dynamicTypeResult(expression *grammar.MatchExpression, result bool, err error, opts *options) (bool, error) {
+	if err != nil && opts.withDynamicTypes {
+		var coercionErr *CoercionError
+		var operatorErr *UnsupportedOperatorError
+		if errors.As(err, &coercionErr) || errors.As(err, &operatorErr) {
+			return negatedOperator(expression.Operator), nil
+		}
+	}
+	return result, err
+}
+
+// negatedOperator reports whether the operator negates another, such that
+// it matches when the other cannot be applied
+func negatedOperator(op grammar.MatchOperator) bool {
+	switch op {
+	case grammar.MatchNotEqual, grammar.MatchNotEqualFold, grammar.MatchNotIn, grammar.MatchNotInSet,
+		grammar.MatchIsNotEmpty, grammar.MatchNotMatches, grammar.MatchNotLike, grammar.MatchNotPrefix,
+		grammar.MatchNotSuffix, grammar.MatchNotBetween, grammar.MatchNotInCIDR,
+		grammar.MatchNotContainsAny, grammar.MatchNotContainsAll:
+		return true
+	}
+	return false
+}
+
+func applyMatchOperator(expression *grammar.MatchExpression, val interface{}, field *structField, opts *options) (bool, error) {
+	_, fold := opts.withCaseInsensitive[pointerKey(expression.Selector.Path)]
+	fold = fold || field.folds()
 
 	rvalue := reflect.Indirect(reflect.ValueOf(val))
 	switch expression.Operator {
 	case grammar.MatchEqual:
-		return doMatchEqual(expression, rvalue)
+		return doMatchEqual(expression, rvalue, fold)
 	case grammar.MatchNotEqual:
-		result, err := doMatchEqual(expression, rvalue)
+		result, err := doMatchEqual(expression, rvalue, fold)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchEqualFold:
+		return doMatchEqual(expression, rvalue, true)
+	case grammar.MatchNotEqualFold:
+		result, err := doMatchEqual(expression, rvalue, true)
 		if err == nil {
 			return !result, nil
 		}
 		return false, err
 	case grammar.MatchIn:
-		return doMatchIn(expression, rvalue)
+		return doMatchIn(expression, rvalue, fold)
 	case grammar.MatchNotIn:
-		result, err := doMatchIn(expression, rvalue)
+		result, err := doMatchIn(expression, rvalue, fold)
 		if err == nil {
 			return !result, nil
 		}
 		return false, err
+	case grammar.MatchContainsAny:
+		return doMatchContainsList(expression, rvalue, fold, false)
+	case grammar.MatchNotContainsAny:
+		result, err := doMatchContainsList(expression, rvalue, fold, false)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchContainsAll:
+		return doMatchContainsList(expression, rvalue, fold, true)
+	case grammar.MatchNotContainsAll:
+		result, err := doMatchContainsList(expression, rvalue, fold, true)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchIsNull:
+		return isNilValue(val), nil
+	case grammar.MatchIsNotNull:
+		return !isNilValue(val), nil
 	case grammar.MatchIsEmpty:
 		return doMatchIsEmpty(expression, rvalue)
 	case grammar.MatchIsNotEmpty:
@@ -223,39 +1231,155 @@ func evaluateMatchExpression(expression *grammar.MatchExpression, datum interfac
 			return !result, nil
 		}
 		return false, err
+	case grammar.MatchLike:
+		return doMatchLike(expression, rvalue)
+	case grammar.MatchNotLike:
+		result, err := doMatchLike(expression, rvalue)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchInCIDR:
+		return doMatchInCIDR(expression, rvalue)
+	case grammar.MatchNotInCIDR:
+		result, err := doMatchInCIDR(expression, rvalue)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchPrefix:
+		return doMatchPrefix(expression, rvalue)
+	case grammar.MatchNotPrefix:
+		result, err := doMatchPrefix(expression, rvalue)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchSuffix:
+		return doMatchSuffix(expression, rvalue)
+	case grammar.MatchNotSuffix:
+		result, err := doMatchSuffix(expression, rvalue)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchBetween:
+		return doMatchBetween(expression, rvalue)
+	case grammar.MatchNotBetween:
+		result, err := doMatchBetween(expression, rvalue)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchInSet:
+		return doMatchInSet(expression, rvalue, opts)
+	case grammar.MatchNotInSet:
+		result, err := doMatchInSet(expression, rvalue, opts)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchLessThan:
+		result, err := doMatchCompare(expression, rvalue)
+		return err == nil && result < 0, err
+	case grammar.MatchLessThanOrEqual:
+		result, err := doMatchCompare(expression, rvalue)
+		return err == nil && result <= 0, err
+	case grammar.MatchGreaterThan:
+		result, err := doMatchCompare(expression, rvalue)
+		return err == nil && result > 0, err
+	case grammar.MatchGreaterThanOrEqual:
+		result, err := doMatchCompare(expression, rvalue)
+		return err == nil && result >= 0, err
 	default:
+		if expression.Operator.IsCustom() {
+			return doMatchCustom(expression, rvalue)
+		}
 		return false, fmt.Errorf("Invalid match operation: %d", expression.Operator)
 	}
 }
 
-func evaluate(ast grammar.Expression, datum interface{}) (bool, error) {
+func evaluate(ast grammar.Expression, datum interface{}, opts *options) (bool, error) {
+	opts = scope(ast, opts)
+	if err := step(opts); err != nil {
+		return false, err
+	}
+
 	switch node := ast.(type) {
 	case *grammar.UnaryExpression:
 		switch node.Operator {
 		case grammar.UnaryOpNot:
-			result, err := evaluate(node.Operand, datum)
+			result, err := evaluate(node.Operand, datum, opts)
 			return !result, err
 		}
 	case *grammar.BinaryExpression:
 		switch node.Operator {
 		case grammar.BinaryOpAnd:
-			result, err := evaluate(node.Left, datum)
+			result, err := evaluate(node.Left, datum, opts)
 			if err != nil || !result {
 				return result, err
 			}
 
-			return evaluate(node.Right, datum)
+			return evaluate(node.Right, datum, opts)
 
 		case grammar.BinaryOpOr:
-			result, err := evaluate(node.Left, datum)
+			result, err := evaluate(node.Left, datum, opts)
 			if err != nil || result {
 				return result, err
 			}
 
-			return evaluate(node.Right, datum)
+			return evaluate(node.Right, datum, opts)
+
+		case grammar.BinaryOpXor:
+			// both sides always need evaluating so there is no short circuit
+			left, err := evaluate(node.Left, datum, opts)
+			if err != nil {
+				return false, err
+			}
+
+			right, err := evaluate(node.Right, datum, opts)
+			if err != nil {
+				return false, err
+			}
+
+			return left != right, nil
 		}
+	case *grammar.QuantifierExpression:
+		return evaluateQuantifier(node, datum, opts)
+	case *grammar.FunctionExpression:
+		return evaluateFunction(node, datum, opts)
+	case *grammar.ComparisonExpression:
+		return evaluateComparison(node, datum, opts)
+	case *grammar.ConstantExpression:
+		return node.Value, nil
 	case *grammar.MatchExpression:
-		return evaluateMatchExpression(node, datum)
+		if len(opts.withHooks) > 0 {
+			return evaluateMatchHooked(node, datum, opts)
+		}
+		return evaluateMatchExpression(node, datum, opts)
 	}
 	return false, fmt.Errorf("Invalid AST node")
 }
+
+// evaluateMatchHooked evaluates the match expression between calls to the
+// hooks of the options
+func evaluateMatchHooked(expression *grammar.MatchExpression, datum interface{}, opts *options) (bool, error) {
+	for _, hook := range opts.withHooks {
+		hook.OnMatchStart(expression.Selector, expression.Operator)
+	}
+
+	start := time.Now()
+	result, err := evaluateMatchExpression(expression, datum, opts)
+	event := MatchEvent{
+		Selector: expression.Selector,
+		Operator: expression.Operator,
+		Result:   result,
+		Err:      err,
+		Duration: time.Since(start),
+	}
+
+	for _, hook := range opts.withHooks {
+		hook.OnMatchEnd(event)
+	}
+	return result, err
+}
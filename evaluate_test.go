@@ -1,9 +1,24 @@
 package bexpr
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-bexpr/grammar"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,6 +34,8 @@ type expressionTest struct {
 	expressions []expressionCheck
 }
 
+var localhost = "127.0.0.1"
+
 var evaluateTests map[string]expressionTest = map[string]expressionTest{
 	"Flat Struct": {
 		testFlatStruct{
@@ -42,6 +59,11 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 		[]expressionCheck{
 			{expression: "Int == -1", result: true, benchQuick: true},
 			{expression: "Int == -99", result: false, benchQuick: true},
+			{expression: "Int eq -1", result: true},
+			{expression: "Int ne -1", result: false},
+			{expression: "Int gt -2 and Int lt 0", result: true},
+			{expression: "Uint ge 6 and Uint le 5", result: false},
+			{expression: "String eq exported", result: true},
 			{expression: "Int != -1", result: false},
 			{expression: "Int != -99", result: true},
 			{expression: "Int8 == -2", result: true},
@@ -102,10 +124,80 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "part not in String", result: true},
 			{expression: "unexported == `unexported`", result: false, err: `error finding value in datum: /unexported at part 0: couldn't find struct field with name "unexported"`},
 			{expression: "Hidden == false", result: false, err: "error finding value in datum: /Hidden at part 0: struct field \"Hidden\" is ignored and cannot be used"},
+			{expression: "Strng == `exported`", result: false, err: `error finding value in datum: /Strng at part 0: couldn't find struct field with name "Strng", did you mean "String"?`},
+			{expression: "float64 == 1.2", result: false, err: `error finding value in datum: /float64 at part 0: couldn't find struct field with name "float64", did you mean "Float64"?`},
+			{expression: "len(Unit8) == 7", result: false, err: `error finding value in datum: /Unit8 at part 0: couldn't find struct field with name "Unit8", did you mean "Uint8"?`},
 			{expression: "String matches 	`^ex.*`", result: true, benchQuick: true},
 			{expression: "String not matches `^anchored.*`", result: true, benchQuick: true},
 			{expression: "String matches 	`^anchored.*`", result: false},
 			{expression: "String not matches `^ex.*`", result: false},
+			{expression: "String =~ `^ex.*`", result: true},
+			{expression: "String !~ `^ex.*`", result: false},
+			{expression: "Int matches `-1`", result: false, err: "Value of type int is not convertible to []byte"},
+			{expression: "String like `ex*`", result: true, benchQuick: true},
+			{expression: "String like `ex?orted`", result: true},
+			{expression: "String like `[!e]*`", result: false},
+			{expression: "String not like `*ed`", result: false},
+			{expression: "Int like `*`", result: false, err: `Cannot perform like operations on type int for selector: "Int"`},
+			{expression: "String ==i `EXPORTED`", result: true, benchQuick: true},
+			{expression: "String iequals `Exported`", result: true},
+			{expression: "String !=i `eXpOrTeD`", result: false},
+			{expression: "String not iequals `imported`", result: true},
+			{expression: "String == `EXPORTED`", result: false},
+			{expression: "Int ==i -1", result: true},
+			{expression: "true", result: true},
+			{expression: "false or Int == -1", result: true},
+			{expression: "true and not false and Int == -99", result: false},
+			{expression: "Int == -1 xor Int8 == -2", result: false},
+			{expression: "Int == -1 xor Int8 == -99", result: true, benchQuick: true},
+			{expression: "Int == -99 xor Int8 == -99", result: false},
+			{expression: "Int == -99 xor foo == 3", result: false, err: `error finding value in datum: /foo at part 0: couldn't find struct field with name "foo"`},
+			{expression: "Bool", result: true, benchQuick: true},
+			{expression: "not Bool", result: false},
+			{expression: "Bool and Int == -1", result: true},
+			{expression: "String", result: false, err: `Cannot perform boolean operations on type string for selector: "String"`},
+			{expression: "Uint8 == 0x07", result: true},
+			{expression: "Int16 == -0o3", result: true},
+			{expression: "Uint64 == 1e1", result: true},
+			{expression: "Int32 > -4.5e0", result: true},
+			{expression: "Float64 == 12e-1", result: true},
+			{expression: "Float32 < 0x2", result: true},
+			{expression: "Int between -1 and 3", result: true, benchQuick: true},
+			{expression: "Int between -5 and -2", result: false},
+			{expression: "Uint16 not between 8 and 9", result: false},
+			{expression: "Float32 between 1 and 1.1", result: true},
+			{expression: "String between `a` and `f`", result: true},
+			{expression: "Int between a and 3", result: false, err: `error getting lower bound in expression: strconv.ParseInt: parsing "a": invalid syntax`},
+			{expression: "Bool between false and true", result: false, err: `Cannot perform between operations on type bool for selector: "Bool"`},
+			{expression: "String in [`exported`, `other`]", result: true, benchQuick: true},
+			{expression: "String not in [`exported`, `other`]", result: false},
+			{expression: "Int in [-1, 0, 1]", result: true},
+			{expression: "Uint8 in [1, 2, 3]", result: false},
+			{expression: "Float64 in [1.2]", result: true},
+			{expression: "Bool in [false]", result: false},
+			{expression: "Int in []", result: false},
+			{expression: "String starts with `exp`", result: true, benchQuick: true},
+			{expression: "String starts with `port`", result: false},
+			{expression: "String not starts with `exp`", result: false},
+			{expression: "String ends with `ted`", result: true},
+			{expression: "String ends with `exp`", result: false},
+			{expression: "String not ends with `exp`", result: true},
+			{expression: "Int starts with `-`", result: false, err: `Cannot perform starts with operations on type int for selector: "Int"`},
+			{expression: "Int < 0", result: true},
+			{expression: "Int >= 0", result: false},
+			{expression: "Uint8 > 6", result: true, benchQuick: true},
+			{expression: "Uint8 <= 6", result: false},
+			{expression: "Float64 >= 1.2", result: true},
+			{expression: "Float32 < 1.1", result: false},
+			{expression: "-10 <= Int <= 0", result: true, benchQuick: true},
+			{expression: "-1 < Int <= 0", result: false},
+			{expression: "10 >= Uint16 > 7", result: true},
+			{expression: "1 < Float64 < 1.2", result: false},
+			{expression: "String > `abc`", result: true},
+			{expression: "String >= `exported`", result: true},
+			{expression: "String < `exported`", result: false},
+			{expression: "`a` < String < `f`", result: true},
+			{expression: "Bool > true", result: false, err: `Cannot perform ordered comparisons on type bool for selector: "Bool"`},
 		},
 	},
 	"Flat Struct Alt Types": {
@@ -183,6 +275,10 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "String == `not-it`", result: false, benchQuick: true},
 			{expression: "String != `exported`", result: false},
 			{expression: "String != `not-it`", result: true},
+			{expression: "Int8 <= -2", result: true},
+			{expression: "Uint32 > 9", result: false},
+			{expression: "Float32 >= 1.1", result: true},
+			{expression: "String < `f`", result: true},
 			{expression: "unexported == `unexported`", result: false, err: `error finding value in datum: /unexported at part 0: couldn't find struct field with name "unexported"`},
 			{expression: "Hidden == false", result: false, err: "error finding value in datum: /Hidden at part 0: struct field \"Hidden\" is ignored and cannot be used"},
 		},
@@ -217,6 +313,268 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "foo.bar.baz == 3", result: false, err: `error finding value in datum: /foo/bar/baz: at part 2, invalid value kind: bool`},
 		},
 	},
+	"String Slices": {
+		map[string]interface{}{
+			"tags": []string{"web-01", "db-02"},
+			"ints": []int{1, 2},
+		},
+		[]expressionCheck{
+			{expression: "tags like `web-*`", result: true},
+			{expression: "tags like `db-0[13]`", result: false},
+			{expression: "tags not like `cache-*`", result: true},
+			{expression: "tags starts with `db-`", result: true},
+			{expression: "tags in [`db-02`, `cache-01`]", result: true},
+			{expression: "ints not in [3, 4]", result: true},
+			{expression: "tags contains any [`cache-01`, `db-02`]", result: true, benchQuick: true},
+			{expression: "tags contains any [`cache-01`]", result: false},
+			{expression: "tags not contains any [`cache-01`]", result: true},
+			{expression: "tags contains all [`web-01`, `db-02`]", result: true},
+			{expression: "tags contains all [`web-01`, `cache-01`]", result: false},
+			{expression: "tags not contains all [`web-01`, `cache-01`]", result: true},
+			{expression: "tags contains any []", result: false},
+			{expression: "tags contains all []", result: true},
+			{expression: "ints contains all [2, 1]", result: true},
+			{expression: "ints contains any [3, `x`]", result: false, err: `error getting match value in expression: strconv.ParseInt: parsing "x": invalid syntax`},
+			{expression: "tags ends with `-03`", result: false},
+			{expression: "tags[0] == `web-01`", result: true},
+			{expression: "tags[-1] == `db-02`", result: true},
+			{expression: "tags[last] == `db-02`", result: true, benchQuick: true},
+			{expression: "tags[-2] starts with `web`", result: true},
+			{expression: "tags[\"-abc\"] == `db-02`", result: false, err: `error finding value in datum: /tags at part 1: invalid index "-abc"`},
+			{expression: "tags[-3] == `db-02`", result: false, err: `error finding value in datum: /tags at part 1: index -3 is out of range (length = 2)`},
+			{expression: "tags[1] starts with `web`", result: false},
+			{expression: "ints[1] > 1", result: true},
+			{expression: "tags[2] == `web-01`", result: false, err: `error finding value in datum: /tags/2 at part 1: index 2 is out of range (length = 2)`},
+			{expression: "ints like `1`", result: false, err: `Cannot perform like operations on type []int for selector: "ints"`},
+			{expression: "tags ==i `WEB-01`", result: false, err: `Cannot perform equality operations on type slice for selector: "tags"`},
+		},
+	},
+	"Nil Pointers": {
+		map[string]interface{}{
+			"nested": (*testNestedLevel2_1)(nil),
+			"set":    &testNestedLevel2_1{Foo: 0},
+			"tags":   []string(nil),
+			"empty":  []string{},
+			"zero":   0,
+		},
+		[]expressionCheck{
+			{expression: "nested is null", result: true, benchQuick: true},
+			{expression: "nested is not nil", result: false},
+			{expression: "nested.Foo is null", result: true},
+			{expression: "nested.Foo is not null", result: false},
+			{expression: "set.Foo is null", result: false},
+			{expression: "set.Foo is not null", result: true},
+			{expression: "tags is null", result: true},
+			{expression: "empty is null", result: false},
+			{expression: "empty is empty", result: true},
+			{expression: "zero is not null", result: true},
+			{expression: "missing is null", result: true},
+			{expression: "nested is empty", result: true},
+			{expression: "zero.foo is null", result: false, err: `error finding value in datum: /zero/foo: at part 1, invalid value kind: int`},
+			{expression: "set.Bar is null", result: false, err: `error finding value in datum: /set/Bar at part 1: couldn't find struct field with name "Bar", did you mean "Baz"?`},
+		},
+	},
+	"Special Map Keys": {
+		map[string]interface{}{
+			"meta": map[string]string{
+				"consul.io/version": "1.9",
+				"with space":        "yes",
+				"quote\"d":          "a \"quoted\"\nvalue!",
+			},
+		},
+		[]expressionCheck{
+			{expression: "meta.`consul.io/version` == `1.9`", result: true, benchQuick: true},
+			{expression: `meta."with space" == yes`, result: true},
+			{expression: `meta["quote\"d"] == "a \"quoted\"\nvalue\u0021"`, result: true},
+			{expression: `meta["consul.io/version"] == "1.9"`, result: true},
+			{expression: "meta[`with space`] != no", result: true},
+			{expression: `meta["missing key"] == yes`, result: false, err: `error finding value in datum: /meta/missing key at part 1: couldn't find key "missing key"`},
+			{expression: "meta.`consul.io` is empty", result: false, err: `error finding value in datum: /meta/consul.io at part 1: couldn't find key "consul.io"`},
+		},
+	},
+	"Wildcards": {
+		map[string]interface{}{
+			"services": map[string]testNestedLevel2_2{
+				"web": {X: 80, Y: 1},
+				"db":  {X: 5432, Y: 2},
+			},
+			"matrix": [][]int{{1, 2}, {3, 4}},
+			"empty":  map[string]testNestedLevel2_2{},
+			"scalar": 3,
+			"meta":   map[string]string{"*": "star", "a": "other"},
+		},
+		[]expressionCheck{
+			{expression: "services.*.X == 80", result: true, benchQuick: true},
+			{expression: "services[*].X == 443", result: false},
+			{expression: "services.*.Y > 1", result: true},
+			{expression: "matrix.*.* == 4", result: true},
+			{expression: "matrix[*][last] == 3", result: false},
+			{expression: "matrix.*[0] == 3", result: true},
+			{expression: "empty.*.X == 80", result: false},
+			{expression: "services.*.Z == 80", result: false, err: `error finding value in datum: /services/db/Z at part 2: couldn't find struct field with name "Z"`},
+			{expression: "scalar.* == 3", result: false, err: `error finding value in datum: /scalar at part 1: wildcard cannot be used with value kind: int`},
+			{expression: "missing.* == 3", result: false, err: `error finding value in datum: /missing at part 0: couldn't find key "missing"`},
+			// a key which is "*" is expanded once and only selected by quoting it
+			{expression: `meta.* == "other"`, result: true},
+			{expression: `meta.* == "none"`, result: false},
+			{expression: `meta["*"] == "star"`, result: true},
+			{expression: `meta["*"] == "other"`, result: false},
+		},
+	},
+	"Quantifiers": {
+		map[string]interface{}{
+			"checks": []testNestedLevel2_2{{X: 1, Y: 2}, {X: 1, Y: 3}},
+			"tags":   []string{"web", "prod"},
+			"limit":  2,
+			"nodes": map[string][]string{
+				"a": {"web"},
+				"b": {"db", "web"},
+			},
+			"empty": []int{},
+		},
+		[]expressionCheck{
+			{expression: "len(checks) == 2", result: true, benchQuick: true},
+			{expression: "len(tags) > 2", result: false},
+			{expression: "count(nodes) >= 2", result: true},
+			{expression: "len(nodes.b) != 1", result: true},
+			{expression: "len(tags[0]) == 3", result: true},
+			{expression: "len(empty) < 1", result: true},
+			{expression: "all(nodes, n -> len(n) > 0)", result: true},
+			{expression: "len(limit) == 1", result: false, err: `Cannot perform len operations on type int for selector: "limit"`},
+			{expression: "all(checks, c -> c.X == 1)", result: true, benchQuick: true},
+			{expression: "all(checks, c -> c.Y == 2)", result: false},
+			{expression: "any(checks, c -> c.Y == 3)", result: true, benchQuick: true},
+			{expression: "any(checks, c -> c.Y > 3)", result: false},
+			{expression: "not all(tags, t -> t != db)", result: false},
+			{expression: "any(tags, tag -> tag == prod and limit == 2)", result: true},
+			{expression: "all(nodes, n -> any(n, tag -> tag == web))", result: true},
+			{expression: "all(nodes, n -> n[0] == web)", result: false},
+			{expression: "all(empty, e -> e == 1)", result: true},
+			{expression: "any(empty, e -> e == 1)", result: false},
+			{expression: "all(limit, l -> l == 2)", result: false, err: `Cannot perform all quantification on type int for selector: "limit"`},
+			{expression: "any(checks, c -> c.Z == 1)", result: false, err: `error finding value in datum: /Z at part 0: couldn't find struct field with name "Z"`},
+		},
+	},
+	"Durations": {
+		map[string]interface{}{
+			"ttl":   5 * time.Minute,
+			"nanos": int64(1500),
+			"count": uint(3),
+		},
+		[]expressionCheck{
+			{expression: "ttl == 5m", result: true, benchQuick: true},
+			{expression: `ttl > "2m30s"`, result: true},
+			{expression: "ttl between 1h and 2h", result: false},
+			{expression: "nanos == 1500", result: true},
+			{expression: "nanos < 1ms", result: false, err: `error getting match value in expression: strconv.ParseInt: parsing "1ms": invalid syntax`},
+			{expression: "ttl == 300000000000", result: true},
+			{expression: "count == 3s", result: false, err: `error getting match value in expression: strconv.ParseUint: parsing "3s": invalid syntax`},
+		},
+	},
+	"IP Addresses": {
+		map[string]interface{}{
+			"addr":   "10.1.2.3",
+			"ip":     net.ParseIP("192.168.0.10"),
+			"addrs":  []string{"172.16.0.1", "fd00::1"},
+			"ips":    []net.IP{net.ParseIP("8.8.8.8")},
+			"name":   "not-an-ip",
+			"port":   80,
+			"counts": []int{1},
+			"none":   nil,
+			"ptrs":   []*string{nil, &localhost},
+			"json":   []interface{}{"not-an-ip", nil, "10.4.5.6"},
+			"mixed":  []interface{}{"10.4.5.6", 1},
+		},
+		[]expressionCheck{
+			{expression: `addr in cidr "10.0.0.0/8"`, result: true, benchQuick: true},
+			{expression: `addr not in cidr "10.0.0.0/8"`, result: false},
+			{expression: `ip in cidr "192.168.0.0/24"`, result: true},
+			{expression: `ip in cidr "192.168.1.0/24"`, result: false},
+			{expression: `addrs in cidr "fd00::/8"`, result: true},
+			{expression: `ips in cidr "10.0.0.0/8"`, result: false},
+			{expression: `name in cidr "10.0.0.0/8"`, result: false},
+			{expression: `port in cidr "10.0.0.0/8"`, result: false, err: `Cannot perform in cidr operations on type int for selector: "port"`},
+			{expression: `counts in cidr "10.0.0.0/8"`, result: false, err: `Cannot perform in cidr operations on type []int for selector: "counts"`},
+			{expression: `none in cidr "10.0.0.0/8"`, result: false},
+			{expression: `none not in cidr "10.0.0.0/8"`, result: true},
+			{expression: `ptrs in cidr "127.0.0.0/8"`, result: true},
+			{expression: `json in cidr "10.0.0.0/8"`, result: true},
+			{expression: `json in cidr "192.168.0.0/16"`, result: false},
+			{expression: `mixed in cidr "192.168.0.0/16"`, result: false, err: `Cannot perform in cidr operations on type []interface {} for selector: "mixed"`},
+		},
+	},
+	"Arithmetic": {
+		map[string]interface{}{
+			"used":    int64(95),
+			"total":   uint(100),
+			"port":    8080,
+			"ratio":   0.5,
+			"ttl":     90 * time.Second,
+			"name":    "web",
+			"missing": nil,
+			"servers": []map[string]int{{"cpu": 3}, {"cpu": 9}},
+			"bytes":   int64(math.MaxInt64 / 100),
+			"max":     int64(math.MaxInt64),
+			"min":     int64(math.MinInt64),
+		},
+		[]expressionCheck{
+			{expression: "used / total > 0.9", result: true, benchQuick: true},
+			{expression: "used / total > 0.96", result: false},
+			{expression: "port + 1000 == 9080", result: true},
+			{expression: "9080 == port + 1000", result: true},
+			{expression: "total - used * 2 == -90", result: true},
+			{expression: "(total - used) * 2 == 10", result: true},
+			{expression: "used % 10 == 5", result: true},
+			{expression: "ratio * 2 == 1", result: true},
+			{expression: "port / 80 >= 101", result: true},
+			{expression: "ttl - 30s == 1m", result: true},
+			{expression: "port + 1 != 8081", result: false},
+			{expression: "servers[-1].cpu - servers[0].cpu == 6", result: true},
+			{expression: "all(servers, s -> s.cpu * 2 < 20)", result: true},
+			{expression: "bytes * 10 > port", result: true},
+			{expression: "max + min == -1", result: true},
+			{expression: "bytes * 1000 > port", result: false, err: "integer overflow in 92233720368547758 * 1000"},
+			{expression: "bytes * -1000 < port", result: false, err: "integer overflow in 92233720368547758 * -1000"},
+			{expression: "max + 1 > max", result: false, err: "integer overflow in 9223372036854775807 + 1"},
+			{expression: "max - -1 > max", result: false, err: "integer overflow in 9223372036854775807 - -1"},
+			{expression: "min - 1 < min", result: false, err: "integer overflow in -9223372036854775808 - 1"},
+			{expression: "min * -1 > 0", result: false, err: "integer overflow in -9223372036854775808 * -1"},
+			{expression: "-1 * min > 0", result: false, err: "integer overflow in -1 * -9223372036854775808"},
+			{expression: "max * 1.5 > max", result: true},
+			{expression: "used / 0 > 1", result: false, err: "division by zero"},
+			{expression: "ratio % 2 == 1", result: false, err: "Cannot perform modulo operations on floating point values"},
+			{expression: "name + 1 == 2", result: false, err: `Cannot perform arithmetic operations on type string for selector: "name"`},
+			{expression: "missing + 1 == 2", result: false, err: `Cannot perform arithmetic operations on a nil value for selector: "missing"`},
+			{expression: "absent + 1 == 2", result: false, err: `error finding value in datum: /absent at part 0: couldn't find key "absent"`},
+		},
+	},
+	"Scalar Map Keys": {
+		map[string]interface{}{
+			"bools":  map[bool]string{true: "yes", false: "no"},
+			"floats": map[float64]string{1.5: "one and a half", 2: "two"},
+			"ints":   map[int]string{-1: "negative", 3: "three"},
+			"uints":  map[uint8]string{255: "max"},
+			"shards": map[CustomInt]struct{ State string }{3: {State: "ready"}},
+		},
+		[]expressionCheck{
+			{expression: "bools.true == yes", result: true},
+			{expression: "bools.false == no", result: true},
+			{expression: "bools.abc == no", result: false, err: `error finding value in datum: /bools/abc at part 1: couldn't convert value "abc" to type bool`},
+			{expression: `floats["1.5"] == "one and a half"`, result: true},
+			{expression: "floats.2 == two", result: true},
+			{expression: "floats.3 == three", result: false, err: `error finding value in datum: /floats/3 at part 1: couldn't find key 3`},
+			{expression: "floats.abc == two", result: false, err: `error finding value in datum: /floats/abc at part 1: couldn't convert value "abc" to type float64`},
+			{expression: `ints["-1"] == negative`, result: true},
+			{expression: "ints.3 == three", result: true},
+			{expression: "ints.abc == three", result: false, err: `error finding value in datum: /ints/abc at part 1: couldn't convert value "abc" to type int`},
+			{expression: "uints.255 == max", result: true},
+			{expression: "uints.256 == max", result: false, err: `error finding value in datum: /uints/256 at part 1: couldn't convert value "256" to type uint8`},
+			{expression: `shards.3.State == "ready"`, result: true},
+			{expression: `shards["0x3"].State == "ready"`, result: true},
+			{expression: `shards.*.State == "ready"`, result: true},
+			{expression: `shards contains 3`, result: true},
+		},
+	},
 	"Nested Structs and Maps": {
 		testNestedTypes{
 			Nested: testNestedLevel1{
@@ -253,6 +611,7 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 		},
 		[]expressionCheck{
 			{expression: "Nested.Map.foo == bar", result: true, benchQuick: true},
+			{expression: "Nested.Map.foo like `b*`", result: true},
 			{expression: "Nested.Map.foo contains ba", result: true, benchQuick: true},
 			{expression: "Nested.Map.foo == baz", result: false},
 			{expression: "Nested.Map is not empty", result: true},
@@ -264,6 +623,8 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "TopInt != 0", result: true},
 			{expression: "Nested.Map contains nope or (Nested.Map contains bar and Nested.Map.bar == `bazel`) or TopInt != 0", result: true, benchQuick: true},
 			{expression: "Nested.MapOfStructs.one.Foo == 42", result: true},
+			{expression: "Nested { Map.foo == bar and MapOfStructs.two { Foo == 77 and Baz == consul } }", result: true},
+			{expression: "Nested.MapOfStructs.two { Foo == 77 and Baz != consul }", result: false},
 			{expression: "7 in Nested.SliceOfInts", result: true},
 			{expression: "Nested.MapOfStructs is empty or (Nested.SliceOfInts contains 7 and 9 in Nested.SliceOfInts)", result: true, benchQuick: true},
 			{expression: "Nested.SliceOfStructs.0.X == 1", result: true},
@@ -272,6 +633,31 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "Map in Nested", result: false, err: "Cannot perform in/contains operations on type struct for selector: \"Nested\""},
 		},
 	},
+	"Defined Types In Collections": {
+		map[string]interface{}{
+			"strings": []CustomString{"web", "Db"},
+			"ports":   [2]CustomUint16{80, 443},
+			"byName":  map[CustomString]CustomInt{"web": 1},
+			"byPort":  map[CustomUint16]CustomBool{8080: true},
+			"status":  func() *CustomString { s := CustomString("up"); return &s }(),
+		},
+		[]expressionCheck{
+			{expression: "strings contains web", result: true},
+			{expression: "strings contains db", result: false},
+			{expression: "strings contains any [db, web]", result: true},
+			{expression: "ports contains 443", result: true, benchQuick: true},
+			{expression: "ports contains all [80, 443]", result: true},
+			{expression: "80 in ports", result: true},
+			{expression: "byName contains web", result: true},
+			{expression: "byName.web == 1", result: true},
+			{expression: "byPort contains 8080", result: true},
+			{expression: "byPort.8080 == true", result: true},
+			{expression: "status == up", result: true},
+			{expression: "status in [down, up]", result: true},
+			{expression: `status matches "^u"`, result: true},
+			{expression: "ports contains http", result: false, err: `error getting match value in expression: strconv.ParseUint: parsing "http": invalid syntax`},
+		},
+	},
 }
 
 func TestEvaluate(t *testing.T) {
@@ -306,6 +692,1860 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+func TestEvaluate_InexactNumbers(t *testing.T) {
+	t.Parallel()
+
+	type sizes struct {
+		Int    int
+		Int8   int8
+		Min    int64
+		Uint   uint
+		Uint8  uint8
+		Max    uint64
+		Counts map[int]string
+	}
+	value := sizes{Int: 5, Int8: -100, Min: math.MinInt64, Uint: 5, Uint8: 200, Max: math.MaxUint64, Counts: map[int]string{1: "one"}}
+
+	for expression, expected := range map[string]bool{
+		`Int == 5.5`:                  false,
+		`Int != 5.5`:                  true,
+		`Int < 5.5`:                   true,
+		`Int > 4.5`:                   true,
+		`Int >= 5.5`:                  false,
+		`Int between 4.5 and 5.5`:     true,
+		`Int in [4.5, 5.5]`:           false,
+		`Int8 > -100.5`:               true,
+		`Int8 < 1e30`:                 true,
+		`Int8 > -1e30`:                true,
+		`Min > -1e19`:                 true,
+		`Min < 9223372036854775808`:   true,
+		`Uint > -1`:                   true,
+		`Uint == -1`:                  false,
+		`Uint <= 4.99`:                false,
+		`Uint8 between -5 and 200.5`:  true,
+		`Max < 18446744073709551616`:  true,
+		`Max == 18446744073709551615`: true,
+		`Max > 1e400`:                 false,
+		`1.5 in Counts`:               false,
+		`1 in Counts`:                 true,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+		require.Empty(t, Validate(expression, sizes{}), expression)
+	}
+
+	// values that are not numbers still fail
+	expr, err := CreateEvaluator(`Int < NaN`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	require.Error(t, err)
+}
+
+func TestEvaluate_Characters(t *testing.T) {
+	t.Parallel()
+
+	type format struct {
+		Delim  rune
+		Quote  byte
+		Escape *rune
+		Marks  []rune
+		Digit  rune
+	}
+	escape := '\\'
+	value := format{Delim: ',', Quote: '"', Escape: &escape, Marks: []rune("→✓"), Digit: '7'}
+
+	for expression, expected := range map[string]bool{
+		`Delim == ","`:              true,
+		`Delim != ";"`:              true,
+		`Delim == 44`:               true,
+		`Delim in [";", ","]`:       true,
+		`Delim in @delims`:          true,
+		`Delim between " " and "/"`: true,
+		"Quote == `\"`":             true,
+		"Escape == `\\`":            true,
+		`"✓" in Marks`:              true,
+		`Marks contains any ["x"]`:  false,
+		// unquoted digits are numbers while quoted ones are characters
+		`Digit == 7`:               false,
+		`Digit == 55`:              true,
+		`Digit == "7"`:             true,
+		`Digit in ["7"]`:           true,
+		`Digit in [7]`:             false,
+		`Digit between 50 and "8"`: true,
+		`Delim == "5"`:             false,
+	} {
+		expr, err := CreateEvaluator(expression, WithValueSet("delims", []string{";", ","}))
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	// bytes only hold ASCII characters and values of one character
+	require.Len(t, Validate(`Quote == "é"`, format{}), 1)
+	require.Len(t, Validate(`Delim == ",;"`, format{}), 1)
+	require.Len(t, Validate(`Delim == x`, format{}), 1)
+
+	// values given in code are strings as quoted values are
+	expr, err := CreateEvaluatorFromExpr(Match("Digit").Equals("7").And(Match("Delim").In(",", ";")))
+	require.NoError(t, err)
+	match, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+	expr, err = CreateEvaluator("Digit == $digit")
+	require.NoError(t, err)
+	expr, err = expr.Bind(map[string]interface{}{"digit": "7"})
+	require.NoError(t, err)
+	match, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+}
+
+func TestEvaluate_DefaultValues(t *testing.T) {
+	t.Parallel()
+
+	type testPointers struct {
+		Nested *testNestedLevel2_1
+		Int    *int
+		Map    map[string]string
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	value := testPointers{Map: map[string]string{"foo": "bar"}}
+
+	tests := map[string]testCase{
+		"Missing Map Key": {
+			expression: "Map.env == prod",
+			opts:       []Option{WithDefaultValue("Map.env", "prod")},
+			result:     true,
+		},
+		"Missing Map Key JSON Pointer": {
+			expression: "Map.env != prod",
+			opts:       []Option{WithDefaultValue(`"/Map/env"`, "dev")},
+			result:     true,
+		},
+		"Present Map Key": {
+			expression: "Map.foo == bar",
+			opts:       []Option{WithDefaultValue("Map.foo", "baz")},
+			result:     true,
+		},
+		"Nil Pointer": {
+			expression: "Int > 3",
+			opts:       []Option{WithDefaultValue("Int", 5)},
+			result:     true,
+		},
+		"Nil Intermediate Pointer": {
+			expression: "Nested.Foo == 42",
+			opts:       []Option{WithDefaultValue("Nested.Foo", 42)},
+			result:     true,
+		},
+		"No Default": {
+			expression: "Map.env == prod",
+			opts:       []Option{WithDefaultValue("Map.other", "prod")},
+			err:        `error finding value in datum: /Map/env at part 1: couldn't find key "env"`,
+		},
+		"Not Missing": {
+			expression: "Map.foo.bar == prod",
+			opts:       []Option{WithDefaultValue("Map.foo.bar", "prod")},
+			err:        `error finding value in datum: /Map/foo/bar: at part 2, invalid value kind: string`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tcase.result, match)
+		})
+	}
+}
+
+func TestEvaluate_MissingResult(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"Meta": map[string]string{"env": "prod"},
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"Not Equal":       {expression: "Meta.team != web", opts: []Option{WithMissingResult(true, grammar.MatchNotEqual)}, result: true},
+		"Other Operator":  {expression: "Meta.team == web", opts: []Option{WithMissingResult(true, grammar.MatchNotEqual)}, err: `error finding value in datum: /Meta/team at part 1: couldn't find key "team"`},
+		"All Operators":   {expression: "Meta.team == web or Meta.team matches `^w`", opts: []Option{WithMissingResult(false)}, result: false},
+		"Override":        {expression: "Meta.team != web", opts: []Option{WithMissingResult(false), WithMissingResult(true, grammar.MatchNotEqual)}, result: true},
+		"Is Null":         {expression: "Meta.team is null", opts: []Option{WithMissingResult(false, grammar.MatchIsNull)}, result: false},
+		"Present":         {expression: "Meta.env == prod", opts: []Option{WithMissingResult(false)}, result: true},
+		"Default Value":   {expression: "Meta.team == web", opts: []Option{WithMissingResult(false), WithDefaultValue("Meta.team", "web")}, result: true},
+		"Not Missing":     {expression: "Meta.env.x == y", opts: []Option{WithMissingResult(true)}, err: `error finding value in datum: /Meta/env/x: at part 2, invalid value kind: string`},
+		"Default Is Null": {expression: "Meta.team is null", result: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+}
+
+type testNode struct {
+	Name string
+	Next *testNode
+	Tags []string
+}
+
+// testChain returns a chain of nodes of the given length, with each node
+// named by its depth
+func testChain(length int) *testNode {
+	var head *testNode
+	for i := length - 1; i >= 0; i-- {
+		head = &testNode{Name: strconv.Itoa(i), Next: head, Tags: []string{"t" + strconv.Itoa(i)}}
+	}
+	return head
+}
+
+func TestEvaluate_NilTraversal(t *testing.T) {
+	t.Parallel()
+
+	modes := map[string]NilTraversal{"Error": NilTraversalError, "False": NilTraversalFalse, "Negated": NilTraversalNegated}
+	for name, mode := range modes {
+		mode := mode
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for depth := 1; depth <= 6; depth++ {
+				selector := strings.Repeat("Next.", depth)
+				for length := 1; length <= 6; length++ {
+					chain := testChain(length)
+					// the node at the depth only exists within longer chains
+					exists := length > depth
+					for expression, negated := range map[string]bool{
+						selector + "Name == " + strconv.Itoa(depth):        false,
+						selector + "Name != " + strconv.Itoa(depth):        true,
+						selector + "Tags contains t" + strconv.Itoa(depth): false,
+						"len(" + selector + "Tags) == 1":                   false,
+					} {
+						expr, err := CreateEvaluator(expression, WithNilTraversal(mode))
+						require.NoError(t, err)
+						result, err := expr.Evaluate(chain)
+						switch {
+						case exists:
+							require.NoError(t, err, expression)
+							require.Equal(t, !negated, result, expression)
+						case mode == NilTraversalError:
+							require.True(t, errors.Is(err, ErrNilValue), expression)
+						default:
+							require.NoError(t, err, expression)
+							require.Equal(t, negated && mode == NilTraversalNegated, result, expression)
+						}
+					}
+
+					// values behind nil pointers are null in every mode
+					expr, err := CreateEvaluator(selector+"Name is null", WithNilTraversal(mode))
+					require.NoError(t, err)
+					result, err := expr.Evaluate(chain)
+					require.NoError(t, err)
+					require.Equal(t, !exists, result)
+				}
+			}
+		})
+	}
+
+	// missing results take precedence
+	expr, err := CreateEvaluator("Next.Next.Name == x", WithNilTraversal(NilTraversalFalse), WithMissingResult(true))
+	require.NoError(t, err)
+	result, err := expr.Evaluate(testChain(1))
+	require.NoError(t, err)
+	require.True(t, result)
+}
+
+func TestEvaluate_CaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"name":   "Web-01",
+		"tags":   []string{"Prod", "East"},
+		"labels": map[string]string{"Env": "prod"},
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+	}
+
+	tests := map[string]testCase{
+		"Equal":                {expression: "name == `web-01`", opts: []Option{WithCaseInsensitive("name")}, result: true},
+		"Not Equal":            {expression: "name != `WEB-01`", opts: []Option{WithCaseInsensitive("name")}, result: false},
+		"Other Selector":       {expression: "name == `web-01`", opts: []Option{WithCaseInsensitive("tags")}, result: false},
+		"Contains Substring":   {expression: "name contains `WEB`", opts: []Option{WithCaseInsensitive("name")}, result: true},
+		"Slice Contains":       {expression: "tags contains prod", opts: []Option{WithCaseInsensitive("tags")}, result: true},
+		"Slice Not Contains":   {expression: "tags not contains west", opts: []Option{WithCaseInsensitive("tags")}, result: true},
+		"Map Key In":           {expression: "env in labels", opts: []Option{WithCaseInsensitive("labels")}, result: true},
+		"Map Key In Sensitive": {expression: "env in labels", result: false},
+		"JSON Pointer":         {expression: "labels.Env == PROD", opts: []Option{WithCaseInsensitive(`"/labels/Env"`)}, result: true},
+		"Fold Operator":        {expression: "name ==i `WEB-01`", result: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+
+	_, err := CreateEvaluator("name == foo", WithCaseInsensitive("name."))
+	require.Error(t, err)
+}
+
+func TestEvaluate_TagOperators(t *testing.T) {
+	t.Parallel()
+
+	type check struct {
+		Status string   `bexpr:"status,ops=eq|ne|inset"`
+		Tags   []string `bexpr:",ops=in|notin|isempty"`
+		Port   int      `bexpr:"port,ops=lt|le|gt|ge|between"`
+		Bad    string   `bexpr:"bad,ops=eq|approx"`
+		Notes  string
+	}
+
+	value := check{Status: "passing", Tags: []string{"web"}, Port: 8080}
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"Allowed":            {expression: "status == passing and web in Tags and port between 80 and 9000", result: true},
+		"Allowed Not":        {expression: "status in [passing, warning] and api not in Tags", result: true},
+		"Length":             {expression: "len(Tags) == 1", result: true},
+		"Untagged":           {expression: "Notes matches `^$`", result: true},
+		"Not Allowed":        {expression: "status matches pass", err: `match operator "Matches" is not supported for selector: "status"`},
+		"Length Not Allowed": {expression: "len(port) > 1", err: `match operator "Greater Than" is not supported for the length of selector: "port"`},
+		"Equality":           {expression: "port == 8080", err: `match operator "Equal" is not supported for selector: "port"`},
+		"Invalid Tag":        {expression: "bad == x", err: `error finding value in datum: /bad at part 0: invalid operator "approx" in the bexpr tag of field Bad`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+
+	// the operators are known up front from the type
+	errs := Validate("status matches pass and port > 1", check{})
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], `match operator "Matches" is not supported for selector: "status"`)
+
+	var listing []string
+	for _, field := range Fields(check{}) {
+		listing = append(listing, field.String())
+	}
+	require.Equal(t, []string{
+		"/status: ==, !=, in",
+		"/Tags: contains, not contains, is empty",
+		"/Tags/<any>: ==, !=, contains, not contains, is empty, is not empty, matches, not matches, <, <=, >, >=, in, not in, " +
+			"like, not like, starts with, not starts with, ends with, not ends with, between, not between, ==i, !=i, " +
+			"in cidr, not in cidr, contains any, not contains any, contains all, not contains all",
+		"/port: <, <=, >, >=, between",
+		"/Notes: ==, !=, contains, not contains, is empty, is not empty, matches, not matches, <, <=, >, >=, in, not in, " +
+			"like, not like, starts with, not starts with, ends with, not ends with, between, not between, ==i, !=i, " +
+			"in cidr, not in cidr, contains any, not contains any, contains all, not contains all",
+	}, listing)
+}
+
+func TestEvaluate_TagFold(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name string            `bexpr:"name,fold"`
+		Tags []string          `bexpr:"tags,fold,ops=in|notin"`
+		Meta map[string]string `bexpr:",fold"`
+		ID   string
+	}
+
+	value := service{Name: "Web", Tags: []string{"Primary"}, Meta: map[string]string{"Env": "prod"}, ID: "Web-1"}
+
+	tests := map[string]bool{
+		"name == web":             true,
+		"name != WEB":             false,
+		"primary in tags":         true,
+		"PRIMARY not in tags":     false,
+		"Meta contains env":       true,
+		"ID == `web-1`":           false,
+		"name matches `^web$`":    false,
+		"name starts with `W`":    true,
+		"name in [web, api]":      false,
+		"ID ==i `web-1`":          true,
+		"Meta.Env == prod":        true,
+		"name == web and ID == x": false,
+	}
+
+	for expression, result := range tests {
+		expression, result := expression, result
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(expression)
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			require.NoError(t, err)
+			require.Equal(t, result, match)
+		})
+	}
+}
+
+func TestEvaluate_JSONTagNames(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Name     string            `json:"name"`
+		Address  string            `json:"address,omitempty"`
+		Meta     map[string]string `json:",omitempty"`
+		Status   string            `json:"status" bexpr:"health"`
+		Token    string            `json:"-"`
+		Internal string            `json:"-" bexpr:"internal"`
+	}
+
+	value := node{Name: "web", Address: "10.0.0.1", Meta: map[string]string{"env": "prod"}, Status: "passing", Token: "x", Internal: "y"}
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"Tag Name":        {expression: "name == web and address == `10.0.0.1`", result: true},
+		"Go Name":         {expression: "Meta.env == prod", result: true},
+		"Bexpr Tag":       {expression: "health == passing", result: true},
+		"Ignored":         {expression: "Token == x", err: `error finding value in datum: /Token at part 0: struct field "Token" is ignored and cannot be used`},
+		"Bexpr Overrides": {expression: "internal == y", result: true},
+		"Not Go Name":     {expression: "Name == web", err: `error finding value in datum: /Name at part 0: couldn't find struct field with name "Name", did you mean "name"?`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression, WithJSONTagNames())
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+
+	// without the option the Go names are used
+	expr, err := CreateEvaluator("Name == web and Token == x")
+	require.NoError(t, err)
+	match, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	var paths []string
+	for _, field := range Fields(node{}, WithJSONTagNames()) {
+		paths = append(paths, pointerKey(field.Path))
+	}
+	require.Equal(t, []string{"/name", "/address", "/Meta", "/Meta/<any>", "/health", "/internal"}, paths)
+}
+
+func TestEvaluate_MapstructureTagNames(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Region  string `mapstructure:"region"`
+		Port    int    `mapstructure:"listen_port" json:"port"`
+		Enabled bool   `json:"enabled"`
+		Secret  string `mapstructure:"-"`
+	}
+
+	value := config{Region: "eu", Port: 8080, Enabled: true}
+
+	tests := map[string][]Option{
+		"region == eu and listen_port == 8080 and Enabled":  {WithMapstructureTagNames()},
+		"region == eu and listen_port == 8080 and enabled":  {WithMapstructureTagNames(), WithJSONTagNames()},
+		"region == eu and port == 8080 and enabled":         {WithJSONTagNames(), WithMapstructureTagNames()},
+		"Region == eu and Port == 8080 and Secret is empty": nil,
+	}
+
+	for expression, opts := range tests {
+		expression, opts := expression, opts
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(expression, opts...)
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			require.NoError(t, err)
+			require.True(t, match)
+		})
+	}
+
+	errs := Validate("Secret == x", config{}, WithMapstructureTagNames())
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], `invalid selector "Secret": struct field "Secret" is ignored and cannot be used`)
+}
+
+// the types below are laid out as protoc-gen-go generates them
+type protoTimestamp struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	Seconds int64 `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	Nanos   int32 `protobuf:"varint,2,opt,name=nanos,proto3" json:"nanos,omitempty"`
+}
+
+func (x *protoTimestamp) AsTime() time.Time {
+	return time.Unix(x.Seconds, int64(x.Nanos)).UTC()
+}
+
+func (x *protoTimestamp) CheckValid() error {
+	return nil
+}
+
+type protoDuration struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	Seconds int64 `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	Nanos   int32 `protobuf:"varint,2,opt,name=nanos,proto3" json:"nanos,omitempty"`
+}
+
+func (x *protoDuration) AsDuration() time.Duration {
+	return time.Duration(x.Seconds)*time.Second + time.Duration(x.Nanos)
+}
+
+func (x *protoDuration) CheckValid() error {
+	return nil
+}
+
+type protoService struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	DisplayName string            `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Tags        []string          `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	Labels      map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CreateTime  *protoTimestamp   `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime  *protoTimestamp   `protobuf:"bytes,6,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	Timeout     *protoDuration    `protobuf:"bytes,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Types that are assignable to Endpoint:
+	//
+	//	*protoService_Address
+	Endpoint isProtoService_Endpoint `protobuf_oneof:"endpoint"`
+
+	XXX_unrecognized []byte `json:"-"`
+}
+
+type isProtoService_Endpoint interface {
+	isProtoService_Endpoint()
+}
+
+type protoService_Address struct {
+	Address string `protobuf:"bytes,5,opt,name=address,proto3,oneof"`
+}
+
+func (*protoService_Address) isProtoService_Endpoint() {}
+
+func TestEvaluate_ProtobufNames(t *testing.T) {
+	t.Parallel()
+
+	value := &protoService{
+		DisplayName: "web",
+		Tags:        []string{"a"},
+		Labels:      map[string]string{"env": "prod"},
+		CreateTime:  &protoTimestamp{Seconds: 1700000001},
+		Timeout:     &protoDuration{Seconds: 90, Nanos: 500},
+		Endpoint:    &protoService_Address{Address: "10.0.0.1"},
+	}
+
+	tests := map[string][]Option{
+		"display_name == web and tags contains a and labels.env == prod and create_time.seconds > 1700000000 and endpoint.address == `10.0.0.1`":                 {WithProtobufTagNames()},
+		"displayName == web and tags contains a and labels.env == prod and createTime.seconds > 1700000000 and endpoint.address == `10.0.0.1`":                   {WithProtobufJSONNames()},
+		`create_time > "2023-01-01T00:00:00Z" and create_time == "2023-11-14T22:13:21Z" and update_time is null and timeout == "1m30.0000005s" and timeout > 1m`: {WithProtobufTagNames()},
+		`createTime between "2023-11-14" and "2023-11-15" and timeout < 2m and updateTime is null`:                                                               {WithProtobufJSONNames()},
+	}
+
+	for expression, opts := range tests {
+		expression, opts := expression, opts
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(expression, opts...)
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			require.NoError(t, err)
+			require.True(t, match)
+		})
+	}
+
+	var paths []string
+	for _, field := range Fields(protoService{}, WithProtobufJSONNames()) {
+		paths = append(paths, pointerKey(field.Path))
+	}
+	require.Equal(t, []string{"/displayName", "/tags", "/tags/<any>", "/labels", "/labels/<any>", "/createTime", "/createTime/seconds", "/createTime/nanos", "/updateTime", "/updateTime/seconds", "/updateTime/nanos", "/timeout", "/timeout/seconds", "/timeout/nanos", "/endpoint"}, paths)
+}
+
+type recordingHook struct {
+	started []string
+	events  []MatchEvent
+}
+
+func (h *recordingHook) OnMatchStart(selector grammar.Selector, operator grammar.MatchOperator) {
+	h.started = append(h.started, selector.String()+" "+operator.String())
+}
+
+func (h *recordingHook) OnMatchEnd(event MatchEvent) {
+	h.events = append(h.events, event)
+}
+
+// Metadata, Timestamps and testAudit are embedded by TestEvaluate_EmbeddedStructs
+type Metadata struct {
+	ID      string
+	Version int
+}
+
+type testAudit struct {
+	Version int
+	Owner   string
+}
+
+type Timestamps struct {
+	Created int
+}
+
+func TestEvaluate_EmbeddedStructs(t *testing.T) {
+	t.Parallel()
+
+	type resource struct {
+		Metadata
+		*testAudit
+		Timestamps `json:"timestamps"`
+		Name       string
+		ID         int
+	}
+	value := resource{
+		Metadata:   Metadata{ID: "r-1", Version: 2},
+		testAudit:  &testAudit{Version: 3, Owner: "ops"},
+		Timestamps: Timestamps{Created: 100},
+		Name:       "web",
+		ID:         7,
+	}
+
+	for expression, expected := range map[string]bool{
+		// fields of the outer struct hide promoted ones
+		`ID == 7`:               true,
+		`Metadata.ID == "r-1"`:  true,
+		`Owner == ops`:          true,
+		`Metadata.Version == 2`: true,
+		`Created == 100`:        true,
+		`Name == web`:           true,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	// Version is ambiguous as it is promoted from two structs
+	expr, err := CreateEvaluator(`Version == 2`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	var selectorErr *UnknownSelectorError
+	require.True(t, errors.As(err, &selectorErr))
+
+	// a nil embedded pointer makes the fields promoted through it null
+	expr, err = CreateEvaluator(`Owner is null`)
+	require.NoError(t, err)
+	result, err := expr.Evaluate(resource{})
+	require.NoError(t, err)
+	require.True(t, result)
+
+	// structs named by a tag are not promoted, as with encoding/json
+	expr, err = CreateEvaluator(`timestamps.Created == 100`, WithJSONTagNames())
+	require.NoError(t, err)
+	result, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, result)
+	require.Len(t, Validate(`Created == 100`, resource{}, WithJSONTagNames()), 1)
+	require.Empty(t, Validate(`Owner == ops and Metadata.ID == "r-1"`, resource{}))
+	// as in Go, embedded structs of unexported types are not selectable
+	require.Len(t, Validate(`testAudit.Owner == ops`, resource{}), 1)
+}
+
+func TestEvaluate_Hooks(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{"Name": "web", "Port": 80}
+
+	hook := new(recordingHook)
+	expr, err := CreateEvaluator("Name == web and (Port > 80 or Name matches `^w`) and Missing == x", WithHook(hook))
+	require.NoError(t, err)
+
+	_, err = expr.Evaluate(value)
+	require.Error(t, err)
+	require.Equal(t, []string{"Name Equal", "Port Greater Than", "Name Matches", "Missing Equal"}, hook.started)
+	require.Len(t, hook.events, 4)
+
+	results := make([]bool, 0, len(hook.events))
+	for _, event := range hook.events {
+		results = append(results, event.Result)
+	}
+	require.Equal(t, []bool{true, false, true, false}, results)
+	require.Equal(t, []string{"Port"}, hook.events[1].Selector.Path)
+	require.Equal(t, grammar.MatchGreaterThan, hook.events[1].Operator)
+	require.NoError(t, hook.events[0].Err)
+	require.Equal(t, err, hook.events[3].Err)
+}
+
+// lazyRecord loads the values of its fields on demand
+type lazyRecord struct {
+	loaded []string
+}
+
+func (r *lazyRecord) Resolve(path []string) (interface{}, bool, error) {
+	r.loaded = append(r.loaded, strings.Join(path, "."))
+	switch path[0] {
+	case "Name":
+		return "web", true, nil
+	case "Tags":
+		tags := []string{"a", "b"}
+		if len(path) == 1 {
+			return tags, true, nil
+		}
+		index, err := strconv.Atoi(path[1])
+		if err != nil || index < 0 || index >= len(tags) {
+			return nil, false, nil
+		}
+		return tags[index], true, nil
+	case "Broken":
+		return nil, false, fmt.Errorf("connection refused")
+	default:
+		return nil, false, nil
+	}
+}
+
+func TestEvaluate_DynamicTypes(t *testing.T) {
+	t.Parallel()
+
+	document := []byte(`{
+		"name": "web",
+		"port": 8080,
+		"tags": ["a", "b"],
+		"ports": [80, 443],
+		"mixed": [1, "a", true, null, {"x": 1}],
+		"meta": {"env": "prod", "replicas": 3}
+	}`)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(document, &decoded))
+
+	var numbers map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&numbers))
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+		dynamic    bool
+	}
+
+	tests := map[string]testCase{
+		"Number":               {expression: "port >= 8000 and meta.replicas == 3", result: true},
+		"Contains String":      {expression: "tags contains b", result: true},
+		"Contains Number":      {expression: "ports contains 443", result: true},
+		"Mixed Contains":       {expression: "mixed contains a and mixed contains 1 and mixed contains true", result: true},
+		"Mixed Not Contains":   {expression: "mixed contains z", result: false},
+		"In List":              {expression: "tags in [x, b] and ports in [443]", result: true},
+		"Mixed In List":        {expression: "mixed in [z, 1]", result: true},
+		"Float Literal":        {expression: "port == 8080.0 and 443.0 in ports", result: true},
+		"Arithmetic":           {expression: "port + meta.replicas == 8083", result: true},
+		"Mismatch":             {expression: "port == web", err: `parsing "web": invalid syntax`},
+		"Dynamic Mismatch":     {expression: "port == web", result: false, dynamic: true},
+		"Dynamic Negated":      {expression: "port != web and name not matches `^a`", result: true, dynamic: true},
+		"Operator Mismatch":    {expression: "meta.env == prod and port matches `^8`", err: `is not convertible to []byte`},
+		"Dynamic Operator":     {expression: "port matches `^8`", result: false, dynamic: true},
+		"Dynamic Still Errors": {expression: "missing == x", err: `error finding value in datum: /missing at part 0: couldn't find key "missing"`, dynamic: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []Option
+			if tcase.dynamic {
+				opts = append(opts, WithDynamicTypes())
+			}
+			expr, err := CreateEvaluator(tcase.expression, opts...)
+			require.NoError(t, err)
+
+			for _, datum := range []map[string]interface{}{decoded, numbers} {
+				result, err := expr.Evaluate(datum)
+				if tcase.err != "" {
+					// the types of numbers depend on how they were decoded
+					require.Error(t, err)
+					require.Contains(t, err.Error(), tcase.err)
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, tcase.result, result)
+			}
+		})
+	}
+}
+
+func TestEvaluate_JSONNumberFields(t *testing.T) {
+	t.Parallel()
+
+	type stats struct {
+		Count   json.Number
+		Ratio   *json.Number
+		Samples []json.Number
+	}
+	ratio := json.Number("0.5")
+	value := stats{Count: "10", Ratio: &ratio, Samples: []json.Number{"1", "2.5"}}
+
+	for expression, expected := range map[string]bool{
+		`Count == 10.0`:         true,
+		`Count > 9`:             true,
+		`Ratio < 1`:             true,
+		`2.5 in Samples`:        true,
+		`Samples contains 1.0`:  true,
+		`Samples in [3, 2.5]`:   true,
+		`Count * Ratio == 5`:    true,
+		`Count between 1 and 5`: false,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	require.Equal(t, "/Count: ==, !=, <, <=, >, >=, in, not in, between, not between", Fields(stats{})[0].String())
+}
+
+func TestEvaluate_InterfaceFields(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Meta  interface{}
+		Value interface{}
+		Ref   *interface{}
+		Unset *interface{}
+	}
+	var ref interface{} = 5
+	var unset interface{}
+	value := config{
+		Meta:  map[string]interface{}{"replicas": 3, "zones": []string{"a", "b"}},
+		Value: "web",
+		Ref:   &ref,
+		Unset: &unset,
+	}
+
+	for expression, expected := range map[string]bool{
+		`Meta.replicas == 3`:    true,
+		`Meta.zones contains b`: true,
+		`Value == web`:          true,
+		`Value matches "^w"`:    true,
+		`Ref == 5`:              true,
+		`Ref between 1 and 4`:   false,
+		`Unset is null`:         true,
+		`Ref is not null`:       true,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+
+		// the types of the values are only checked during evaluation
+		require.Empty(t, Validate(expression, config{}), expression)
+	}
+
+	expr, err := CreateEvaluator(`Ref == web`, WithDynamicTypes())
+	require.NoError(t, err)
+	result, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, result)
+}
+
+func TestEvaluate_FieldResolver(t *testing.T) {
+	t.Parallel()
+
+	record := new(lazyRecord)
+	expr, err := CreateEvaluator(`Name == web and Tags contains b and Tags.1 == b`)
+	require.NoError(t, err)
+	result, err := expr.Evaluate(record)
+	require.NoError(t, err)
+	require.True(t, result)
+	require.Equal(t, []string{"Name", "Tags", "Tags.1"}, record.loaded)
+
+	// resolvers can be reached through other values
+	result, err = expr.Evaluate(map[string]interface{}{"Record": record, "Name": "web", "Tags": []string{"b", "b"}})
+	require.NoError(t, err)
+	require.True(t, result)
+
+	expr, err = CreateEvaluator(`Record.Name == web`)
+	require.NoError(t, err)
+	result, err = expr.Evaluate(map[string]interface{}{"Record": ResolverFunc(func(path []string) (interface{}, bool, error) {
+		return "web", len(path) == 1 && path[0] == "Name", nil
+	})})
+	require.NoError(t, err)
+	require.True(t, result)
+
+	// values the resolver does not have are missing
+	expr, err = CreateEvaluator(`Missing == x`, WithDefaultValue("Missing", "x"))
+	require.NoError(t, err)
+	result, err = expr.Evaluate(record)
+	require.NoError(t, err)
+	require.True(t, result)
+
+	expr, err = CreateEvaluator(`Broken == x`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(record)
+	require.EqualError(t, err, "error finding value in datum: /Broken at part 0: connection refused")
+}
+
+func TestEvaluate_Records(t *testing.T) {
+	t.Parallel()
+
+	rows, err := csv.NewReader(strings.NewReader(`name,port,healthy,weight
+web,80,true,0.5
+db,5432,false,
+cache,6379,true,2
+`)).ReadAll()
+	require.NoError(t, err)
+
+	schema, err := NewRecordSchema(rows[0], map[string]reflect.Type{
+		"port":    reflect.TypeOf(uint16(0)),
+		"healthy": reflect.TypeOf(false),
+		"weight":  reflect.TypeOf(float64(0)),
+	})
+	require.NoError(t, err)
+
+	filter := func(expression string) []string {
+		t.Helper()
+		expr, err := CreateEvaluator(expression, WithFields(schema.Fields()...))
+		require.NoError(t, err)
+		var names []string
+		for _, row := range rows[1:] {
+			result, err := expr.Evaluate(schema.Record(row))
+			require.NoError(t, err)
+			if result {
+				names = append(names, row[0])
+			}
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"db", "cache"}, filter(`port > 1024`))
+	require.Equal(t, []string{"web", "cache"}, filter(`healthy and name != web or weight is not null and weight < 1`))
+	require.Equal(t, []string{"db"}, filter(`weight is null`))
+	require.Equal(t, []string{"web"}, filter(`name matches "^w"`))
+
+	// expressions are checked against the schema up front
+	_, err = CreateEvaluator(`address == x`, WithFields(schema.Fields()...))
+	require.Error(t, err)
+	_, err = CreateEvaluator(`port == http`, WithFields(schema.Fields()...))
+	require.Error(t, err)
+
+	expr, err := CreateEvaluator(`port == 80`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(schema.Record([]string{"web", "http", "true", ""}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid value "http" in column "port"`)
+
+	_, err = NewRecordSchema([]string{"a", "a"}, nil)
+	require.EqualError(t, err, `duplicate column "a"`)
+	_, err = NewRecordSchema([]string{"a"}, map[string]reflect.Type{"b": reflect.TypeOf(0)})
+	require.EqualError(t, err, `type given for column "b" which is not in the header`)
+	_, err = NewRecordSchema([]string{"a"}, map[string]reflect.Type{"a": reflect.TypeOf([]int{})})
+	require.EqualError(t, err, `column "a" cannot have type []int`)
+}
+
+func TestEvaluate_Truth(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Name   string
+		Port   *int
+		Tags   []string
+		Checks []map[string]string
+	}
+	port := 80
+	value := node{
+		Name:   "web",
+		Port:   nil,
+		Tags:   []string{"a"},
+		Checks: []map[string]string{{"Status": "passing"}, {}},
+	}
+
+	for expression, expected := range map[string]Truth{
+		`Name == web`:                                  TruthTrue,
+		`Name == db`:                                   TruthFalse,
+		`Port == 80`:                                   TruthUnknown,
+		`Port != 80`:                                   TruthUnknown,
+		`Port is null`:                                 TruthTrue,
+		`not Port > 80`:                                TruthUnknown,
+		`Name == db and Port == 80`:                    TruthFalse,
+		`Port == 80 and Name == db`:                    TruthFalse,
+		`Name == web and Port == 80`:                   TruthUnknown,
+		`Name == web or Port == 80`:                    TruthTrue,
+		`Port == 80 or Name == web`:                    TruthTrue,
+		`Name == db or Port == 80`:                     TruthUnknown,
+		`Name == web xor Port == 80`:                   TruthUnknown,
+		`Name == web xor Tags contains b`:              TruthTrue,
+		`any(Checks, c -> c.Status == passing)`:        TruthTrue,
+		`any(Checks, c -> c.Status == critical)`:       TruthUnknown,
+		`all(Checks, c -> c.Status == passing)`:        TruthUnknown,
+		`all(Checks, c -> c.Status == critical)`:       TruthFalse,
+		`all(Checks, c -> c.Status != critical)`:       TruthUnknown,
+		`Checks.1.Status == passing`:                   TruthUnknown,
+		`Checks.1.Status is empty or Name == db`:       TruthTrue,
+		`any(Checks, c -> c.Missing.Status == x)`:      TruthUnknown,
+		`not (Name == db or Checks.1.Status == x)`:     TruthUnknown,
+		`not (Name == web and Checks.0.Status == x)`:   TruthTrue,
+		`not (Name == db and Checks.1.Status == x)`:    TruthTrue,
+		`Tags contains a and not (Port < 1024)`:        TruthUnknown,
+		`Tags contains b or not (Port < 1024)`:         TruthUnknown,
+		`Tags contains a or Checks.1.Status == x`:      TruthTrue,
+		`Checks.1.Status == x and Tags contains b`:     TruthFalse,
+		`Checks.1.Status == x and not (Name == web)`:   TruthFalse,
+		`Checks.1.Status == x or not (Name == db)`:     TruthTrue,
+		`Checks.1.Status == x or Checks.0.Status == x`: TruthUnknown,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		truth, err := expr.EvaluateTruth(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, truth, "%s: %s", expression, truth)
+	}
+
+	value.Port = &port
+	expr, err := CreateEvaluator(`Name == web and Port == 80`)
+	require.NoError(t, err)
+	truth, err := expr.EvaluateTruth(value)
+	require.NoError(t, err)
+	require.Equal(t, TruthTrue, truth)
+
+	// defaults and missing results still apply
+	expr, err = CreateEvaluator(`Checks.1.Status == passing`, WithMissingResult(false))
+	require.NoError(t, err)
+	truth, err = expr.EvaluateTruth(value)
+	require.NoError(t, err)
+	require.Equal(t, TruthFalse, truth)
+
+	// other errors are still reported
+	expr, err = CreateEvaluator(`Missing == x`)
+	require.NoError(t, err)
+	_, err = expr.EvaluateTruth(value)
+	require.Error(t, err)
+}
+
+func TestEvaluate_MatchEvaluator(t *testing.T) {
+	t.Parallel()
+
+	type call struct {
+		selector []string
+		op       grammar.MatchOperator
+		values   []string
+	}
+	var calls []call
+	index := MatchEvaluatorFunc(func(ctx context.Context, selector []string, op grammar.MatchOperator, values []*grammar.MatchValue) (bool, error) {
+		c := call{selector: selector, op: op}
+		for _, value := range values {
+			c.values = append(c.values, value.Raw)
+		}
+		calls = append(calls, c)
+		switch op {
+		case grammar.MatchEqual, grammar.MatchInSet:
+			return ctx.Value(testContextKey{}) == nil, nil
+		case grammar.MatchMatches:
+			if _, ok := values[0].Converted.(*regexp.Regexp); !ok {
+				return false, errors.New("regular expression was not compiled")
+			}
+			return true, nil
+		}
+		return false, &UnsupportedOperatorError{Operator: op, Err: fmt.Errorf("operator %s is not supported", op)}
+	})
+	value := map[string]interface{}{"Name": "web", "Index": index}
+
+	expr, err := CreateEvaluator(`Name == web and Index.city == Berlin and Index matches "^B" and Index.city in [Berlin, Paris]`)
+	require.NoError(t, err)
+	result, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, result)
+	require.Equal(t, []call{
+		{selector: []string{"city"}, op: grammar.MatchEqual, values: []string{"Berlin"}},
+		{selector: nil, op: grammar.MatchMatches, values: []string{"^B"}},
+		{selector: []string{"city"}, op: grammar.MatchInSet, values: []string{"Berlin", "Paris"}},
+	}, calls)
+
+	expr, err = CreateEvaluator(`Index.city is empty`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	var operatorErr *UnsupportedOperatorError
+	require.True(t, errors.As(err, &operatorErr))
+
+	expr, err = CreateEvaluator(`Index.city is empty`, WithDynamicTypes())
+	require.NoError(t, err)
+	result, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, result)
+
+	// the context of the evaluation is passed on
+	expr, err = CreateEvaluator(`Index.city == Berlin`)
+	require.NoError(t, err)
+	result, err = expr.EvaluateContext(context.WithValue(context.Background(), testContextKey{}, true), value)
+	require.NoError(t, err)
+	require.False(t, result)
+}
+
+type testContextKey struct{}
+
+func TestEvaluate_Time(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		Created time.Time
+		Expires *time.Time
+		Name    string
+		Far     time.Time
+		Zero    time.Time
+	}
+	created := time.Date(2023, 6, 15, 12, 0, 0, 0, time.UTC)
+	far := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	value := event{Created: created, Expires: nil, Name: "deploy", Far: far}
+
+	for expression, expected := range map[string]bool{
+		`Created == "2023-06-15T12:00:00Z"`:             true,
+		`Created == "2023-06-15T14:00:00+02:00"`:        true,
+		`Created != "2023-06-15T12:00:00Z"`:             false,
+		`Created > "2023-06-15"`:                        true,
+		`Created < 1686830400`:                          false,
+		`Created <= 1686830400`:                         true,
+		`Created between "2023-01-01" and "2024-01-01"`: true,
+		`Created < now`:                                 true,
+		`Created > "now-1h"`:                            false,
+		`Created > now-1h`:                              false,
+		`Created < now+1h`:                              true,
+		`Created > now-87600h`:                          true,
+		`Created in ["2023-06-15T12:00:00Z"]`:           true,
+		`Expires is null`:                               true,
+		// times beyond the range of int64 nanoseconds compare exactly
+		`Far > "2020-01-01"`:                     true,
+		`Far == "3000-01-01T00:00:00Z"`:          true,
+		`Far < "3000-01-01T00:00:00.000000001Z"`: true,
+		`Far in ["3000-01-01T00:00:00Z"]`:        true,
+		`Zero < "1970-01-01"`:                    true,
+		`Zero < "1600-01-01"`:                    true,
+		`Zero == "0001-01-01T00:00:00Z"`:         true,
+		`Zero > "0001-01-01T00:00:00Z"`:          false,
+		`Zero not in ["1970-01-01T00:00:00Z"]`:   true,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	expires := created.Add(time.Hour)
+	value.Expires = &expires
+	expr, err := CreateEvaluator(`Expires > "2023-06-15T12:30:00Z"`)
+	require.NoError(t, err)
+	result, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, result)
+
+	// timestamps are checked up front and listed as fields
+	require.Len(t, Validate(`Created > yesterday`, event{}), 1)
+	require.Empty(t, Validate(`Created > "2023-06-15"`, event{}))
+	require.Equal(t, "/Created: ==, !=, <, <=, >, >=, in, not in, between, not between", Fields(event{})[0].String())
+}
+
+func TestEvaluate_DurationFields(t *testing.T) {
+	t.Parallel()
+
+	type job struct {
+		Timeout  time.Duration
+		Interval uint64 `bexpr:"interval,duration"`
+		Backoff  *int64 `bexpr:"backoff,duration"`
+		Retries  uint64
+		Attempts int64
+	}
+	backoff := int64(2 * time.Second)
+	value := job{Timeout: 90 * time.Minute, Interval: uint64(30 * time.Second), Backoff: &backoff, Retries: 3, Attempts: 1}
+
+	for expression, expected := range map[string]bool{
+		`Timeout == 1h30m`:               true,
+		`Timeout > "1h"`:                 true,
+		`Timeout between 1h and 2h`:      true,
+		`Timeout in [90m, 2h]`:           true,
+		`interval == 30s`:                true,
+		`interval < 1m`:                  true,
+		`interval == 30000000000`:        true,
+		`backoff >= 2s and backoff < 3s`: true,
+		`Timeout in @timeouts`:           true,
+		`backoff in @timeouts`:           false,
+	} {
+		expr, err := CreateEvaluator(expression, WithValueSet("timeouts", []string{"90m", "1s"}))
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	require.Empty(t, Validate(`interval > 1m and Timeout < 2h`, job{}))
+	require.Len(t, Validate(`interval > soon`, job{}), 1)
+	// only fields tagged as durations take them
+	require.Len(t, Validate(`Retries > 1m`, job{}), 1)
+	require.Len(t, Validate(`Attempts < 1s`, job{}), 1)
+	expr, err := CreateEvaluator(`Attempts < 1s`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	require.EqualError(t, err, `error getting match value in expression: strconv.ParseInt: parsing "1s": invalid syntax`)
+
+	type invalid struct {
+		Name string `bexpr:"name,duration"`
+	}
+	expr, err = CreateEvaluator(`name == 1s`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(invalid{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the duration option in the bexpr tag of field Name requires an integer field")
+
+	schema, err := JSONSchema(job{})
+	require.NoError(t, err)
+	var decoded struct {
+		Properties map[string]map[string]interface{}
+	}
+	require.NoError(t, json.Unmarshal(schema, &decoded))
+	require.Equal(t, "duration", decoded.Properties["Timeout"]["x-bexpr-format"])
+	require.Equal(t, "duration", decoded.Properties["interval"]["x-bexpr-format"])
+	require.Equal(t, "duration", decoded.Properties["backoff"]["x-bexpr-format"])
+	require.Nil(t, decoded.Properties["Retries"]["x-bexpr-format"])
+}
+
+type testUUID [16]byte
+
+func TestEvaluate_SQLNullTypes(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Name     sql.NullString
+		Age      sql.NullInt64
+		Active   sql.NullBool
+		Score    sql.NullFloat64
+		Joined   sql.NullTime
+		Manager  sql.NullString
+		Level    *sql.NullInt32
+		Nickname sql.NullString
+	}
+	value := row{
+		Name:     sql.NullString{String: "alice", Valid: true},
+		Age:      sql.NullInt64{Int64: 42, Valid: true},
+		Active:   sql.NullBool{Bool: true, Valid: true},
+		Score:    sql.NullFloat64{Float64: 9.5, Valid: true},
+		Joined:   sql.NullTime{Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+		Level:    &sql.NullInt32{Int32: 3, Valid: true},
+		Nickname: sql.NullString{String: "stale", Valid: false},
+	}
+
+	for expression, expected := range map[string]bool{
+		`Name == alice`:                   true,
+		`Name matches "^a"`:               true,
+		`Name is not null`:                true,
+		`Age > 40`:                        true,
+		`Age in [41, 42]`:                 true,
+		`Active == true`:                  true,
+		`Score between 9 and 10`:          true,
+		`Joined < "2021-01-01T00:00:00Z"`: true,
+		`Level == 3`:                      true,
+		`Manager is null`:                 true,
+		`Nickname is null`:                true,
+		// the fields of the types can still be selected
+		`Nickname.String == stale`: true,
+		`Nickname.Valid == false`:  true,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+		require.Empty(t, Validate(expression, row{}), expression)
+	}
+
+	// values which are not valid are null rather than zero
+	expr, err := CreateEvaluator(`Manager == ""`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	require.Error(t, err)
+
+	expr, err = CreateEvaluator(`Manager == boss`, WithDefaultValue("Manager", "boss"))
+	require.NoError(t, err)
+	result, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, result)
+
+	require.Len(t, Validate(`Age == old`, row{}), 1)
+	require.Equal(t, "/Age: is null, is not null, ==, !=, <, <=, >, >=, in, not in, between, not between", Fields(row{})[3].String())
+}
+
+func TestEvaluate_UUID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, RegisterUUIDType(reflect.TypeOf(testUUID{})))
+	require.Error(t, RegisterUUIDType(reflect.TypeOf([8]byte{})))
+
+	type record struct {
+		ID     testUUID
+		Parent *testUUID
+		Raw    [16]byte
+	}
+	id := testUUID{0xf4, 0x7a, 0xc1, 0x0b, 0x58, 0xcc, 0x43, 0x72, 0xa5, 0x67, 0x0e, 0x02, 0xb2, 0xc3, 0xd4, 0x79}
+	value := record{ID: id, Parent: &id}
+
+	for expression, expected := range map[string]bool{
+		`ID == "f47ac10b-58cc-4372-a567-0e02b2c3d479"`:                                   true,
+		`ID == "F47AC10B-58CC-4372-A567-0E02B2C3D479"`:                                   true,
+		`ID == f47ac10b58cc4372a5670e02b2c3d479`:                                         true,
+		`ID == "{f47ac10b-58cc-4372-a567-0e02b2c3d479}"`:                                 true,
+		`ID == "urn:uuid:f47ac10b-58cc-4372-a567-0e02b2c3d479"`:                          true,
+		`ID != "f47ac10b-58cc-4372-a567-0e02b2c3d479"`:                                   false,
+		`ID == "00000000-0000-0000-0000-000000000000"`:                                   false,
+		`ID in ["00000000000000000000000000000000", "F47AC10B58CC4372A5670E02B2C3D479"]`: true,
+		`Parent == "f47ac10b-58cc-4372-a567-0e02b2c3d479"`:                               true,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	expr, err := CreateEvaluator(`ID in @ids`, WithValueSet("ids", []string{"F47AC10B58CC4372A5670E02B2C3D479"}))
+	require.NoError(t, err)
+	result, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, result)
+
+	require.Len(t, Validate(`ID == "not-a-uuid"`, record{}), 1)
+	// byte arrays of unregistered types are not UUIDs
+	require.Len(t, Validate(`Raw == "f47ac10b-58cc-4372-a567-0e02b2c3d479"`, record{}), 1)
+	require.Equal(t, "/ID: ==, !=, in, not in", Fields(record{})[0].String())
+}
+
+type testVersion struct {
+	Major, Minor, Patch int
+	Pre                 string
+}
+
+func (v *testVersion) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+func TestEvaluate_Stringers(t *testing.T) {
+	t.Parallel()
+
+	type release struct {
+		Version  testVersion
+		Previous *testVersion
+	}
+	value := release{
+		Version:  testVersion{Major: 1, Minor: 2, Patch: 3, Pre: "beta"},
+		Previous: &testVersion{Major: 1, Minor: 1},
+	}
+
+	for expression, expected := range map[string]bool{
+		`Version == "1.2.3-beta"`:     true,
+		`Version != "1.2.3-beta"`:     false,
+		`"beta" in Version`:           true,
+		`Version matches "^1\\.2\\."`: true,
+		`Previous == "1.1.0"`:         true,
+		`Previous is not null`:        true,
+		`Version.Major == 1`:          true,
+	} {
+		expr, err := CreateEvaluator(expression, WithStringers())
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	// without the option structs are not comparable
+	expr, err := CreateEvaluator(`Version == "1.2.3-beta"`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	require.Error(t, err)
+
+	require.Empty(t, Validate(`Version == "1.2.3"`, release{}, WithStringers()))
+	require.Len(t, Validate(`Version == "1.2.3"`, release{}), 1)
+	fields := Fields(release{}, WithStringers())
+	stringFields := Fields(struct{ Version string }{})
+	require.Equal(t, []string{"Version"}, fields[0].Path)
+	require.Equal(t, stringFields[0].Operators, fields[0].Operators)
+}
+
+type testSeverity int
+
+func (l *testSeverity) UnmarshalText(text []byte) error {
+	for i, name := range []string{"debug", "info", "warn", "error"} {
+		if string(text) == name {
+			*l = testSeverity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+type testKey struct {
+	Region string
+	ID     int
+}
+
+func (k *testKey) UnmarshalText(text []byte) error {
+	parts := strings.Split(string(text), "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid key %q", text)
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return err
+	}
+	*k = testKey{Region: parts[0], ID: id}
+	return nil
+}
+
+type testLabels struct {
+	Names []string
+}
+
+func (l *testLabels) UnmarshalText(text []byte) error {
+	l.Names = strings.Split(string(text), ",")
+	return nil
+}
+
+func TestEvaluate_TextUnmarshalers(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		Severity testSeverity
+		Key      testKey
+		Parent   *testKey
+		Labels   testLabels
+	}
+	value := record{
+		Severity: 2,
+		Key:      testKey{Region: "us", ID: 3},
+		Parent:   &testKey{Region: "us", ID: 1},
+		Labels:   testLabels{Names: []string{"a", "b"}},
+	}
+
+	for expression, expected := range map[string]bool{
+		`Severity == warn`:             true,
+		`Severity == 2`:                true,
+		`Severity > info`:              true,
+		`Severity in [debug, error]`:   false,
+		`Key == "us/3"`:                true,
+		`Key != "eu/3"`:                true,
+		`Key in ["eu/1", "us/3"]`:      true,
+		`Key in @keys`:                 false,
+		`Parent in @keys`:              true,
+		`Parent == "us/1"`:             true,
+		`Labels == "a,b"`:              true,
+		`Labels not in ["a", "a,b,c"]`: true,
+	} {
+		expr, err := CreateEvaluator(expression, WithValueSet("keys", []string{"us/1", "invalid"}))
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	expr, err := CreateEvaluator(`Key == "invalid"`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	var coercionErr *CoercionError
+	require.True(t, errors.As(err, &coercionErr))
+
+	require.Len(t, Validate(`Key == "invalid"`, record{}), 1)
+	require.Len(t, Validate(`Key < "us/3"`, record{}), 1)
+	require.Equal(t, "/Key: ==, !=, in, not in", Fields(record{})[1].String())
+}
+
+func TestEvaluate_BigNumbers(t *testing.T) {
+	t.Parallel()
+
+	type account struct {
+		Balance *big.Int
+		Supply  big.Int
+		Price   *big.Float
+		Missing *big.Int
+	}
+	value := account{
+		Balance: new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 100), big.NewInt(1)),
+		Price:   big.NewFloat(0.1),
+	}
+	value.Supply.SetInt64(10)
+
+	for expression, expected := range map[string]bool{
+		`Balance == 1267650600228229401496703205377`: true,
+		`Balance > 1267650600228229401496703205376`:  true,
+		`Balance < 1e31`:             true,
+		`Supply == 10`:               true,
+		`Supply == 10.5`:             false,
+		`Supply < 10.5`:              true,
+		`Supply in [5, 10]`:          true,
+		`Supply between 1 and 9`:     false,
+		`Price == 0.1`:               true,
+		`Price between 0.05 and 0.2`: true,
+		`Missing is null`:            true,
+	} {
+		expr, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	expr, err := CreateEvaluator(`Balance == many`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	var coercionErr *CoercionError
+	require.True(t, errors.As(err, &coercionErr))
+
+	require.Len(t, Validate(`Price > cheap`, account{}), 1)
+	require.Equal(t, "/Supply: ==, !=, <, <=, >, >=, in, not in, between, not between", Fields(account{})[1].String())
+}
+
+// testDecimal is a decimal which also unmarshals itself and implements
+// fmt.Stringer, which Comparer takes precedence over
+type testDecimal struct {
+	digits string
+}
+
+func (d *testDecimal) CompareValue(value string) (int, error) {
+	self, ok := new(big.Rat).SetString(d.digits)
+	if !ok {
+		return 0, fmt.Errorf("invalid decimal %q", d.digits)
+	}
+	other, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return 0, fmt.Errorf("invalid decimal %q", value)
+	}
+	return self.Cmp(other), nil
+}
+
+func (d *testDecimal) UnmarshalText(text []byte) error {
+	d.digits = string(text)
+	return nil
+}
+
+func (d *testDecimal) String() string {
+	return d.digits
+}
+
+func TestEvaluate_Comparers(t *testing.T) {
+	t.Parallel()
+
+	type order struct {
+		Total    testDecimal
+		Discount *testDecimal
+		Refund   *testDecimal
+	}
+	value := order{
+		Total:    testDecimal{digits: "19.990"},
+		Discount: &testDecimal{digits: "0.1"},
+	}
+
+	for expression, expected := range map[string]bool{
+		`Total == 19.99`:              true,
+		`Total != 19.99`:              false,
+		`Total > 19.98`:               true,
+		`Total <= 20`:                 true,
+		`Total between 10 and 19.99`:  true,
+		`Total not between 10 and 19`: true,
+		`Total in [5, 19.99]`:         true,
+		`Total in @prices`:            false,
+		`Discount in @prices`:         true,
+		`Discount < "1/5"`:            true,
+		`Refund is null`:              true,
+		`Discount is not null`:        true,
+	} {
+		expr, err := CreateEvaluator(expression, WithValueSet("prices", []string{"free", "0.10"}), WithStringers())
+		require.NoError(t, err, expression)
+		result, err := expr.Evaluate(value)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, result, expression)
+	}
+
+	expr, err := CreateEvaluator(`Total < lots`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	var coercionErr *CoercionError
+	require.True(t, errors.As(err, &coercionErr))
+	require.Equal(t, "lots", coercionErr.Value)
+
+	expr, err = CreateEvaluator(`Total matches "^1"`)
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	var operatorErr *UnsupportedOperatorError
+	require.True(t, errors.As(err, &operatorErr))
+
+	require.Equal(t, "/Total: ==, !=, <, <=, >, >=, in, not in, between, not between", Fields(order{})[0].String())
+}
+
+func TestEvaluate_CustomOperators(t *testing.T) {
+	t.Parallel()
+
+	multipleOf, err := RegisterOperator("multipleof", Operator{
+		Parse: func(value string) (interface{}, error) {
+			return strconv.ParseUint(value, 10, 32)
+		},
+		Evaluate: map[reflect.Kind]OperatorFunc{
+			reflect.Uint32: func(selected reflect.Value, value interface{}) (bool, error) {
+				divisor := value.(uint64)
+				return divisor != 0 && selected.Uint()%divisor == 0, nil
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = RegisterOperator("in", Operator{Evaluate: map[reflect.Kind]OperatorFunc{reflect.String: nil}})
+	require.EqualError(t, err, `invalid match operator token "in": already part of the syntax`)
+	_, err = RegisterOperator("multiple of", Operator{Evaluate: map[reflect.Kind]OperatorFunc{reflect.String: nil}})
+	require.EqualError(t, err, `invalid match operator token "multiple of": must be a single word`)
+	_, err = RegisterOperator("nothing", Operator{})
+	require.EqualError(t, err, `operator "nothing" has no evaluation functions`)
+
+	type item struct {
+		Count uint32
+		Name  string
+		Limit uint32 `bexpr:"limit,ops=eq|multipleof"`
+	}
+
+	expr, err := CreateEvaluator("Count multipleof 3 and not (Count multipleof 2)")
+	require.NoError(t, err)
+	require.Equal(t, "Count multipleof 3 and not Count multipleof 2", expr.String())
+	for count, expected := range map[uint32]bool{9: true, 6: false, 10: false} {
+		result, err := expr.Evaluate(item{Count: count})
+		require.NoError(t, err)
+		require.Equal(t, expected, result, count)
+	}
+
+	// the expression keeps the operator through its JSON encoding
+	encoded, err := expr.MarshalJSON()
+	require.NoError(t, err)
+	decoded, err := CreateEvaluatorFromJSON(encoded)
+	require.NoError(t, err)
+	require.Equal(t, expr.String(), decoded.String())
+
+	_, err = CreateEvaluator("Count multipleof three")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Invalid value "three" for multipleof`)
+
+	expr, err = CreateEvaluator("Name multipleof 3")
+	require.NoError(t, err)
+	_, err = expr.Evaluate(item{Name: "web"})
+	require.EqualError(t, err, `Cannot perform multipleof operations on type string for selector: "Name"`)
+
+	require.Equal(t, "Count multipleof 4", Match("Count").Operator(multipleOf, 4).String())
+
+	require.Empty(t, Validate("limit multipleof 5", item{}))
+	require.Len(t, Validate("limit > 5", item{}), 1)
+
+	fields := Fields(item{})
+	require.Contains(t, fields[0].Operators, multipleOf)
+	require.NotContains(t, fields[1].Operators, multipleOf)
+	require.Equal(t, []grammar.MatchOperator{grammar.MatchEqual, multipleOf}, fields[2].Operators)
+}
+func TestEvaluate_Functions(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"Node": "web-01",
+		"Tags": []string{"a", "b"},
+	}
+
+	hasPrefix := func(args ...interface{}) (bool, error) {
+		if len(args) != 2 {
+			return false, fmt.Errorf("expected 2 arguments, got %d", len(args))
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return false, fmt.Errorf("expected a string, got %T", args[0])
+		}
+		return strings.HasPrefix(s, args[1].(string)), nil
+	}
+	opts := []Option{WithFunction("hasPrefix", hasPrefix)}
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"Match":           {expression: `hasPrefix(Node, "web-")`, result: true},
+		"No Match":        {expression: `hasPrefix(Node, "db-")`, result: false},
+		"Combined":        {expression: `not hasPrefix(Node, "db-") and Tags contains a`, result: true},
+		"Function Error":  {expression: `hasPrefix(Tags, "a")`, err: `error calling function "hasPrefix": expected a string, got []string`},
+		"Missing Value":   {expression: `hasPrefix(Missing, "a")`, err: "error finding value in datum"},
+		"Argument Count":  {expression: `hasPrefix(Node)`, err: `error calling function "hasPrefix": expected 2 arguments, got 1`},
+		"Quoted Argument": {expression: `hasPrefix("web-01", "web")`, result: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CreateEvaluator(tcase.expression, opts...)
+			require.NoError(t, err)
+
+			match, err := expr.Evaluate(value)
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+
+	_, err := CreateEvaluator(`hasSuffix(Node, "-01")`, opts...)
+	require.EqualError(t, err, `function "hasSuffix" is not defined`)
+}
+
+func TestEvaluate_ValueSets(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]interface{}{
+		"owner":  "alice",
+		"port":   8080,
+		"tags":   []string{"web", "prod"},
+		"nested": map[string]int{"x": 1},
+	}
+
+	expr, err := CreateEvaluator("owner in @admins and port not in @ports",
+		WithValueSet("admins", []string{"alice", "bob"}),
+		WithValueSet("ports", []string{"22", "0x50"}))
+	require.NoError(t, err)
+
+	match, err := expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// refreshing the set at runtime changes the result
+	expr.SetValueSet("admins", []string{"bob"})
+	match, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, match)
+
+	expr.SetValueSet("ports", []string{"8080"})
+	expr.SetValueSet("admins", []string{"alice"})
+	match, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.False(t, match)
+
+	expr.DeleteValueSet("admins")
+	_, err = expr.Evaluate(value)
+	require.EqualError(t, err, `value set "@admins" is not defined`)
+
+	// slices match if any element is within the set
+	expr, err = CreateEvaluator("tags in @envs", WithValueSet("envs", []string{"dev", "prod"}))
+	require.NoError(t, err)
+	match, err = expr.Evaluate(value)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	expr, err = CreateEvaluator("nested in @envs", WithValueSet("envs", []string{"dev", "prod"}))
+	require.NoError(t, err)
+	_, err = expr.Evaluate(value)
+	require.EqualError(t, err, `Cannot perform in set operations on type map for selector: "nested"`)
+}
+
 func BenchmarkEvaluate(b *testing.B) {
 	for name, tcase := range evaluateTests {
 		// capture these values in the closure
@@ -0,0 +1,283 @@
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Explanation describes how a clause of an expression was evaluated
+type Explanation struct {
+	// Expression is the clause in canonical form
+	Expression string
+	Result     bool
+	// Values holds the values the clause compared, keyed by the selectors
+	// they were found at. Values which could not be found are left out.
+	// For the clauses within a quantifier it also holds the element bound
+	// to the quantifier variable.
+	Values map[string]interface{}
+	// Clauses explains the clauses that the result of this one was made
+	// from, such as the operands of and, in the order they were evaluated
+	Clauses []*Explanation
+}
+
+// Explain evaluates the expression against the datum like Evaluate and
+// returns the result of each clause along with the values it compared, such
+// as to find out why a filter did or did not match. Clauses that evaluation
+// skipped, such as the right operand of an and whose left operand is false,
+// are not explained.
+func (eval *Evaluator) Explain(datum interface{}) (*Explanation, error) {
+	if len(eval.unbound) > 0 {
+		return nil, fmt.Errorf("parameter $%s is not bound", eval.unbound[0])
+	}
+	return explain(eval.ast, datum, &eval.opts)
+}
+
+// String formats the explanation as a tree with a line for each clause,
+// such as:
+//
+//	false: Name == web and Port > 80
+//	  true: Name == web (Name = "web")
+//	  false: Port > 80 (Port = 80)
+func (e *Explanation) String() string {
+	var b strings.Builder
+	e.write(&b, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (e *Explanation) write(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(strconv.FormatBool(e.Result) + ": " + e.Expression)
+	if len(e.Values) > 0 {
+		keys := make([]string, 0, len(e.Values))
+		for key := range e.Values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		values := make([]string, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, key+" = "+formatExplainedValue(e.Values[key]))
+		}
+		b.WriteString(" (" + strings.Join(values, ", ") + ")")
+	}
+	b.WriteString("\n")
+
+	for _, clause := range e.Clauses {
+		clause.write(b, depth+1)
+	}
+}
+
+func formatExplainedValue(val interface{}) string {
+	// strings of defined types are quoted as well
+	if rvalue := reflect.ValueOf(val); rvalue.Kind() == reflect.String {
+		return strconv.Quote(rvalue.String())
+	}
+	return fmt.Sprint(val)
+}
+
+func explain(ast grammar.Expression, datum interface{}, opts *options) (*Explanation, error) {
+	opts = scope(ast, opts)
+	explanation := &Explanation{Expression: ast.(fmt.Stringer).String()}
+
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand, err := explain(node.Operand, datum, opts)
+		if err != nil {
+			return nil, err
+		}
+		explanation.Result = !operand.Result
+		explanation.Clauses = []*Explanation{operand}
+		return explanation, nil
+
+	case *grammar.BinaryExpression:
+		left, err := explain(node.Left, datum, opts)
+		if err != nil {
+			return nil, err
+		}
+		explanation.Clauses = []*Explanation{left}
+
+		// and and or skip the right operand when the left decides the result
+		switch {
+		case node.Operator == grammar.BinaryOpAnd && !left.Result:
+			return explanation, nil
+		case node.Operator == grammar.BinaryOpOr && left.Result:
+			explanation.Result = true
+			return explanation, nil
+		}
+
+		right, err := explain(node.Right, datum, opts)
+		if err != nil {
+			return nil, err
+		}
+		explanation.Clauses = append(explanation.Clauses, right)
+		if node.Operator == grammar.BinaryOpXor {
+			explanation.Result = left.Result != right.Result
+		} else {
+			explanation.Result = right.Result
+		}
+		return explanation, nil
+
+	case *grammar.QuantifierExpression:
+		return explainQuantifier(node, explanation, datum, opts)
+	}
+
+	result, err := evaluate(ast, datum, opts)
+	if err != nil {
+		return nil, err
+	}
+	explanation.Result = result
+	explanation.Values = explainedValues(ast, datum, opts)
+	return explanation, nil
+}
+
+// explainQuantifier explains the body of the quantifier for each element
+// evaluated, stopping where the evaluation would
+func explainQuantifier(node *grammar.QuantifierExpression, explanation *Explanation, datum interface{}, opts *options) (*Explanation, error) {
+	// the result and any error are those of the evaluation
+	result, err := evaluate(node, datum, opts)
+	if err != nil {
+		return nil, err
+	}
+	explanation.Result = result
+
+	val, err := getSelectorValue(node.Selector, datum, opts)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := quantifierElements(node, val)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, element := range elements {
+		clause, err := explain(node.Expression, &binding{variable: node.Variable, value: element.Interface(), parent: datum}, opts)
+		if err != nil {
+			return nil, err
+		}
+		if clause.Values == nil {
+			clause.Values = make(map[string]interface{})
+		}
+		clause.Values[node.Variable] = element.Interface()
+		explanation.Clauses = append(explanation.Clauses, clause)
+
+		if clause.Result == (node.Quantifier == grammar.QuantifierAny) {
+			break
+		}
+	}
+	return explanation, nil
+}
+
+// explainedValues returns the values at the selectors of a clause which is
+// evaluated as a whole
+func explainedValues(ast grammar.Expression, datum interface{}, opts *options) map[string]interface{} {
+	var selectors []grammar.Selector
+	switch node := ast.(type) {
+	case *grammar.MatchExpression:
+		selectors = append(selectors, node.Selector)
+	case *grammar.FunctionExpression:
+		for _, arg := range node.Args {
+			if arg.Value == nil {
+				selectors = append(selectors, arg.Selector)
+			}
+		}
+	case *grammar.ComparisonExpression:
+		collect := func(sel grammar.Selector) error {
+			selectors = append(selectors, sel)
+			return nil
+		}
+		walkOperandSelectors(node.Left, collect)
+		walkOperandSelectors(node.Right, collect)
+	}
+
+	values := make(map[string]interface{})
+	for _, sel := range selectors {
+		if sel.HasWildcard() {
+			continue
+		}
+		if val, err := getSelectorValue(sel, datum, opts); err == nil {
+			values[sel.String()] = val
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// Capture is a clause of an expression which decided the result of an
+// evaluation, along with the values it compared
+type Capture struct {
+	// Expression is the clause in canonical form
+	Expression string
+	Result     bool
+	// Values holds the values the clause compared keyed by their selectors,
+	// as for Explanation
+	Values map[string]interface{}
+}
+
+// EvaluateCapture evaluates the expression against the datum like Evaluate
+// and also returns the clauses that decided the result, such as to
+// highlight the fields of a search result that matched. When the result is
+// true these are the clauses that made it true, such as both operands of an
+// and but only the matching operand of an or. Clauses within quantifiers
+// are captured for the elements that decided the result of the quantifier,
+// with the element included in their values.
+func (eval *Evaluator) EvaluateCapture(datum interface{}) (bool, []Capture, error) {
+	explanation, err := eval.Explain(datum)
+	if err != nil {
+		return false, nil, err
+	}
+	return explanation.Result, captureClauses(eval.ast, explanation, nil), nil
+}
+
+// captureClauses appends the clauses of the explanation of the expression
+// which decided its result
+func captureClauses(ast grammar.Expression, e *Explanation, captures []Capture) []Capture {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return captureClauses(node.Operand, e.Clauses[0], captures)
+
+	case *grammar.BinaryExpression:
+		left := e.Clauses[0]
+		if len(e.Clauses) == 1 {
+			// the right operand was skipped as the left decided the result
+			return captureClauses(node.Left, left, captures)
+		}
+		right := e.Clauses[1]
+		switch {
+		case node.Operator == grammar.BinaryOpAnd && !right.Result,
+			node.Operator == grammar.BinaryOpOr && right.Result:
+			return captureClauses(node.Right, right, captures)
+		}
+		captures = captureClauses(node.Left, left, captures)
+		return captureClauses(node.Right, right, captures)
+
+	case *grammar.QuantifierExpression:
+		if len(e.Clauses) == 0 {
+			break
+		}
+		clauses := e.Clauses
+		if e.Result == (node.Quantifier == grammar.QuantifierAny) {
+			// the element that made any true or all false decided it alone
+			clauses = clauses[len(clauses)-1:]
+		}
+		for _, clause := range clauses {
+			start := len(captures)
+			captures = captureClauses(node.Expression, clause, captures)
+			for i := start; i < len(captures); i++ {
+				if captures[i].Values == nil {
+					captures[i].Values = make(map[string]interface{})
+				}
+				captures[i].Values[node.Variable] = clause.Values[node.Variable]
+			}
+		}
+		return captures
+	}
+
+	return append(captures, Capture{Expression: e.Expression, Result: e.Result, Values: e.Values})
+}
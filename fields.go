@@ -0,0 +1,692 @@
+package bexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// FieldAny is the selector segment standing for any map key or slice index
+// in the selectors of the fields listed by Fields
+const FieldAny = "<any>"
+
+// Field describes a value that expressions can select within values of a
+// type along with the operators that can be applied to it
+type Field struct {
+	// Path is the selector of the field, with FieldAny for the segments
+	// which are map keys or slice indexes
+	Path []string
+
+	// Type is the type of the values selected
+	Type reflect.Type
+
+	// Operators are the match operators which can be used with the field
+	Operators []grammar.MatchOperator
+
+	// Aliases are other paths that expressions can select the field by when
+	// restricted to the field with WithFields. Each alias must have as many
+	// FieldAny segments as Path, which take the same values in order.
+	Aliases [][]string
+
+	// Hidden fields can only be selected by their aliases, such as to give
+	// a field another name
+	Hidden bool
+}
+
+// String formats the field as its JSON Pointer followed by the symbols of
+// its operators, such as: /Status: ==, !=
+func (f Field) String() string {
+	symbols := make([]string, 0, len(f.Operators))
+	for _, op := range f.Operators {
+		symbols = append(symbols, op.Symbol())
+	}
+	return pointerKey(f.Path) + ": " + strings.Join(symbols, ", ")
+}
+
+// Fields lists every value that expressions can select within values of
+// the given type, such as to tell the clients of an API which fields they
+// can filter on. Struct fields are listed in declaration order, each before
+// the fields within it. The values within maps and slices are listed under
+// FieldAny. Structs themselves have no operators and are not listed, unless
+// they implement fmt.Stringer and WithStringers is given. The
+// operators of struct fields are limited to those their tags allow, such as
+// by: bexpr:"status,ops=eq|ne"
+// Interfaces are listed as supporting every built in operator as the types
+// of their values are only known during evaluation, and nothing within them
+// is listed. Operators registered with RegisterOperator are listed for the
+// kinds of values they support. Options that change how struct fields are
+// named, such as WithJSONTagNames, and WithStringers are applied and the
+// others are ignored.
+func Fields(dataType interface{}, opts ...Option) []Field {
+	var fields []Field
+	parsedOpts := getOpts(opts...)
+	collectFields(reflect.TypeOf(dataType), nil, nil, make(map[reflect.Type]bool), &parsedOpts, &fields)
+	return fields
+}
+
+// collectFields adds the fields of the type at the path, which is held by
+// the struct field tagField if any
+func collectFields(rtype reflect.Type, path []string, tagField *structField, visiting map[reflect.Type]bool, opts *options, fields *[]Field) {
+	if rtype == nil {
+		return
+	}
+
+	elem := derefType(rtype)
+	if ops := allowedOperators(rtype, tagField, opts); len(path) > 0 && len(ops) > 0 {
+		*fields = append(*fields, Field{
+			Path:      path,
+			Type:      rtype,
+			Operators: ops,
+		})
+	}
+
+	// recursive types are only expanded once along each path
+	if visiting[elem] {
+		return
+	}
+	visiting[elem] = true
+	defer delete(visiting, elem)
+
+	child := func(name string) []string {
+		return append(path[:len(path):len(path)], name)
+	}
+
+	switch elem.Kind() {
+	case reflect.Struct:
+		for _, field := range getStructInfo(elem, opts).fields {
+			if field.err == nil {
+				collectFields(field.typ, child(field.name), field, visiting, opts, fields)
+			}
+		}
+	case reflect.Map, reflect.Slice, reflect.Array:
+		// a []byte is selected as a whole such as for matches
+		if elem.Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		collectFields(elem.Elem(), child(FieldAny), nil, visiting, opts, fields)
+	}
+}
+
+var (
+	nullOperators     = []grammar.MatchOperator{grammar.MatchIsNull, grammar.MatchIsNotNull}
+	emptyOperators    = []grammar.MatchOperator{grammar.MatchIsEmpty, grammar.MatchIsNotEmpty}
+	equalityOperators = []grammar.MatchOperator{grammar.MatchEqual, grammar.MatchNotEqual, grammar.MatchInSet, grammar.MatchNotInSet}
+	orderedOperators  = []grammar.MatchOperator{
+		grammar.MatchLessThan, grammar.MatchLessThanOrEqual, grammar.MatchGreaterThan, grammar.MatchGreaterThanOrEqual,
+		grammar.MatchBetween, grammar.MatchNotBetween,
+	}
+	containsOperators = []grammar.MatchOperator{
+		grammar.MatchIn, grammar.MatchNotIn,
+		grammar.MatchContainsAny, grammar.MatchNotContainsAny, grammar.MatchContainsAll, grammar.MatchNotContainsAll,
+	}
+	stringOperators = []grammar.MatchOperator{
+		grammar.MatchEqualFold, grammar.MatchNotEqualFold,
+		grammar.MatchMatches, grammar.MatchNotMatches, grammar.MatchLike, grammar.MatchNotLike,
+		grammar.MatchPrefix, grammar.MatchNotPrefix, grammar.MatchSuffix, grammar.MatchNotSuffix,
+		grammar.MatchInCIDR, grammar.MatchNotInCIDR,
+	}
+	patternOperators = []grammar.MatchOperator{
+		grammar.MatchLike, grammar.MatchNotLike, grammar.MatchPrefix, grammar.MatchNotPrefix,
+		grammar.MatchSuffix, grammar.MatchNotSuffix, grammar.MatchInCIDR, grammar.MatchNotInCIDR,
+	}
+	cidrOperators = []grammar.MatchOperator{grammar.MatchInCIDR, grammar.MatchNotInCIDR}
+)
+
+// allowedOperators returns the operators supported for the type which the
+// tag of the struct field holding the values, if any, allows
+func allowedOperators(rtype reflect.Type, field *structField, opts *options) []grammar.MatchOperator {
+	ops := fieldOperators(rtype)
+	if opts.withStringers && isStringerType(rtype) {
+		ops = fieldOperators(stringType)
+		if rtype.Kind() == reflect.Ptr {
+			ops = append(nullOperators[:len(nullOperators):len(nullOperators)], ops...)
+		}
+	}
+	allowed := ops[:0:0]
+	for _, op := range ops {
+		if field.allows(op) {
+			allowed = append(allowed, op)
+		}
+	}
+	return allowed
+}
+
+// fieldOperators returns the match operators which the evaluation supports
+// for values of the type, in the order they are declared
+func fieldOperators(rtype reflect.Type) []grammar.MatchOperator {
+	if inner, ok := sqlNullType(derefType(rtype)); ok {
+		// database/sql null values are null when they are not valid
+		return append(nullOperators[:len(nullOperators):len(nullOperators)], fieldOperators(inner)...)
+	}
+
+	var ops []grammar.MatchOperator
+	switch rtype.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		ops = append(ops, nullOperators...)
+	}
+
+	elem := derefType(rtype)
+	switch kind := elem.Kind(); {
+	case kind == reflect.Interface:
+		return builtinOperators()
+	case elem == ipTyp:
+		ops = append(ops, cidrOperators...)
+	case elem == timeTyp || elem == jsonNumberTyp || isBigType(elem) || isComparerType(elem):
+		ops = append(ops, equalityOperators...)
+		ops = append(ops, orderedOperators...)
+	case isUUIDType(elem):
+		ops = append(ops, equalityOperators...)
+	case primitiveEqualityFn(kind) == nil && isTextType(elem):
+		ops = append(ops, equalityOperators...)
+	case kind == reflect.String:
+		ops = append(ops, equalityOperators...)
+		ops = append(ops, orderedOperators...)
+		ops = append(ops, containsOperators...)
+		ops = append(ops, emptyOperators...)
+		ops = append(ops, stringOperators...)
+	case primitiveCompareFn(kind) != nil:
+		ops = append(ops, equalityOperators...)
+		ops = append(ops, orderedOperators...)
+	case primitiveEqualityFn(kind) != nil:
+		ops = append(ops, equalityOperators...)
+	case kind == reflect.Map:
+		ops = append(ops, containsOperators...)
+		ops = append(ops, emptyOperators...)
+	case kind == reflect.Slice || kind == reflect.Array:
+		item := derefType(elem.Elem())
+		if primitiveEqualityFn(item.Kind()) != nil {
+			ops = append(ops, containsOperators...)
+			ops = append(ops, grammar.MatchInSet, grammar.MatchNotInSet)
+		}
+		ops = append(ops, emptyOperators...)
+		switch {
+		case elem.Elem().Kind() == reflect.Uint8:
+			ops = append(ops, grammar.MatchMatches, grammar.MatchNotMatches)
+		case item.Kind() == reflect.String && item != jsonNumberTyp:
+			ops = append(ops, patternOperators...)
+		case item == ipTyp:
+			ops = append(ops, cidrOperators...)
+		}
+	}
+	ops = append(ops, customOperators(elem.Kind())...)
+	return sortOperators(ops)
+}
+
+func sortOperators(ops []grammar.MatchOperator) []grammar.MatchOperator {
+	sorted := make([]grammar.MatchOperator, 0, len(ops))
+	for _, op := range allOperators() {
+		for _, candidate := range ops {
+			if candidate == op {
+				sorted = append(sorted, op)
+				break
+			}
+		}
+	}
+	return sorted
+}
+
+// pathMatches reports whether the path of a field selects the path, where
+// a FieldAny within the path, standing for a * wildcard, only matches
+// FieldAny
+func pathMatches(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, part := range pattern {
+		if part != FieldAny && part != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonTypes are the types of fields declared with the type names of JSON
+// Schema
+var jsonTypes = map[string]reflect.Type{
+	"boolean": reflect.TypeOf(false),
+	"integer": reflect.TypeOf(int64(0)),
+	"number":  reflect.TypeOf(float64(0)),
+	"string":  reflect.TypeOf(""),
+	"array":   reflect.TypeOf([]interface{}{}),
+	"object":  reflect.TypeOf(map[string]interface{}{}),
+}
+
+// jsonField is the form of a Field within the files read by LoadFields
+type jsonField struct {
+	Selector  string   `json:"selector"`
+	Aliases   []string `json:"aliases"`
+	Type      string   `json:"type"`
+	Operators []string `json:"operators"`
+	Hidden    bool     `json:"hidden"`
+}
+
+// LoadFields reads field declarations from JSON, such as a configuration
+// file, for restricting expressions with WithFields when the values they
+// are evaluated against are not described by Go types. The JSON is an
+// array of objects such as:
+//
+//	{"selector": "Meta.*", "aliases": ["Labels.*"], "type": "string", "operators": ["==", "!="]}
+//
+// Selectors use either the dotted bexpr syntax, with * for FieldAny, or the
+// quoted JSON Pointer syntax. Fields can also set "hidden" to only be
+// selectable by their aliases. The type is one of the type names of JSON
+// Schema and the values of expressions are checked to be convertible to it.
+// The operators are given by their symbols as listed by Field.String and
+// default to those supported by the type, or all operators when the type
+// is not given either.
+func LoadFields(r io.Reader) ([]Field, error) {
+	var decoded []jsonField
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("error decoding fields: %w", err)
+	}
+
+	fields := make([]Field, 0, len(decoded))
+	for _, field := range decoded {
+		path, err := fieldPath(field.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q for field: %w", field.Selector, err)
+		}
+
+		var rtype reflect.Type
+		if field.Type != "" {
+			var ok bool
+			if rtype, ok = jsonTypes[field.Type]; !ok {
+				return nil, fmt.Errorf("unknown type %q for field %q", field.Type, field.Selector)
+			}
+		}
+
+		ops := fieldOperators(reflect.TypeOf((*interface{})(nil)).Elem())
+		if rtype != nil {
+			ops = fieldOperators(rtype)
+		}
+		if field.Operators != nil {
+			ops = make([]grammar.MatchOperator, 0, len(field.Operators))
+			for _, symbol := range field.Operators {
+				op, ok := lookupOperatorSymbol(symbol)
+				if !ok {
+					return nil, fmt.Errorf("unknown operator %q for field %q", symbol, field.Selector)
+				}
+				ops = append(ops, op)
+			}
+		}
+
+		var aliases [][]string
+		for _, alias := range field.Aliases {
+			aliasPath, err := fieldPath(alias)
+			if err != nil {
+				return nil, fmt.Errorf("invalid alias %q for field %q: %w", alias, field.Selector, err)
+			}
+			if countAny(aliasPath) != countAny(path) {
+				return nil, fmt.Errorf("alias %q for field %q must have the same number of wildcards", alias, field.Selector)
+			}
+			aliases = append(aliases, aliasPath)
+		}
+
+		fields = append(fields, Field{
+			Path:      path,
+			Type:      rtype,
+			Operators: ops,
+			Aliases:   aliases,
+			Hidden:    field.Hidden,
+		})
+	}
+	return fields, nil
+}
+
+// fieldPath parses the selector of a field declaration
+func fieldPath(selector string) ([]string, error) {
+	sel, err := grammar.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return selectorPath(sel), nil
+}
+
+// selectorPath returns the path of the selector with FieldAny for its *
+// wildcards, as the paths of fields are written
+func selectorPath(sel grammar.Selector) []string {
+	path := make([]string, len(sel.Path))
+	for i, part := range sel.Path {
+		if sel.IsWildcard(i) {
+			part = FieldAny
+		}
+		path[i] = part
+	}
+	return path
+}
+
+func countAny(path []string) int {
+	count := 0
+	for _, part := range path {
+		if part == FieldAny {
+			count++
+		}
+	}
+	return count
+}
+
+func lookupOperatorSymbol(symbol string) (grammar.MatchOperator, bool) {
+	for _, op := range allOperators() {
+		if op.Symbol() == symbol {
+			return op, true
+		}
+	}
+	return 0, false
+}
+
+// findField returns the field selected by the path, either its own path
+// or one of its aliases
+func findField(fields []Field, path []string) (Field, bool) {
+	for _, field := range fields {
+		if !field.Hidden && pathMatches(field.Path, path) {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		for _, alias := range field.Aliases {
+			if pathMatches(alias, path) {
+				return field, true
+			}
+		}
+	}
+	return Field{}, false
+}
+
+// validateFields checks that the expression only selects the fields and
+// uses the operators given by WithFields. It is run before the aliases of
+// the fields are replaced so that hidden fields can be told apart.
+func validateFields(ast grammar.Expression, opts *options) error {
+	return walkResolvedSelectors(ast, func(sel grammar.Selector, match *grammar.MatchExpression) error {
+		path := selectorPath(sel)
+		field, ok := findField(opts.withFields, path)
+		if !ok {
+			// suggest the fields as they would be written in place of the
+			// selector, with its own segments standing in for FieldAny
+			names := make(map[string]struct{}, len(opts.withFields))
+			addName := func(pattern []string) {
+				if len(pattern) == len(path) {
+					names[fieldSelector(substituteAny(pattern, pattern, path)).String()] = struct{}{}
+				}
+			}
+			for _, field := range opts.withFields {
+				if !field.Hidden {
+					addName(field.Path)
+				}
+				for _, alias := range field.Aliases {
+					addName(alias)
+				}
+			}
+			name := fieldSelector(path).String()
+			return &UnknownSelectorError{
+				Selector: sel,
+				Err:      fmt.Errorf("invalid selector %q: not a known field%s", name, nameSuggestion(name, names)),
+			}
+		}
+		if match == nil {
+			return nil
+		}
+
+		if match.Length {
+			// lengths are integers which any field with a length can be
+			// compared to
+			if !hasOperator(field.Operators, grammar.MatchIsEmpty) {
+				return &UnsupportedOperatorError{
+					Operator: match.Operator,
+					Selector: match.Selector,
+					Err:      fmt.Errorf("match operator %q is not supported for the length of selector: %q", match.Operator, match.Selector),
+				}
+			}
+		} else if !hasOperator(field.Operators, match.Operator) {
+			return &UnsupportedOperatorError{
+				Operator: match.Operator,
+				Selector: match.Selector,
+				Err:      fmt.Errorf("match operator %q is not supported for selector: %q", match.Operator, match.Selector),
+			}
+		}
+
+		if field.Type != nil {
+			return validateMatchType(match, derefType(field.Type), nil, opts)
+		}
+		return nil
+	})
+}
+
+// validateFieldTypes checks the values of the expression against the types
+// of the fields given by WithFields after their aliases have been replaced,
+// such as once values have been bound to placeholders, when the fields are
+// found by their paths whether they are hidden or not
+func validateFieldTypes(ast grammar.Expression, opts *options) error {
+	return walkResolvedSelectors(ast, func(sel grammar.Selector, match *grammar.MatchExpression) error {
+		if match == nil {
+			return nil
+		}
+		path := selectorPath(sel)
+		for _, field := range opts.withFields {
+			if field.Type != nil && pathMatches(field.Path, path) {
+				return validateMatchType(match, derefType(field.Type), nil, opts)
+			}
+		}
+		return nil
+	})
+}
+
+// MergeFields applies the overrides to the fields, such as those listed by
+// Fields for a type, so that only the differences from the generated fields
+// have to be written out. For example to restrict the operators of a field,
+// rename another and hide a third along with everything within it:
+//
+//	fields := MergeFields(Fields(Service{}),
+//		Field{Path: []string{"Name"}, Operators: []grammar.MatchOperator{grammar.MatchEqual}},
+//		Field{Path: []string{"Meta"}, Aliases: [][]string{{"Labels"}}, Hidden: true},
+//		Field{Path: []string{"Secrets"}, Hidden: true},
+//	)
+//
+// An override replaces the type and operators of the field with the same
+// path where it sets them and adds its aliases to those of the field. Hiding
+// a field also hides the fields within it: those are removed when the
+// override has no aliases and are otherwise given the matching aliases, so
+// that renaming Meta to Labels also renames Meta.env to Labels.env.
+// Overrides which do not match a field are added as new fields, such as for
+// the values within interfaces. The original fields are not modified.
+func MergeFields(fields []Field, overrides ...Field) []Field {
+	merged := make([]Field, 0, len(fields)+len(overrides))
+	for _, field := range fields {
+		field.Aliases = append([][]string(nil), field.Aliases...)
+		merged = append(merged, field)
+	}
+
+	for _, override := range overrides {
+		found := false
+		kept := merged[:0]
+		for _, field := range merged {
+			if equalPaths(field.Path, override.Path) {
+				found = true
+				if override.Type != nil {
+					field.Type = override.Type
+				}
+				if override.Operators != nil {
+					field.Operators = override.Operators
+				}
+				field.Aliases = append(field.Aliases, override.Aliases...)
+			}
+			if override.Hidden && hasPrefix(field.Path, override.Path) {
+				if len(override.Aliases) == 0 {
+					continue
+				}
+				field.Hidden = true
+				for _, alias := range override.Aliases {
+					if len(field.Path) > len(override.Path) {
+						field.Aliases = append(field.Aliases, append(alias[:len(alias):len(alias)], field.Path[len(override.Path):]...))
+					}
+				}
+			}
+			kept = append(kept, field)
+		}
+		merged = kept
+
+		if !found && !(override.Hidden && len(override.Aliases) == 0) {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
+
+func equalPaths(a, b []string) bool {
+	return len(a) == len(b) && hasPrefix(a, b)
+}
+
+func hasPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i, part := range prefix {
+		if path[i] != part {
+			return false
+		}
+	}
+	return true
+}
+
+func hasOperator(ops []grammar.MatchOperator, op grammar.MatchOperator) bool {
+	for _, candidate := range ops {
+		if candidate == op {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldSelector returns the selector of the path in the dotted syntax with
+// FieldAny written as the * wildcard
+func fieldSelector(path []string) grammar.Selector {
+	sel := grammar.Selector{Type: grammar.SelectorTypeBexpr, Path: make([]string, len(path))}
+	for i, part := range path {
+		if part == FieldAny {
+			part = "*"
+			sel.Wildcards = append(sel.Wildcards, i)
+		}
+		sel.Path[i] = part
+	}
+	return sel
+}
+
+// aliasSelectors returns a copy of the expression with the selectors which
+// are aliases of the fields replaced by the paths of the fields. Selectors
+// relative to quantifier variables are left alone.
+func aliasSelectors(ast grammar.Expression, fields []Field) grammar.Expression {
+	return rewriteSelectors(ast, func(sel grammar.Selector) grammar.Selector {
+		path := selectorPath(sel)
+		for _, field := range fields {
+			if !field.Hidden && pathMatches(field.Path, path) {
+				return sel
+			}
+		}
+		for _, field := range fields {
+			for _, alias := range field.Aliases {
+				if pathMatches(alias, path) {
+					replaced := fieldSelector(substituteAny(field.Path, alias, path))
+					replaced.Type = sel.Type
+					return replaced
+				}
+			}
+		}
+		return sel
+	}, nil)
+}
+
+// substituteAny returns the pattern with its FieldAny segments replaced by
+// the segments of the path that the FieldAny segments of from matched
+func substituteAny(pattern, from, path []string) []string {
+	var values []string
+	for i, part := range from {
+		if part == FieldAny {
+			values = append(values, path[i])
+		}
+	}
+	result := make([]string, len(pattern))
+	for i, part := range pattern {
+		if part == FieldAny {
+			part, values = values[0], values[1:]
+		}
+		result[i] = part
+	}
+	return result
+}
+
+// rewriteSelectors copies the expression, replacing the selectors referring
+// to the datum with the result of fn
+func rewriteSelectors(ast grammar.Expression, fn func(grammar.Selector) grammar.Selector, bound map[string]struct{}) grammar.Expression {
+	rewrite := func(sel grammar.Selector) grammar.Selector {
+		if len(sel.Path) > 0 {
+			if _, ok := bound[sel.Path[0]]; ok {
+				return sel
+			}
+		}
+		return fn(sel)
+	}
+
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: rewriteSelectors(node.Operand, fn, bound)}
+	case *grammar.BinaryExpression:
+		return &grammar.BinaryExpression{
+			Left:     rewriteSelectors(node.Left, fn, bound),
+			Operator: node.Operator,
+			Right:    rewriteSelectors(node.Right, fn, bound),
+		}
+	case *grammar.QuantifierExpression:
+		inner := make(map[string]struct{}, len(bound)+1)
+		for variable := range bound {
+			inner[variable] = struct{}{}
+		}
+		inner[node.Variable] = struct{}{}
+		copied := *node
+		copied.Selector = rewrite(node.Selector)
+		copied.Expression = rewriteSelectors(node.Expression, fn, inner)
+		return &copied
+	case *grammar.FunctionExpression:
+		copied := &grammar.FunctionExpression{Name: node.Name, Args: make([]*grammar.FunctionArgument, len(node.Args))}
+		for i, arg := range node.Args {
+			copied.Args[i] = arg
+			if arg.Value == nil {
+				copied.Args[i] = &grammar.FunctionArgument{Selector: rewrite(arg.Selector)}
+			}
+		}
+		return copied
+	case *grammar.ComparisonExpression:
+		return &grammar.ComparisonExpression{
+			Left:     rewriteOperandSelectors(node.Left, rewrite),
+			Operator: node.Operator,
+			Right:    rewriteOperandSelectors(node.Right, rewrite),
+		}
+	case *grammar.MatchExpression:
+		copied := *node
+		copied.Selector = rewrite(node.Selector)
+		return &copied
+	case *grammar.ConstantExpression:
+		copied := *node
+		return &copied
+	}
+	return ast
+}
+
+func rewriteOperandSelectors(o *grammar.Operand, fn func(grammar.Selector) grammar.Selector) *grammar.Operand {
+	switch {
+	case o.Arithmetic != nil:
+		return &grammar.Operand{Arithmetic: &grammar.ArithmeticExpression{
+			Left:     rewriteOperandSelectors(o.Arithmetic.Left, fn),
+			Operator: o.Arithmetic.Operator,
+			Right:    rewriteOperandSelectors(o.Arithmetic.Right, fn),
+		}}
+	case o.Value != nil:
+		return o
+	default:
+		return &grammar.Operand{Selector: fn(o.Selector)}
+	}
+}
@@ -1,8 +1,11 @@
 package bexpr
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"sync"
 )
 
 type Filter struct {
@@ -41,29 +44,8 @@ func (f *Filter) Execute(data interface{}) (interface{}, error) {
 	rtype := rvalue.Type()
 
 	switch rvalue.Kind() {
-	case reflect.Array:
-		// For arrays we return slices instead of fixed sized arrays
-		rtype = reflect.SliceOf(rtype.Elem())
-		fallthrough
-	case reflect.Slice:
-		newSlice := reflect.MakeSlice(rtype, 0, rvalue.Len())
-
-		for i := 0; i < rvalue.Len(); i++ {
-			item := rvalue.Index(i)
-			if !item.CanInterface() {
-				return nil, fmt.Errorf("Slice/Array value can not be used")
-			}
-			result, err := f.evaluator.Evaluate(item.Interface())
-			if err != nil {
-				return nil, err
-			}
-
-			if result {
-				newSlice = reflect.Append(newSlice, item)
-			}
-		}
-
-		return newSlice.Interface(), nil
+	case reflect.Array, reflect.Slice:
+		return f.evaluator.FilterSlice(data)
 	case reflect.Map:
 		newMap := reflect.MakeMap(rtype)
 
@@ -91,3 +73,195 @@ func (f *Filter) Execute(data interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("Only slices, arrays and maps are filterable")
 	}
 }
+
+// FilterSlice creates an Evaluator for the expression and uses it to filter
+// the given slice or array. See Evaluator.FilterSlice for details.
+func FilterSlice(expression string, slice interface{}, opts ...Option) (interface{}, error) {
+	eval, err := CreateEvaluator(expression, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return eval.FilterSlice(slice)
+}
+
+// FilterSlice evaluates the expression against each element of the given
+// slice or array and returns a new slice of the same element type holding
+// only the matching elements, in their original order. Arrays result in
+// a slice rather than a fixed size array. See WithParallelism to evaluate
+// the elements concurrently.
+func (eval *Evaluator) FilterSlice(slice interface{}) (interface{}, error) {
+	return eval.FilterSliceContext(context.Background(), slice)
+}
+
+// FilterSliceContext filters the slice or array like FilterSlice but stops
+// early with the error of the context once it is done, as for
+// EvaluateContext.
+func (eval *Evaluator) FilterSliceContext(ctx context.Context, slice interface{}) (interface{}, error) {
+	rvalue := reflect.ValueOf(slice)
+	if !rvalue.IsValid() {
+		return nil, fmt.Errorf("Only slices and arrays can be filtered")
+	}
+	rtype := rvalue.Type()
+
+	switch rvalue.Kind() {
+	case reflect.Array:
+		// For arrays we return slices instead of fixed sized arrays
+		rtype = reflect.SliceOf(rtype.Elem())
+	case reflect.Slice:
+	default:
+		return nil, fmt.Errorf("Only slices and arrays can be filtered")
+	}
+
+	results := make([]bool, rvalue.Len())
+	if err := eval.evaluateShards(ctx, rvalue, results); err != nil {
+		return nil, err
+	}
+
+	newSlice := reflect.MakeSlice(rtype, 0, rvalue.Len())
+	for i, result := range results {
+		if result {
+			newSlice = reflect.Append(newSlice, rvalue.Index(i))
+		}
+	}
+
+	return newSlice.Interface(), nil
+}
+
+// evaluateShards evaluates the expression against each element of the slice
+// or array, storing the results by index. The elements are split into a
+// shard for each worker set by WithParallelism, which are evaluated
+// concurrently, and the first error of any shard stops the others.
+func (eval *Evaluator) evaluateShards(ctx context.Context, rvalue reflect.Value, results []bool) error {
+	workers := eval.opts.withParallelism
+	if workers > len(results) {
+		workers = len(results)
+	}
+	if workers <= 1 {
+		return eval.evaluateShard(ctx, rvalue, results, 0, len(results))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	size := (len(results) + workers - 1) / workers
+	for start := 0; start < len(results); start += size {
+		end := start + size
+		if end > len(results) {
+			end = len(results)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			if err := eval.evaluateShard(ctx, rvalue, results, start, end); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (eval *Evaluator) evaluateShard(ctx context.Context, rvalue reflect.Value, results []bool, start, end int) error {
+	for i := start; i < end; i++ {
+		item := rvalue.Index(i)
+		if !item.CanInterface() {
+			return fmt.Errorf("Slice/Array value can not be used")
+		}
+		result, err := eval.EvaluateContext(ctx, item.Interface())
+		if err != nil {
+			return err
+		}
+		results[i] = result
+	}
+	return nil
+}
+
+// EvaluateAll evaluates the expression against each element of the given
+// slice or array and returns the results in the order of the elements, such
+// as to filter a large result set held in some other form than a slice of
+// its elements. The operators and values of the expression are checked
+// against the element type once up front, as by Validate, rather than being
+// reported by the evaluation of each element.
+func (eval *Evaluator) EvaluateAll(data interface{}) ([]bool, error) {
+	rvalue := reflect.ValueOf(data)
+	if kind := rvalue.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return nil, fmt.Errorf("Only slices and arrays can be evaluated")
+	}
+	if len(eval.unbound) > 0 {
+		return nil, fmt.Errorf("parameter $%s is not bound", eval.unbound[0])
+	}
+	if err := eval.validateType(rvalue.Type().Elem()); err != nil {
+		return nil, err
+	}
+
+	results := make([]bool, rvalue.Len())
+	for i := range results {
+		item := rvalue.Index(i)
+		if !item.CanInterface() {
+			return nil, fmt.Errorf("Slice/Array value can not be used")
+		}
+		result, err := eval.evaluate(nil, item.Interface())
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// FilterMap evaluates the expression against each value of the given map
+// and returns a new map of the same type holding only the matching entries.
+// Within the expression the key of the entry is available as the "key"
+// selector, or the name set with WithMapKeySelector, such as:
+//
+//	key matches "^web-" and Status == passing
+func (eval *Evaluator) FilterMap(m interface{}) (interface{}, error) {
+	return eval.FilterMapContext(context.Background(), m)
+}
+
+// FilterMapContext filters the map like FilterMap but stops early with the
+// error of the context once it is done, as for EvaluateContext.
+func (eval *Evaluator) FilterMapContext(ctx context.Context, m interface{}) (interface{}, error) {
+	rvalue := reflect.ValueOf(m)
+	if rvalue.Kind() != reflect.Map {
+		return nil, fmt.Errorf("Only maps can be filtered")
+	}
+
+	keySelector := eval.opts.withMapKeySelector
+	if keySelector == "" {
+		keySelector = "key"
+	}
+
+	keys := rvalue.MapKeys()
+	// keep the evaluation order and so any errors deterministic
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	newMap := reflect.MakeMap(rvalue.Type())
+	for _, mapKey := range keys {
+		item := rvalue.MapIndex(mapKey)
+		if !item.CanInterface() || !mapKey.CanInterface() {
+			return nil, fmt.Errorf("Map value cannot be used")
+		}
+
+		result, err := eval.EvaluateContext(ctx, &binding{variable: keySelector, value: mapKey.Interface(), parent: item.Interface()})
+		if err != nil {
+			return nil, err
+		}
+
+		if result {
+			newMap.SetMapIndex(mapKey, item)
+		}
+	}
+
+	return newMap.Interface(), nil
+}
@@ -1,6 +1,8 @@
 package bexpr
 
 import (
+	"errors"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -154,6 +156,153 @@ func TestFilter(t *testing.T) {
 	}
 }
 
+func TestFilterSlice(t *testing.T) {
+	t.Parallel()
+
+	results, err := FilterSlice("X==1", testSlice)
+	require.NoError(t, err)
+	require.Equal(t, []testStruct{
+		{X: 1, Y: "a"},
+		{X: 1, Y: "b"},
+	}, results)
+
+	eval, err := CreateEvaluator("Y in [`b`, `c`]")
+	require.NoError(t, err)
+
+	results, err = eval.FilterSlice(testArray)
+	require.NoError(t, err)
+	require.Equal(t, []testStruct{
+		{X: 1, Y: "b"},
+		{X: 2, Y: "b"},
+		{X: 3, Y: "c"},
+	}, results)
+
+	results, err = eval.FilterSlice([]testStruct{})
+	require.NoError(t, err)
+	require.Equal(t, []testStruct{}, results)
+
+	_, err = eval.FilterSlice(testMap)
+	require.EqualError(t, err, "Only slices and arrays can be filtered")
+
+	_, err = FilterSlice("X ==", testSlice)
+	require.Error(t, err)
+}
+
+func TestFilterSlice_Parallelism(t *testing.T) {
+	t.Parallel()
+
+	data := make([]testStruct, 1000)
+	var expected []testStruct
+	for i := range data {
+		data[i] = testStruct{X: i, Y: strconv.Itoa(i % 7)}
+		if i%7 == 3 {
+			expected = append(expected, data[i])
+		}
+	}
+
+	for _, workers := range []int{0, 1, 4, 3000} {
+		results, err := FilterSlice("Y == 3", data, WithParallelism(workers))
+		require.NoError(t, err)
+		require.Equal(t, expected, results, "workers: %d", workers)
+	}
+
+	results, err := FilterSlice("Y == 3", []testStruct{}, WithParallelism(4))
+	require.NoError(t, err)
+	require.Equal(t, []testStruct{}, results)
+
+	// an error in any shard fails the filter
+	values := make([]interface{}, 100)
+	for i := range values {
+		values[i] = map[string]int{"X": i}
+	}
+	values[60] = map[string]string{"Y": "a"}
+	_, err = FilterSlice("X >= 0", values, WithParallelism(4))
+	require.Error(t, err)
+}
+
+func TestEvaluator_EvaluateAll(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator("X == 2 or Y == c")
+	require.NoError(t, err)
+
+	results, err := eval.EvaluateAll(testSlice)
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, false, true, true, true}, results)
+
+	results, err = eval.EvaluateAll(testArray)
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, false, true, true, true}, results)
+
+	results, err = eval.EvaluateAll([]testStruct{})
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	_, err = eval.EvaluateAll(testMap)
+	require.EqualError(t, err, "Only slices and arrays can be evaluated")
+
+	// the expression is checked against the element type even when there
+	// are no elements
+	eval, err = CreateEvaluator("X == a")
+	require.NoError(t, err)
+	_, err = eval.EvaluateAll([]testStruct{})
+	require.Error(t, err)
+	var coerceErr *CoercionError
+	require.True(t, errors.As(err, &coerceErr))
+
+	eval, err = CreateEvaluator("Z == a")
+	require.NoError(t, err)
+	_, err = eval.EvaluateAll([]testStruct{})
+	var selectorErr *UnknownSelectorError
+	require.True(t, errors.As(err, &selectorErr))
+
+	// the types of values within interfaces are not known until evaluation
+	results, err = eval.EvaluateAll([]interface{}{map[string]string{"Z": "a"}})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true}, results)
+}
+
+func TestFilterMap(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator("key in [one, four, five] and X != 1")
+	require.NoError(t, err)
+
+	results, err := eval.FilterMap(testMap)
+	require.NoError(t, err)
+	require.Equal(t, map[string]testStruct{
+		"four": {X: 2, Y: "b"},
+		"five": {X: 3, Y: "c"},
+	}, results)
+
+	eval, err = CreateEvaluator(`name matches "^t" or Y == c`, WithMapKeySelector("name"))
+	require.NoError(t, err)
+
+	results, err = eval.FilterMap(testMap)
+	require.NoError(t, err)
+	require.Equal(t, map[string]testStruct{
+		"two":   {X: 1, Y: "b"},
+		"three": {X: 2, Y: "a"},
+		"five":  {X: 3, Y: "c"},
+	}, results)
+
+	// the key shadows the values of the map
+	eval, err = CreateEvaluator("key == b")
+	require.NoError(t, err)
+
+	results, err = eval.FilterMap(map[string]map[string]string{
+		"a": {"key": "b"},
+		"b": {"key": "a"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]string{
+		"b": {"key": "a"},
+	}, results)
+
+	_, err = eval.FilterMap(testSlice)
+	require.EqualError(t, err, "Only maps can be filtered")
+}
+
 func BenchmarkFilter(b *testing.B) {
 	type benchCase struct {
 		expression string
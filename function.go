@@ -0,0 +1,7 @@
+package bexpr
+
+// Function is an application provided predicate that expressions can call
+// by the name it was registered with using WithFunction. Selector arguments
+// are passed as the value they select within the datum and literal
+// arguments as their raw string.
+type Function func(args ...interface{}) (bool, error)
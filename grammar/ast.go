@@ -3,6 +3,8 @@ package grammar
 import (
 	"fmt"
 	"io"
+	"net"
+	"regexp"
 	"strings"
 )
 
@@ -32,6 +34,7 @@ type BinaryOperator int
 const (
 	BinaryOpAnd BinaryOperator = iota
 	BinaryOpOr
+	BinaryOpXor
 )
 
 func (op BinaryOperator) String() string {
@@ -40,6 +43,53 @@ func (op BinaryOperator) String() string {
 		return "And"
 	case BinaryOpOr:
 		return "Or"
+	case BinaryOpXor:
+		return "Xor"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+type Quantifier int
+
+const (
+	QuantifierAny Quantifier = iota
+	QuantifierAll
+)
+
+func (q Quantifier) String() string {
+	switch q {
+	case QuantifierAny:
+		return "Any"
+	case QuantifierAll:
+		return "All"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+type ArithmeticOperator int
+
+const (
+	ArithmeticAdd ArithmeticOperator = iota
+	ArithmeticSubtract
+	ArithmeticMultiply
+	ArithmeticDivide
+	ArithmeticModulo
+)
+
+func (op ArithmeticOperator) String() string {
+	switch op {
+	case ArithmeticAdd:
+		return "+"
+	case ArithmeticSubtract:
+		return "-"
+	case ArithmeticMultiply:
+		return "*"
+	case ArithmeticDivide:
+		return "/"
+	case ArithmeticModulo:
+		return "%"
 	default:
 		return "UNKNOWN"
 	}
@@ -56,6 +106,30 @@ const (
 	MatchIsNotEmpty
 	MatchMatches
 	MatchNotMatches
+	MatchLessThan
+	MatchLessThanOrEqual
+	MatchGreaterThan
+	MatchGreaterThanOrEqual
+	MatchInSet
+	MatchNotInSet
+	MatchLike
+	MatchNotLike
+	MatchPrefix
+	MatchNotPrefix
+	MatchSuffix
+	MatchNotSuffix
+	MatchBetween
+	MatchNotBetween
+	MatchEqualFold
+	MatchNotEqualFold
+	MatchIsNull
+	MatchIsNotNull
+	MatchInCIDR
+	MatchNotInCIDR
+	MatchContainsAny
+	MatchNotContainsAny
+	MatchContainsAll
+	MatchNotContainsAll
 )
 
 func (op MatchOperator) String() string {
@@ -76,14 +150,88 @@ func (op MatchOperator) String() string {
 		return "Matches"
 	case MatchNotMatches:
 		return "Not Matches"
+	case MatchLessThan:
+		return "Less Than"
+	case MatchLessThanOrEqual:
+		return "Less Than Or Equal"
+	case MatchGreaterThan:
+		return "Greater Than"
+	case MatchGreaterThanOrEqual:
+		return "Greater Than Or Equal"
+	case MatchInSet:
+		return "In Set"
+	case MatchNotInSet:
+		return "Not In Set"
+	case MatchLike:
+		return "Like"
+	case MatchNotLike:
+		return "Not Like"
+	case MatchPrefix:
+		return "Starts With"
+	case MatchNotPrefix:
+		return "Not Starts With"
+	case MatchSuffix:
+		return "Ends With"
+	case MatchNotSuffix:
+		return "Not Ends With"
+	case MatchBetween:
+		return "Between"
+	case MatchNotBetween:
+		return "Not Between"
+	case MatchEqualFold:
+		return "Equal Fold"
+	case MatchNotEqualFold:
+		return "Not Equal Fold"
+	case MatchIsNull:
+		return "Is Null"
+	case MatchIsNotNull:
+		return "Is Not Null"
+	case MatchInCIDR:
+		return "In CIDR"
+	case MatchNotInCIDR:
+		return "Not In CIDR"
+	case MatchContainsAny:
+		return "Contains Any"
+	case MatchNotContainsAny:
+		return "Not Contains Any"
+	case MatchContainsAll:
+		return "Contains All"
+	case MatchNotContainsAll:
+		return "Not Contains All"
 	default:
+		if custom := getCustomOperator(op); custom != nil {
+			return custom.token
+		}
 		return "UNKNOWN"
 	}
 }
 
+// reverse returns the operator that yields the same result when
+// the selector and value are swapped, i.e. 3 < foo becomes foo > 3
+func (op MatchOperator) reverse() MatchOperator {
+	switch op {
+	case MatchLessThan:
+		return MatchGreaterThan
+	case MatchLessThanOrEqual:
+		return MatchGreaterThanOrEqual
+	case MatchGreaterThan:
+		return MatchLessThan
+	case MatchGreaterThanOrEqual:
+		return MatchLessThanOrEqual
+	default:
+		return op
+	}
+}
+
 type MatchValue struct {
 	Raw       string
 	Converted interface{}
+	// Parameter is the name of a $name placeholder which must be bound to
+	// a value before the expression can be evaluated
+	Parameter string
+	// Quoted is set for string literals, such as ",", which unlike numbers
+	// are compared against runes and bytes as characters
+	Quoted bool
 }
 
 type UnaryExpression struct {
@@ -97,6 +245,73 @@ type BinaryExpression struct {
 	Right    Expression
 }
 
+// QuantifierExpression evaluates its Expression once for each element of
+// the collection at Selector, such as in: all(Checks, c -> c.Status == passing)
+// Selectors within the Expression that start with the Variable are relative
+// to the element being evaluated.
+type QuantifierExpression struct {
+	Quantifier Quantifier
+	Selector   Selector
+	Variable   string
+	Expression Expression
+}
+
+// FunctionExpression is a call to an application provided predicate such
+// as: hasPrefix(Node, "web-")
+type FunctionExpression struct {
+	Name string
+	Args []*FunctionArgument
+}
+
+// FunctionArgument is either a selector whose value is passed to the
+// function or, when Value is set, a literal value.
+type FunctionArgument struct {
+	Selector Selector
+	Value    *MatchValue
+}
+
+// ComparisonExpression compares the numeric results of two operands where
+// at least one is an arithmetic expression, such as in: Used / Total > 0.9
+// Operator is one of the equality or ordering match operators.
+type ComparisonExpression struct {
+	Left     *Operand
+	Operator MatchOperator
+	Right    *Operand
+}
+
+// Operand is a term of an arithmetic expression. Exactly one of its fields
+// is set: a selector whose numeric value is used, a number or duration
+// literal, or a nested arithmetic expression.
+type Operand struct {
+	Selector   Selector
+	Value      *MatchValue
+	Arithmetic *ArithmeticExpression
+}
+
+func (o *Operand) String() string {
+	switch {
+	case o.Arithmetic != nil:
+		return fmt.Sprintf("(%v %v %v)", o.Arithmetic.Left, o.Arithmetic.Operator, o.Arithmetic.Right)
+	case o.Value != nil:
+		return o.Value.Raw
+	default:
+		return o.Selector.String()
+	}
+}
+
+type ArithmeticExpression struct {
+	Left     *Operand
+	Operator ArithmeticOperator
+	Right    *Operand
+}
+
+// ConstantExpression is a literal true or false used in place of a match.
+// It allows generated expressions such as `true and (foo == 3)` to degrade
+// gracefully when some of their conditions are empty.
+type ConstantExpression struct {
+	Value bool
+}
+
 type SelectorType uint32
 
 const (
@@ -108,8 +323,17 @@ const (
 type Selector struct {
 	Type SelectorType
 	Path []string
+	// Wildcards holds the indexes of the segments of Path, in increasing
+	// order, which are * wildcards standing for every map key or slice
+	// index. Their segments hold "*" while any other "*" segment, such as
+	// that of foo["*"], selects the key "*" itself.
+	Wildcards []int
 }
 
+// wildcardSegment is the segment the parser returns for a * wildcard of a
+// selector
+type wildcardSegment struct{}
+
 func (sel Selector) String() string {
 	if len(sel.Path) == 0 {
 		return ""
@@ -124,10 +348,173 @@ func (sel Selector) String() string {
 	}
 }
 
+// HasWildcard reports whether any segment of the selector is a * wildcard
+func (sel Selector) HasWildcard() bool {
+	return len(sel.Wildcards) > 0
+}
+
+// IsWildcard reports whether the segment of the path at index i is a *
+// wildcard
+func (sel Selector) IsWildcard(i int) bool {
+	for _, wildcard := range sel.Wildcards {
+		if wildcard == i {
+			return true
+		}
+	}
+	return false
+}
+
+// Slice returns the selector of the segments of the path from index i up to
+// but not including index j, keeping those which are wildcards
+func (sel Selector) Slice(i, j int) Selector {
+	sliced := Selector{Type: sel.Type, Path: sel.Path[i:j:j]}
+	for _, wildcard := range sel.Wildcards {
+		if wildcard >= i && wildcard < j {
+			sliced.Wildcards = append(sliced.Wildcards, wildcard-i)
+		}
+	}
+	return sliced
+}
+
+// Append returns the selector followed by the segments of other, keeping
+// the wildcards of both
+func (sel Selector) Append(other Selector) Selector {
+	joined := Selector{Type: sel.Type, Path: make([]string, 0, len(sel.Path)+len(other.Path))}
+	joined.Path = append(append(joined.Path, sel.Path...), other.Path...)
+	joined.Wildcards = append(joined.Wildcards, sel.Wildcards...)
+	for _, wildcard := range other.Wildcards {
+		joined.Wildcards = append(joined.Wildcards, len(sel.Path)+wildcard)
+	}
+	return joined
+}
+
+// ParseSelector parses a standalone selector in either the dotted bexpr
+// syntax or the quoted JSON Pointer syntax.
+func ParseSelector(selector string) (Selector, error) {
+	sel, err := Parse("", []byte(selector), Entrypoint("SelectorInput"))
+	if err != nil {
+		return Selector{}, parseErrors([]byte(selector), err)
+	}
+	return sel.(Selector), nil
+}
+
 type MatchExpression struct {
 	Selector Selector
 	Operator MatchOperator
 	Value    *MatchValue
+	// Values holds the elements of a list literal such as in: foo in [1, 2]
+	// or the lower and upper bounds of a between operation
+	Values []*MatchValue
+	// Length is set when the match applies to the length of the selected
+	// value rather than the value itself, such as in: len(foo) > 3
+	Length bool
+}
+
+// ConvertValue precomputes the converted form of the value for the operators
+// which need one, such as the compiled regular expression of matches. It is
+// called by the parser and only needs calling again if the value changes.
+// Placeholder values are left alone until they are bound.
+func (expr *MatchExpression) ConvertValue() error {
+	if expr.Value == nil || expr.Value.Parameter != "" {
+		return nil
+	}
+
+	switch expr.Operator {
+	case MatchMatches, MatchNotMatches:
+		// compile the regular expression once up front rather than for each evaluation
+		re, err := regexp.Compile(expr.Value.Raw)
+		if err != nil {
+			return fmt.Errorf("Invalid regular expression %q: %v", expr.Value.Raw, err)
+		}
+		expr.Value.Converted = re
+	case MatchLike, MatchNotLike:
+		re, err := globToRegexp(expr.Value.Raw)
+		if err != nil {
+			return fmt.Errorf("Invalid glob pattern %q: %v", expr.Value.Raw, err)
+		}
+		expr.Value.Converted = re
+	case MatchInCIDR, MatchNotInCIDR:
+		_, network, err := net.ParseCIDR(expr.Value.Raw)
+		if err != nil {
+			return fmt.Errorf("Invalid CIDR %q: %v", expr.Value.Raw, err)
+		}
+		expr.Value.Converted = network
+	default:
+		if custom := getCustomOperator(expr.Operator); custom != nil && custom.convert != nil {
+			converted, err := custom.convert(expr.Value.Raw)
+			if err != nil {
+				return fmt.Errorf("Invalid value %q for %s: %v", expr.Value.Raw, custom.token, err)
+			}
+			expr.Value.Converted = converted
+		}
+	}
+	return nil
+}
+
+// foldArithmetic combines the first operand with the operator and operand
+// pairs that follow it, left to right, so that a - b - c is (a - b) - c
+func foldArithmetic(first interface{}, rest interface{}) *Operand {
+	result := first.(*Operand)
+	for _, v := range rest.([]interface{}) {
+		parts := v.([]interface{})
+		result = &Operand{Arithmetic: &ArithmeticExpression{
+			Left:     result,
+			Operator: parts[1].(ArithmeticOperator),
+			Right:    parts[3].(*Operand),
+		}}
+	}
+	return result
+}
+
+// scopeExpression rewrites all the selectors within the expression to be
+// relative to the scope selector. This is what makes the block in
+// `foo { bar == 3 }` equivalent to `foo.bar == 3`
+//
+// Selectors starting with one of the bound quantifier variables are left alone.
+func scopeExpression(expr Expression, scope Selector, bound ...string) Expression {
+	switch node := expr.(type) {
+	case *UnaryExpression:
+		scopeExpression(node.Operand, scope, bound...)
+	case *BinaryExpression:
+		scopeExpression(node.Left, scope, bound...)
+		scopeExpression(node.Right, scope, bound...)
+	case *QuantifierExpression:
+		node.Selector = scopeSelector(node.Selector, scope, bound)
+		scopeExpression(node.Expression, scope, append(bound, node.Variable)...)
+	case *FunctionExpression:
+		for _, arg := range node.Args {
+			if arg.Value == nil {
+				arg.Selector = scopeSelector(arg.Selector, scope, bound)
+			}
+		}
+	case *ComparisonExpression:
+		scopeOperand(node.Left, scope, bound)
+		scopeOperand(node.Right, scope, bound)
+	case *MatchExpression:
+		node.Selector = scopeSelector(node.Selector, scope, bound)
+	}
+	return expr
+}
+
+func scopeOperand(o *Operand, scope Selector, bound []string) {
+	switch {
+	case o.Arithmetic != nil:
+		scopeOperand(o.Arithmetic.Left, scope, bound)
+		scopeOperand(o.Arithmetic.Right, scope, bound)
+	case o.Value == nil:
+		o.Selector = scopeSelector(o.Selector, scope, bound)
+	}
+}
+
+func scopeSelector(sel Selector, scope Selector, bound []string) Selector {
+	for _, variable := range bound {
+		if len(sel.Path) > 0 && sel.Path[0] == variable {
+			return sel
+		}
+	}
+	scoped := scope.Append(sel)
+	scoped.Type = sel.Type
+	return scoped
 }
 
 func (expr *UnaryExpression) ExpressionDump(w io.Writer, indent string, level int) {
@@ -145,11 +532,54 @@ func (expr *BinaryExpression) ExpressionDump(w io.Writer, indent string, level i
 	fmt.Fprintf(w, "%s}\n", localIndent)
 }
 
+func (expr *QuantifierExpression) ExpressionDump(w io.Writer, indent string, level int) {
+	localIndent := strings.Repeat(indent, level)
+	fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sSelector: %[4]v\n%[2]sVariable: %[5]s\n", localIndent, strings.Repeat(indent, level+1), expr.Quantifier.String(), expr.Selector, expr.Variable)
+	expr.Expression.ExpressionDump(w, indent, level+1)
+	fmt.Fprintf(w, "%s}\n", localIndent)
+}
+
+func (expr *FunctionExpression) ExpressionDump(w io.Writer, indent string, level int) {
+	localIndent := strings.Repeat(indent, level)
+	argIndent := strings.Repeat(indent, level+1)
+	fmt.Fprintf(w, "%sFunction %s {\n", localIndent, expr.Name)
+	for _, arg := range expr.Args {
+		if arg.Value != nil {
+			fmt.Fprintf(w, "%sValue: %q\n", argIndent, arg.Value.Raw)
+		} else {
+			fmt.Fprintf(w, "%sSelector: %v\n", argIndent, arg.Selector)
+		}
+	}
+	fmt.Fprintf(w, "%s}\n", localIndent)
+}
+
+func (expr *ComparisonExpression) ExpressionDump(w io.Writer, indent string, level int) {
+	fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sLeft: %[4]v\n%[2]sRight: %[5]v\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Operator.String(), expr.Left, expr.Right)
+}
+
+func (expr *ConstantExpression) ExpressionDump(w io.Writer, indent string, level int) {
+	fmt.Fprintf(w, "%[1]sConstant {\n%[2]sValue: %[3]t\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Value)
+}
+
 func (expr *MatchExpression) ExpressionDump(w io.Writer, indent string, level int) {
+	selector := expr.Selector.String()
+	if expr.Length {
+		selector = "len(" + selector + ")"
+	}
+
+	if expr.Values != nil {
+		raw := make([]string, 0, len(expr.Values))
+		for _, value := range expr.Values {
+			raw = append(raw, fmt.Sprintf("%q", value.Raw))
+		}
+		fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sSelector: %[4]v\n%[2]sValues: [%[5]s]\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Operator.String(), selector, strings.Join(raw, ", "))
+		return
+	}
+
 	switch expr.Operator {
-	case MatchEqual, MatchNotEqual, MatchIn, MatchNotIn:
-		fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sSelector: %[4]v\n%[2]sValue: %[5]q\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Operator.String(), expr.Selector, expr.Value.Raw)
+	case MatchEqual, MatchNotEqual, MatchIn, MatchNotIn, MatchLessThan, MatchLessThanOrEqual, MatchGreaterThan, MatchGreaterThanOrEqual, MatchInSet, MatchNotInSet, MatchPrefix, MatchNotPrefix, MatchSuffix, MatchNotSuffix, MatchMatches, MatchNotMatches, MatchLike, MatchNotLike, MatchEqualFold, MatchNotEqualFold, MatchInCIDR, MatchNotInCIDR:
+		fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sSelector: %[4]v\n%[2]sValue: %[5]q\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Operator.String(), selector, expr.Value.Raw)
 	default:
-		fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sSelector: %[4]v\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Operator.String(), expr.Selector)
+		fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sSelector: %[4]v\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Operator.String(), selector)
 	}
 }
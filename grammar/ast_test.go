@@ -39,6 +39,62 @@ func TestAST_Dump(t *testing.T) {
 			expr:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchIsNotEmpty, Value: nil},
 			expected: "Is Not Empty {\n   Selector: foo.bar\n}\n",
 		},
+		"Length": {
+			expr:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchGreaterThan, Value: &MatchValue{Raw: "3"}, Length: true},
+			expected: "Greater Than {\n   Selector: len(foo.bar)\n   Value: \"3\"\n}\n",
+		},
+		"Quantifier": {
+			expr: &QuantifierExpression{
+				Quantifier: QuantifierAny,
+				Selector:   Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}},
+				Variable:   "f",
+				Expression: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"f", "bar"}}, Operator: MatchIsEmpty, Value: nil},
+			},
+			expected: "Any {\n   Selector: foo\n   Variable: f\n   Is Empty {\n      Selector: f.bar\n   }\n}\n",
+		},
+		"Constant": {
+			expr:     &ConstantExpression{Value: true},
+			expected: "Constant {\n   Value: true\n}\n",
+		},
+		"Comparison": {
+			expr: &ComparisonExpression{
+				Left: &Operand{Arithmetic: &ArithmeticExpression{
+					Left:     &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "used"}}},
+					Operator: ArithmeticDivide,
+					Right:    &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "total"}}},
+				}},
+				Operator: MatchGreaterThan,
+				Right:    &Operand{Value: &MatchValue{Raw: "0.9"}},
+			},
+			expected: "Greater Than {\n   Left: (foo.used / foo.total)\n   Right: 0.9\n}\n",
+		},
+		"Function": {
+			expr: &FunctionExpression{Name: "hasPrefix", Args: []*FunctionArgument{
+				{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Node"}}},
+				{Value: &MatchValue{Raw: "web-"}},
+			}},
+			expected: "Function hasPrefix {\n   Selector: Node\n   Value: \"web-\"\n}\n",
+		},
+		"MatchLessThan": {
+			expr:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchLessThan, Value: &MatchValue{Raw: "3"}},
+			expected: "Less Than {\n   Selector: foo.bar\n   Value: \"3\"\n}\n",
+		},
+		"MatchLessThanOrEqual": {
+			expr:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchLessThanOrEqual, Value: &MatchValue{Raw: "3"}},
+			expected: "Less Than Or Equal {\n   Selector: foo.bar\n   Value: \"3\"\n}\n",
+		},
+		"MatchGreaterThan": {
+			expr:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchGreaterThan, Value: &MatchValue{Raw: "3"}},
+			expected: "Greater Than {\n   Selector: foo.bar\n   Value: \"3\"\n}\n",
+		},
+		"MatchGreaterThanOrEqual": {
+			expr:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchGreaterThanOrEqual, Value: &MatchValue{Raw: "3"}},
+			expected: "Greater Than Or Equal {\n   Selector: foo.bar\n   Value: \"3\"\n}\n",
+		},
+		"MatchInSetList": {
+			expr:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchInSet, Values: []*MatchValue{{Raw: "a"}, {Raw: "b"}}},
+			expected: "In Set {\n   Selector: foo.bar\n   Values: [\"a\", \"b\"]\n}\n",
+		},
 		"MatchUnknown": {
 			expr:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchOperator(42), Value: nil},
 			expected: "UNKNOWN {\n   Selector: foo.bar\n}\n",
@@ -67,6 +123,14 @@ func TestAST_Dump(t *testing.T) {
 			},
 			expected: "Or {\n   Is Empty {\n      Selector: foo.bar\n   }\n   Is Empty {\n      Selector: foo.bar\n   }\n}\n",
 		},
+		"BinaryOpXor": {
+			expr: &BinaryExpression{
+				Operator: BinaryOpXor,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchIsEmpty, Value: nil},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}, Operator: MatchIsEmpty, Value: nil},
+			},
+			expected: "Xor {\n   Is Empty {\n      Selector: foo.bar\n   }\n   Is Empty {\n      Selector: foo.bar\n   }\n}\n",
+		},
 		"BinaryOpUnknown": {
 			expr: &BinaryExpression{
 				Operator: BinaryOperator(42),
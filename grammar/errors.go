@@ -0,0 +1,137 @@
+package grammar
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"unicode"
+)
+
+// ParseError is a syntax error within an expression. Along with the error
+// message it records where in the expression the error is so that callers
+// can point out the offending text.
+type ParseError struct {
+	// Offset is the byte offset of the error within the expression while
+	// Line and Column are its 1-based position, counting columns in runes.
+	Offset int
+	Line   int
+	Column int
+	// Token is the text at the error position up to the next whitespace.
+	// It is empty when the error is at the end of the expression.
+	Token string
+	// Snippet is the line of the expression containing the error
+	Snippet string
+	// Expected holds the tokens which would have been valid at the error
+	// position. It is only set when the parser could not match the input.
+	Expected []string
+	// Err is the underlying error
+	Err error
+
+	msg string
+}
+
+func (e *ParseError) Error() string {
+	return e.msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// As lets errors.As find the first of the errors matching target, so that
+// the *ParseError of a single syntax error can be retrieved directly.
+func (e errList) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseExpression parses the expression like Parse but returns syntax
+// errors as *ParseError values. Use Errors to get each of them when
+// there may be several, such as with the ErrorRecovery option.
+func ParseExpression(expression []byte, opts ...Option) (Expression, error) {
+	ast, err := Parse("", expression, opts...)
+	if err != nil {
+		return nil, parseErrors(expression, err)
+	}
+	return ast.(Expression), nil
+}
+
+// Errors returns the individual errors that make up an error returned by
+// Parse, ordered by their position within the input. Any other error is
+// returned as the only element.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	list, ok := err.(errList)
+	if !ok {
+		return []error{err}
+	}
+
+	errs := make([]error, len(list))
+	copy(errs, list)
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errorOffset(errs[i]) < errorOffset(errs[j])
+	})
+	return errs
+}
+
+func errorOffset(err error) int {
+	switch err := err.(type) {
+	case *parserError:
+		return err.pos.offset
+	case *ParseError:
+		return err.Offset
+	default:
+		return 0
+	}
+}
+
+// parseErrors converts the errors returned by Parse into ParseErrors
+func parseErrors(input []byte, err error) error {
+	switch err := err.(type) {
+	case errList:
+		converted := make(errList, 0, len(err))
+		for _, inner := range err {
+			converted = append(converted, parseErrors(input, inner))
+		}
+		return converted
+	case *parserError:
+		return newParseError(input, err)
+	default:
+		return err
+	}
+}
+
+func newParseError(input []byte, err *parserError) *ParseError {
+	offset := err.pos.offset
+	if offset > len(input) {
+		offset = len(input)
+	}
+
+	token := input[offset:]
+	if end := bytes.IndexFunc(token, unicode.IsSpace); end >= 0 {
+		token = token[:end]
+	}
+
+	start := bytes.LastIndexByte(input[:offset], '\n') + 1
+	end := len(input)
+	if idx := bytes.IndexByte(input[offset:], '\n'); idx >= 0 {
+		end = offset + idx
+	}
+
+	return &ParseError{
+		Offset:   offset,
+		Line:     err.pos.line,
+		Column:   err.pos.col,
+		Token:    string(token),
+		Snippet:  string(bytes.TrimSuffix(input[start:end], []byte("\r"))),
+		Expected: err.expected,
+		Err:      err.Inner,
+		msg:      err.Error(),
+	}
+}
@@ -0,0 +1,367 @@
+package grammar
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The String methods of the expressions render them in a canonical form of
+// the expression syntax using the fewest parentheses needed to keep the
+// same structure. Parsing the result yields an equivalent expression.
+
+func (expr *UnaryExpression) String() string      { return formatExpression(expr) }
+func (expr *BinaryExpression) String() string     { return formatExpression(expr) }
+func (expr *QuantifierExpression) String() string { return formatExpression(expr) }
+func (expr *FunctionExpression) String() string   { return formatExpression(expr) }
+func (expr *ComparisonExpression) String() string { return formatExpression(expr) }
+func (expr *ConstantExpression) String() string   { return formatExpression(expr) }
+func (expr *MatchExpression) String() string      { return formatExpression(expr) }
+
+// the precedence of the expressions from the loosest binding to the tightest
+const (
+	precedenceOr = iota
+	precedenceXor
+	precedenceAnd
+	precedenceNot
+	precedencePrimary
+)
+
+var (
+	identifierRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+	indexRegexp      = regexp.MustCompile(`^(-?[0-9]+|last)$`)
+	numberRegexp     = regexp.MustCompile(`^-?(0[xX][0-9a-fA-F]+|0[oO][0-7]+|(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?)$`)
+	pointerRegexp    = regexp.MustCompile(`^[\pL\pN\-_.~|]+$`)
+	durationRegexp   = regexp.MustCompile(`^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`)
+	timeRegexp       = regexp.MustCompile(`^(now[+-]([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+|[0-9]{4}-[0-9]{2}-[0-9]{2}(T[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2}))?)$`)
+)
+
+func formatExpression(expr Expression) string {
+	var b strings.Builder
+	writeExpression(&b, expr)
+	return b.String()
+}
+
+func precedence(expr Expression) int {
+	switch node := expr.(type) {
+	case *BinaryExpression:
+		switch node.Operator {
+		case BinaryOpOr:
+			return precedenceOr
+		case BinaryOpXor:
+			return precedenceXor
+		default:
+			return precedenceAnd
+		}
+	case *UnaryExpression:
+		return precedenceNot
+	default:
+		return precedencePrimary
+	}
+}
+
+// writeOperand writes the expression, in parentheses if it binds more
+// loosely than the minimum precedence
+func writeOperand(b *strings.Builder, expr Expression, min int) {
+	if precedence(expr) < min {
+		b.WriteString("(")
+		writeExpression(b, expr)
+		b.WriteString(")")
+		return
+	}
+	writeExpression(b, expr)
+}
+
+func writeExpression(b *strings.Builder, expr Expression) {
+	switch node := expr.(type) {
+	case *UnaryExpression:
+		b.WriteString("not ")
+		writeOperand(b, node.Operand, precedenceNot)
+	case *BinaryExpression:
+		// the binary operators group to the right so a left operand using
+		// the same operator needs parentheses
+		prec := precedence(node)
+		writeOperand(b, node.Left, prec+1)
+		b.WriteString(" " + strings.ToLower(node.Operator.String()) + " ")
+		writeOperand(b, node.Right, prec)
+	case *QuantifierExpression:
+		b.WriteString(strings.ToLower(node.Quantifier.String()) + "(")
+		b.WriteString(formatSelector(node.Selector))
+		b.WriteString(", " + node.Variable + " -> ")
+		writeExpression(b, node.Expression)
+		b.WriteString(")")
+	case *FunctionExpression:
+		b.WriteString(node.Name + "(")
+		for i, arg := range node.Args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if arg.Value != nil {
+				// bare words would be parsed as selectors
+				b.WriteString(formatLiteral(arg.Value, false))
+			} else {
+				b.WriteString(formatSelector(arg.Selector))
+			}
+		}
+		b.WriteString(")")
+	case *ComparisonExpression:
+		writeArithmetic(b, node.Left, 0)
+		b.WriteString(" " + matchOperatorSymbol(node.Operator) + " ")
+		writeArithmetic(b, node.Right, 0)
+	case *ConstantExpression:
+		b.WriteString(strconv.FormatBool(node.Value))
+	case *MatchExpression:
+		writeMatch(b, node)
+	}
+}
+
+func arithmeticPrecedence(o *Operand) int {
+	if o.Arithmetic == nil {
+		return 2
+	}
+	switch o.Arithmetic.Operator {
+	case ArithmeticAdd, ArithmeticSubtract:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func writeArithmetic(b *strings.Builder, o *Operand, min int) {
+	switch {
+	case o.Arithmetic != nil:
+		// arithmetic groups to the left so a right operand of the same
+		// precedence needs parentheses
+		prec := arithmeticPrecedence(o)
+		if prec < min {
+			b.WriteString("(")
+		}
+		writeArithmetic(b, o.Arithmetic.Left, prec)
+		b.WriteString(" " + o.Arithmetic.Operator.String() + " ")
+		writeArithmetic(b, o.Arithmetic.Right, prec+1)
+		if prec < min {
+			b.WriteString(")")
+		}
+	case o.Value != nil:
+		b.WriteString(o.Value.Raw)
+	default:
+		b.WriteString(formatSelector(o.Selector))
+	}
+}
+
+func writeMatch(b *strings.Builder, expr *MatchExpression) {
+	selector := formatSelector(expr.Selector)
+	if expr.Length {
+		selector = "len(" + selector + ")"
+	}
+
+	switch expr.Operator {
+	case MatchIsEmpty:
+		b.WriteString(selector + " is empty")
+	case MatchIsNotEmpty:
+		b.WriteString(selector + " is not empty")
+	case MatchIsNull:
+		b.WriteString(selector + " is null")
+	case MatchIsNotNull:
+		b.WriteString(selector + " is not null")
+	case MatchIn, MatchNotIn:
+		b.WriteString(formatValue(expr.Value) + " " + matchOperatorSymbol(expr.Operator) + " " + selector)
+	case MatchInSet, MatchNotInSet:
+		op := "in"
+		if expr.Operator == MatchNotInSet {
+			op = "not in"
+		}
+		if expr.Values == nil {
+			b.WriteString(selector + " " + op + " @" + expr.Value.Raw)
+			return
+		}
+		b.WriteString(selector + " " + op + " " + formatList(expr.Values))
+	case MatchContainsAny, MatchNotContainsAny, MatchContainsAll, MatchNotContainsAll:
+		b.WriteString(selector + " " + matchOperatorSymbol(expr.Operator) + " " + formatList(expr.Values))
+	case MatchBetween, MatchNotBetween:
+		b.WriteString(selector + " " + matchOperatorSymbol(expr.Operator) + " " + formatValue(expr.Values[0]) + " and " + formatValue(expr.Values[1]))
+	default:
+		if expr.Value == nil {
+			b.WriteString(selector + " " + matchOperatorSymbol(expr.Operator) + ` ""`)
+			return
+		}
+		if _, bare := expr.Value.Converted.(bool); bare && expr.Operator == MatchEqual {
+			b.WriteString(selector)
+			return
+		}
+		b.WriteString(selector + " " + matchOperatorSymbol(expr.Operator) + " " + formatValue(expr.Value))
+	}
+}
+
+// Symbol returns the keyword or symbol that applies the operator in an
+// expression of the form: selector <symbol> value. The In operators, which
+// have the value first, are given their contains form.
+func (op MatchOperator) Symbol() string {
+	switch op {
+	case MatchIn:
+		return "contains"
+	case MatchNotIn:
+		return "not contains"
+	case MatchInSet:
+		return "in"
+	case MatchNotInSet:
+		return "not in"
+	default:
+		return matchOperatorSymbol(op)
+	}
+}
+
+func matchOperatorSymbol(op MatchOperator) string {
+	if op.IsCustom() {
+		return op.String()
+	}
+	switch op {
+	case MatchEqual:
+		return "=="
+	case MatchNotEqual:
+		return "!="
+	case MatchLessThan:
+		return "<"
+	case MatchLessThanOrEqual:
+		return "<="
+	case MatchGreaterThan:
+		return ">"
+	case MatchGreaterThanOrEqual:
+		return ">="
+	case MatchEqualFold:
+		return "==i"
+	case MatchNotEqualFold:
+		return "!=i"
+	case MatchPrefix:
+		return "starts with"
+	case MatchNotPrefix:
+		return "not starts with"
+	case MatchSuffix:
+		return "ends with"
+	case MatchNotSuffix:
+		return "not ends with"
+	default:
+		// the remaining operators are written as their lower cased names
+		// such as: not in cidr
+		return strings.ToLower(op.String())
+	}
+}
+
+func formatList(values []*MatchValue) string {
+	elems := make([]string, 0, len(values))
+	for _, value := range values {
+		elems = append(elems, formatValue(value))
+	}
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+func formatValue(value *MatchValue) string {
+	return formatLiteral(value, true)
+}
+
+// formatLiteral writes values unquoted when they would be parsed back to the
+// same raw value, such as numbers, durations, times and, if words are
+// allowed, single words which are not reserved. Quoted values stay quoted.
+func formatLiteral(value *MatchValue, words bool) string {
+	raw := value.Raw
+	switch {
+	case value.Parameter != "":
+		return "$" + value.Parameter
+	case value.Quoted:
+		return quoteString(raw)
+	case numberRegexp.MatchString(raw), durationRegexp.MatchString(raw), timeRegexp.MatchString(raw):
+		return raw
+	case words && identifierRegexp.MatchString(raw) && !isReservedWord(raw):
+		return raw
+	default:
+		return quoteString(raw)
+	}
+}
+
+func isReservedWord(word string) bool {
+	switch word {
+	case "and", "or", "xor", "not":
+		return true
+	}
+	return false
+}
+
+// quoteString quotes the string using the escape sequences of the grammar,
+// which unlike Go does not support \U. Strings with runes that Go would
+// escape with it are raw strings unless they contain a backtick, in which
+// case those runes are written as they are.
+func quoteString(s string) string {
+	quoted := strconv.Quote(s)
+	if !strings.Contains(quoted, `\U`) {
+		return quoted
+	}
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	start := 0
+	for i, r := range s {
+		if r > 0xFFFF && !strconv.IsPrint(r) {
+			b.WriteString(quoteInner(s[start:i]))
+			b.WriteRune(r)
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	b.WriteString(quoteInner(s[start:]))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// quoteInner returns the string as strconv.Quote escapes it, without quotes
+func quoteInner(s string) string {
+	quoted := strconv.Quote(s)
+	return quoted[1 : len(quoted)-1]
+}
+
+func formatSelector(sel Selector) string {
+	if sel.Type == SelectorTypeJsonPointer || len(sel.Path) == 0 || !identifierRegexp.MatchString(sel.Path[0]) {
+		if ptr, ok := formatPointer(sel); ok {
+			return ptr
+		}
+	}
+
+	var b strings.Builder
+	for i, part := range sel.Path {
+		switch {
+		case i == 0 && identifierRegexp.MatchString(part):
+			b.WriteString(part)
+		case i == 0:
+			// there is no syntax for a first segment which is neither an
+			// identifier nor valid within a JSON Pointer
+			b.WriteString(quoteString(part))
+		case sel.IsWildcard(i):
+			b.WriteString(".*")
+		case identifierRegexp.MatchString(part):
+			b.WriteString("." + part)
+		case indexRegexp.MatchString(part):
+			b.WriteString("[" + part + "]")
+		default:
+			b.WriteString("[" + quoteString(part) + "]")
+		}
+	}
+	return b.String()
+}
+
+// formatPointer writes the selector using the quoted JSON Pointer syntax if
+// all of its segments are made of the characters the syntax allows
+func formatPointer(sel Selector) (string, bool) {
+	var b strings.Builder
+	b.WriteString(`"`)
+	for _, part := range sel.Path {
+		part = strings.NewReplacer("~", "~0", "/", "~1").Replace(part)
+		if !pointerRegexp.MatchString(part) {
+			return "", false
+		}
+		b.WriteString("/" + part)
+	}
+	b.WriteString(`"`)
+	return b.String(), true
+}
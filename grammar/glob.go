@@ -0,0 +1,69 @@
+package grammar
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// globToRegexp converts a shell style glob pattern into an anchored regular
+// expression. A * matches any sequence of characters, a ? matches any single
+// character and [abc] matches any of the bracketed characters. Character
+// classes may contain ranges such as [a-z] and are negated with [!abc] or
+// [^abc]. A backslash matches the following character literally.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var re strings.Builder
+	re.WriteString("^")
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			re.WriteString("(?s:.*)")
+		case '?':
+			re.WriteString("(?s:.)")
+		case '\\':
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("trailing escape character")
+			}
+			i++
+			re.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '!' || runes[end] == '^') {
+				end++
+			}
+			// a closing bracket immediately after the opening one is a literal
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unclosed character class")
+			}
+
+			class := runes[i+1 : end]
+			re.WriteString("[")
+			if len(class) > 0 && (class[0] == '!' || class[0] == '^') {
+				re.WriteString("^")
+				class = class[1:]
+			}
+			for _, c := range class {
+				if c == '-' {
+					re.WriteRune(c)
+				} else {
+					re.WriteString(regexp.QuoteMeta(string(c)))
+				}
+			}
+			re.WriteString("]")
+			i = end
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	re.WriteString("$")
+	return regexp.Compile(re.String())
+}
@@ -0,0 +1,83 @@
+package grammar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	t.Parallel()
+	type testCase struct {
+		pattern string
+		matches []string
+		nomatch []string
+		err     string
+	}
+
+	tests := map[string]testCase{
+		"Literal": {
+			pattern: "web.1",
+			matches: []string{"web.1"},
+			nomatch: []string{"webx1", "web.10", "aweb.1"},
+		},
+		"Star": {
+			pattern: "web-*",
+			matches: []string{"web-", "web-01", "web-a/b\nc"},
+			nomatch: []string{"web", "db-web-01"},
+		},
+		"Question Mark": {
+			pattern: "db?",
+			matches: []string{"db1", "dbx"},
+			nomatch: []string{"db", "db12"},
+		},
+		"Character Class": {
+			pattern: "node[0-2a]",
+			matches: []string{"node0", "node2", "nodea"},
+			nomatch: []string{"node3", "nodeb", "node"},
+		},
+		"Negated Character Class": {
+			pattern: "node[!0-2]",
+			matches: []string{"node3", "nodeb"},
+			nomatch: []string{"node0", "node2"},
+		},
+		"Literal Bracket In Class": {
+			pattern: "[]]x",
+			matches: []string{"]x"},
+			nomatch: []string{"x"},
+		},
+		"Escapes": {
+			pattern: `a\*b\?`,
+			matches: []string{"a*b?"},
+			nomatch: []string{"axb?", "a*bc"},
+		},
+		"Unclosed Class": {
+			pattern: "a[bc",
+			err:     "unclosed character class",
+		},
+		"Trailing Escape": {
+			pattern: `abc\`,
+			err:     "trailing escape character",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			re, err := globToRegexp(tcase.pattern)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			for _, m := range tcase.matches {
+				require.True(t, re.MatchString(m), "%q should match %q", tcase.pattern, m)
+			}
+			for _, m := range tcase.nomatch {
+				require.False(t, re.MatchString(m), "%q should not match %q", tcase.pattern, m)
+			}
+		})
+	}
+}
@@ -106,18 +106,61 @@ var g = &grammar{
 									label: "expr",
 									expr: &ruleRefExpr{
 										pos:  position{line: 14, col: 13, offset: 188},
-										name: "OrExpression",
+										name: "CommaExpression",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 14, col: 29, offset: 204},
+									expr: &ruleRefExpr{
+										pos:  position{line: 14, col: 29, offset: 204},
+										name: "_",
+									},
+								},
+								&ruleRefExpr{
+									pos:  position{line: 14, col: 32, offset: 207},
+									name: "EOF",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 16, col: 5, offset: 237},
+						run: (*parser).callonInput26,
+						expr: &seqExpr{
+							pos: position{line: 16, col: 5, offset: 237},
+							exprs: []interface{}{
+								&andCodeExpr{
+									pos: position{line: 16, col: 5, offset: 237},
+									run: (*parser).callonInput28,
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 18, col: 3, offset: 297},
+									expr: &ruleRefExpr{
+										pos:  position{line: 18, col: 3, offset: 297},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 18, col: 6, offset: 300},
+									label: "expr",
+									expr: &ruleRefExpr{
+										pos:  position{line: 18, col: 11, offset: 305},
+										name: "CommaExpression",
 									},
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 14, col: 26, offset: 201},
+									pos: position{line: 18, col: 27, offset: 321},
 									expr: &ruleRefExpr{
-										pos:  position{line: 14, col: 26, offset: 201},
+										pos:  position{line: 18, col: 27, offset: 321},
 										name: "_",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 14, col: 29, offset: 204},
+									pos:  position{line: 18, col: 30, offset: 324},
+									name: "TrailingText",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 18, col: 43, offset: 337},
 									name: "EOF",
 								},
 							},
@@ -126,45 +169,159 @@ var g = &grammar{
 				},
 			},
 		},
+		{
+			name: "CommaExpression",
+			pos:  position{line: 23, col: 1, offset: 443},
+			expr: &actionExpr{
+				pos: position{line: 23, col: 20, offset: 462},
+				run: (*parser).callonCommaExpression1,
+				expr: &seqExpr{
+					pos: position{line: 23, col: 20, offset: 462},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 23, col: 20, offset: 462},
+							label: "first",
+							expr: &ruleRefExpr{
+								pos:  position{line: 23, col: 26, offset: 468},
+								name: "OrExpression",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 23, col: 39, offset: 481},
+							label: "rest",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 23, col: 44, offset: 486},
+								expr: &seqExpr{
+									pos: position{line: 23, col: 45, offset: 487},
+									exprs: []interface{}{
+										&andCodeExpr{
+											pos: position{line: 23, col: 45, offset: 487},
+											run: (*parser).callonCommaExpression8,
+										},
+										&zeroOrOneExpr{
+											pos: position{line: 25, col: 3, offset: 542},
+											expr: &ruleRefExpr{
+												pos:  position{line: 25, col: 3, offset: 542},
+												name: "_",
+											},
+										},
+										&litMatcher{
+											pos:        position{line: 25, col: 6, offset: 545},
+											val:        ",",
+											ignoreCase: false,
+											want:       "\",\"",
+										},
+										&zeroOrOneExpr{
+											pos: position{line: 25, col: 10, offset: 549},
+											expr: &ruleRefExpr{
+												pos:  position{line: 25, col: 10, offset: 549},
+												name: "_",
+											},
+										},
+										&ruleRefExpr{
+											pos:  position{line: 25, col: 13, offset: 552},
+											name: "OrExpression",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "TrailingText",
+			pos:  position{line: 49, col: 1, offset: 1207},
+			expr: &actionExpr{
+				pos: position{line: 49, col: 17, offset: 1223},
+				run: (*parser).callonTrailingText1,
+				expr: &oneOrMoreExpr{
+					pos: position{line: 49, col: 17, offset: 1223},
+					expr: &anyMatcher{
+						line: 49, col: 17, offset: 1223,
+					},
+				},
+			},
+		},
+		{
+			name: "SelectorInput",
+			pos:  position{line: 53, col: 1, offset: 1293},
+			expr: &actionExpr{
+				pos: position{line: 53, col: 18, offset: 1310},
+				run: (*parser).callonSelectorInput1,
+				expr: &seqExpr{
+					pos: position{line: 53, col: 18, offset: 1310},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 53, col: 18, offset: 1310},
+							expr: &ruleRefExpr{
+								pos:  position{line: 53, col: 18, offset: 1310},
+								name: "_",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 53, col: 21, offset: 1313},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 53, col: 30, offset: 1322},
+								name: "Selector",
+							},
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 53, col: 39, offset: 1331},
+							expr: &ruleRefExpr{
+								pos:  position{line: 53, col: 39, offset: 1331},
+								name: "_",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 53, col: 42, offset: 1334},
+							name: "EOF",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "OrExpression",
-			pos:  position{line: 18, col: 1, offset: 233},
+			pos:  position{line: 57, col: 1, offset: 1367},
 			expr: &choiceExpr{
-				pos: position{line: 18, col: 17, offset: 249},
+				pos: position{line: 57, col: 17, offset: 1383},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 18, col: 17, offset: 249},
+						pos: position{line: 57, col: 17, offset: 1383},
 						run: (*parser).callonOrExpression2,
 						expr: &seqExpr{
-							pos: position{line: 18, col: 17, offset: 249},
+							pos: position{line: 57, col: 17, offset: 1383},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 18, col: 17, offset: 249},
+									pos:   position{line: 57, col: 17, offset: 1383},
 									label: "left",
 									expr: &ruleRefExpr{
-										pos:  position{line: 18, col: 22, offset: 254},
-										name: "AndExpression",
+										pos:  position{line: 57, col: 22, offset: 1388},
+										name: "XorExpression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 18, col: 36, offset: 268},
+									pos:  position{line: 57, col: 36, offset: 1402},
 									name: "_",
 								},
 								&litMatcher{
-									pos:        position{line: 18, col: 38, offset: 270},
+									pos:        position{line: 57, col: 38, offset: 1404},
 									val:        "or",
 									ignoreCase: false,
 									want:       "\"or\"",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 18, col: 43, offset: 275},
+									pos:  position{line: 57, col: 43, offset: 1409},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 18, col: 45, offset: 277},
+									pos:   position{line: 57, col: 45, offset: 1411},
 									label: "right",
 									expr: &ruleRefExpr{
-										pos:  position{line: 18, col: 51, offset: 283},
+										pos:  position{line: 57, col: 51, offset: 1417},
 										name: "OrExpression",
 									},
 								},
@@ -172,59 +329,110 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 24, col: 5, offset: 433},
+						pos: position{line: 63, col: 5, offset: 1567},
 						run: (*parser).callonOrExpression11,
 						expr: &labeledExpr{
-							pos:   position{line: 24, col: 5, offset: 433},
+							pos:   position{line: 63, col: 5, offset: 1567},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 24, col: 10, offset: 438},
+								pos:  position{line: 63, col: 10, offset: 1572},
+								name: "XorExpression",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "XorExpression",
+			pos:  position{line: 67, col: 1, offset: 1611},
+			expr: &actionExpr{
+				pos: position{line: 67, col: 18, offset: 1628},
+				run: (*parser).callonXorExpression1,
+				expr: &seqExpr{
+					pos: position{line: 67, col: 18, offset: 1628},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 67, col: 18, offset: 1628},
+							label: "left",
+							expr: &ruleRefExpr{
+								pos:  position{line: 67, col: 23, offset: 1633},
 								name: "AndExpression",
 							},
 						},
+						&labeledExpr{
+							pos:   position{line: 67, col: 37, offset: 1647},
+							label: "right",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 67, col: 43, offset: 1653},
+								expr: &seqExpr{
+									pos: position{line: 67, col: 44, offset: 1654},
+									exprs: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 67, col: 44, offset: 1654},
+											name: "_",
+										},
+										&litMatcher{
+											pos:        position{line: 67, col: 46, offset: 1656},
+											val:        "xor",
+											ignoreCase: false,
+											want:       "\"xor\"",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 67, col: 52, offset: 1662},
+											name: "_",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 67, col: 54, offset: 1664},
+											name: "XorExpression",
+										},
+									},
+								},
+							},
+						},
 					},
 				},
 			},
 		},
 		{
 			name: "AndExpression",
-			pos:  position{line: 28, col: 1, offset: 477},
+			pos:  position{line: 79, col: 1, offset: 1970},
 			expr: &choiceExpr{
-				pos: position{line: 28, col: 18, offset: 494},
+				pos: position{line: 79, col: 18, offset: 1987},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 28, col: 18, offset: 494},
+						pos: position{line: 79, col: 18, offset: 1987},
 						run: (*parser).callonAndExpression2,
 						expr: &seqExpr{
-							pos: position{line: 28, col: 18, offset: 494},
+							pos: position{line: 79, col: 18, offset: 1987},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 28, col: 18, offset: 494},
+									pos:   position{line: 79, col: 18, offset: 1987},
 									label: "left",
 									expr: &ruleRefExpr{
-										pos:  position{line: 28, col: 23, offset: 499},
+										pos:  position{line: 79, col: 23, offset: 1992},
 										name: "NotExpression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 28, col: 37, offset: 513},
+									pos:  position{line: 79, col: 37, offset: 2006},
 									name: "_",
 								},
 								&litMatcher{
-									pos:        position{line: 28, col: 39, offset: 515},
+									pos:        position{line: 79, col: 39, offset: 2008},
 									val:        "and",
 									ignoreCase: false,
 									want:       "\"and\"",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 28, col: 45, offset: 521},
+									pos:  position{line: 79, col: 45, offset: 2014},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 28, col: 47, offset: 523},
+									pos:   position{line: 79, col: 47, offset: 2016},
 									label: "right",
 									expr: &ruleRefExpr{
-										pos:  position{line: 28, col: 53, offset: 529},
+										pos:  position{line: 79, col: 53, offset: 2022},
 										name: "AndExpression",
 									},
 								},
@@ -232,13 +440,13 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 34, col: 5, offset: 681},
+						pos: position{line: 85, col: 5, offset: 2174},
 						run: (*parser).callonAndExpression11,
 						expr: &labeledExpr{
-							pos:   position{line: 34, col: 5, offset: 681},
+							pos:   position{line: 85, col: 5, offset: 2174},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 34, col: 10, offset: 686},
+								pos:  position{line: 85, col: 10, offset: 2179},
 								name: "NotExpression",
 							},
 						},
@@ -248,31 +456,31 @@ var g = &grammar{
 		},
 		{
 			name: "NotExpression",
-			pos:  position{line: 38, col: 1, offset: 725},
+			pos:  position{line: 89, col: 1, offset: 2218},
 			expr: &choiceExpr{
-				pos: position{line: 38, col: 18, offset: 742},
+				pos: position{line: 89, col: 18, offset: 2235},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 38, col: 18, offset: 742},
+						pos: position{line: 89, col: 18, offset: 2235},
 						run: (*parser).callonNotExpression2,
 						expr: &seqExpr{
-							pos: position{line: 38, col: 18, offset: 742},
+							pos: position{line: 89, col: 18, offset: 2235},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 38, col: 18, offset: 742},
+									pos:        position{line: 89, col: 18, offset: 2235},
 									val:        "not",
 									ignoreCase: false,
 									want:       "\"not\"",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 38, col: 24, offset: 748},
+									pos:  position{line: 89, col: 24, offset: 2241},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 38, col: 26, offset: 750},
+									pos:   position{line: 89, col: 26, offset: 2243},
 									label: "expr",
 									expr: &ruleRefExpr{
-										pos:  position{line: 38, col: 31, offset: 755},
+										pos:  position{line: 89, col: 31, offset: 2248},
 										name: "NotExpression",
 									},
 								},
@@ -280,13 +488,13 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 49, col: 5, offset: 1142},
+						pos: position{line: 104, col: 5, offset: 2758},
 						run: (*parser).callonNotExpression8,
 						expr: &labeledExpr{
-							pos:   position{line: 49, col: 5, offset: 1142},
+							pos:   position{line: 104, col: 5, offset: 2758},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 49, col: 10, offset: 1147},
+								pos:  position{line: 104, col: 10, offset: 2763},
 								name: "ParenthesizedExpression",
 							},
 						},
@@ -297,46 +505,46 @@ var g = &grammar{
 		{
 			name:        "ParenthesizedExpression",
 			displayName: "\"grouping\"",
-			pos:         position{line: 53, col: 1, offset: 1196},
+			pos:         position{line: 108, col: 1, offset: 2812},
 			expr: &choiceExpr{
-				pos: position{line: 53, col: 39, offset: 1234},
+				pos: position{line: 108, col: 39, offset: 2850},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 53, col: 39, offset: 1234},
+						pos: position{line: 108, col: 39, offset: 2850},
 						run: (*parser).callonParenthesizedExpression2,
 						expr: &seqExpr{
-							pos: position{line: 53, col: 39, offset: 1234},
+							pos: position{line: 108, col: 39, offset: 2850},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 53, col: 39, offset: 1234},
+									pos:        position{line: 108, col: 39, offset: 2850},
 									val:        "(",
 									ignoreCase: false,
 									want:       "\"(\"",
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 53, col: 43, offset: 1238},
+									pos: position{line: 108, col: 43, offset: 2854},
 									expr: &ruleRefExpr{
-										pos:  position{line: 53, col: 43, offset: 1238},
+										pos:  position{line: 108, col: 43, offset: 2854},
 										name: "_",
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 53, col: 46, offset: 1241},
+									pos:   position{line: 108, col: 46, offset: 2857},
 									label: "expr",
 									expr: &ruleRefExpr{
-										pos:  position{line: 53, col: 51, offset: 1246},
+										pos:  position{line: 108, col: 51, offset: 2862},
 										name: "OrExpression",
 									},
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 53, col: 64, offset: 1259},
+									pos: position{line: 108, col: 64, offset: 2875},
 									expr: &ruleRefExpr{
-										pos:  position{line: 53, col: 64, offset: 1259},
+										pos:  position{line: 108, col: 64, offset: 2875},
 										name: "_",
 									},
 								},
 								&litMatcher{
-									pos:        position{line: 53, col: 67, offset: 1262},
+									pos:        position{line: 108, col: 67, offset: 2878},
 									val:        ")",
 									ignoreCase: false,
 									want:       "\")\"",
@@ -345,269 +553,533 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 55, col: 5, offset: 1292},
+						pos: position{line: 110, col: 5, offset: 2908},
 						run: (*parser).callonParenthesizedExpression12,
 						expr: &labeledExpr{
-							pos:   position{line: 55, col: 5, offset: 1292},
+							pos:   position{line: 110, col: 5, offset: 2908},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 110, col: 10, offset: 2913},
+								name: "QuantifierExpression",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 112, col: 5, offset: 2960},
+						run: (*parser).callonParenthesizedExpression15,
+						expr: &labeledExpr{
+							pos:   position{line: 112, col: 5, offset: 2960},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 112, col: 10, offset: 2965},
+								name: "FunctionExpression",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 114, col: 5, offset: 3010},
+						run: (*parser).callonParenthesizedExpression18,
+						expr: &labeledExpr{
+							pos:   position{line: 114, col: 5, offset: 3010},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 114, col: 10, offset: 3015},
+								name: "ConstantExpression",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 116, col: 5, offset: 3060},
+						run: (*parser).callonParenthesizedExpression21,
+						expr: &labeledExpr{
+							pos:   position{line: 116, col: 5, offset: 3060},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 116, col: 10, offset: 3065},
+								name: "ComparisonExpression",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 118, col: 5, offset: 3112},
+						run: (*parser).callonParenthesizedExpression24,
+						expr: &labeledExpr{
+							pos:   position{line: 118, col: 5, offset: 3112},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 55, col: 10, offset: 1297},
+								pos:  position{line: 118, col: 10, offset: 3117},
 								name: "MatchExpression",
 							},
 						},
 					},
+					&actionExpr{
+						pos: position{line: 120, col: 5, offset: 3159},
+						run: (*parser).callonParenthesizedExpression27,
+						expr: &labeledExpr{
+							pos:   position{line: 120, col: 5, offset: 3159},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 120, col: 10, offset: 3164},
+								name: "ScopedExpression",
+							},
+						},
+					},
 					&seqExpr{
-						pos: position{line: 57, col: 5, offset: 1339},
+						pos: position{line: 122, col: 5, offset: 3207},
 						exprs: []interface{}{
 							&litMatcher{
-								pos:        position{line: 57, col: 5, offset: 1339},
+								pos:        position{line: 122, col: 5, offset: 3207},
 								val:        "(",
 								ignoreCase: false,
 								want:       "\"(\"",
 							},
 							&zeroOrOneExpr{
-								pos: position{line: 57, col: 9, offset: 1343},
+								pos: position{line: 122, col: 9, offset: 3211},
 								expr: &ruleRefExpr{
-									pos:  position{line: 57, col: 9, offset: 1343},
+									pos:  position{line: 122, col: 9, offset: 3211},
 									name: "_",
 								},
 							},
 							&ruleRefExpr{
-								pos:  position{line: 57, col: 12, offset: 1346},
+								pos:  position{line: 122, col: 12, offset: 3214},
 								name: "OrExpression",
 							},
 							&zeroOrOneExpr{
-								pos: position{line: 57, col: 25, offset: 1359},
+								pos: position{line: 122, col: 25, offset: 3227},
 								expr: &ruleRefExpr{
-									pos:  position{line: 57, col: 25, offset: 1359},
+									pos:  position{line: 122, col: 25, offset: 3227},
 									name: "_",
 								},
 							},
 							&notExpr{
-								pos: position{line: 57, col: 28, offset: 1362},
+								pos: position{line: 122, col: 28, offset: 3230},
 								expr: &litMatcher{
-									pos:        position{line: 57, col: 29, offset: 1363},
+									pos:        position{line: 122, col: 29, offset: 3231},
 									val:        ")",
 									ignoreCase: false,
 									want:       "\")\"",
 								},
 							},
 							&andCodeExpr{
-								pos: position{line: 57, col: 33, offset: 1367},
-								run: (*parser).callonParenthesizedExpression24,
+								pos: position{line: 122, col: 33, offset: 3235},
+								run: (*parser).callonParenthesizedExpression39,
 							},
 						},
 					},
-				},
-			},
-		},
-		{
-			name:        "MatchExpression",
-			displayName: "\"match\"",
-			pos:         position{line: 61, col: 1, offset: 1426},
-			expr: &choiceExpr{
-				pos: position{line: 61, col: 28, offset: 1453},
-				alternatives: []interface{}{
-					&ruleRefExpr{
-						pos:  position{line: 61, col: 28, offset: 1453},
-						name: "MatchSelectorOpValue",
-					},
-					&ruleRefExpr{
-						pos:  position{line: 61, col: 51, offset: 1476},
-						name: "MatchSelectorOp",
-					},
-					&ruleRefExpr{
-						pos:  position{line: 61, col: 69, offset: 1494},
-						name: "MatchValueOpSelector",
+					&actionExpr{
+						pos: position{line: 124, col: 5, offset: 3295},
+						run: (*parser).callonParenthesizedExpression40,
+						expr: &labeledExpr{
+							pos:   position{line: 124, col: 5, offset: 3295},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 124, col: 10, offset: 3300},
+								name: "RecoveredClause",
+							},
+						},
 					},
 				},
 			},
 		},
 		{
-			name:        "MatchSelectorOpValue",
-			displayName: "\"match\"",
-			pos:         position{line: 63, col: 1, offset: 1516},
+			name:        "RecoveredClause",
+			displayName: "\"clause\"",
+			pos:         position{line: 131, col: 1, offset: 3560},
 			expr: &actionExpr{
-				pos: position{line: 63, col: 33, offset: 1548},
-				run: (*parser).callonMatchSelectorOpValue1,
+				pos: position{line: 131, col: 29, offset: 3588},
+				run: (*parser).callonRecoveredClause1,
 				expr: &seqExpr{
-					pos: position{line: 63, col: 33, offset: 1548},
+					pos: position{line: 131, col: 29, offset: 3588},
 					exprs: []interface{}{
-						&labeledExpr{
-							pos:   position{line: 63, col: 33, offset: 1548},
-							label: "selector",
-							expr: &ruleRefExpr{
-								pos:  position{line: 63, col: 42, offset: 1557},
-								name: "Selector",
+						&andCodeExpr{
+							pos: position{line: 131, col: 29, offset: 3588},
+							run: (*parser).callonRecoveredClause3,
+						},
+						&notExpr{
+							pos: position{line: 133, col: 3, offset: 3648},
+							expr: &litMatcher{
+								pos:        position{line: 133, col: 4, offset: 3649},
+								val:        "(",
+								ignoreCase: false,
+								want:       "\"(\"",
 							},
 						},
-						&labeledExpr{
-							pos:   position{line: 63, col: 51, offset: 1566},
-							label: "operator",
-							expr: &choiceExpr{
-								pos: position{line: 63, col: 61, offset: 1576},
-								alternatives: []interface{}{
-									&ruleRefExpr{
-										pos:  position{line: 63, col: 61, offset: 1576},
-										name: "MatchEqual",
-									},
-									&ruleRefExpr{
-										pos:  position{line: 63, col: 74, offset: 1589},
-										name: "MatchNotEqual",
-									},
-									&ruleRefExpr{
-										pos:  position{line: 63, col: 90, offset: 1605},
-										name: "MatchContains",
-									},
-									&ruleRefExpr{
-										pos:  position{line: 63, col: 106, offset: 1621},
-										name: "MatchNotContains",
-									},
-									&ruleRefExpr{
-										pos:  position{line: 63, col: 125, offset: 1640},
-										name: "MatchMatches",
+						&oneOrMoreExpr{
+							pos: position{line: 133, col: 8, offset: 3653},
+							expr: &seqExpr{
+								pos: position{line: 133, col: 9, offset: 3654},
+								exprs: []interface{}{
+									&notExpr{
+										pos: position{line: 133, col: 9, offset: 3654},
+										expr: &ruleRefExpr{
+											pos:  position{line: 133, col: 10, offset: 3655},
+											name: "ClauseEnd",
+										},
 									},
-									&ruleRefExpr{
-										pos:  position{line: 63, col: 140, offset: 1655},
-										name: "MatchNotMatches",
+									&choiceExpr{
+										pos: position{line: 133, col: 21, offset: 3666},
+										alternatives: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 133, col: 21, offset: 3666},
+												name: "StringLiteral",
+											},
+											&anyMatcher{
+												line: 133, col: 37, offset: 3682,
+											},
+										},
 									},
 								},
 							},
 						},
-						&labeledExpr{
-							pos:   position{line: 63, col: 157, offset: 1672},
-							label: "value",
-							expr: &ruleRefExpr{
-								pos:  position{line: 63, col: 163, offset: 1678},
-								name: "Value",
-							},
-						},
 					},
 				},
 			},
 		},
 		{
-			name:        "MatchSelectorOp",
-			displayName: "\"match\"",
-			pos:         position{line: 67, col: 1, offset: 1816},
-			expr: &actionExpr{
-				pos: position{line: 67, col: 28, offset: 1843},
-				run: (*parser).callonMatchSelectorOp1,
-				expr: &seqExpr{
-					pos: position{line: 67, col: 28, offset: 1843},
-					exprs: []interface{}{
-						&labeledExpr{
-							pos:   position{line: 67, col: 28, offset: 1843},
-							label: "selector",
-							expr: &ruleRefExpr{
-								pos:  position{line: 67, col: 37, offset: 1852},
-								name: "Selector",
+			name: "ClauseEnd",
+			pos:  position{line: 137, col: 1, offset: 3808},
+			expr: &choiceExpr{
+				pos: position{line: 137, col: 14, offset: 3821},
+				alternatives: []interface{}{
+					&seqExpr{
+						pos: position{line: 137, col: 14, offset: 3821},
+						exprs: []interface{}{
+							&ruleRefExpr{
+								pos:  position{line: 137, col: 14, offset: 3821},
+								name: "_",
 							},
-						},
-						&labeledExpr{
-							pos:   position{line: 67, col: 46, offset: 1861},
-							label: "operator",
-							expr: &choiceExpr{
-								pos: position{line: 67, col: 56, offset: 1871},
+							&choiceExpr{
+								pos: position{line: 137, col: 17, offset: 3824},
 								alternatives: []interface{}{
-									&ruleRefExpr{
-										pos:  position{line: 67, col: 56, offset: 1871},
-										name: "MatchIsEmpty",
+									&litMatcher{
+										pos:        position{line: 137, col: 17, offset: 3824},
+										val:        "and",
+										ignoreCase: false,
+										want:       "\"and\"",
 									},
-									&ruleRefExpr{
-										pos:  position{line: 67, col: 71, offset: 1886},
-										name: "MatchIsNotEmpty",
+									&litMatcher{
+										pos:        position{line: 137, col: 25, offset: 3832},
+										val:        "or",
+										ignoreCase: false,
+										want:       "\"or\"",
+									},
+									&litMatcher{
+										pos:        position{line: 137, col: 32, offset: 3839},
+										val:        "xor",
+										ignoreCase: false,
+										want:       "\"xor\"",
 									},
 								},
 							},
+							&ruleRefExpr{
+								pos:  position{line: 137, col: 39, offset: 3846},
+								name: "_",
+							},
 						},
 					},
-				},
-			},
+					&seqExpr{
+						pos: position{line: 137, col: 43, offset: 3850},
+						exprs: []interface{}{
+							&zeroOrOneExpr{
+								pos: position{line: 137, col: 43, offset: 3850},
+								expr: &ruleRefExpr{
+									pos:  position{line: 137, col: 43, offset: 3850},
+									name: "_",
+								},
+							},
+							&choiceExpr{
+								pos: position{line: 137, col: 47, offset: 3854},
+								alternatives: []interface{}{
+									&litMatcher{
+										pos:        position{line: 137, col: 47, offset: 3854},
+										val:        ")",
+										ignoreCase: false,
+										want:       "\")\"",
+									},
+									&litMatcher{
+										pos:        position{line: 137, col: 53, offset: 3860},
+										val:        "}",
+										ignoreCase: false,
+										want:       "\"}\"",
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 137, col: 60, offset: 3867},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 137, col: 60, offset: 3867},
+								run: (*parser).callonClauseEnd16,
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 139, col: 3, offset: 3922},
+								expr: &ruleRefExpr{
+									pos:  position{line: 139, col: 3, offset: 3922},
+									name: "_",
+								},
+							},
+							&litMatcher{
+								pos:        position{line: 139, col: 6, offset: 3925},
+								val:        ",",
+								ignoreCase: false,
+								want:       "\",\"",
+							},
+						},
+					},
+					&ruleRefExpr{
+						pos:  position{line: 139, col: 12, offset: 3931},
+						name: "EOF",
+					},
+				},
+			},
 		},
 		{
-			name:        "MatchValueOpSelector",
-			displayName: "\"match\"",
-			pos:         position{line: 71, col: 1, offset: 2019},
+			name:        "QuantifierExpression",
+			displayName: "\"quantifier\"",
+			pos:         position{line: 141, col: 1, offset: 3936},
 			expr: &choiceExpr{
-				pos: position{line: 71, col: 33, offset: 2051},
+				pos: position{line: 141, col: 38, offset: 3973},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 71, col: 33, offset: 2051},
-						run: (*parser).callonMatchValueOpSelector2,
+						pos: position{line: 141, col: 38, offset: 3973},
+						run: (*parser).callonQuantifierExpression2,
 						expr: &seqExpr{
-							pos: position{line: 71, col: 33, offset: 2051},
+							pos: position{line: 141, col: 38, offset: 3973},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 71, col: 33, offset: 2051},
-									label: "value",
-									expr: &ruleRefExpr{
-										pos:  position{line: 71, col: 39, offset: 2057},
-										name: "Value",
-									},
-								},
-								&labeledExpr{
-									pos:   position{line: 71, col: 45, offset: 2063},
-									label: "operator",
+									pos:   position{line: 141, col: 38, offset: 3973},
+									label: "quantifier",
 									expr: &choiceExpr{
-										pos: position{line: 71, col: 55, offset: 2073},
+										pos: position{line: 141, col: 50, offset: 3985},
 										alternatives: []interface{}{
-											&ruleRefExpr{
-												pos:  position{line: 71, col: 55, offset: 2073},
-												name: "MatchIn",
+											&litMatcher{
+												pos:        position{line: 141, col: 50, offset: 3985},
+												val:        "any",
+												ignoreCase: false,
+												want:       "\"any\"",
 											},
-											&ruleRefExpr{
-												pos:  position{line: 71, col: 65, offset: 2083},
-												name: "MatchNotIn",
+											&litMatcher{
+												pos:        position{line: 141, col: 58, offset: 3993},
+												val:        "all",
+												ignoreCase: false,
+												want:       "\"all\"",
 											},
 										},
 									},
 								},
+								&zeroOrOneExpr{
+									pos: position{line: 141, col: 65, offset: 4000},
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 65, offset: 4000},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 141, col: 68, offset: 4003},
+									val:        "(",
+									ignoreCase: false,
+									want:       "\"(\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 141, col: 72, offset: 4007},
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 72, offset: 4007},
+										name: "_",
+									},
+								},
 								&labeledExpr{
-									pos:   position{line: 71, col: 77, offset: 2095},
+									pos:   position{line: 141, col: 75, offset: 4010},
 									label: "selector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 71, col: 86, offset: 2104},
+										pos:  position{line: 141, col: 84, offset: 4019},
 										name: "Selector",
 									},
 								},
+								&zeroOrOneExpr{
+									pos: position{line: 141, col: 93, offset: 4028},
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 93, offset: 4028},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 141, col: 96, offset: 4031},
+									val:        ",",
+									ignoreCase: false,
+									want:       "\",\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 141, col: 100, offset: 4035},
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 100, offset: 4035},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 141, col: 103, offset: 4038},
+									label: "variable",
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 112, offset: 4047},
+										name: "Identifier",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 141, col: 123, offset: 4058},
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 123, offset: 4058},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 141, col: 126, offset: 4061},
+									val:        "->",
+									ignoreCase: false,
+									want:       "\"->\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 141, col: 131, offset: 4066},
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 131, offset: 4066},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 141, col: 134, offset: 4069},
+									label: "expr",
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 139, offset: 4074},
+										name: "OrExpression",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 141, col: 152, offset: 4087},
+									expr: &ruleRefExpr{
+										pos:  position{line: 141, col: 152, offset: 4087},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 141, col: 155, offset: 4090},
+									val:        ")",
+									ignoreCase: false,
+									want:       "\")\"",
+								},
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 73, col: 5, offset: 2246},
+						pos: position{line: 147, col: 5, offset: 4339},
 						exprs: []interface{}{
+							&choiceExpr{
+								pos: position{line: 147, col: 6, offset: 4340},
+								alternatives: []interface{}{
+									&litMatcher{
+										pos:        position{line: 147, col: 6, offset: 4340},
+										val:        "any",
+										ignoreCase: false,
+										want:       "\"any\"",
+									},
+									&litMatcher{
+										pos:        position{line: 147, col: 14, offset: 4348},
+										val:        "all",
+										ignoreCase: false,
+										want:       "\"all\"",
+									},
+								},
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 147, col: 21, offset: 4355},
+								expr: &ruleRefExpr{
+									pos:  position{line: 147, col: 21, offset: 4355},
+									name: "_",
+								},
+							},
+							&litMatcher{
+								pos:        position{line: 147, col: 24, offset: 4358},
+								val:        "(",
+								ignoreCase: false,
+								want:       "\"(\"",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 147, col: 28, offset: 4362},
+								expr: &ruleRefExpr{
+									pos:  position{line: 147, col: 28, offset: 4362},
+									name: "_",
+								},
+							},
 							&ruleRefExpr{
-								pos:  position{line: 73, col: 5, offset: 2246},
-								name: "Value",
+								pos:  position{line: 147, col: 31, offset: 4365},
+								name: "Selector",
 							},
-							&labeledExpr{
-								pos:   position{line: 73, col: 11, offset: 2252},
-								label: "operator",
-								expr: &choiceExpr{
-									pos: position{line: 73, col: 21, offset: 2262},
-									alternatives: []interface{}{
-										&ruleRefExpr{
-											pos:  position{line: 73, col: 21, offset: 2262},
-											name: "MatchIn",
-										},
-										&ruleRefExpr{
-											pos:  position{line: 73, col: 31, offset: 2272},
-											name: "MatchNotIn",
-										},
-									},
+							&zeroOrOneExpr{
+								pos: position{line: 147, col: 40, offset: 4374},
+								expr: &ruleRefExpr{
+									pos:  position{line: 147, col: 40, offset: 4374},
+									name: "_",
 								},
 							},
-							&notExpr{
-								pos: position{line: 73, col: 43, offset: 2284},
+							&litMatcher{
+								pos:        position{line: 147, col: 43, offset: 4377},
+								val:        ",",
+								ignoreCase: false,
+								want:       "\",\"",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 147, col: 47, offset: 4381},
 								expr: &ruleRefExpr{
-									pos:  position{line: 73, col: 44, offset: 2285},
-									name: "Selector",
+									pos:  position{line: 147, col: 47, offset: 4381},
+									name: "_",
+								},
+							},
+							&ruleRefExpr{
+								pos:  position{line: 147, col: 50, offset: 4384},
+								name: "Identifier",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 147, col: 61, offset: 4395},
+								expr: &ruleRefExpr{
+									pos:  position{line: 147, col: 61, offset: 4395},
+									name: "_",
+								},
+							},
+							&litMatcher{
+								pos:        position{line: 147, col: 64, offset: 4398},
+								val:        "->",
+								ignoreCase: false,
+								want:       "\"->\"",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 147, col: 69, offset: 4403},
+								expr: &ruleRefExpr{
+									pos:  position{line: 147, col: 69, offset: 4403},
+									name: "_",
+								},
+							},
+							&ruleRefExpr{
+								pos:  position{line: 147, col: 72, offset: 4406},
+								name: "OrExpression",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 147, col: 85, offset: 4419},
+								expr: &ruleRefExpr{
+									pos:  position{line: 147, col: 85, offset: 4419},
+									name: "_",
+								},
+							},
+							&notExpr{
+								pos: position{line: 147, col: 88, offset: 4422},
+								expr: &litMatcher{
+									pos:        position{line: 147, col: 89, offset: 4423},
+									val:        ")",
+									ignoreCase: false,
+									want:       "\")\"",
 								},
 							},
 							&andCodeExpr{
-								pos: position{line: 73, col: 53, offset: 2294},
-								run: (*parser).callonMatchValueOpSelector20,
+								pos: position{line: 147, col: 93, offset: 4427},
+								run: (*parser).callonQuantifierExpression58,
 							},
 						},
 					},
@@ -615,65 +1087,142 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "MatchEqual",
-			pos:  position{line: 77, col: 1, offset: 2348},
+			name:        "FunctionExpression",
+			displayName: "\"function\"",
+			pos:         position{line: 151, col: 1, offset: 4484},
 			expr: &actionExpr{
-				pos: position{line: 77, col: 15, offset: 2362},
-				run: (*parser).callonMatchEqual1,
+				pos: position{line: 151, col: 34, offset: 4517},
+				run: (*parser).callonFunctionExpression1,
 				expr: &seqExpr{
-					pos: position{line: 77, col: 15, offset: 2362},
+					pos: position{line: 151, col: 34, offset: 4517},
 					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 151, col: 34, offset: 4517},
+							label: "name",
+							expr: &ruleRefExpr{
+								pos:  position{line: 151, col: 39, offset: 4522},
+								name: "Identifier",
+							},
+						},
 						&zeroOrOneExpr{
-							pos: position{line: 77, col: 15, offset: 2362},
+							pos: position{line: 151, col: 50, offset: 4533},
 							expr: &ruleRefExpr{
-								pos:  position{line: 77, col: 15, offset: 2362},
+								pos:  position{line: 151, col: 50, offset: 4533},
 								name: "_",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 77, col: 18, offset: 2365},
-							val:        "==",
+							pos:        position{line: 151, col: 53, offset: 4536},
+							val:        "(",
 							ignoreCase: false,
-							want:       "\"==\"",
+							want:       "\"(\"",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 77, col: 23, offset: 2370},
+							pos: position{line: 151, col: 57, offset: 4540},
 							expr: &ruleRefExpr{
-								pos:  position{line: 77, col: 23, offset: 2370},
+								pos:  position{line: 151, col: 57, offset: 4540},
 								name: "_",
 							},
 						},
-					},
-				},
-			},
-		},
-		{
-			name: "MatchNotEqual",
-			pos:  position{line: 80, col: 1, offset: 2403},
-			expr: &actionExpr{
-				pos: position{line: 80, col: 18, offset: 2420},
-				run: (*parser).callonMatchNotEqual1,
-				expr: &seqExpr{
-					pos: position{line: 80, col: 18, offset: 2420},
-					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 151, col: 60, offset: 4543},
+							label: "args",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 151, col: 65, offset: 4548},
+								expr: &ruleRefExpr{
+									pos:  position{line: 151, col: 65, offset: 4548},
+									name: "FunctionArguments",
+								},
+							},
+						},
 						&zeroOrOneExpr{
-							pos: position{line: 80, col: 18, offset: 2420},
+							pos: position{line: 151, col: 84, offset: 4567},
 							expr: &ruleRefExpr{
-								pos:  position{line: 80, col: 18, offset: 2420},
+								pos:  position{line: 151, col: 84, offset: 4567},
 								name: "_",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 80, col: 21, offset: 2423},
-							val:        "!=",
+							pos:        position{line: 151, col: 87, offset: 4570},
+							val:        ")",
 							ignoreCase: false,
-							want:       "\"!=\"",
+							want:       "\")\"",
 						},
-						&zeroOrOneExpr{
-							pos: position{line: 80, col: 26, offset: 2428},
-							expr: &ruleRefExpr{
-								pos:  position{line: 80, col: 26, offset: 2428},
-								name: "_",
+						&andExpr{
+							pos: position{line: 151, col: 91, offset: 4574},
+							expr: &choiceExpr{
+								pos: position{line: 151, col: 93, offset: 4576},
+								alternatives: []interface{}{
+									&seqExpr{
+										pos: position{line: 151, col: 93, offset: 4576},
+										exprs: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 151, col: 93, offset: 4576},
+												name: "_",
+											},
+											&choiceExpr{
+												pos: position{line: 151, col: 96, offset: 4579},
+												alternatives: []interface{}{
+													&litMatcher{
+														pos:        position{line: 151, col: 96, offset: 4579},
+														val:        "and",
+														ignoreCase: false,
+														want:       "\"and\"",
+													},
+													&litMatcher{
+														pos:        position{line: 151, col: 104, offset: 4587},
+														val:        "or",
+														ignoreCase: false,
+														want:       "\"or\"",
+													},
+													&litMatcher{
+														pos:        position{line: 151, col: 111, offset: 4594},
+														val:        "xor",
+														ignoreCase: false,
+														want:       "\"xor\"",
+													},
+												},
+											},
+											&ruleRefExpr{
+												pos:  position{line: 151, col: 118, offset: 4601},
+												name: "_",
+											},
+										},
+									},
+									&seqExpr{
+										pos: position{line: 151, col: 122, offset: 4605},
+										exprs: []interface{}{
+											&zeroOrOneExpr{
+												pos: position{line: 151, col: 122, offset: 4605},
+												expr: &ruleRefExpr{
+													pos:  position{line: 151, col: 122, offset: 4605},
+													name: "_",
+												},
+											},
+											&choiceExpr{
+												pos: position{line: 151, col: 126, offset: 4609},
+												alternatives: []interface{}{
+													&litMatcher{
+														pos:        position{line: 151, col: 126, offset: 4609},
+														val:        ")",
+														ignoreCase: false,
+														want:       "\")\"",
+													},
+													&litMatcher{
+														pos:        position{line: 151, col: 132, offset: 4615},
+														val:        "}",
+														ignoreCase: false,
+														want:       "\"}\"",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 151, col: 138, offset: 4621},
+														name: "EOF",
+													},
+												},
+											},
+										},
+									},
+								},
 							},
 						},
 					},
@@ -681,370 +1230,479 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "MatchIsEmpty",
-			pos:  position{line: 83, col: 1, offset: 2464},
+			name: "FunctionArguments",
+			pos:  position{line: 159, col: 1, offset: 4774},
 			expr: &actionExpr{
-				pos: position{line: 83, col: 17, offset: 2480},
-				run: (*parser).callonMatchIsEmpty1,
+				pos: position{line: 159, col: 22, offset: 4795},
+				run: (*parser).callonFunctionArguments1,
 				expr: &seqExpr{
-					pos: position{line: 83, col: 17, offset: 2480},
+					pos: position{line: 159, col: 22, offset: 4795},
 					exprs: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 83, col: 17, offset: 2480},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 83, col: 19, offset: 2482},
-							val:        "is",
-							ignoreCase: false,
-							want:       "\"is\"",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 83, col: 24, offset: 2487},
-							name: "_",
+						&labeledExpr{
+							pos:   position{line: 159, col: 22, offset: 4795},
+							label: "first",
+							expr: &ruleRefExpr{
+								pos:  position{line: 159, col: 28, offset: 4801},
+								name: "FunctionArgument",
+							},
 						},
-						&litMatcher{
-							pos:        position{line: 83, col: 26, offset: 2489},
-							val:        "empty",
-							ignoreCase: false,
-							want:       "\"empty\"",
+						&labeledExpr{
+							pos:   position{line: 159, col: 45, offset: 4818},
+							label: "rest",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 159, col: 50, offset: 4823},
+								expr: &seqExpr{
+									pos: position{line: 159, col: 51, offset: 4824},
+									exprs: []interface{}{
+										&zeroOrOneExpr{
+											pos: position{line: 159, col: 51, offset: 4824},
+											expr: &ruleRefExpr{
+												pos:  position{line: 159, col: 51, offset: 4824},
+												name: "_",
+											},
+										},
+										&litMatcher{
+											pos:        position{line: 159, col: 54, offset: 4827},
+											val:        ",",
+											ignoreCase: false,
+											want:       "\",\"",
+										},
+										&zeroOrOneExpr{
+											pos: position{line: 159, col: 58, offset: 4831},
+											expr: &ruleRefExpr{
+												pos:  position{line: 159, col: 58, offset: 4831},
+												name: "_",
+											},
+										},
+										&ruleRefExpr{
+											pos:  position{line: 159, col: 61, offset: 4834},
+											name: "FunctionArgument",
+										},
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "MatchIsNotEmpty",
-			pos:  position{line: 86, col: 1, offset: 2529},
-			expr: &actionExpr{
-				pos: position{line: 86, col: 20, offset: 2548},
-				run: (*parser).callonMatchIsNotEmpty1,
-				expr: &seqExpr{
-					pos: position{line: 86, col: 20, offset: 2548},
-					exprs: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 86, col: 20, offset: 2548},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 86, col: 21, offset: 2549},
-							val:        "is",
-							ignoreCase: false,
-							want:       "\"is\"",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 86, col: 26, offset: 2554},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 86, col: 28, offset: 2556},
-							val:        "not",
-							ignoreCase: false,
-							want:       "\"not\"",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 86, col: 34, offset: 2562},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 86, col: 36, offset: 2564},
-							val:        "empty",
-							ignoreCase: false,
-							want:       "\"empty\"",
+			name: "FunctionArgument",
+			pos:  position{line: 167, col: 1, offset: 5053},
+			expr: &choiceExpr{
+				pos: position{line: 167, col: 21, offset: 5073},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 167, col: 21, offset: 5073},
+						run: (*parser).callonFunctionArgument2,
+						expr: &labeledExpr{
+							pos:   position{line: 167, col: 21, offset: 5073},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 167, col: 30, offset: 5082},
+								name: "Selector",
+							},
 						},
 					},
-				},
-			},
-		},
-		{
-			name: "MatchIn",
-			pos:  position{line: 89, col: 1, offset: 2607},
-			expr: &actionExpr{
-				pos: position{line: 89, col: 12, offset: 2618},
-				run: (*parser).callonMatchIn1,
-				expr: &seqExpr{
-					pos: position{line: 89, col: 12, offset: 2618},
-					exprs: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 89, col: 12, offset: 2618},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 89, col: 14, offset: 2620},
-							val:        "in",
-							ignoreCase: false,
-							want:       "\"in\"",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 89, col: 19, offset: 2625},
-							name: "_",
+					&actionExpr{
+						pos: position{line: 169, col: 5, offset: 5161},
+						run: (*parser).callonFunctionArgument5,
+						expr: &labeledExpr{
+							pos:   position{line: 169, col: 5, offset: 5161},
+							label: "value",
+							expr: &ruleRefExpr{
+								pos:  position{line: 169, col: 11, offset: 5167},
+								name: "Value",
+							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "MatchNotIn",
-			pos:  position{line: 92, col: 1, offset: 2654},
+			name:        "ComparisonExpression",
+			displayName: "\"comparison\"",
+			pos:         position{line: 173, col: 1, offset: 5239},
 			expr: &actionExpr{
-				pos: position{line: 92, col: 15, offset: 2668},
-				run: (*parser).callonMatchNotIn1,
+				pos: position{line: 173, col: 38, offset: 5276},
+				run: (*parser).callonComparisonExpression1,
 				expr: &seqExpr{
-					pos: position{line: 92, col: 15, offset: 2668},
+					pos: position{line: 173, col: 38, offset: 5276},
 					exprs: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 92, col: 15, offset: 2668},
-							name: "_",
+						&labeledExpr{
+							pos:   position{line: 173, col: 38, offset: 5276},
+							label: "left",
+							expr: &ruleRefExpr{
+								pos:  position{line: 173, col: 43, offset: 5281},
+								name: "ArithmeticSum",
+							},
 						},
-						&litMatcher{
-							pos:        position{line: 92, col: 17, offset: 2670},
-							val:        "not",
-							ignoreCase: false,
-							want:       "\"not\"",
+						&notExpr{
+							pos: position{line: 173, col: 57, offset: 5295},
+							expr: &choiceExpr{
+								pos: position{line: 173, col: 59, offset: 5297},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 59, offset: 5297},
+										name: "MatchEqualFold",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 76, offset: 5314},
+										name: "MatchNotEqualFold",
+									},
+								},
+							},
 						},
-						&ruleRefExpr{
-							pos:  position{line: 92, col: 23, offset: 2676},
-							name: "_",
+						&labeledExpr{
+							pos:   position{line: 173, col: 95, offset: 5333},
+							label: "operator",
+							expr: &choiceExpr{
+								pos: position{line: 173, col: 105, offset: 5343},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 105, offset: 5343},
+										name: "MatchEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 118, offset: 5356},
+										name: "MatchNotEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 134, offset: 5372},
+										name: "MatchLessThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 157, offset: 5395},
+										name: "MatchLessThan",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 173, offset: 5411},
+										name: "MatchGreaterThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 173, col: 199, offset: 5437},
+										name: "MatchGreaterThan",
+									},
+								},
+							},
 						},
-						&litMatcher{
-							pos:        position{line: 92, col: 25, offset: 2678},
-							val:        "in",
-							ignoreCase: false,
-							want:       "\"in\"",
+						&labeledExpr{
+							pos:   position{line: 173, col: 217, offset: 5455},
+							label: "right",
+							expr: &ruleRefExpr{
+								pos:  position{line: 173, col: 223, offset: 5461},
+								name: "ArithmeticSum",
+							},
 						},
-						&ruleRefExpr{
-							pos:  position{line: 92, col: 30, offset: 2683},
-							name: "_",
+						&andCodeExpr{
+							pos: position{line: 173, col: 237, offset: 5475},
+							run: (*parser).callonComparisonExpression19,
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "MatchContains",
-			pos:  position{line: 95, col: 1, offset: 2715},
+			name: "ArithmeticSum",
+			pos:  position{line: 180, col: 1, offset: 5762},
 			expr: &actionExpr{
-				pos: position{line: 95, col: 18, offset: 2732},
-				run: (*parser).callonMatchContains1,
+				pos: position{line: 180, col: 18, offset: 5779},
+				run: (*parser).callonArithmeticSum1,
 				expr: &seqExpr{
-					pos: position{line: 95, col: 18, offset: 2732},
+					pos: position{line: 180, col: 18, offset: 5779},
 					exprs: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 95, col: 18, offset: 2732},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 95, col: 20, offset: 2734},
-							val:        "contains",
-							ignoreCase: false,
-							want:       "\"contains\"",
+						&labeledExpr{
+							pos:   position{line: 180, col: 18, offset: 5779},
+							label: "first",
+							expr: &ruleRefExpr{
+								pos:  position{line: 180, col: 24, offset: 5785},
+								name: "ArithmeticProduct",
+							},
 						},
-						&ruleRefExpr{
-							pos:  position{line: 95, col: 31, offset: 2745},
-							name: "_",
+						&labeledExpr{
+							pos:   position{line: 180, col: 42, offset: 5803},
+							label: "rest",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 180, col: 47, offset: 5808},
+								expr: &seqExpr{
+									pos: position{line: 180, col: 48, offset: 5809},
+									exprs: []interface{}{
+										&zeroOrOneExpr{
+											pos: position{line: 180, col: 48, offset: 5809},
+											expr: &ruleRefExpr{
+												pos:  position{line: 180, col: 48, offset: 5809},
+												name: "_",
+											},
+										},
+										&ruleRefExpr{
+											pos:  position{line: 180, col: 51, offset: 5812},
+											name: "ArithmeticSumOp",
+										},
+										&zeroOrOneExpr{
+											pos: position{line: 180, col: 67, offset: 5828},
+											expr: &ruleRefExpr{
+												pos:  position{line: 180, col: 67, offset: 5828},
+												name: "_",
+											},
+										},
+										&ruleRefExpr{
+											pos:  position{line: 180, col: 70, offset: 5831},
+											name: "ArithmeticProduct",
+										},
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "MatchNotContains",
-			pos:  position{line: 98, col: 1, offset: 2774},
+			name: "ArithmeticProduct",
+			pos:  position{line: 184, col: 1, offset: 5899},
 			expr: &actionExpr{
-				pos: position{line: 98, col: 21, offset: 2794},
-				run: (*parser).callonMatchNotContains1,
+				pos: position{line: 184, col: 22, offset: 5920},
+				run: (*parser).callonArithmeticProduct1,
 				expr: &seqExpr{
-					pos: position{line: 98, col: 21, offset: 2794},
+					pos: position{line: 184, col: 22, offset: 5920},
 					exprs: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 98, col: 21, offset: 2794},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 98, col: 23, offset: 2796},
-							val:        "not",
-							ignoreCase: false,
-							want:       "\"not\"",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 98, col: 29, offset: 2802},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 98, col: 31, offset: 2804},
-							val:        "contains",
-							ignoreCase: false,
-							want:       "\"contains\"",
+						&labeledExpr{
+							pos:   position{line: 184, col: 22, offset: 5920},
+							label: "first",
+							expr: &ruleRefExpr{
+								pos:  position{line: 184, col: 28, offset: 5926},
+								name: "ArithmeticOperand",
+							},
 						},
-						&ruleRefExpr{
-							pos:  position{line: 98, col: 42, offset: 2815},
-							name: "_",
+						&labeledExpr{
+							pos:   position{line: 184, col: 46, offset: 5944},
+							label: "rest",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 184, col: 51, offset: 5949},
+								expr: &seqExpr{
+									pos: position{line: 184, col: 52, offset: 5950},
+									exprs: []interface{}{
+										&zeroOrOneExpr{
+											pos: position{line: 184, col: 52, offset: 5950},
+											expr: &ruleRefExpr{
+												pos:  position{line: 184, col: 52, offset: 5950},
+												name: "_",
+											},
+										},
+										&ruleRefExpr{
+											pos:  position{line: 184, col: 55, offset: 5953},
+											name: "ArithmeticProductOp",
+										},
+										&zeroOrOneExpr{
+											pos: position{line: 184, col: 75, offset: 5973},
+											expr: &ruleRefExpr{
+												pos:  position{line: 184, col: 75, offset: 5973},
+												name: "_",
+											},
+										},
+										&ruleRefExpr{
+											pos:  position{line: 184, col: 78, offset: 5976},
+											name: "ArithmeticOperand",
+										},
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "MatchMatches",
-			pos:  position{line: 101, col: 1, offset: 2847},
-			expr: &actionExpr{
-				pos: position{line: 101, col: 17, offset: 2863},
-				run: (*parser).callonMatchMatches1,
-				expr: &seqExpr{
-					pos: position{line: 101, col: 17, offset: 2863},
-					exprs: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 101, col: 17, offset: 2863},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 101, col: 19, offset: 2865},
-							val:        "matches",
+			name: "ArithmeticSumOp",
+			pos:  position{line: 188, col: 1, offset: 6044},
+			expr: &choiceExpr{
+				pos: position{line: 188, col: 20, offset: 6063},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 188, col: 20, offset: 6063},
+						run: (*parser).callonArithmeticSumOp2,
+						expr: &litMatcher{
+							pos:        position{line: 188, col: 20, offset: 6063},
+							val:        "+",
 							ignoreCase: false,
-							want:       "\"matches\"",
+							want:       "\"+\"",
 						},
-						&ruleRefExpr{
-							pos:  position{line: 101, col: 29, offset: 2875},
-							name: "_",
+					},
+					&actionExpr{
+						pos: position{line: 190, col: 5, offset: 6102},
+						run: (*parser).callonArithmeticSumOp4,
+						expr: &litMatcher{
+							pos:        position{line: 190, col: 5, offset: 6102},
+							val:        "-",
+							ignoreCase: false,
+							want:       "\"-\"",
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "MatchNotMatches",
-			pos:  position{line: 104, col: 1, offset: 2909},
-			expr: &actionExpr{
-				pos: position{line: 104, col: 20, offset: 2928},
-				run: (*parser).callonMatchNotMatches1,
-				expr: &seqExpr{
-					pos: position{line: 104, col: 20, offset: 2928},
-					exprs: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 104, col: 20, offset: 2928},
-							name: "_",
-						},
-						&litMatcher{
-							pos:        position{line: 104, col: 22, offset: 2930},
-							val:        "not",
+			name: "ArithmeticProductOp",
+			pos:  position{line: 194, col: 1, offset: 6145},
+			expr: &choiceExpr{
+				pos: position{line: 194, col: 24, offset: 6168},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 194, col: 24, offset: 6168},
+						run: (*parser).callonArithmeticProductOp2,
+						expr: &litMatcher{
+							pos:        position{line: 194, col: 24, offset: 6168},
+							val:        "*",
 							ignoreCase: false,
-							want:       "\"not\"",
+							want:       "\"*\"",
 						},
-						&ruleRefExpr{
-							pos:  position{line: 104, col: 28, offset: 2936},
-							name: "_",
+					},
+					&actionExpr{
+						pos: position{line: 196, col: 5, offset: 6212},
+						run: (*parser).callonArithmeticProductOp4,
+						expr: &seqExpr{
+							pos: position{line: 196, col: 5, offset: 6212},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 196, col: 5, offset: 6212},
+									val:        "/",
+									ignoreCase: false,
+									want:       "\"/\"",
+								},
+								&notExpr{
+									pos: position{line: 196, col: 9, offset: 6216},
+									expr: &litMatcher{
+										pos:        position{line: 196, col: 10, offset: 6217},
+										val:        "*",
+										ignoreCase: false,
+										want:       "\"*\"",
+									},
+								},
+							},
 						},
-						&litMatcher{
-							pos:        position{line: 104, col: 30, offset: 2938},
-							val:        "matches",
+					},
+					&actionExpr{
+						pos: position{line: 198, col: 5, offset: 6259},
+						run: (*parser).callonArithmeticProductOp9,
+						expr: &litMatcher{
+							pos:        position{line: 198, col: 5, offset: 6259},
+							val:        "%",
 							ignoreCase: false,
-							want:       "\"matches\"",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 104, col: 40, offset: 2948},
-							name: "_",
+							want:       "\"%\"",
 						},
 					},
 				},
 			},
 		},
 		{
-			name:        "Selector",
-			displayName: "\"selector\"",
-			pos:         position{line: 108, col: 1, offset: 2986},
+			name: "ArithmeticOperand",
+			pos:  position{line: 202, col: 1, offset: 6300},
 			expr: &choiceExpr{
-				pos: position{line: 108, col: 24, offset: 3009},
+				pos: position{line: 202, col: 22, offset: 6321},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 108, col: 24, offset: 3009},
-						run: (*parser).callonSelector2,
+						pos: position{line: 202, col: 22, offset: 6321},
+						run: (*parser).callonArithmeticOperand2,
 						expr: &seqExpr{
-							pos: position{line: 108, col: 24, offset: 3009},
+							pos: position{line: 202, col: 22, offset: 6321},
 							exprs: []interface{}{
-								&labeledExpr{
-									pos:   position{line: 108, col: 24, offset: 3009},
-									label: "first",
-									expr: &ruleRefExpr{
-										pos:  position{line: 108, col: 30, offset: 3015},
-										name: "Identifier",
-									},
-								},
-								&labeledExpr{
-									pos:   position{line: 108, col: 41, offset: 3026},
-									label: "rest",
-									expr: &zeroOrMoreExpr{
-										pos: position{line: 108, col: 46, offset: 3031},
-										expr: &ruleRefExpr{
-											pos:  position{line: 108, col: 46, offset: 3031},
-											name: "SelectorOrIndex",
-										},
+								&litMatcher{
+									pos:        position{line: 202, col: 22, offset: 6321},
+									val:        "(",
+									ignoreCase: false,
+									want:       "\"(\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 202, col: 26, offset: 6325},
+									expr: &ruleRefExpr{
+										pos:  position{line: 202, col: 26, offset: 6325},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 202, col: 29, offset: 6328},
+									label: "expr",
+									expr: &ruleRefExpr{
+										pos:  position{line: 202, col: 34, offset: 6333},
+										name: "ArithmeticSum",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 202, col: 48, offset: 6347},
+									expr: &ruleRefExpr{
+										pos:  position{line: 202, col: 48, offset: 6347},
+										name: "_",
 									},
 								},
+								&litMatcher{
+									pos:        position{line: 202, col: 51, offset: 6350},
+									val:        ")",
+									ignoreCase: false,
+									want:       "\")\"",
+								},
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 119, col: 5, offset: 3295},
-						run: (*parser).callonSelector9,
+						pos: position{line: 204, col: 5, offset: 6380},
+						run: (*parser).callonArithmeticOperand12,
 						expr: &seqExpr{
-							pos: position{line: 119, col: 5, offset: 3295},
+							pos: position{line: 204, col: 5, offset: 6380},
 							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 119, col: 5, offset: 3295},
-									val:        "\"",
-									ignoreCase: false,
-									want:       "\"\\\"\"",
+								&notExpr{
+									pos: position{line: 204, col: 5, offset: 6380},
+									expr: &ruleRefExpr{
+										pos:  position{line: 204, col: 6, offset: 6381},
+										name: "TimeLiteral",
+									},
 								},
 								&labeledExpr{
-									pos:   position{line: 119, col: 9, offset: 3299},
-									label: "ptrsegs",
-									expr: &zeroOrMoreExpr{
-										pos: position{line: 119, col: 17, offset: 3307},
-										expr: &ruleRefExpr{
-											pos:  position{line: 119, col: 17, offset: 3307},
-											name: "JsonPointerSegment",
+									pos:   position{line: 204, col: 18, offset: 6393},
+									label: "value",
+									expr: &choiceExpr{
+										pos: position{line: 204, col: 25, offset: 6400},
+										alternatives: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 204, col: 25, offset: 6400},
+												name: "DurationLiteral",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 204, col: 43, offset: 6418},
+												name: "NumberLiteral",
+											},
 										},
 									},
 								},
-								&litMatcher{
-									pos:        position{line: 119, col: 37, offset: 3327},
-									val:        "\"",
-									ignoreCase: false,
-									want:       "\"\\\"\"",
-								},
 							},
 						},
 					},
-				},
-			},
-		},
-		{
-			name: "JsonPointerSegment",
-			pos:  position{line: 140, col: 1, offset: 3805},
-			expr: &actionExpr{
-				pos: position{line: 140, col: 23, offset: 3827},
-				run: (*parser).callonJsonPointerSegment1,
-				expr: &seqExpr{
-					pos: position{line: 140, col: 23, offset: 3827},
-					exprs: []interface{}{
-						&litMatcher{
-							pos:        position{line: 140, col: 23, offset: 3827},
-							val:        "/",
-							ignoreCase: false,
-							want:       "\"/\"",
-						},
-						&labeledExpr{
-							pos:   position{line: 140, col: 27, offset: 3831},
-							label: "ident",
-							expr: &oneOrMoreExpr{
-								pos: position{line: 140, col: 33, offset: 3837},
-								expr: &charClassMatcher{
-									pos:        position{line: 140, col: 33, offset: 3837},
-									val:        "[\\pL\\pN-_.~|]",
-									chars:      []rune{'-', '_', '.', '~', '|'},
-									classes:    []*unicode.RangeTable{rangeTable("L"), rangeTable("N")},
-									ignoreCase: false,
-									inverted:   false,
+					&actionExpr{
+						pos: position{line: 206, col: 5, offset: 6504},
+						run: (*parser).callonArithmeticOperand20,
+						expr: &seqExpr{
+							pos: position{line: 206, col: 5, offset: 6504},
+							exprs: []interface{}{
+								&notExpr{
+									pos: position{line: 206, col: 5, offset: 6504},
+									expr: &ruleRefExpr{
+										pos:  position{line: 206, col: 6, offset: 6505},
+										name: "ReservedWord",
+									},
+								},
+								&notExpr{
+									pos: position{line: 206, col: 19, offset: 6518},
+									expr: &ruleRefExpr{
+										pos:  position{line: 206, col: 20, offset: 6519},
+										name: "TimeLiteral",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 206, col: 32, offset: 6531},
+									label: "selector",
+									expr: &ruleRefExpr{
+										pos:  position{line: 206, col: 41, offset: 6540},
+										name: "Selector",
+									},
 								},
 							},
 						},
@@ -1053,100 +1711,108 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "Identifier",
-			pos:  position{line: 144, col: 1, offset: 3891},
+			name:        "ConstantExpression",
+			displayName: "\"constant\"",
+			pos:         position{line: 211, col: 1, offset: 6679},
 			expr: &actionExpr{
-				pos: position{line: 144, col: 15, offset: 3905},
-				run: (*parser).callonIdentifier1,
+				pos: position{line: 211, col: 34, offset: 6712},
+				run: (*parser).callonConstantExpression1,
 				expr: &seqExpr{
-					pos: position{line: 144, col: 15, offset: 3905},
+					pos: position{line: 211, col: 34, offset: 6712},
 					exprs: []interface{}{
-						&charClassMatcher{
-							pos:        position{line: 144, col: 15, offset: 3905},
-							val:        "[a-zA-Z]",
-							ranges:     []rune{'a', 'z', 'A', 'Z'},
-							ignoreCase: false,
-							inverted:   false,
-						},
-						&zeroOrMoreExpr{
-							pos: position{line: 144, col: 24, offset: 3914},
-							expr: &charClassMatcher{
-								pos:        position{line: 144, col: 24, offset: 3914},
-								val:        "[a-zA-Z0-9_]",
-								chars:      []rune{'_'},
-								ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
-								ignoreCase: false,
-								inverted:   false,
-							},
-						},
-					},
-				},
-			},
-		},
-		{
-			name: "SelectorOrIndex",
-			pos:  position{line: 148, col: 1, offset: 3963},
-			expr: &choiceExpr{
-				pos: position{line: 148, col: 20, offset: 3982},
-				alternatives: []interface{}{
-					&actionExpr{
-						pos: position{line: 148, col: 20, offset: 3982},
-						run: (*parser).callonSelectorOrIndex2,
-						expr: &seqExpr{
-							pos: position{line: 148, col: 20, offset: 3982},
-							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 148, col: 20, offset: 3982},
-									val:        ".",
-									ignoreCase: false,
-									want:       "\".\"",
-								},
-								&labeledExpr{
-									pos:   position{line: 148, col: 24, offset: 3986},
-									label: "ident",
-									expr: &ruleRefExpr{
-										pos:  position{line: 148, col: 30, offset: 3992},
-										name: "Identifier",
+						&labeledExpr{
+							pos:   position{line: 211, col: 34, offset: 6712},
+							label: "value",
+							expr: &choiceExpr{
+								pos: position{line: 211, col: 41, offset: 6719},
+								alternatives: []interface{}{
+									&litMatcher{
+										pos:        position{line: 211, col: 41, offset: 6719},
+										val:        "true",
+										ignoreCase: false,
+										want:       "\"true\"",
+									},
+									&litMatcher{
+										pos:        position{line: 211, col: 50, offset: 6728},
+										val:        "false",
+										ignoreCase: false,
+										want:       "\"false\"",
 									},
 								},
 							},
 						},
-					},
-					&actionExpr{
-						pos: position{line: 150, col: 5, offset: 4030},
-						run: (*parser).callonSelectorOrIndex7,
-						expr: &labeledExpr{
-							pos:   position{line: 150, col: 5, offset: 4030},
-							label: "expr",
-							expr: &ruleRefExpr{
-								pos:  position{line: 150, col: 10, offset: 4035},
-								name: "IndexExpression",
-							},
-						},
-					},
-					&actionExpr{
-						pos: position{line: 152, col: 5, offset: 4077},
-						run: (*parser).callonSelectorOrIndex10,
-						expr: &seqExpr{
-							pos: position{line: 152, col: 5, offset: 4077},
-							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 152, col: 5, offset: 4077},
-									val:        ".",
-									ignoreCase: false,
-									want:       "\".\"",
-								},
-								&labeledExpr{
-									pos:   position{line: 152, col: 9, offset: 4081},
-									label: "idx",
-									expr: &oneOrMoreExpr{
-										pos: position{line: 152, col: 13, offset: 4085},
-										expr: &charClassMatcher{
-											pos:        position{line: 152, col: 13, offset: 4085},
-											val:        "[0-9]",
-											ranges:     []rune{'0', '9'},
-											ignoreCase: false,
-											inverted:   false,
+						&andExpr{
+							pos: position{line: 211, col: 59, offset: 6737},
+							expr: &choiceExpr{
+								pos: position{line: 211, col: 61, offset: 6739},
+								alternatives: []interface{}{
+									&seqExpr{
+										pos: position{line: 211, col: 61, offset: 6739},
+										exprs: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 211, col: 61, offset: 6739},
+												name: "_",
+											},
+											&choiceExpr{
+												pos: position{line: 211, col: 64, offset: 6742},
+												alternatives: []interface{}{
+													&litMatcher{
+														pos:        position{line: 211, col: 64, offset: 6742},
+														val:        "and",
+														ignoreCase: false,
+														want:       "\"and\"",
+													},
+													&litMatcher{
+														pos:        position{line: 211, col: 72, offset: 6750},
+														val:        "or",
+														ignoreCase: false,
+														want:       "\"or\"",
+													},
+													&litMatcher{
+														pos:        position{line: 211, col: 79, offset: 6757},
+														val:        "xor",
+														ignoreCase: false,
+														want:       "\"xor\"",
+													},
+												},
+											},
+											&ruleRefExpr{
+												pos:  position{line: 211, col: 86, offset: 6764},
+												name: "_",
+											},
+										},
+									},
+									&seqExpr{
+										pos: position{line: 211, col: 90, offset: 6768},
+										exprs: []interface{}{
+											&zeroOrOneExpr{
+												pos: position{line: 211, col: 90, offset: 6768},
+												expr: &ruleRefExpr{
+													pos:  position{line: 211, col: 90, offset: 6768},
+													name: "_",
+												},
+											},
+											&choiceExpr{
+												pos: position{line: 211, col: 94, offset: 6772},
+												alternatives: []interface{}{
+													&litMatcher{
+														pos:        position{line: 211, col: 94, offset: 6772},
+														val:        ")",
+														ignoreCase: false,
+														want:       "\")\"",
+													},
+													&litMatcher{
+														pos:        position{line: 211, col: 100, offset: 6778},
+														val:        "}",
+														ignoreCase: false,
+														want:       "\"}\"",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 211, col: 106, offset: 6784},
+														name: "EOF",
+													},
+												},
+											},
 										},
 									},
 								},
@@ -1157,123 +1823,120 @@ var g = &grammar{
 			},
 		},
 		{
-			name:        "IndexExpression",
-			displayName: "\"index\"",
-			pos:         position{line: 156, col: 1, offset: 4131},
+			name:        "ScopedExpression",
+			displayName: "\"scope\"",
+			pos:         position{line: 215, col: 1, offset: 6871},
 			expr: &choiceExpr{
-				pos: position{line: 156, col: 28, offset: 4158},
+				pos: position{line: 215, col: 29, offset: 6899},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 156, col: 28, offset: 4158},
-						run: (*parser).callonIndexExpression2,
+						pos: position{line: 215, col: 29, offset: 6899},
+						run: (*parser).callonScopedExpression2,
 						expr: &seqExpr{
-							pos: position{line: 156, col: 28, offset: 4158},
+							pos: position{line: 215, col: 29, offset: 6899},
 							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 215, col: 29, offset: 6899},
+									label: "selector",
+									expr: &ruleRefExpr{
+										pos:  position{line: 215, col: 38, offset: 6908},
+										name: "Selector",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 215, col: 47, offset: 6917},
+									expr: &ruleRefExpr{
+										pos:  position{line: 215, col: 47, offset: 6917},
+										name: "_",
+									},
+								},
 								&litMatcher{
-									pos:        position{line: 156, col: 28, offset: 4158},
-									val:        "[",
+									pos:        position{line: 215, col: 50, offset: 6920},
+									val:        "{",
 									ignoreCase: false,
-									want:       "\"[\"",
+									want:       "\"{\"",
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 156, col: 32, offset: 4162},
+									pos: position{line: 215, col: 54, offset: 6924},
 									expr: &ruleRefExpr{
-										pos:  position{line: 156, col: 32, offset: 4162},
+										pos:  position{line: 215, col: 54, offset: 6924},
 										name: "_",
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 156, col: 35, offset: 4165},
-									label: "lit",
+									pos:   position{line: 215, col: 57, offset: 6927},
+									label: "expr",
 									expr: &ruleRefExpr{
-										pos:  position{line: 156, col: 39, offset: 4169},
-										name: "StringLiteral",
+										pos:  position{line: 215, col: 62, offset: 6932},
+										name: "OrExpression",
 									},
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 156, col: 53, offset: 4183},
+									pos: position{line: 215, col: 75, offset: 6945},
 									expr: &ruleRefExpr{
-										pos:  position{line: 156, col: 53, offset: 4183},
+										pos:  position{line: 215, col: 75, offset: 6945},
 										name: "_",
 									},
 								},
 								&litMatcher{
-									pos:        position{line: 156, col: 56, offset: 4186},
-									val:        "]",
+									pos:        position{line: 215, col: 78, offset: 6948},
+									val:        "}",
 									ignoreCase: false,
-									want:       "\"]\"",
+									want:       "\"}\"",
 								},
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 158, col: 5, offset: 4215},
+						pos: position{line: 217, col: 5, offset: 7029},
 						exprs: []interface{}{
-							&litMatcher{
-								pos:        position{line: 158, col: 5, offset: 4215},
-								val:        "[",
-								ignoreCase: false,
-								want:       "\"[\"",
+							&ruleRefExpr{
+								pos:  position{line: 217, col: 5, offset: 7029},
+								name: "Selector",
 							},
 							&zeroOrOneExpr{
-								pos: position{line: 158, col: 9, offset: 4219},
+								pos: position{line: 217, col: 14, offset: 7038},
 								expr: &ruleRefExpr{
-									pos:  position{line: 158, col: 9, offset: 4219},
+									pos:  position{line: 217, col: 14, offset: 7038},
 									name: "_",
 								},
 							},
-							&notExpr{
-								pos: position{line: 158, col: 12, offset: 4222},
-								expr: &ruleRefExpr{
-									pos:  position{line: 158, col: 13, offset: 4223},
-									name: "StringLiteral",
-								},
-							},
-							&andCodeExpr{
-								pos: position{line: 158, col: 27, offset: 4237},
-								run: (*parser).callonIndexExpression18,
-							},
-						},
-					},
-					&seqExpr{
-						pos: position{line: 160, col: 5, offset: 4289},
-						exprs: []interface{}{
 							&litMatcher{
-								pos:        position{line: 160, col: 5, offset: 4289},
-								val:        "[",
+								pos:        position{line: 217, col: 17, offset: 7041},
+								val:        "{",
 								ignoreCase: false,
-								want:       "\"[\"",
+								want:       "\"{\"",
 							},
 							&zeroOrOneExpr{
-								pos: position{line: 160, col: 9, offset: 4293},
+								pos: position{line: 217, col: 21, offset: 7045},
 								expr: &ruleRefExpr{
-									pos:  position{line: 160, col: 9, offset: 4293},
+									pos:  position{line: 217, col: 21, offset: 7045},
 									name: "_",
 								},
 							},
 							&ruleRefExpr{
-								pos:  position{line: 160, col: 12, offset: 4296},
-								name: "StringLiteral",
+								pos:  position{line: 217, col: 24, offset: 7048},
+								name: "OrExpression",
 							},
 							&zeroOrOneExpr{
-								pos: position{line: 160, col: 26, offset: 4310},
+								pos: position{line: 217, col: 37, offset: 7061},
 								expr: &ruleRefExpr{
-									pos:  position{line: 160, col: 26, offset: 4310},
+									pos:  position{line: 217, col: 37, offset: 7061},
 									name: "_",
 								},
 							},
 							&notExpr{
-								pos: position{line: 160, col: 29, offset: 4313},
+								pos: position{line: 217, col: 40, offset: 7064},
 								expr: &litMatcher{
-									pos:        position{line: 160, col: 30, offset: 4314},
-									val:        "]",
+									pos:        position{line: 217, col: 41, offset: 7065},
+									val:        "}",
 									ignoreCase: false,
-									want:       "\"]\"",
+									want:       "\"}\"",
 								},
 							},
 							&andCodeExpr{
-								pos: position{line: 160, col: 34, offset: 4318},
-								run: (*parser).callonIndexExpression28,
+								pos: position{line: 217, col: 45, offset: 7069},
+								run: (*parser).callonScopedExpression28,
 							},
 						},
 					},
@@ -1281,587 +1944,5475 @@ var g = &grammar{
 			},
 		},
 		{
-			name:        "Value",
-			displayName: "\"value\"",
-			pos:         position{line: 164, col: 1, offset: 4381},
+			name:        "MatchExpression",
+			displayName: "\"match\"",
+			pos:         position{line: 221, col: 1, offset: 7127},
 			expr: &choiceExpr{
-				pos: position{line: 164, col: 18, offset: 4398},
+				pos: position{line: 221, col: 28, offset: 7154},
 				alternatives: []interface{}{
-					&actionExpr{
-						pos: position{line: 164, col: 18, offset: 4398},
-						run: (*parser).callonValue2,
-						expr: &labeledExpr{
-							pos:   position{line: 164, col: 18, offset: 4398},
-							label: "selector",
-							expr: &ruleRefExpr{
-								pos:  position{line: 164, col: 27, offset: 4407},
-								name: "Selector",
-							},
-						},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 28, offset: 7154},
+						name: "MatchLengthOpValue",
 					},
-					&actionExpr{
-						pos: position{line: 166, col: 5, offset: 4484},
-						run: (*parser).callonValue5,
-						expr: &labeledExpr{
-							pos:   position{line: 166, col: 5, offset: 4484},
-							label: "n",
-							expr: &ruleRefExpr{
-								pos:  position{line: 166, col: 7, offset: 4486},
-								name: "NumberLiteral",
-							},
-						},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 49, offset: 7175},
+						name: "MatchSelectorContainsList",
 					},
-					&actionExpr{
-						pos: position{line: 168, col: 5, offset: 4550},
-						run: (*parser).callonValue8,
-						expr: &labeledExpr{
-							pos:   position{line: 168, col: 5, offset: 4550},
-							label: "s",
-							expr: &ruleRefExpr{
-								pos:  position{line: 168, col: 7, offset: 4552},
-								name: "StringLiteral",
-							},
-						},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 77, offset: 7203},
+						name: "MatchSelectorOpValue",
 					},
-				},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 100, offset: 7226},
+						name: "MatchSelectorInSet",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 121, offset: 7247},
+						name: "MatchSelectorBetween",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 144, offset: 7270},
+						name: "MatchSelectorOp",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 162, offset: 7288},
+						name: "MatchSelectorCustomOp",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 186, offset: 7312},
+						name: "MatchChainedComparison",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 211, offset: 7337},
+						name: "MatchValueOpSelector",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 221, col: 234, offset: 7360},
+						name: "MatchBareSelector",
+					},
+				},
 			},
 		},
 		{
-			name:        "NumberLiteral",
-			displayName: "\"number\"",
-			pos:         position{line: 172, col: 1, offset: 4615},
+			name:        "MatchBareSelector",
+			displayName: "\"match\"",
+			pos:         position{line: 223, col: 1, offset: 7379},
+			expr: &actionExpr{
+				pos: position{line: 223, col: 30, offset: 7408},
+				run: (*parser).callonMatchBareSelector1,
+				expr: &seqExpr{
+					pos: position{line: 223, col: 30, offset: 7408},
+					exprs: []interface{}{
+						&notExpr{
+							pos: position{line: 223, col: 30, offset: 7408},
+							expr: &ruleRefExpr{
+								pos:  position{line: 223, col: 31, offset: 7409},
+								name: "ReservedWord",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 223, col: 44, offset: 7422},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 223, col: 53, offset: 7431},
+								name: "Selector",
+							},
+						},
+						&andExpr{
+							pos: position{line: 223, col: 62, offset: 7440},
+							expr: &choiceExpr{
+								pos: position{line: 223, col: 64, offset: 7442},
+								alternatives: []interface{}{
+									&seqExpr{
+										pos: position{line: 223, col: 64, offset: 7442},
+										exprs: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 223, col: 64, offset: 7442},
+												name: "_",
+											},
+											&choiceExpr{
+												pos: position{line: 223, col: 67, offset: 7445},
+												alternatives: []interface{}{
+													&litMatcher{
+														pos:        position{line: 223, col: 67, offset: 7445},
+														val:        "and",
+														ignoreCase: false,
+														want:       "\"and\"",
+													},
+													&litMatcher{
+														pos:        position{line: 223, col: 75, offset: 7453},
+														val:        "or",
+														ignoreCase: false,
+														want:       "\"or\"",
+													},
+													&litMatcher{
+														pos:        position{line: 223, col: 82, offset: 7460},
+														val:        "xor",
+														ignoreCase: false,
+														want:       "\"xor\"",
+													},
+												},
+											},
+											&ruleRefExpr{
+												pos:  position{line: 223, col: 89, offset: 7467},
+												name: "_",
+											},
+										},
+									},
+									&seqExpr{
+										pos: position{line: 223, col: 93, offset: 7471},
+										exprs: []interface{}{
+											&zeroOrOneExpr{
+												pos: position{line: 223, col: 93, offset: 7471},
+												expr: &ruleRefExpr{
+													pos:  position{line: 223, col: 93, offset: 7471},
+													name: "_",
+												},
+											},
+											&choiceExpr{
+												pos: position{line: 223, col: 97, offset: 7475},
+												alternatives: []interface{}{
+													&litMatcher{
+														pos:        position{line: 223, col: 97, offset: 7475},
+														val:        ")",
+														ignoreCase: false,
+														want:       "\")\"",
+													},
+													&litMatcher{
+														pos:        position{line: 223, col: 103, offset: 7481},
+														val:        "}",
+														ignoreCase: false,
+														want:       "\"}\"",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 223, col: 109, offset: 7487},
+														name: "EOF",
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "MatchChainedComparison",
+			displayName: "\"match\"",
+			pos:         position{line: 228, col: 1, offset: 7690},
 			expr: &choiceExpr{
-				pos: position{line: 172, col: 27, offset: 4641},
+				pos: position{line: 228, col: 35, offset: 7724},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 172, col: 27, offset: 4641},
-						run: (*parser).callonNumberLiteral2,
+						pos: position{line: 228, col: 35, offset: 7724},
+						run: (*parser).callonMatchChainedComparison2,
 						expr: &seqExpr{
-							pos: position{line: 172, col: 27, offset: 4641},
+							pos: position{line: 228, col: 35, offset: 7724},
 							exprs: []interface{}{
-								&zeroOrOneExpr{
-									pos: position{line: 172, col: 27, offset: 4641},
-									expr: &litMatcher{
-										pos:        position{line: 172, col: 27, offset: 4641},
-										val:        "-",
-										ignoreCase: false,
-										want:       "\"-\"",
+								&labeledExpr{
+									pos:   position{line: 228, col: 35, offset: 7724},
+									label: "low",
+									expr: &ruleRefExpr{
+										pos:  position{line: 228, col: 39, offset: 7728},
+										name: "Value",
 									},
 								},
-								&ruleRefExpr{
-									pos:  position{line: 172, col: 32, offset: 4646},
-									name: "IntegerOrFloat",
+								&labeledExpr{
+									pos:   position{line: 228, col: 45, offset: 7734},
+									label: "lowOp",
+									expr: &choiceExpr{
+										pos: position{line: 228, col: 52, offset: 7741},
+										alternatives: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 228, col: 52, offset: 7741},
+												name: "MatchLessThanOrEqual",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 228, col: 75, offset: 7764},
+												name: "MatchLessThan",
+											},
+										},
+									},
 								},
-								&andExpr{
-									pos: position{line: 172, col: 47, offset: 4661},
+								&labeledExpr{
+									pos:   position{line: 228, col: 90, offset: 7779},
+									label: "selector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 172, col: 48, offset: 4662},
-										name: "AfterNumbers",
+										pos:  position{line: 228, col: 99, offset: 7788},
+										name: "Selector",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 228, col: 108, offset: 7797},
+									label: "highOp",
+									expr: &choiceExpr{
+										pos: position{line: 228, col: 116, offset: 7805},
+										alternatives: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 228, col: 116, offset: 7805},
+												name: "MatchLessThanOrEqual",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 228, col: 139, offset: 7828},
+												name: "MatchLessThan",
+											},
+										},
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 228, col: 154, offset: 7843},
+									label: "high",
+									expr: &ruleRefExpr{
+										pos:  position{line: 228, col: 159, offset: 7848},
+										name: "Value",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 235, col: 5, offset: 8258},
+						run: (*parser).callonMatchChainedComparison18,
+						expr: &seqExpr{
+							pos: position{line: 235, col: 5, offset: 8258},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 235, col: 5, offset: 8258},
+									label: "high",
+									expr: &ruleRefExpr{
+										pos:  position{line: 235, col: 10, offset: 8263},
+										name: "Value",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 235, col: 16, offset: 8269},
+									label: "highOp",
+									expr: &choiceExpr{
+										pos: position{line: 235, col: 24, offset: 8277},
+										alternatives: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 235, col: 24, offset: 8277},
+												name: "MatchGreaterThanOrEqual",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 235, col: 50, offset: 8303},
+												name: "MatchGreaterThan",
+											},
+										},
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 235, col: 68, offset: 8321},
+									label: "selector",
+									expr: &ruleRefExpr{
+										pos:  position{line: 235, col: 77, offset: 8330},
+										name: "Selector",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 235, col: 86, offset: 8339},
+									label: "lowOp",
+									expr: &choiceExpr{
+										pos: position{line: 235, col: 93, offset: 8346},
+										alternatives: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 235, col: 93, offset: 8346},
+												name: "MatchGreaterThanOrEqual",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 235, col: 119, offset: 8372},
+												name: "MatchGreaterThan",
+											},
+										},
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 235, col: 137, offset: 8390},
+									label: "low",
+									expr: &ruleRefExpr{
+										pos:  position{line: 235, col: 141, offset: 8394},
+										name: "Value",
 									},
 								},
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 174, col: 5, offset: 4711},
+						pos: position{line: 242, col: 5, offset: 8804},
 						exprs: []interface{}{
-							&zeroOrOneExpr{
-								pos: position{line: 174, col: 5, offset: 4711},
-								expr: &litMatcher{
-									pos:        position{line: 174, col: 5, offset: 4711},
-									val:        "-",
-									ignoreCase: false,
-									want:       "\"-\"",
+							&ruleRefExpr{
+								pos:  position{line: 242, col: 5, offset: 8804},
+								name: "Value",
+							},
+							&choiceExpr{
+								pos: position{line: 242, col: 12, offset: 8811},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 242, col: 12, offset: 8811},
+										name: "MatchLessThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 242, col: 35, offset: 8834},
+										name: "MatchLessThan",
+									},
 								},
 							},
 							&ruleRefExpr{
-								pos:  position{line: 174, col: 10, offset: 4716},
-								name: "IntegerOrFloat",
+								pos:  position{line: 242, col: 50, offset: 8849},
+								name: "Selector",
 							},
-							&notExpr{
-								pos: position{line: 174, col: 25, offset: 4731},
-								expr: &ruleRefExpr{
-									pos:  position{line: 174, col: 26, offset: 4732},
-									name: "AfterNumbers",
+							&choiceExpr{
+								pos: position{line: 242, col: 60, offset: 8859},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 242, col: 60, offset: 8859},
+										name: "MatchGreaterThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 242, col: 86, offset: 8885},
+										name: "MatchGreaterThan",
+									},
 								},
 							},
+							&ruleRefExpr{
+								pos:  position{line: 242, col: 104, offset: 8903},
+								name: "Value",
+							},
 							&andCodeExpr{
-								pos: position{line: 174, col: 39, offset: 4745},
-								run: (*parser).callonNumberLiteral15,
+								pos: position{line: 242, col: 110, offset: 8909},
+								run: (*parser).callonMatchChainedComparison44,
 							},
 						},
 					},
-				},
-			},
-		},
-		{
-			name: "AfterNumbers",
-			pos:  position{line: 178, col: 1, offset: 4805},
-			expr: &andExpr{
-				pos: position{line: 178, col: 17, offset: 4821},
-				expr: &choiceExpr{
-					pos: position{line: 178, col: 19, offset: 4823},
-					alternatives: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 178, col: 19, offset: 4823},
-							name: "_",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 178, col: 23, offset: 4827},
-							name: "EOF",
-						},
-						&litMatcher{
-							pos:        position{line: 178, col: 29, offset: 4833},
-							val:        ")",
-							ignoreCase: false,
-							want:       "\")\"",
-						},
-					},
-				},
-			},
-		},
-		{
-			name: "IntegerOrFloat",
-			pos:  position{line: 180, col: 1, offset: 4839},
-			expr: &seqExpr{
-				pos: position{line: 180, col: 19, offset: 4857},
-				exprs: []interface{}{
-					&choiceExpr{
-						pos: position{line: 180, col: 20, offset: 4858},
-						alternatives: []interface{}{
-							&litMatcher{
-								pos:        position{line: 180, col: 20, offset: 4858},
-								val:        "0",
-								ignoreCase: false,
-								want:       "\"0\"",
+					&seqExpr{
+						pos: position{line: 244, col: 5, offset: 9008},
+						exprs: []interface{}{
+							&ruleRefExpr{
+								pos:  position{line: 244, col: 5, offset: 9008},
+								name: "Value",
 							},
-							&seqExpr{
-								pos: position{line: 180, col: 26, offset: 4864},
-								exprs: []interface{}{
-									&charClassMatcher{
-										pos:        position{line: 180, col: 26, offset: 4864},
-										val:        "[1-9]",
-										ranges:     []rune{'1', '9'},
-										ignoreCase: false,
-										inverted:   false,
+							&choiceExpr{
+								pos: position{line: 244, col: 12, offset: 9015},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 244, col: 12, offset: 9015},
+										name: "MatchGreaterThanOrEqual",
 									},
-									&zeroOrMoreExpr{
-										pos: position{line: 180, col: 31, offset: 4869},
-										expr: &charClassMatcher{
-											pos:        position{line: 180, col: 31, offset: 4869},
-											val:        "[0-9]",
-											ranges:     []rune{'0', '9'},
-											ignoreCase: false,
-											inverted:   false,
-										},
+									&ruleRefExpr{
+										pos:  position{line: 244, col: 38, offset: 9041},
+										name: "MatchGreaterThan",
 									},
 								},
 							},
-						},
-					},
-					&zeroOrOneExpr{
-						pos: position{line: 180, col: 39, offset: 4877},
-						expr: &seqExpr{
-							pos: position{line: 180, col: 40, offset: 4878},
-							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 180, col: 40, offset: 4878},
-									val:        ".",
-									ignoreCase: false,
-									want:       "\".\"",
-								},
-								&oneOrMoreExpr{
-									pos: position{line: 180, col: 44, offset: 4882},
-									expr: &charClassMatcher{
-										pos:        position{line: 180, col: 44, offset: 4882},
-										val:        "[0-9]",
-										ranges:     []rune{'0', '9'},
-										ignoreCase: false,
-										inverted:   false,
+							&ruleRefExpr{
+								pos:  position{line: 244, col: 56, offset: 9059},
+								name: "Selector",
+							},
+							&choiceExpr{
+								pos: position{line: 244, col: 66, offset: 9069},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 244, col: 66, offset: 9069},
+										name: "MatchLessThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 244, col: 89, offset: 9092},
+										name: "MatchLessThan",
 									},
 								},
 							},
+							&ruleRefExpr{
+								pos:  position{line: 244, col: 104, offset: 9107},
+								name: "Value",
+							},
+							&andCodeExpr{
+								pos: position{line: 244, col: 110, offset: 9113},
+								run: (*parser).callonMatchChainedComparison55,
+							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name:        "StringLiteral",
-			displayName: "\"string\"",
-			pos:         position{line: 182, col: 1, offset: 4892},
-			expr: &choiceExpr{
-				pos: position{line: 182, col: 27, offset: 4918},
-				alternatives: []interface{}{
-					&actionExpr{
-						pos: position{line: 182, col: 27, offset: 4918},
-						run: (*parser).callonStringLiteral2,
-						expr: &choiceExpr{
-							pos: position{line: 182, col: 28, offset: 4919},
-							alternatives: []interface{}{
-								&seqExpr{
-									pos: position{line: 182, col: 28, offset: 4919},
-									exprs: []interface{}{
-										&litMatcher{
-											pos:        position{line: 182, col: 28, offset: 4919},
-											val:        "`",
-											ignoreCase: false,
-											want:       "\"`\"",
-										},
-										&zeroOrMoreExpr{
-											pos: position{line: 182, col: 32, offset: 4923},
-											expr: &ruleRefExpr{
-												pos:  position{line: 182, col: 32, offset: 4923},
-												name: "RawStringChar",
-											},
-										},
-										&litMatcher{
-											pos:        position{line: 182, col: 47, offset: 4938},
-											val:        "`",
-											ignoreCase: false,
-											want:       "\"`\"",
-										},
+			name:        "MatchSelectorInSet",
+			displayName: "\"match\"",
+			pos:         position{line: 248, col: 1, offset: 9211},
+			expr: &actionExpr{
+				pos: position{line: 248, col: 31, offset: 9241},
+				run: (*parser).callonMatchSelectorInSet1,
+				expr: &seqExpr{
+					pos: position{line: 248, col: 31, offset: 9241},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 248, col: 31, offset: 9241},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 248, col: 40, offset: 9250},
+								name: "Selector",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 248, col: 49, offset: 9259},
+							label: "operator",
+							expr: &choiceExpr{
+								pos: position{line: 248, col: 59, offset: 9269},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 248, col: 59, offset: 9269},
+										name: "MatchIn",
 									},
-								},
-								&seqExpr{
-									pos: position{line: 182, col: 53, offset: 4944},
-									exprs: []interface{}{
-										&litMatcher{
-											pos:        position{line: 182, col: 53, offset: 4944},
-											val:        "\"",
-											ignoreCase: false,
-											want:       "\"\\\"\"",
-										},
-										&zeroOrMoreExpr{
-											pos: position{line: 182, col: 57, offset: 4948},
-											expr: &ruleRefExpr{
-												pos:  position{line: 182, col: 57, offset: 4948},
-												name: "DoubleStringChar",
-											},
-										},
-										&litMatcher{
-											pos:        position{line: 182, col: 75, offset: 4966},
-											val:        "\"",
-											ignoreCase: false,
-											want:       "\"\\\"\"",
-										},
+									&ruleRefExpr{
+										pos:  position{line: 248, col: 69, offset: 9279},
+										name: "MatchNotIn",
 									},
 								},
 							},
 						},
-					},
-					&seqExpr{
-						pos: position{line: 184, col: 5, offset: 5018},
-						exprs: []interface{}{
-							&choiceExpr{
-								pos: position{line: 184, col: 6, offset: 5019},
+						&labeledExpr{
+							pos:   position{line: 248, col: 81, offset: 9291},
+							label: "set",
+							expr: &choiceExpr{
+								pos: position{line: 248, col: 86, offset: 9296},
 								alternatives: []interface{}{
-									&seqExpr{
-										pos: position{line: 184, col: 6, offset: 5019},
-										exprs: []interface{}{
-											&litMatcher{
-												pos:        position{line: 184, col: 6, offset: 5019},
-												val:        "`",
-												ignoreCase: false,
-												want:       "\"`\"",
-											},
-											&zeroOrMoreExpr{
-												pos: position{line: 184, col: 10, offset: 5023},
-												expr: &ruleRefExpr{
-													pos:  position{line: 184, col: 10, offset: 5023},
-													name: "RawStringChar",
-												},
-											},
-										},
+									&ruleRefExpr{
+										pos:  position{line: 248, col: 86, offset: 9296},
+										name: "NamedSet",
 									},
-									&seqExpr{
-										pos: position{line: 184, col: 27, offset: 5040},
-										exprs: []interface{}{
-											&litMatcher{
-												pos:        position{line: 184, col: 27, offset: 5040},
-												val:        "\"",
-												ignoreCase: false,
-												want:       "\"\\\"\"",
-											},
-											&zeroOrMoreExpr{
-												pos: position{line: 184, col: 31, offset: 5044},
-												expr: &ruleRefExpr{
-													pos:  position{line: 184, col: 31, offset: 5044},
-													name: "DoubleStringChar",
-												},
-											},
-										},
+									&ruleRefExpr{
+										pos:  position{line: 248, col: 97, offset: 9307},
+										name: "ListLiteral",
 									},
 								},
 							},
-							&ruleRefExpr{
-								pos:  position{line: 184, col: 50, offset: 5063},
-								name: "EOF",
-							},
-							&andCodeExpr{
-								pos: position{line: 184, col: 54, offset: 5067},
-								run: (*parser).callonStringLiteral25,
-							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "RawStringChar",
-			pos:  position{line: 188, col: 1, offset: 5131},
-			expr: &seqExpr{
-				pos: position{line: 188, col: 18, offset: 5148},
-				exprs: []interface{}{
-					&notExpr{
-						pos: position{line: 188, col: 18, offset: 5148},
-						expr: &litMatcher{
-							pos:        position{line: 188, col: 19, offset: 5149},
-							val:        "`",
-							ignoreCase: false,
-							want:       "\"`\"",
+			name:        "MatchSelectorContainsList",
+			displayName: "\"match\"",
+			pos:         position{line: 263, col: 1, offset: 9654},
+			expr: &actionExpr{
+				pos: position{line: 263, col: 38, offset: 9691},
+				run: (*parser).callonMatchSelectorContainsList1,
+				expr: &seqExpr{
+					pos: position{line: 263, col: 38, offset: 9691},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 263, col: 38, offset: 9691},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 263, col: 47, offset: 9700},
+								name: "Selector",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 263, col: 56, offset: 9709},
+							label: "operator",
+							expr: &choiceExpr{
+								pos: position{line: 263, col: 66, offset: 9719},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 263, col: 66, offset: 9719},
+										name: "MatchContainsAny",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 263, col: 85, offset: 9738},
+										name: "MatchNotContainsAny",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 263, col: 107, offset: 9760},
+										name: "MatchContainsAll",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 263, col: 126, offset: 9779},
+										name: "MatchNotContainsAll",
+									},
+								},
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 263, col: 147, offset: 9800},
+							label: "list",
+							expr: &ruleRefExpr{
+								pos:  position{line: 263, col: 152, offset: 9805},
+								name: "ListLiteral",
+							},
 						},
-					},
-					&anyMatcher{
-						line: 188, col: 23, offset: 5153,
 					},
 				},
 			},
 		},
 		{
-			name: "DoubleStringChar",
-			pos:  position{line: 189, col: 1, offset: 5155},
-			expr: &seqExpr{
-				pos: position{line: 189, col: 21, offset: 5175},
-				exprs: []interface{}{
-					&notExpr{
-						pos: position{line: 189, col: 21, offset: 5175},
-						expr: &litMatcher{
-							pos:        position{line: 189, col: 22, offset: 5176},
-							val:        "\"",
+			name:        "MatchSelectorBetween",
+			displayName: "\"match\"",
+			pos:         position{line: 267, col: 1, offset: 9951},
+			expr: &actionExpr{
+				pos: position{line: 267, col: 33, offset: 9983},
+				run: (*parser).callonMatchSelectorBetween1,
+				expr: &seqExpr{
+					pos: position{line: 267, col: 33, offset: 9983},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 267, col: 33, offset: 9983},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 267, col: 42, offset: 9992},
+								name: "Selector",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 267, col: 51, offset: 10001},
+							label: "operator",
+							expr: &choiceExpr{
+								pos: position{line: 267, col: 61, offset: 10011},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 267, col: 61, offset: 10011},
+										name: "MatchBetween",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 267, col: 76, offset: 10026},
+										name: "MatchNotBetween",
+									},
+								},
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 267, col: 93, offset: 10043},
+							label: "low",
+							expr: &ruleRefExpr{
+								pos:  position{line: 267, col: 97, offset: 10047},
+								name: "Value",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 267, col: 103, offset: 10053},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 267, col: 105, offset: 10055},
+							val:        "and",
 							ignoreCase: false,
-							want:       "\"\\\"\"",
+							want:       "\"and\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 267, col: 111, offset: 10061},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 267, col: 113, offset: 10063},
+							label: "high",
+							expr: &ruleRefExpr{
+								pos:  position{line: 267, col: 118, offset: 10068},
+								name: "Value",
+							},
 						},
-					},
-					&anyMatcher{
-						line: 189, col: 26, offset: 5180,
 					},
 				},
 			},
 		},
 		{
-			name:        "_",
-			displayName: "\"whitespace\"",
-			pos:         position{line: 191, col: 1, offset: 5183},
-			expr: &oneOrMoreExpr{
-				pos: position{line: 191, col: 19, offset: 5201},
-				expr: &charClassMatcher{
-					pos:        position{line: 191, col: 19, offset: 5201},
-					val:        "[ \\t\\r\\n]",
-					chars:      []rune{' ', '\t', '\r', '\n'},
-					ignoreCase: false,
-					inverted:   false,
+			name:        "MatchSelectorOpValue",
+			displayName: "\"match\"",
+			pos:         position{line: 271, col: 1, offset: 10240},
+			expr: &actionExpr{
+				pos: position{line: 271, col: 33, offset: 10272},
+				run: (*parser).callonMatchSelectorOpValue1,
+				expr: &seqExpr{
+					pos: position{line: 271, col: 33, offset: 10272},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 271, col: 33, offset: 10272},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 271, col: 42, offset: 10281},
+								name: "Selector",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 271, col: 51, offset: 10290},
+							label: "operator",
+							expr: &choiceExpr{
+								pos: position{line: 271, col: 61, offset: 10300},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 61, offset: 10300},
+										name: "MatchInCIDR",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 75, offset: 10314},
+										name: "MatchNotInCIDR",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 92, offset: 10331},
+										name: "MatchEqualFold",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 109, offset: 10348},
+										name: "MatchNotEqualFold",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 129, offset: 10368},
+										name: "MatchEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 142, offset: 10381},
+										name: "MatchNotEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 158, offset: 10397},
+										name: "MatchLessThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 181, offset: 10420},
+										name: "MatchLessThan",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 197, offset: 10436},
+										name: "MatchGreaterThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 223, offset: 10462},
+										name: "MatchGreaterThan",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 242, offset: 10481},
+										name: "MatchContains",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 258, offset: 10497},
+										name: "MatchNotContains",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 277, offset: 10516},
+										name: "MatchMatches",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 292, offset: 10531},
+										name: "MatchNotMatches",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 310, offset: 10549},
+										name: "MatchLike",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 322, offset: 10561},
+										name: "MatchNotLike",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 337, offset: 10576},
+										name: "MatchPrefix",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 351, offset: 10590},
+										name: "MatchNotPrefix",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 368, offset: 10607},
+										name: "MatchSuffix",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 271, col: 382, offset: 10621},
+										name: "MatchNotSuffix",
+									},
+								},
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 271, col: 398, offset: 10637},
+							label: "value",
+							expr: &ruleRefExpr{
+								pos:  position{line: 271, col: 404, offset: 10643},
+								name: "Value",
+							},
+						},
+					},
 				},
 			},
 		},
 		{
-			name: "EOF",
-			pos:  position{line: 193, col: 1, offset: 5213},
-			expr: &notExpr{
-				pos: position{line: 193, col: 8, offset: 5220},
-				expr: &anyMatcher{
-					line: 193, col: 9, offset: 5221,
+			name:        "MatchLengthOpValue",
+			displayName: "\"match\"",
+			pos:         position{line: 279, col: 1, offset: 10871},
+			expr: &actionExpr{
+				pos: position{line: 279, col: 31, offset: 10901},
+				run: (*parser).callonMatchLengthOpValue1,
+				expr: &seqExpr{
+					pos: position{line: 279, col: 31, offset: 10901},
+					exprs: []interface{}{
+						&choiceExpr{
+							pos: position{line: 279, col: 32, offset: 10902},
+							alternatives: []interface{}{
+								&litMatcher{
+									pos:        position{line: 279, col: 32, offset: 10902},
+									val:        "len",
+									ignoreCase: false,
+									want:       "\"len\"",
+								},
+								&litMatcher{
+									pos:        position{line: 279, col: 40, offset: 10910},
+									val:        "count",
+									ignoreCase: false,
+									want:       "\"count\"",
+								},
+							},
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 279, col: 49, offset: 10919},
+							expr: &ruleRefExpr{
+								pos:  position{line: 279, col: 49, offset: 10919},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 279, col: 52, offset: 10922},
+							val:        "(",
+							ignoreCase: false,
+							want:       "\"(\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 279, col: 56, offset: 10926},
+							expr: &ruleRefExpr{
+								pos:  position{line: 279, col: 56, offset: 10926},
+								name: "_",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 279, col: 59, offset: 10929},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 279, col: 68, offset: 10938},
+								name: "Selector",
+							},
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 279, col: 77, offset: 10947},
+							expr: &ruleRefExpr{
+								pos:  position{line: 279, col: 77, offset: 10947},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 279, col: 80, offset: 10950},
+							val:        ")",
+							ignoreCase: false,
+							want:       "\")\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 279, col: 84, offset: 10954},
+							label: "operator",
+							expr: &choiceExpr{
+								pos: position{line: 279, col: 94, offset: 10964},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 279, col: 94, offset: 10964},
+										name: "MatchEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 279, col: 107, offset: 10977},
+										name: "MatchNotEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 279, col: 123, offset: 10993},
+										name: "MatchLessThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 279, col: 146, offset: 11016},
+										name: "MatchLessThan",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 279, col: 162, offset: 11032},
+										name: "MatchGreaterThanOrEqual",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 279, col: 188, offset: 11058},
+										name: "MatchGreaterThan",
+									},
+								},
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 279, col: 206, offset: 11076},
+							label: "value",
+							expr: &ruleRefExpr{
+								pos:  position{line: 279, col: 212, offset: 11082},
+								name: "Value",
+							},
+						},
+					},
 				},
 			},
 		},
-	},
+		{
+			name:        "MatchSelectorOp",
+			displayName: "\"match\"",
+			pos:         position{line: 283, col: 1, offset: 11234},
+			expr: &actionExpr{
+				pos: position{line: 283, col: 28, offset: 11261},
+				run: (*parser).callonMatchSelectorOp1,
+				expr: &seqExpr{
+					pos: position{line: 283, col: 28, offset: 11261},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 283, col: 28, offset: 11261},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 283, col: 37, offset: 11270},
+								name: "Selector",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 283, col: 46, offset: 11279},
+							label: "operator",
+							expr: &choiceExpr{
+								pos: position{line: 283, col: 56, offset: 11289},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 283, col: 56, offset: 11289},
+										name: "MatchIsEmpty",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 283, col: 71, offset: 11304},
+										name: "MatchIsNotEmpty",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 283, col: 89, offset: 11322},
+										name: "MatchIsNull",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 283, col: 103, offset: 11336},
+										name: "MatchIsNotNull",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "MatchSelectorCustomOp",
+			displayName: "\"match\"",
+			pos:         position{line: 290, col: 1, offset: 11685},
+			expr: &actionExpr{
+				pos: position{line: 290, col: 34, offset: 11718},
+				run: (*parser).callonMatchSelectorCustomOp1,
+				expr: &seqExpr{
+					pos: position{line: 290, col: 34, offset: 11718},
+					exprs: []interface{}{
+						&andCodeExpr{
+							pos: position{line: 290, col: 34, offset: 11718},
+							run: (*parser).callonMatchSelectorCustomOp3,
+						},
+						&labeledExpr{
+							pos:   position{line: 292, col: 3, offset: 11759},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 292, col: 12, offset: 11768},
+								name: "Selector",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 292, col: 21, offset: 11777},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 292, col: 23, offset: 11779},
+							label: "token",
+							expr: &ruleRefExpr{
+								pos:  position{line: 292, col: 29, offset: 11785},
+								name: "Identifier",
+							},
+						},
+						&andCodeExpr{
+							pos: position{line: 292, col: 40, offset: 11796},
+							run: (*parser).callonMatchSelectorCustomOp9,
+						},
+						&ruleRefExpr{
+							pos:  position{line: 295, col: 3, offset: 11868},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 295, col: 5, offset: 11870},
+							label: "value",
+							expr: &ruleRefExpr{
+								pos:  position{line: 295, col: 11, offset: 11876},
+								name: "Value",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "MatchValueOpSelector",
+			displayName: "\"match\"",
+			pos:         position{line: 304, col: 1, offset: 12131},
+			expr: &choiceExpr{
+				pos: position{line: 304, col: 33, offset: 12163},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 304, col: 33, offset: 12163},
+						run: (*parser).callonMatchValueOpSelector2,
+						expr: &seqExpr{
+							pos: position{line: 304, col: 33, offset: 12163},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 304, col: 33, offset: 12163},
+									label: "value",
+									expr: &ruleRefExpr{
+										pos:  position{line: 304, col: 39, offset: 12169},
+										name: "Value",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 304, col: 45, offset: 12175},
+									label: "operator",
+									expr: &choiceExpr{
+										pos: position{line: 304, col: 55, offset: 12185},
+										alternatives: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 304, col: 55, offset: 12185},
+												name: "MatchIn",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 304, col: 65, offset: 12195},
+												name: "MatchNotIn",
+											},
+										},
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 304, col: 77, offset: 12207},
+									label: "selector",
+									expr: &ruleRefExpr{
+										pos:  position{line: 304, col: 86, offset: 12216},
+										name: "Selector",
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 306, col: 5, offset: 12358},
+						exprs: []interface{}{
+							&ruleRefExpr{
+								pos:  position{line: 306, col: 5, offset: 12358},
+								name: "Value",
+							},
+							&labeledExpr{
+								pos:   position{line: 306, col: 11, offset: 12364},
+								label: "operator",
+								expr: &choiceExpr{
+									pos: position{line: 306, col: 21, offset: 12374},
+									alternatives: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 306, col: 21, offset: 12374},
+											name: "MatchIn",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 306, col: 31, offset: 12384},
+											name: "MatchNotIn",
+										},
+									},
+								},
+							},
+							&notExpr{
+								pos: position{line: 306, col: 43, offset: 12396},
+								expr: &ruleRefExpr{
+									pos:  position{line: 306, col: 44, offset: 12397},
+									name: "Selector",
+								},
+							},
+							&notExpr{
+								pos: position{line: 306, col: 53, offset: 12406},
+								expr: &litMatcher{
+									pos:        position{line: 306, col: 54, offset: 12407},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 306, col: 58, offset: 12411},
+								run: (*parser).callonMatchValueOpSelector22,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchEqualFold",
+			pos:  position{line: 310, col: 1, offset: 12465},
+			expr: &choiceExpr{
+				pos: position{line: 310, col: 19, offset: 12483},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 310, col: 19, offset: 12483},
+						run: (*parser).callonMatchEqualFold2,
+						expr: &seqExpr{
+							pos: position{line: 310, col: 19, offset: 12483},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 310, col: 19, offset: 12483},
+									expr: &ruleRefExpr{
+										pos:  position{line: 310, col: 19, offset: 12483},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 310, col: 22, offset: 12486},
+									val:        "==i",
+									ignoreCase: false,
+									want:       "\"==i\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 310, col: 28, offset: 12492},
+									name: "_",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 312, col: 5, offset: 12530},
+						run: (*parser).callonMatchEqualFold8,
+						expr: &seqExpr{
+							pos: position{line: 312, col: 5, offset: 12530},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 312, col: 5, offset: 12530},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 312, col: 7, offset: 12532},
+									val:        "iequals",
+									ignoreCase: false,
+									want:       "\"iequals\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 312, col: 17, offset: 12542},
+									name: "_",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotEqualFold",
+			pos:  position{line: 315, col: 1, offset: 12578},
+			expr: &choiceExpr{
+				pos: position{line: 315, col: 22, offset: 12599},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 315, col: 22, offset: 12599},
+						run: (*parser).callonMatchNotEqualFold2,
+						expr: &seqExpr{
+							pos: position{line: 315, col: 22, offset: 12599},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 315, col: 22, offset: 12599},
+									expr: &ruleRefExpr{
+										pos:  position{line: 315, col: 22, offset: 12599},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 315, col: 25, offset: 12602},
+									val:        "!=i",
+									ignoreCase: false,
+									want:       "\"!=i\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 315, col: 31, offset: 12608},
+									name: "_",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 317, col: 5, offset: 12649},
+						run: (*parser).callonMatchNotEqualFold8,
+						expr: &seqExpr{
+							pos: position{line: 317, col: 5, offset: 12649},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 317, col: 5, offset: 12649},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 317, col: 7, offset: 12651},
+									val:        "not",
+									ignoreCase: false,
+									want:       "\"not\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 317, col: 13, offset: 12657},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 317, col: 15, offset: 12659},
+									val:        "iequals",
+									ignoreCase: false,
+									want:       "\"iequals\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 317, col: 25, offset: 12669},
+									name: "_",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchEqual",
+			pos:  position{line: 320, col: 1, offset: 12708},
+			expr: &choiceExpr{
+				pos: position{line: 320, col: 15, offset: 12722},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 320, col: 15, offset: 12722},
+						run: (*parser).callonMatchEqual2,
+						expr: &seqExpr{
+							pos: position{line: 320, col: 15, offset: 12722},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 320, col: 15, offset: 12722},
+									expr: &ruleRefExpr{
+										pos:  position{line: 320, col: 15, offset: 12722},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 320, col: 18, offset: 12725},
+									val:        "==",
+									ignoreCase: false,
+									want:       "\"==\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 320, col: 23, offset: 12730},
+									expr: &ruleRefExpr{
+										pos:  position{line: 320, col: 23, offset: 12730},
+										name: "_",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 322, col: 5, offset: 12765},
+						run: (*parser).callonMatchEqual9,
+						expr: &seqExpr{
+							pos: position{line: 322, col: 5, offset: 12765},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 322, col: 5, offset: 12765},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 322, col: 7, offset: 12767},
+									val:        "eq",
+									ignoreCase: false,
+									want:       "\"eq\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 322, col: 12, offset: 12772},
+									name: "_",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotEqual",
+			pos:  position{line: 326, col: 1, offset: 12869},
+			expr: &choiceExpr{
+				pos: position{line: 326, col: 18, offset: 12886},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 326, col: 18, offset: 12886},
+						run: (*parser).callonMatchNotEqual2,
+						expr: &seqExpr{
+							pos: position{line: 326, col: 18, offset: 12886},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 326, col: 18, offset: 12886},
+									expr: &ruleRefExpr{
+										pos:  position{line: 326, col: 18, offset: 12886},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 326, col: 21, offset: 12889},
+									val:        "!=",
+									ignoreCase: false,
+									want:       "\"!=\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 326, col: 26, offset: 12894},
+									expr: &ruleRefExpr{
+										pos:  position{line: 326, col: 26, offset: 12894},
+										name: "_",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 328, col: 5, offset: 12932},
+						run: (*parser).callonMatchNotEqual9,
+						expr: &seqExpr{
+							pos: position{line: 328, col: 5, offset: 12932},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 328, col: 5, offset: 12932},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 328, col: 7, offset: 12934},
+									val:        "ne",
+									ignoreCase: false,
+									want:       "\"ne\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 328, col: 12, offset: 12939},
+									name: "_",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchLike",
+			pos:  position{line: 331, col: 1, offset: 12974},
+			expr: &actionExpr{
+				pos: position{line: 331, col: 14, offset: 12987},
+				run: (*parser).callonMatchLike1,
+				expr: &seqExpr{
+					pos: position{line: 331, col: 14, offset: 12987},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 331, col: 14, offset: 12987},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 331, col: 16, offset: 12989},
+							val:        "like",
+							ignoreCase: false,
+							want:       "\"like\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 331, col: 23, offset: 12996},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotLike",
+			pos:  position{line: 334, col: 1, offset: 13027},
+			expr: &actionExpr{
+				pos: position{line: 334, col: 17, offset: 13043},
+				run: (*parser).callonMatchNotLike1,
+				expr: &seqExpr{
+					pos: position{line: 334, col: 17, offset: 13043},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 334, col: 17, offset: 13043},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 334, col: 19, offset: 13045},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 334, col: 25, offset: 13051},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 334, col: 27, offset: 13053},
+							val:        "like",
+							ignoreCase: false,
+							want:       "\"like\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 334, col: 34, offset: 13060},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchPrefix",
+			pos:  position{line: 337, col: 1, offset: 13094},
+			expr: &actionExpr{
+				pos: position{line: 337, col: 16, offset: 13109},
+				run: (*parser).callonMatchPrefix1,
+				expr: &seqExpr{
+					pos: position{line: 337, col: 16, offset: 13109},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 337, col: 16, offset: 13109},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 337, col: 18, offset: 13111},
+							val:        "starts",
+							ignoreCase: false,
+							want:       "\"starts\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 337, col: 27, offset: 13120},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 337, col: 29, offset: 13122},
+							val:        "with",
+							ignoreCase: false,
+							want:       "\"with\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 337, col: 36, offset: 13129},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotPrefix",
+			pos:  position{line: 340, col: 1, offset: 13162},
+			expr: &actionExpr{
+				pos: position{line: 340, col: 19, offset: 13180},
+				run: (*parser).callonMatchNotPrefix1,
+				expr: &seqExpr{
+					pos: position{line: 340, col: 19, offset: 13180},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 340, col: 19, offset: 13180},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 340, col: 21, offset: 13182},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 340, col: 27, offset: 13188},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 340, col: 29, offset: 13190},
+							val:        "starts",
+							ignoreCase: false,
+							want:       "\"starts\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 340, col: 38, offset: 13199},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 340, col: 40, offset: 13201},
+							val:        "with",
+							ignoreCase: false,
+							want:       "\"with\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 340, col: 47, offset: 13208},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchSuffix",
+			pos:  position{line: 343, col: 1, offset: 13244},
+			expr: &actionExpr{
+				pos: position{line: 343, col: 16, offset: 13259},
+				run: (*parser).callonMatchSuffix1,
+				expr: &seqExpr{
+					pos: position{line: 343, col: 16, offset: 13259},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 343, col: 16, offset: 13259},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 343, col: 18, offset: 13261},
+							val:        "ends",
+							ignoreCase: false,
+							want:       "\"ends\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 343, col: 25, offset: 13268},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 343, col: 27, offset: 13270},
+							val:        "with",
+							ignoreCase: false,
+							want:       "\"with\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 343, col: 34, offset: 13277},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotSuffix",
+			pos:  position{line: 346, col: 1, offset: 13310},
+			expr: &actionExpr{
+				pos: position{line: 346, col: 19, offset: 13328},
+				run: (*parser).callonMatchNotSuffix1,
+				expr: &seqExpr{
+					pos: position{line: 346, col: 19, offset: 13328},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 346, col: 19, offset: 13328},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 346, col: 21, offset: 13330},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 346, col: 27, offset: 13336},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 346, col: 29, offset: 13338},
+							val:        "ends",
+							ignoreCase: false,
+							want:       "\"ends\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 346, col: 36, offset: 13345},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 346, col: 38, offset: 13347},
+							val:        "with",
+							ignoreCase: false,
+							want:       "\"with\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 346, col: 45, offset: 13354},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchBetween",
+			pos:  position{line: 349, col: 1, offset: 13390},
+			expr: &actionExpr{
+				pos: position{line: 349, col: 17, offset: 13406},
+				run: (*parser).callonMatchBetween1,
+				expr: &seqExpr{
+					pos: position{line: 349, col: 17, offset: 13406},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 349, col: 17, offset: 13406},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 349, col: 19, offset: 13408},
+							val:        "between",
+							ignoreCase: false,
+							want:       "\"between\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 349, col: 29, offset: 13418},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotBetween",
+			pos:  position{line: 352, col: 1, offset: 13452},
+			expr: &actionExpr{
+				pos: position{line: 352, col: 20, offset: 13471},
+				run: (*parser).callonMatchNotBetween1,
+				expr: &seqExpr{
+					pos: position{line: 352, col: 20, offset: 13471},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 352, col: 20, offset: 13471},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 352, col: 22, offset: 13473},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 352, col: 28, offset: 13479},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 352, col: 30, offset: 13481},
+							val:        "between",
+							ignoreCase: false,
+							want:       "\"between\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 352, col: 40, offset: 13491},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchLessThan",
+			pos:  position{line: 355, col: 1, offset: 13528},
+			expr: &choiceExpr{
+				pos: position{line: 355, col: 18, offset: 13545},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 355, col: 18, offset: 13545},
+						run: (*parser).callonMatchLessThan2,
+						expr: &seqExpr{
+							pos: position{line: 355, col: 18, offset: 13545},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 355, col: 18, offset: 13545},
+									expr: &ruleRefExpr{
+										pos:  position{line: 355, col: 18, offset: 13545},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 355, col: 21, offset: 13548},
+									val:        "<",
+									ignoreCase: false,
+									want:       "\"<\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 355, col: 25, offset: 13552},
+									expr: &ruleRefExpr{
+										pos:  position{line: 355, col: 25, offset: 13552},
+										name: "_",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 357, col: 5, offset: 13590},
+						run: (*parser).callonMatchLessThan9,
+						expr: &seqExpr{
+							pos: position{line: 357, col: 5, offset: 13590},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 357, col: 5, offset: 13590},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 357, col: 7, offset: 13592},
+									val:        "lt",
+									ignoreCase: false,
+									want:       "\"lt\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 357, col: 12, offset: 13597},
+									name: "_",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchLessThanOrEqual",
+			pos:  position{line: 360, col: 1, offset: 13632},
+			expr: &choiceExpr{
+				pos: position{line: 360, col: 25, offset: 13656},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 360, col: 25, offset: 13656},
+						run: (*parser).callonMatchLessThanOrEqual2,
+						expr: &seqExpr{
+							pos: position{line: 360, col: 25, offset: 13656},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 360, col: 25, offset: 13656},
+									expr: &ruleRefExpr{
+										pos:  position{line: 360, col: 25, offset: 13656},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 360, col: 28, offset: 13659},
+									val:        "<=",
+									ignoreCase: false,
+									want:       "\"<=\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 360, col: 33, offset: 13664},
+									expr: &ruleRefExpr{
+										pos:  position{line: 360, col: 33, offset: 13664},
+										name: "_",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 362, col: 5, offset: 13709},
+						run: (*parser).callonMatchLessThanOrEqual9,
+						expr: &seqExpr{
+							pos: position{line: 362, col: 5, offset: 13709},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 362, col: 5, offset: 13709},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 362, col: 7, offset: 13711},
+									val:        "le",
+									ignoreCase: false,
+									want:       "\"le\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 362, col: 12, offset: 13716},
+									name: "_",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchGreaterThan",
+			pos:  position{line: 365, col: 1, offset: 13758},
+			expr: &choiceExpr{
+				pos: position{line: 365, col: 21, offset: 13778},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 365, col: 21, offset: 13778},
+						run: (*parser).callonMatchGreaterThan2,
+						expr: &seqExpr{
+							pos: position{line: 365, col: 21, offset: 13778},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 365, col: 21, offset: 13778},
+									expr: &ruleRefExpr{
+										pos:  position{line: 365, col: 21, offset: 13778},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 365, col: 24, offset: 13781},
+									val:        ">",
+									ignoreCase: false,
+									want:       "\">\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 365, col: 28, offset: 13785},
+									expr: &ruleRefExpr{
+										pos:  position{line: 365, col: 28, offset: 13785},
+										name: "_",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 367, col: 5, offset: 13826},
+						run: (*parser).callonMatchGreaterThan9,
+						expr: &seqExpr{
+							pos: position{line: 367, col: 5, offset: 13826},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 367, col: 5, offset: 13826},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 367, col: 7, offset: 13828},
+									val:        "gt",
+									ignoreCase: false,
+									want:       "\"gt\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 367, col: 12, offset: 13833},
+									name: "_",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchGreaterThanOrEqual",
+			pos:  position{line: 370, col: 1, offset: 13871},
+			expr: &choiceExpr{
+				pos: position{line: 370, col: 28, offset: 13898},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 370, col: 28, offset: 13898},
+						run: (*parser).callonMatchGreaterThanOrEqual2,
+						expr: &seqExpr{
+							pos: position{line: 370, col: 28, offset: 13898},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 370, col: 28, offset: 13898},
+									expr: &ruleRefExpr{
+										pos:  position{line: 370, col: 28, offset: 13898},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 370, col: 31, offset: 13901},
+									val:        ">=",
+									ignoreCase: false,
+									want:       "\">=\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 370, col: 36, offset: 13906},
+									expr: &ruleRefExpr{
+										pos:  position{line: 370, col: 36, offset: 13906},
+										name: "_",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 372, col: 5, offset: 13954},
+						run: (*parser).callonMatchGreaterThanOrEqual9,
+						expr: &seqExpr{
+							pos: position{line: 372, col: 5, offset: 13954},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 372, col: 5, offset: 13954},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 372, col: 7, offset: 13956},
+									val:        "ge",
+									ignoreCase: false,
+									want:       "\"ge\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 372, col: 12, offset: 13961},
+									name: "_",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchIsEmpty",
+			pos:  position{line: 375, col: 1, offset: 14006},
+			expr: &actionExpr{
+				pos: position{line: 375, col: 17, offset: 14022},
+				run: (*parser).callonMatchIsEmpty1,
+				expr: &seqExpr{
+					pos: position{line: 375, col: 17, offset: 14022},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 375, col: 17, offset: 14022},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 375, col: 19, offset: 14024},
+							val:        "is",
+							ignoreCase: false,
+							want:       "\"is\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 375, col: 24, offset: 14029},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 375, col: 26, offset: 14031},
+							val:        "empty",
+							ignoreCase: false,
+							want:       "\"empty\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchIsNotEmpty",
+			pos:  position{line: 378, col: 1, offset: 14071},
+			expr: &actionExpr{
+				pos: position{line: 378, col: 20, offset: 14090},
+				run: (*parser).callonMatchIsNotEmpty1,
+				expr: &seqExpr{
+					pos: position{line: 378, col: 20, offset: 14090},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 378, col: 20, offset: 14090},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 378, col: 21, offset: 14091},
+							val:        "is",
+							ignoreCase: false,
+							want:       "\"is\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 378, col: 26, offset: 14096},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 378, col: 28, offset: 14098},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 378, col: 34, offset: 14104},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 378, col: 36, offset: 14106},
+							val:        "empty",
+							ignoreCase: false,
+							want:       "\"empty\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchIsNull",
+			pos:  position{line: 381, col: 1, offset: 14149},
+			expr: &actionExpr{
+				pos: position{line: 381, col: 16, offset: 14164},
+				run: (*parser).callonMatchIsNull1,
+				expr: &seqExpr{
+					pos: position{line: 381, col: 16, offset: 14164},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 381, col: 16, offset: 14164},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 381, col: 18, offset: 14166},
+							val:        "is",
+							ignoreCase: false,
+							want:       "\"is\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 381, col: 23, offset: 14171},
+							name: "_",
+						},
+						&choiceExpr{
+							pos: position{line: 381, col: 26, offset: 14174},
+							alternatives: []interface{}{
+								&litMatcher{
+									pos:        position{line: 381, col: 26, offset: 14174},
+									val:        "null",
+									ignoreCase: false,
+									want:       "\"null\"",
+								},
+								&litMatcher{
+									pos:        position{line: 381, col: 35, offset: 14183},
+									val:        "nil",
+									ignoreCase: false,
+									want:       "\"nil\"",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchIsNotNull",
+			pos:  position{line: 384, col: 1, offset: 14221},
+			expr: &actionExpr{
+				pos: position{line: 384, col: 19, offset: 14239},
+				run: (*parser).callonMatchIsNotNull1,
+				expr: &seqExpr{
+					pos: position{line: 384, col: 19, offset: 14239},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 384, col: 19, offset: 14239},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 384, col: 21, offset: 14241},
+							val:        "is",
+							ignoreCase: false,
+							want:       "\"is\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 384, col: 26, offset: 14246},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 384, col: 28, offset: 14248},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 384, col: 34, offset: 14254},
+							name: "_",
+						},
+						&choiceExpr{
+							pos: position{line: 384, col: 37, offset: 14257},
+							alternatives: []interface{}{
+								&litMatcher{
+									pos:        position{line: 384, col: 37, offset: 14257},
+									val:        "null",
+									ignoreCase: false,
+									want:       "\"null\"",
+								},
+								&litMatcher{
+									pos:        position{line: 384, col: 46, offset: 14266},
+									val:        "nil",
+									ignoreCase: false,
+									want:       "\"nil\"",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchInCIDR",
+			pos:  position{line: 387, col: 1, offset: 14307},
+			expr: &actionExpr{
+				pos: position{line: 387, col: 16, offset: 14322},
+				run: (*parser).callonMatchInCIDR1,
+				expr: &seqExpr{
+					pos: position{line: 387, col: 16, offset: 14322},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 387, col: 16, offset: 14322},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 387, col: 18, offset: 14324},
+							val:        "in",
+							ignoreCase: false,
+							want:       "\"in\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 387, col: 23, offset: 14329},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 387, col: 25, offset: 14331},
+							val:        "cidr",
+							ignoreCase: false,
+							want:       "\"cidr\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 387, col: 32, offset: 14338},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotInCIDR",
+			pos:  position{line: 390, col: 1, offset: 14371},
+			expr: &actionExpr{
+				pos: position{line: 390, col: 19, offset: 14389},
+				run: (*parser).callonMatchNotInCIDR1,
+				expr: &seqExpr{
+					pos: position{line: 390, col: 19, offset: 14389},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 390, col: 19, offset: 14389},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 390, col: 21, offset: 14391},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 390, col: 27, offset: 14397},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 390, col: 29, offset: 14399},
+							val:        "in",
+							ignoreCase: false,
+							want:       "\"in\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 390, col: 34, offset: 14404},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 390, col: 36, offset: 14406},
+							val:        "cidr",
+							ignoreCase: false,
+							want:       "\"cidr\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 390, col: 43, offset: 14413},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchIn",
+			pos:  position{line: 393, col: 1, offset: 14449},
+			expr: &actionExpr{
+				pos: position{line: 393, col: 12, offset: 14460},
+				run: (*parser).callonMatchIn1,
+				expr: &seqExpr{
+					pos: position{line: 393, col: 12, offset: 14460},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 393, col: 12, offset: 14460},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 393, col: 14, offset: 14462},
+							val:        "in",
+							ignoreCase: false,
+							want:       "\"in\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 393, col: 19, offset: 14467},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotIn",
+			pos:  position{line: 396, col: 1, offset: 14496},
+			expr: &actionExpr{
+				pos: position{line: 396, col: 15, offset: 14510},
+				run: (*parser).callonMatchNotIn1,
+				expr: &seqExpr{
+					pos: position{line: 396, col: 15, offset: 14510},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 396, col: 15, offset: 14510},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 396, col: 17, offset: 14512},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 396, col: 23, offset: 14518},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 396, col: 25, offset: 14520},
+							val:        "in",
+							ignoreCase: false,
+							want:       "\"in\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 396, col: 30, offset: 14525},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchContainsAny",
+			pos:  position{line: 399, col: 1, offset: 14557},
+			expr: &actionExpr{
+				pos: position{line: 399, col: 21, offset: 14577},
+				run: (*parser).callonMatchContainsAny1,
+				expr: &seqExpr{
+					pos: position{line: 399, col: 21, offset: 14577},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 399, col: 21, offset: 14577},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 399, col: 23, offset: 14579},
+							val:        "contains",
+							ignoreCase: false,
+							want:       "\"contains\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 399, col: 34, offset: 14590},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 399, col: 36, offset: 14592},
+							val:        "any",
+							ignoreCase: false,
+							want:       "\"any\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 399, col: 42, offset: 14598},
+							expr: &ruleRefExpr{
+								pos:  position{line: 399, col: 42, offset: 14598},
+								name: "_",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotContainsAny",
+			pos:  position{line: 403, col: 1, offset: 14638},
+			expr: &actionExpr{
+				pos: position{line: 403, col: 24, offset: 14661},
+				run: (*parser).callonMatchNotContainsAny1,
+				expr: &seqExpr{
+					pos: position{line: 403, col: 24, offset: 14661},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 403, col: 24, offset: 14661},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 403, col: 26, offset: 14663},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 403, col: 32, offset: 14669},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 403, col: 34, offset: 14671},
+							val:        "contains",
+							ignoreCase: false,
+							want:       "\"contains\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 403, col: 45, offset: 14682},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 403, col: 47, offset: 14684},
+							val:        "any",
+							ignoreCase: false,
+							want:       "\"any\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 403, col: 53, offset: 14690},
+							expr: &ruleRefExpr{
+								pos:  position{line: 403, col: 53, offset: 14690},
+								name: "_",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchContainsAll",
+			pos:  position{line: 407, col: 1, offset: 14733},
+			expr: &actionExpr{
+				pos: position{line: 407, col: 21, offset: 14753},
+				run: (*parser).callonMatchContainsAll1,
+				expr: &seqExpr{
+					pos: position{line: 407, col: 21, offset: 14753},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 407, col: 21, offset: 14753},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 407, col: 23, offset: 14755},
+							val:        "contains",
+							ignoreCase: false,
+							want:       "\"contains\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 407, col: 34, offset: 14766},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 407, col: 36, offset: 14768},
+							val:        "all",
+							ignoreCase: false,
+							want:       "\"all\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 407, col: 42, offset: 14774},
+							expr: &ruleRefExpr{
+								pos:  position{line: 407, col: 42, offset: 14774},
+								name: "_",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotContainsAll",
+			pos:  position{line: 411, col: 1, offset: 14814},
+			expr: &actionExpr{
+				pos: position{line: 411, col: 24, offset: 14837},
+				run: (*parser).callonMatchNotContainsAll1,
+				expr: &seqExpr{
+					pos: position{line: 411, col: 24, offset: 14837},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 411, col: 24, offset: 14837},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 411, col: 26, offset: 14839},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 411, col: 32, offset: 14845},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 411, col: 34, offset: 14847},
+							val:        "contains",
+							ignoreCase: false,
+							want:       "\"contains\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 411, col: 45, offset: 14858},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 411, col: 47, offset: 14860},
+							val:        "all",
+							ignoreCase: false,
+							want:       "\"all\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 411, col: 53, offset: 14866},
+							expr: &ruleRefExpr{
+								pos:  position{line: 411, col: 53, offset: 14866},
+								name: "_",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchContains",
+			pos:  position{line: 415, col: 1, offset: 14909},
+			expr: &actionExpr{
+				pos: position{line: 415, col: 18, offset: 14926},
+				run: (*parser).callonMatchContains1,
+				expr: &seqExpr{
+					pos: position{line: 415, col: 18, offset: 14926},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 415, col: 18, offset: 14926},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 415, col: 20, offset: 14928},
+							val:        "contains",
+							ignoreCase: false,
+							want:       "\"contains\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 415, col: 31, offset: 14939},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotContains",
+			pos:  position{line: 418, col: 1, offset: 14968},
+			expr: &actionExpr{
+				pos: position{line: 418, col: 21, offset: 14988},
+				run: (*parser).callonMatchNotContains1,
+				expr: &seqExpr{
+					pos: position{line: 418, col: 21, offset: 14988},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 418, col: 21, offset: 14988},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 418, col: 23, offset: 14990},
+							val:        "not",
+							ignoreCase: false,
+							want:       "\"not\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 418, col: 29, offset: 14996},
+							name: "_",
+						},
+						&litMatcher{
+							pos:        position{line: 418, col: 31, offset: 14998},
+							val:        "contains",
+							ignoreCase: false,
+							want:       "\"contains\"",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 418, col: 42, offset: 15009},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchMatches",
+			pos:  position{line: 421, col: 1, offset: 15041},
+			expr: &choiceExpr{
+				pos: position{line: 421, col: 17, offset: 15057},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 421, col: 17, offset: 15057},
+						run: (*parser).callonMatchMatches2,
+						expr: &seqExpr{
+							pos: position{line: 421, col: 17, offset: 15057},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 421, col: 17, offset: 15057},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 421, col: 19, offset: 15059},
+									val:        "matches",
+									ignoreCase: false,
+									want:       "\"matches\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 421, col: 29, offset: 15069},
+									name: "_",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 423, col: 5, offset: 15105},
+						run: (*parser).callonMatchMatches7,
+						expr: &seqExpr{
+							pos: position{line: 423, col: 5, offset: 15105},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 423, col: 5, offset: 15105},
+									expr: &ruleRefExpr{
+										pos:  position{line: 423, col: 5, offset: 15105},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 423, col: 8, offset: 15108},
+									val:        "=~",
+									ignoreCase: false,
+									want:       "\"=~\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 423, col: 13, offset: 15113},
+									expr: &ruleRefExpr{
+										pos:  position{line: 423, col: 13, offset: 15113},
+										name: "_",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotMatches",
+			pos:  position{line: 426, col: 1, offset: 15148},
+			expr: &choiceExpr{
+				pos: position{line: 426, col: 20, offset: 15167},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 426, col: 20, offset: 15167},
+						run: (*parser).callonMatchNotMatches2,
+						expr: &seqExpr{
+							pos: position{line: 426, col: 20, offset: 15167},
+							exprs: []interface{}{
+								&ruleRefExpr{
+									pos:  position{line: 426, col: 20, offset: 15167},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 426, col: 22, offset: 15169},
+									val:        "not",
+									ignoreCase: false,
+									want:       "\"not\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 426, col: 28, offset: 15175},
+									name: "_",
+								},
+								&litMatcher{
+									pos:        position{line: 426, col: 30, offset: 15177},
+									val:        "matches",
+									ignoreCase: false,
+									want:       "\"matches\"",
+								},
+								&ruleRefExpr{
+									pos:  position{line: 426, col: 40, offset: 15187},
+									name: "_",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 428, col: 5, offset: 15226},
+						run: (*parser).callonMatchNotMatches9,
+						expr: &seqExpr{
+							pos: position{line: 428, col: 5, offset: 15226},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 428, col: 5, offset: 15226},
+									expr: &ruleRefExpr{
+										pos:  position{line: 428, col: 5, offset: 15226},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 428, col: 8, offset: 15229},
+									val:        "!~",
+									ignoreCase: false,
+									want:       "\"!~\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 428, col: 13, offset: 15234},
+									expr: &ruleRefExpr{
+										pos:  position{line: 428, col: 13, offset: 15234},
+										name: "_",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "Selector",
+			displayName: "\"selector\"",
+			pos:         position{line: 432, col: 1, offset: 15273},
+			expr: &choiceExpr{
+				pos: position{line: 432, col: 24, offset: 15296},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 432, col: 24, offset: 15296},
+						run: (*parser).callonSelector2,
+						expr: &seqExpr{
+							pos: position{line: 432, col: 24, offset: 15296},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 432, col: 24, offset: 15296},
+									label: "first",
+									expr: &ruleRefExpr{
+										pos:  position{line: 432, col: 30, offset: 15302},
+										name: "Identifier",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 432, col: 41, offset: 15313},
+									label: "rest",
+									expr: &zeroOrMoreExpr{
+										pos: position{line: 432, col: 46, offset: 15318},
+										expr: &ruleRefExpr{
+											pos:  position{line: 432, col: 46, offset: 15318},
+											name: "SelectorOrIndex",
+										},
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 447, col: 5, offset: 15721},
+						run: (*parser).callonSelector9,
+						expr: &seqExpr{
+							pos: position{line: 447, col: 5, offset: 15721},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 447, col: 5, offset: 15721},
+									val:        "\"",
+									ignoreCase: false,
+									want:       "\"\\\"\"",
+								},
+								&labeledExpr{
+									pos:   position{line: 447, col: 9, offset: 15725},
+									label: "ptrsegs",
+									expr: &zeroOrMoreExpr{
+										pos: position{line: 447, col: 17, offset: 15733},
+										expr: &ruleRefExpr{
+											pos:  position{line: 447, col: 17, offset: 15733},
+											name: "JsonPointerSegment",
+										},
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 447, col: 37, offset: 15753},
+									val:        "\"",
+									ignoreCase: false,
+									want:       "\"\\\"\"",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "JsonPointerSegment",
+			pos:  position{line: 468, col: 1, offset: 16231},
+			expr: &actionExpr{
+				pos: position{line: 468, col: 23, offset: 16253},
+				run: (*parser).callonJsonPointerSegment1,
+				expr: &seqExpr{
+					pos: position{line: 468, col: 23, offset: 16253},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 468, col: 23, offset: 16253},
+							val:        "/",
+							ignoreCase: false,
+							want:       "\"/\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 468, col: 27, offset: 16257},
+							label: "ident",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 468, col: 33, offset: 16263},
+								expr: &charClassMatcher{
+									pos:        position{line: 468, col: 33, offset: 16263},
+									val:        "[\\pL\\pN-_.~|]",
+									chars:      []rune{'-', '_', '.', '~', '|'},
+									classes:    []*unicode.RangeTable{rangeTable("L"), rangeTable("N")},
+									ignoreCase: false,
+									inverted:   false,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "NamedSet",
+			displayName: "\"value set\"",
+			pos:         position{line: 472, col: 1, offset: 16317},
+			expr: &actionExpr{
+				pos: position{line: 472, col: 25, offset: 16341},
+				run: (*parser).callonNamedSet1,
+				expr: &seqExpr{
+					pos: position{line: 472, col: 25, offset: 16341},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 472, col: 25, offset: 16341},
+							val:        "@",
+							ignoreCase: false,
+							want:       "\"@\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 472, col: 29, offset: 16345},
+							label: "name",
+							expr: &ruleRefExpr{
+								pos:  position{line: 472, col: 34, offset: 16350},
+								name: "Identifier",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "ListLiteral",
+			displayName: "\"list\"",
+			pos:         position{line: 476, col: 1, offset: 16386},
+			expr: &choiceExpr{
+				pos: position{line: 476, col: 23, offset: 16408},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 476, col: 23, offset: 16408},
+						run: (*parser).callonListLiteral2,
+						expr: &seqExpr{
+							pos: position{line: 476, col: 23, offset: 16408},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 476, col: 23, offset: 16408},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 476, col: 27, offset: 16412},
+									expr: &ruleRefExpr{
+										pos:  position{line: 476, col: 27, offset: 16412},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 476, col: 30, offset: 16415},
+									label: "first",
+									expr: &ruleRefExpr{
+										pos:  position{line: 476, col: 36, offset: 16421},
+										name: "Value",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 476, col: 42, offset: 16427},
+									label: "rest",
+									expr: &zeroOrMoreExpr{
+										pos: position{line: 476, col: 47, offset: 16432},
+										expr: &ruleRefExpr{
+											pos:  position{line: 476, col: 47, offset: 16432},
+											name: "ListLiteralItem",
+										},
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 476, col: 64, offset: 16449},
+									expr: &ruleRefExpr{
+										pos:  position{line: 476, col: 64, offset: 16449},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 476, col: 67, offset: 16452},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 484, col: 5, offset: 16662},
+						run: (*parser).callonListLiteral15,
+						expr: &seqExpr{
+							pos: position{line: 484, col: 5, offset: 16662},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 484, col: 5, offset: 16662},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 484, col: 9, offset: 16666},
+									expr: &ruleRefExpr{
+										pos:  position{line: 484, col: 9, offset: 16666},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 484, col: 12, offset: 16669},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 486, col: 5, offset: 16710},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 486, col: 5, offset: 16710},
+								val:        "[",
+								ignoreCase: false,
+								want:       "\"[\"",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 486, col: 9, offset: 16714},
+								expr: &ruleRefExpr{
+									pos:  position{line: 486, col: 9, offset: 16714},
+									name: "_",
+								},
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 486, col: 12, offset: 16717},
+								expr: &seqExpr{
+									pos: position{line: 486, col: 13, offset: 16718},
+									exprs: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 486, col: 13, offset: 16718},
+											name: "Value",
+										},
+										&zeroOrMoreExpr{
+											pos: position{line: 486, col: 19, offset: 16724},
+											expr: &ruleRefExpr{
+												pos:  position{line: 486, col: 19, offset: 16724},
+												name: "ListLiteralItem",
+											},
+										},
+										&zeroOrOneExpr{
+											pos: position{line: 486, col: 36, offset: 16741},
+											expr: &ruleRefExpr{
+												pos:  position{line: 486, col: 36, offset: 16741},
+												name: "_",
+											},
+										},
+									},
+								},
+							},
+							&notExpr{
+								pos: position{line: 486, col: 41, offset: 16746},
+								expr: &litMatcher{
+									pos:        position{line: 486, col: 42, offset: 16747},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 486, col: 46, offset: 16751},
+								run: (*parser).callonListLiteral34,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ListLiteralItem",
+			pos:  position{line: 490, col: 1, offset: 16810},
+			expr: &actionExpr{
+				pos: position{line: 490, col: 20, offset: 16829},
+				run: (*parser).callonListLiteralItem1,
+				expr: &seqExpr{
+					pos: position{line: 490, col: 20, offset: 16829},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 490, col: 20, offset: 16829},
+							expr: &ruleRefExpr{
+								pos:  position{line: 490, col: 20, offset: 16829},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 490, col: 23, offset: 16832},
+							val:        ",",
+							ignoreCase: false,
+							want:       "\",\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 490, col: 27, offset: 16836},
+							expr: &ruleRefExpr{
+								pos:  position{line: 490, col: 27, offset: 16836},
+								name: "_",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 490, col: 30, offset: 16839},
+							label: "value",
+							expr: &ruleRefExpr{
+								pos:  position{line: 490, col: 36, offset: 16845},
+								name: "Value",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ReservedWord",
+			pos:  position{line: 494, col: 1, offset: 16877},
+			expr: &seqExpr{
+				pos: position{line: 494, col: 17, offset: 16893},
+				exprs: []interface{}{
+					&choiceExpr{
+						pos: position{line: 494, col: 18, offset: 16894},
+						alternatives: []interface{}{
+							&litMatcher{
+								pos:        position{line: 494, col: 18, offset: 16894},
+								val:        "and",
+								ignoreCase: false,
+								want:       "\"and\"",
+							},
+							&litMatcher{
+								pos:        position{line: 494, col: 26, offset: 16902},
+								val:        "or",
+								ignoreCase: false,
+								want:       "\"or\"",
+							},
+							&litMatcher{
+								pos:        position{line: 494, col: 33, offset: 16909},
+								val:        "xor",
+								ignoreCase: false,
+								want:       "\"xor\"",
+							},
+							&litMatcher{
+								pos:        position{line: 494, col: 41, offset: 16917},
+								val:        "not",
+								ignoreCase: false,
+								want:       "\"not\"",
+							},
+						},
+					},
+					&notExpr{
+						pos: position{line: 494, col: 48, offset: 16924},
+						expr: &choiceExpr{
+							pos: position{line: 494, col: 50, offset: 16926},
+							alternatives: []interface{}{
+								&charClassMatcher{
+									pos:        position{line: 494, col: 50, offset: 16926},
+									val:        "[a-zA-Z0-9_]",
+									chars:      []rune{'_'},
+									ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
+									ignoreCase: false,
+									inverted:   false,
+								},
+								&litMatcher{
+									pos:        position{line: 494, col: 65, offset: 16941},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
+								},
+								&litMatcher{
+									pos:        position{line: 494, col: 71, offset: 16947},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Identifier",
+			pos:  position{line: 496, col: 1, offset: 16953},
+			expr: &actionExpr{
+				pos: position{line: 496, col: 15, offset: 16967},
+				run: (*parser).callonIdentifier1,
+				expr: &seqExpr{
+					pos: position{line: 496, col: 15, offset: 16967},
+					exprs: []interface{}{
+						&charClassMatcher{
+							pos:        position{line: 496, col: 15, offset: 16967},
+							val:        "[a-zA-Z]",
+							ranges:     []rune{'a', 'z', 'A', 'Z'},
+							ignoreCase: false,
+							inverted:   false,
+						},
+						&zeroOrMoreExpr{
+							pos: position{line: 496, col: 24, offset: 16976},
+							expr: &charClassMatcher{
+								pos:        position{line: 496, col: 24, offset: 16976},
+								val:        "[a-zA-Z0-9_]",
+								chars:      []rune{'_'},
+								ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
+								ignoreCase: false,
+								inverted:   false,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "SelectorOrIndex",
+			pos:  position{line: 500, col: 1, offset: 17025},
+			expr: &choiceExpr{
+				pos: position{line: 500, col: 20, offset: 17044},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 500, col: 20, offset: 17044},
+						run: (*parser).callonSelectorOrIndex2,
+						expr: &seqExpr{
+							pos: position{line: 500, col: 20, offset: 17044},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 500, col: 20, offset: 17044},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
+								},
+								&labeledExpr{
+									pos:   position{line: 500, col: 24, offset: 17048},
+									label: "ident",
+									expr: &ruleRefExpr{
+										pos:  position{line: 500, col: 30, offset: 17054},
+										name: "Identifier",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 502, col: 5, offset: 17092},
+						run: (*parser).callonSelectorOrIndex7,
+						expr: &seqExpr{
+							pos: position{line: 502, col: 5, offset: 17092},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 502, col: 5, offset: 17092},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
+								},
+								&litMatcher{
+									pos:        position{line: 502, col: 9, offset: 17096},
+									val:        "*",
+									ignoreCase: false,
+									want:       "\"*\"",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 504, col: 5, offset: 17139},
+						run: (*parser).callonSelectorOrIndex11,
+						expr: &seqExpr{
+							pos: position{line: 504, col: 5, offset: 17139},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 504, col: 5, offset: 17139},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
+								},
+								&labeledExpr{
+									pos:   position{line: 504, col: 9, offset: 17143},
+									label: "lit",
+									expr: &ruleRefExpr{
+										pos:  position{line: 504, col: 13, offset: 17147},
+										name: "StringLiteral",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 507, col: 5, offset: 17270},
+						run: (*parser).callonSelectorOrIndex16,
+						expr: &labeledExpr{
+							pos:   position{line: 507, col: 5, offset: 17270},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 507, col: 10, offset: 17275},
+								name: "IndexExpression",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 509, col: 5, offset: 17317},
+						run: (*parser).callonSelectorOrIndex19,
+						expr: &seqExpr{
+							pos: position{line: 509, col: 5, offset: 17317},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 509, col: 5, offset: 17317},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
+								},
+								&labeledExpr{
+									pos:   position{line: 509, col: 9, offset: 17321},
+									label: "idx",
+									expr: &oneOrMoreExpr{
+										pos: position{line: 509, col: 13, offset: 17325},
+										expr: &charClassMatcher{
+											pos:        position{line: 509, col: 13, offset: 17325},
+											val:        "[0-9]",
+											ranges:     []rune{'0', '9'},
+											ignoreCase: false,
+											inverted:   false,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "IndexExpression",
+			displayName: "\"index\"",
+			pos:         position{line: 513, col: 1, offset: 17371},
+			expr: &choiceExpr{
+				pos: position{line: 513, col: 28, offset: 17398},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 513, col: 28, offset: 17398},
+						run: (*parser).callonIndexExpression2,
+						expr: &seqExpr{
+							pos: position{line: 513, col: 28, offset: 17398},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 513, col: 28, offset: 17398},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 513, col: 32, offset: 17402},
+									expr: &ruleRefExpr{
+										pos:  position{line: 513, col: 32, offset: 17402},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 513, col: 35, offset: 17405},
+									label: "lit",
+									expr: &ruleRefExpr{
+										pos:  position{line: 513, col: 39, offset: 17409},
+										name: "StringLiteral",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 513, col: 53, offset: 17423},
+									expr: &ruleRefExpr{
+										pos:  position{line: 513, col: 53, offset: 17423},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 513, col: 56, offset: 17426},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 515, col: 5, offset: 17455},
+						run: (*parser).callonIndexExpression12,
+						expr: &seqExpr{
+							pos: position{line: 515, col: 5, offset: 17455},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 515, col: 5, offset: 17455},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 515, col: 9, offset: 17459},
+									expr: &ruleRefExpr{
+										pos:  position{line: 515, col: 9, offset: 17459},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 515, col: 12, offset: 17462},
+									label: "idx",
+									expr: &ruleRefExpr{
+										pos:  position{line: 515, col: 16, offset: 17466},
+										name: "IndexNumber",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 515, col: 28, offset: 17478},
+									expr: &ruleRefExpr{
+										pos:  position{line: 515, col: 28, offset: 17478},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 515, col: 31, offset: 17481},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 517, col: 5, offset: 17510},
+						run: (*parser).callonIndexExpression22,
+						expr: &seqExpr{
+							pos: position{line: 517, col: 5, offset: 17510},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 517, col: 5, offset: 17510},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 517, col: 9, offset: 17514},
+									expr: &ruleRefExpr{
+										pos:  position{line: 517, col: 9, offset: 17514},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 517, col: 12, offset: 17517},
+									val:        "*",
+									ignoreCase: false,
+									want:       "\"*\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 517, col: 16, offset: 17521},
+									expr: &ruleRefExpr{
+										pos:  position{line: 517, col: 16, offset: 17521},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 517, col: 19, offset: 17524},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 519, col: 5, offset: 17567},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 519, col: 5, offset: 17567},
+								val:        "[",
+								ignoreCase: false,
+								want:       "\"[\"",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 519, col: 9, offset: 17571},
+								expr: &ruleRefExpr{
+									pos:  position{line: 519, col: 9, offset: 17571},
+									name: "_",
+								},
+							},
+							&notExpr{
+								pos: position{line: 519, col: 12, offset: 17574},
+								expr: &ruleRefExpr{
+									pos:  position{line: 519, col: 13, offset: 17575},
+									name: "StringLiteral",
+								},
+							},
+							&notExpr{
+								pos: position{line: 519, col: 27, offset: 17589},
+								expr: &ruleRefExpr{
+									pos:  position{line: 519, col: 28, offset: 17590},
+									name: "IndexNumber",
+								},
+							},
+							&notExpr{
+								pos: position{line: 519, col: 40, offset: 17602},
+								expr: &litMatcher{
+									pos:        position{line: 519, col: 41, offset: 17603},
+									val:        "*",
+									ignoreCase: false,
+									want:       "\"*\"",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 519, col: 45, offset: 17607},
+								run: (*parser).callonIndexExpression41,
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 521, col: 5, offset: 17659},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 521, col: 5, offset: 17659},
+								val:        "[",
+								ignoreCase: false,
+								want:       "\"[\"",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 521, col: 9, offset: 17663},
+								expr: &ruleRefExpr{
+									pos:  position{line: 521, col: 9, offset: 17663},
+									name: "_",
+								},
+							},
+							&choiceExpr{
+								pos: position{line: 521, col: 13, offset: 17667},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 521, col: 13, offset: 17667},
+										name: "StringLiteral",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 521, col: 29, offset: 17683},
+										name: "IndexNumber",
+									},
+									&litMatcher{
+										pos:        position{line: 521, col: 43, offset: 17697},
+										val:        "*",
+										ignoreCase: false,
+										want:       "\"*\"",
+									},
+								},
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 521, col: 48, offset: 17702},
+								expr: &ruleRefExpr{
+									pos:  position{line: 521, col: 48, offset: 17702},
+									name: "_",
+								},
+							},
+							&notExpr{
+								pos: position{line: 521, col: 51, offset: 17705},
+								expr: &litMatcher{
+									pos:        position{line: 521, col: 52, offset: 17706},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 521, col: 56, offset: 17710},
+								run: (*parser).callonIndexExpression54,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "IndexNumber",
+			pos:  position{line: 525, col: 1, offset: 17773},
+			expr: &actionExpr{
+				pos: position{line: 525, col: 16, offset: 17788},
+				run: (*parser).callonIndexNumber1,
+				expr: &choiceExpr{
+					pos: position{line: 525, col: 17, offset: 17789},
+					alternatives: []interface{}{
+						&seqExpr{
+							pos: position{line: 525, col: 17, offset: 17789},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 525, col: 17, offset: 17789},
+									expr: &litMatcher{
+										pos:        position{line: 525, col: 17, offset: 17789},
+										val:        "-",
+										ignoreCase: false,
+										want:       "\"-\"",
+									},
+								},
+								&oneOrMoreExpr{
+									pos: position{line: 525, col: 22, offset: 17794},
+									expr: &charClassMatcher{
+										pos:        position{line: 525, col: 22, offset: 17794},
+										val:        "[0-9]",
+										ranges:     []rune{'0', '9'},
+										ignoreCase: false,
+										inverted:   false,
+									},
+								},
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 525, col: 31, offset: 17803},
+							val:        "last",
+							ignoreCase: false,
+							want:       "\"last\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "Value",
+			displayName: "\"value\"",
+			pos:         position{line: 529, col: 1, offset: 17846},
+			expr: &choiceExpr{
+				pos: position{line: 529, col: 18, offset: 17863},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 529, col: 18, offset: 17863},
+						run: (*parser).callonValue2,
+						expr: &labeledExpr{
+							pos:   position{line: 529, col: 18, offset: 17863},
+							label: "t",
+							expr: &ruleRefExpr{
+								pos:  position{line: 529, col: 20, offset: 17865},
+								name: "TimeLiteral",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 531, col: 5, offset: 17927},
+						run: (*parser).callonValue5,
+						expr: &labeledExpr{
+							pos:   position{line: 531, col: 5, offset: 17927},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 531, col: 14, offset: 17936},
+								name: "Selector",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 533, col: 5, offset: 18013},
+						run: (*parser).callonValue8,
+						expr: &seqExpr{
+							pos: position{line: 533, col: 5, offset: 18013},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 533, col: 5, offset: 18013},
+									val:        "$",
+									ignoreCase: false,
+									want:       "\"$\"",
+								},
+								&labeledExpr{
+									pos:   position{line: 533, col: 9, offset: 18017},
+									label: "name",
+									expr: &ruleRefExpr{
+										pos:  position{line: 533, col: 14, offset: 18022},
+										name: "Identifier",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 535, col: 5, offset: 18113},
+						run: (*parser).callonValue13,
+						expr: &labeledExpr{
+							pos:   position{line: 535, col: 5, offset: 18113},
+							label: "d",
+							expr: &ruleRefExpr{
+								pos:  position{line: 535, col: 7, offset: 18115},
+								name: "DurationLiteral",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 537, col: 5, offset: 18181},
+						run: (*parser).callonValue16,
+						expr: &labeledExpr{
+							pos:   position{line: 537, col: 5, offset: 18181},
+							label: "n",
+							expr: &ruleRefExpr{
+								pos:  position{line: 537, col: 7, offset: 18183},
+								name: "NumberLiteral",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 539, col: 5, offset: 18247},
+						run: (*parser).callonValue19,
+						expr: &labeledExpr{
+							pos:   position{line: 539, col: 5, offset: 18247},
+							label: "s",
+							expr: &ruleRefExpr{
+								pos:  position{line: 539, col: 7, offset: 18249},
+								name: "StringLiteral",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "NumberLiteral",
+			displayName: "\"number\"",
+			pos:         position{line: 543, col: 1, offset: 18326},
+			expr: &choiceExpr{
+				pos: position{line: 543, col: 27, offset: 18352},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 543, col: 27, offset: 18352},
+						run: (*parser).callonNumberLiteral2,
+						expr: &seqExpr{
+							pos: position{line: 543, col: 27, offset: 18352},
+							exprs: []interface{}{
+								&zeroOrOneExpr{
+									pos: position{line: 543, col: 27, offset: 18352},
+									expr: &litMatcher{
+										pos:        position{line: 543, col: 27, offset: 18352},
+										val:        "-",
+										ignoreCase: false,
+										want:       "\"-\"",
+									},
+								},
+								&choiceExpr{
+									pos: position{line: 543, col: 33, offset: 18358},
+									alternatives: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 543, col: 33, offset: 18358},
+											name: "HexOrOctal",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 543, col: 46, offset: 18371},
+											name: "IntegerOrFloat",
+										},
+									},
+								},
+								&andExpr{
+									pos: position{line: 543, col: 62, offset: 18387},
+									expr: &ruleRefExpr{
+										pos:  position{line: 543, col: 63, offset: 18388},
+										name: "AfterNumbers",
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 545, col: 5, offset: 18437},
+						exprs: []interface{}{
+							&zeroOrOneExpr{
+								pos: position{line: 545, col: 5, offset: 18437},
+								expr: &litMatcher{
+									pos:        position{line: 545, col: 5, offset: 18437},
+									val:        "-",
+									ignoreCase: false,
+									want:       "\"-\"",
+								},
+							},
+							&choiceExpr{
+								pos: position{line: 545, col: 11, offset: 18443},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 545, col: 11, offset: 18443},
+										name: "HexOrOctal",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 545, col: 24, offset: 18456},
+										name: "IntegerOrFloat",
+									},
+								},
+							},
+							&notExpr{
+								pos: position{line: 545, col: 40, offset: 18472},
+								expr: &ruleRefExpr{
+									pos:  position{line: 545, col: 41, offset: 18473},
+									name: "AfterNumbers",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 545, col: 54, offset: 18486},
+								run: (*parser).callonNumberLiteral19,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "TimeLiteral",
+			displayName: "\"time\"",
+			pos:         position{line: 551, col: 1, offset: 18678},
+			expr: &actionExpr{
+				pos: position{line: 551, col: 23, offset: 18700},
+				run: (*parser).callonTimeLiteral1,
+				expr: &seqExpr{
+					pos: position{line: 551, col: 23, offset: 18700},
+					exprs: []interface{}{
+						&choiceExpr{
+							pos: position{line: 551, col: 24, offset: 18701},
+							alternatives: []interface{}{
+								&seqExpr{
+									pos: position{line: 551, col: 24, offset: 18701},
+									exprs: []interface{}{
+										&litMatcher{
+											pos:        position{line: 551, col: 24, offset: 18701},
+											val:        "now",
+											ignoreCase: false,
+											want:       "\"now\"",
+										},
+										&charClassMatcher{
+											pos:        position{line: 551, col: 30, offset: 18707},
+											val:        "[+-]",
+											chars:      []rune{'+', '-'},
+											ignoreCase: false,
+											inverted:   false,
+										},
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 35, offset: 18712},
+											name: "DurationBody",
+										},
+									},
+								},
+								&seqExpr{
+									pos: position{line: 551, col: 50, offset: 18727},
+									exprs: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 50, offset: 18727},
+											name: "Digit",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 56, offset: 18733},
+											name: "Digit",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 62, offset: 18739},
+											name: "Digit",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 68, offset: 18745},
+											name: "Digit",
+										},
+										&litMatcher{
+											pos:        position{line: 551, col: 74, offset: 18751},
+											val:        "-",
+											ignoreCase: false,
+											want:       "\"-\"",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 78, offset: 18755},
+											name: "Digit",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 84, offset: 18761},
+											name: "Digit",
+										},
+										&litMatcher{
+											pos:        position{line: 551, col: 90, offset: 18767},
+											val:        "-",
+											ignoreCase: false,
+											want:       "\"-\"",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 94, offset: 18771},
+											name: "Digit",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 551, col: 100, offset: 18777},
+											name: "Digit",
+										},
+										&zeroOrOneExpr{
+											pos: position{line: 551, col: 106, offset: 18783},
+											expr: &seqExpr{
+												pos: position{line: 551, col: 107, offset: 18784},
+												exprs: []interface{}{
+													&litMatcher{
+														pos:        position{line: 551, col: 107, offset: 18784},
+														val:        "T",
+														ignoreCase: false,
+														want:       "\"T\"",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 551, col: 111, offset: 18788},
+														name: "Digit",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 551, col: 117, offset: 18794},
+														name: "Digit",
+													},
+													&litMatcher{
+														pos:        position{line: 551, col: 123, offset: 18800},
+														val:        ":",
+														ignoreCase: false,
+														want:       "\":\"",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 551, col: 127, offset: 18804},
+														name: "Digit",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 551, col: 133, offset: 18810},
+														name: "Digit",
+													},
+													&litMatcher{
+														pos:        position{line: 551, col: 139, offset: 18816},
+														val:        ":",
+														ignoreCase: false,
+														want:       "\":\"",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 551, col: 143, offset: 18820},
+														name: "Digit",
+													},
+													&ruleRefExpr{
+														pos:  position{line: 551, col: 149, offset: 18826},
+														name: "Digit",
+													},
+													&zeroOrOneExpr{
+														pos: position{line: 551, col: 155, offset: 18832},
+														expr: &seqExpr{
+															pos: position{line: 551, col: 156, offset: 18833},
+															exprs: []interface{}{
+																&litMatcher{
+																	pos:        position{line: 551, col: 156, offset: 18833},
+																	val:        ".",
+																	ignoreCase: false,
+																	want:       "\".\"",
+																},
+																&oneOrMoreExpr{
+																	pos: position{line: 551, col: 160, offset: 18837},
+																	expr: &ruleRefExpr{
+																		pos:  position{line: 551, col: 160, offset: 18837},
+																		name: "Digit",
+																	},
+																},
+															},
+														},
+													},
+													&choiceExpr{
+														pos: position{line: 551, col: 170, offset: 18847},
+														alternatives: []interface{}{
+															&litMatcher{
+																pos:        position{line: 551, col: 170, offset: 18847},
+																val:        "Z",
+																ignoreCase: false,
+																want:       "\"Z\"",
+															},
+															&seqExpr{
+																pos: position{line: 551, col: 176, offset: 18853},
+																exprs: []interface{}{
+																	&charClassMatcher{
+																		pos:        position{line: 551, col: 176, offset: 18853},
+																		val:        "[+-]",
+																		chars:      []rune{'+', '-'},
+																		ignoreCase: false,
+																		inverted:   false,
+																	},
+																	&ruleRefExpr{
+																		pos:  position{line: 551, col: 181, offset: 18858},
+																		name: "Digit",
+																	},
+																	&ruleRefExpr{
+																		pos:  position{line: 551, col: 187, offset: 18864},
+																		name: "Digit",
+																	},
+																	&litMatcher{
+																		pos:        position{line: 551, col: 193, offset: 18870},
+																		val:        ":",
+																		ignoreCase: false,
+																		want:       "\":\"",
+																	},
+																	&ruleRefExpr{
+																		pos:  position{line: 551, col: 197, offset: 18874},
+																		name: "Digit",
+																	},
+																	&ruleRefExpr{
+																		pos:  position{line: 551, col: 203, offset: 18880},
+																		name: "Digit",
+																	},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						&andExpr{
+							pos: position{line: 551, col: 213, offset: 18890},
+							expr: &ruleRefExpr{
+								pos:  position{line: 551, col: 214, offset: 18891},
+								name: "AfterNumbers",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Digit",
+			pos:  position{line: 555, col: 1, offset: 18939},
+			expr: &charClassMatcher{
+				pos:        position{line: 555, col: 10, offset: 18948},
+				val:        "[0-9]",
+				ranges:     []rune{'0', '9'},
+				ignoreCase: false,
+				inverted:   false,
+			},
+		},
+		{
+			name:        "DurationLiteral",
+			displayName: "\"duration\"",
+			pos:         position{line: 557, col: 1, offset: 18955},
+			expr: &actionExpr{
+				pos: position{line: 557, col: 31, offset: 18985},
+				run: (*parser).callonDurationLiteral1,
+				expr: &seqExpr{
+					pos: position{line: 557, col: 31, offset: 18985},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 557, col: 31, offset: 18985},
+							expr: &litMatcher{
+								pos:        position{line: 557, col: 31, offset: 18985},
+								val:        "-",
+								ignoreCase: false,
+								want:       "\"-\"",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 557, col: 36, offset: 18990},
+							name: "DurationBody",
+						},
+						&andExpr{
+							pos: position{line: 557, col: 49, offset: 19003},
+							expr: &ruleRefExpr{
+								pos:  position{line: 557, col: 50, offset: 19004},
+								name: "AfterNumbers",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "DurationBody",
+			pos:  position{line: 561, col: 1, offset: 19052},
+			expr: &oneOrMoreExpr{
+				pos: position{line: 561, col: 17, offset: 19068},
+				expr: &seqExpr{
+					pos: position{line: 561, col: 18, offset: 19069},
+					exprs: []interface{}{
+						&oneOrMoreExpr{
+							pos: position{line: 561, col: 18, offset: 19069},
+							expr: &charClassMatcher{
+								pos:        position{line: 561, col: 18, offset: 19069},
+								val:        "[0-9]",
+								ranges:     []rune{'0', '9'},
+								ignoreCase: false,
+								inverted:   false,
+							},
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 561, col: 25, offset: 19076},
+							expr: &seqExpr{
+								pos: position{line: 561, col: 26, offset: 19077},
+								exprs: []interface{}{
+									&litMatcher{
+										pos:        position{line: 561, col: 26, offset: 19077},
+										val:        ".",
+										ignoreCase: false,
+										want:       "\".\"",
+									},
+									&oneOrMoreExpr{
+										pos: position{line: 561, col: 30, offset: 19081},
+										expr: &charClassMatcher{
+											pos:        position{line: 561, col: 30, offset: 19081},
+											val:        "[0-9]",
+											ranges:     []rune{'0', '9'},
+											ignoreCase: false,
+											inverted:   false,
+										},
+									},
+								},
+							},
+						},
+						&choiceExpr{
+							pos: position{line: 561, col: 40, offset: 19091},
+							alternatives: []interface{}{
+								&litMatcher{
+									pos:        position{line: 561, col: 40, offset: 19091},
+									val:        "ns",
+									ignoreCase: false,
+									want:       "\"ns\"",
+								},
+								&litMatcher{
+									pos:        position{line: 561, col: 47, offset: 19098},
+									val:        "us",
+									ignoreCase: false,
+									want:       "\"us\"",
+								},
+								&litMatcher{
+									pos:        position{line: 561, col: 54, offset: 19105},
+									val:        "µs",
+									ignoreCase: false,
+									want:       "\"µs\"",
+								},
+								&litMatcher{
+									pos:        position{line: 561, col: 61, offset: 19113},
+									val:        "ms",
+									ignoreCase: false,
+									want:       "\"ms\"",
+								},
+								&litMatcher{
+									pos:        position{line: 561, col: 68, offset: 19120},
+									val:        "s",
+									ignoreCase: false,
+									want:       "\"s\"",
+								},
+								&litMatcher{
+									pos:        position{line: 561, col: 74, offset: 19126},
+									val:        "m",
+									ignoreCase: false,
+									want:       "\"m\"",
+								},
+								&litMatcher{
+									pos:        position{line: 561, col: 80, offset: 19132},
+									val:        "h",
+									ignoreCase: false,
+									want:       "\"h\"",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "AfterNumbers",
+			pos:  position{line: 563, col: 1, offset: 19140},
+			expr: &andExpr{
+				pos: position{line: 563, col: 17, offset: 19156},
+				expr: &choiceExpr{
+					pos: position{line: 563, col: 19, offset: 19158},
+					alternatives: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 563, col: 19, offset: 19158},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 563, col: 23, offset: 19162},
+							name: "EOF",
+						},
+						&litMatcher{
+							pos:        position{line: 563, col: 29, offset: 19168},
+							val:        ")",
+							ignoreCase: false,
+							want:       "\")\"",
+						},
+						&litMatcher{
+							pos:        position{line: 563, col: 35, offset: 19174},
+							val:        "]",
+							ignoreCase: false,
+							want:       "\"]\"",
+						},
+						&litMatcher{
+							pos:        position{line: 563, col: 41, offset: 19180},
+							val:        ",",
+							ignoreCase: false,
+							want:       "\",\"",
+						},
+						&litMatcher{
+							pos:        position{line: 563, col: 47, offset: 19186},
+							val:        "}",
+							ignoreCase: false,
+							want:       "\"}\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "IntegerOrFloat",
+			pos:  position{line: 565, col: 1, offset: 19192},
+			expr: &seqExpr{
+				pos: position{line: 565, col: 19, offset: 19210},
+				exprs: []interface{}{
+					&choiceExpr{
+						pos: position{line: 565, col: 20, offset: 19211},
+						alternatives: []interface{}{
+							&litMatcher{
+								pos:        position{line: 565, col: 20, offset: 19211},
+								val:        "0",
+								ignoreCase: false,
+								want:       "\"0\"",
+							},
+							&seqExpr{
+								pos: position{line: 565, col: 26, offset: 19217},
+								exprs: []interface{}{
+									&charClassMatcher{
+										pos:        position{line: 565, col: 26, offset: 19217},
+										val:        "[1-9]",
+										ranges:     []rune{'1', '9'},
+										ignoreCase: false,
+										inverted:   false,
+									},
+									&zeroOrMoreExpr{
+										pos: position{line: 565, col: 31, offset: 19222},
+										expr: &charClassMatcher{
+											pos:        position{line: 565, col: 31, offset: 19222},
+											val:        "[0-9]",
+											ranges:     []rune{'0', '9'},
+											ignoreCase: false,
+											inverted:   false,
+										},
+									},
+								},
+							},
+						},
+					},
+					&zeroOrOneExpr{
+						pos: position{line: 565, col: 39, offset: 19230},
+						expr: &seqExpr{
+							pos: position{line: 565, col: 40, offset: 19231},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 565, col: 40, offset: 19231},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
+								},
+								&oneOrMoreExpr{
+									pos: position{line: 565, col: 44, offset: 19235},
+									expr: &charClassMatcher{
+										pos:        position{line: 565, col: 44, offset: 19235},
+										val:        "[0-9]",
+										ranges:     []rune{'0', '9'},
+										ignoreCase: false,
+										inverted:   false,
+									},
+								},
+							},
+						},
+					},
+					&zeroOrOneExpr{
+						pos: position{line: 565, col: 53, offset: 19244},
+						expr: &seqExpr{
+							pos: position{line: 565, col: 54, offset: 19245},
+							exprs: []interface{}{
+								&charClassMatcher{
+									pos:        position{line: 565, col: 54, offset: 19245},
+									val:        "[eE]",
+									chars:      []rune{'e', 'E'},
+									ignoreCase: false,
+									inverted:   false,
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 565, col: 59, offset: 19250},
+									expr: &charClassMatcher{
+										pos:        position{line: 565, col: 59, offset: 19250},
+										val:        "[+-]",
+										chars:      []rune{'+', '-'},
+										ignoreCase: false,
+										inverted:   false,
+									},
+								},
+								&oneOrMoreExpr{
+									pos: position{line: 565, col: 65, offset: 19256},
+									expr: &charClassMatcher{
+										pos:        position{line: 565, col: 65, offset: 19256},
+										val:        "[0-9]",
+										ranges:     []rune{'0', '9'},
+										ignoreCase: false,
+										inverted:   false,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "HexOrOctal",
+			pos:  position{line: 567, col: 1, offset: 19266},
+			expr: &choiceExpr{
+				pos: position{line: 567, col: 15, offset: 19280},
+				alternatives: []interface{}{
+					&seqExpr{
+						pos: position{line: 567, col: 15, offset: 19280},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 567, col: 15, offset: 19280},
+								val:        "0",
+								ignoreCase: false,
+								want:       "\"0\"",
+							},
+							&charClassMatcher{
+								pos:        position{line: 567, col: 19, offset: 19284},
+								val:        "[xX]",
+								chars:      []rune{'x', 'X'},
+								ignoreCase: false,
+								inverted:   false,
+							},
+							&oneOrMoreExpr{
+								pos: position{line: 567, col: 24, offset: 19289},
+								expr: &charClassMatcher{
+									pos:        position{line: 567, col: 24, offset: 19289},
+									val:        "[0-9a-fA-F]",
+									ranges:     []rune{'0', '9', 'a', 'f', 'A', 'F'},
+									ignoreCase: false,
+									inverted:   false,
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 567, col: 39, offset: 19304},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 567, col: 39, offset: 19304},
+								val:        "0",
+								ignoreCase: false,
+								want:       "\"0\"",
+							},
+							&charClassMatcher{
+								pos:        position{line: 567, col: 43, offset: 19308},
+								val:        "[oO]",
+								chars:      []rune{'o', 'O'},
+								ignoreCase: false,
+								inverted:   false,
+							},
+							&oneOrMoreExpr{
+								pos: position{line: 567, col: 48, offset: 19313},
+								expr: &charClassMatcher{
+									pos:        position{line: 567, col: 48, offset: 19313},
+									val:        "[0-7]",
+									ranges:     []rune{'0', '7'},
+									ignoreCase: false,
+									inverted:   false,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "StringLiteral",
+			displayName: "\"string\"",
+			pos:         position{line: 569, col: 1, offset: 19321},
+			expr: &choiceExpr{
+				pos: position{line: 569, col: 27, offset: 19347},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 569, col: 27, offset: 19347},
+						run: (*parser).callonStringLiteral2,
+						expr: &choiceExpr{
+							pos: position{line: 569, col: 28, offset: 19348},
+							alternatives: []interface{}{
+								&seqExpr{
+									pos: position{line: 569, col: 28, offset: 19348},
+									exprs: []interface{}{
+										&litMatcher{
+											pos:        position{line: 569, col: 28, offset: 19348},
+											val:        "`",
+											ignoreCase: false,
+											want:       "\"`\"",
+										},
+										&zeroOrMoreExpr{
+											pos: position{line: 569, col: 32, offset: 19352},
+											expr: &ruleRefExpr{
+												pos:  position{line: 569, col: 32, offset: 19352},
+												name: "RawStringChar",
+											},
+										},
+										&litMatcher{
+											pos:        position{line: 569, col: 47, offset: 19367},
+											val:        "`",
+											ignoreCase: false,
+											want:       "\"`\"",
+										},
+									},
+								},
+								&seqExpr{
+									pos: position{line: 569, col: 53, offset: 19373},
+									exprs: []interface{}{
+										&litMatcher{
+											pos:        position{line: 569, col: 53, offset: 19373},
+											val:        "\"",
+											ignoreCase: false,
+											want:       "\"\\\"\"",
+										},
+										&zeroOrMoreExpr{
+											pos: position{line: 569, col: 57, offset: 19377},
+											expr: &ruleRefExpr{
+												pos:  position{line: 569, col: 57, offset: 19377},
+												name: "DoubleStringChar",
+											},
+										},
+										&litMatcher{
+											pos:        position{line: 569, col: 75, offset: 19395},
+											val:        "\"",
+											ignoreCase: false,
+											want:       "\"\\\"\"",
+										},
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 571, col: 5, offset: 19447},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 571, col: 5, offset: 19447},
+								val:        "\"",
+								ignoreCase: false,
+								want:       "\"\\\"\"",
+							},
+							&zeroOrMoreExpr{
+								pos: position{line: 571, col: 9, offset: 19451},
+								expr: &ruleRefExpr{
+									pos:  position{line: 571, col: 9, offset: 19451},
+									name: "DoubleStringChar",
+								},
+							},
+							&litMatcher{
+								pos:        position{line: 571, col: 27, offset: 19469},
+								val:        "\\",
+								ignoreCase: false,
+								want:       "\"\\\\\"",
+							},
+							&notExpr{
+								pos: position{line: 571, col: 32, offset: 19474},
+								expr: &ruleRefExpr{
+									pos:  position{line: 571, col: 33, offset: 19475},
+									name: "EscapeSequence",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 571, col: 48, offset: 19490},
+								run: (*parser).callonStringLiteral21,
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 573, col: 5, offset: 19569},
+						exprs: []interface{}{
+							&choiceExpr{
+								pos: position{line: 573, col: 6, offset: 19570},
+								alternatives: []interface{}{
+									&seqExpr{
+										pos: position{line: 573, col: 6, offset: 19570},
+										exprs: []interface{}{
+											&litMatcher{
+												pos:        position{line: 573, col: 6, offset: 19570},
+												val:        "`",
+												ignoreCase: false,
+												want:       "\"`\"",
+											},
+											&zeroOrMoreExpr{
+												pos: position{line: 573, col: 10, offset: 19574},
+												expr: &ruleRefExpr{
+													pos:  position{line: 573, col: 10, offset: 19574},
+													name: "RawStringChar",
+												},
+											},
+										},
+									},
+									&seqExpr{
+										pos: position{line: 573, col: 27, offset: 19591},
+										exprs: []interface{}{
+											&litMatcher{
+												pos:        position{line: 573, col: 27, offset: 19591},
+												val:        "\"",
+												ignoreCase: false,
+												want:       "\"\\\"\"",
+											},
+											&zeroOrMoreExpr{
+												pos: position{line: 573, col: 31, offset: 19595},
+												expr: &ruleRefExpr{
+													pos:  position{line: 573, col: 31, offset: 19595},
+													name: "DoubleStringChar",
+												},
+											},
+										},
+									},
+								},
+							},
+							&ruleRefExpr{
+								pos:  position{line: 573, col: 50, offset: 19614},
+								name: "EOF",
+							},
+							&andCodeExpr{
+								pos: position{line: 573, col: 54, offset: 19618},
+								run: (*parser).callonStringLiteral33,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "RawStringChar",
+			pos:  position{line: 577, col: 1, offset: 19682},
+			expr: &seqExpr{
+				pos: position{line: 577, col: 18, offset: 19699},
+				exprs: []interface{}{
+					&notExpr{
+						pos: position{line: 577, col: 18, offset: 19699},
+						expr: &litMatcher{
+							pos:        position{line: 577, col: 19, offset: 19700},
+							val:        "`",
+							ignoreCase: false,
+							want:       "\"`\"",
+						},
+					},
+					&anyMatcher{
+						line: 577, col: 23, offset: 19704,
+					},
+				},
+			},
+		},
+		{
+			name: "DoubleStringChar",
+			pos:  position{line: 578, col: 1, offset: 19706},
+			expr: &choiceExpr{
+				pos: position{line: 578, col: 21, offset: 19726},
+				alternatives: []interface{}{
+					&seqExpr{
+						pos: position{line: 578, col: 21, offset: 19726},
+						exprs: []interface{}{
+							&notExpr{
+								pos: position{line: 578, col: 21, offset: 19726},
+								expr: &choiceExpr{
+									pos: position{line: 578, col: 23, offset: 19728},
+									alternatives: []interface{}{
+										&litMatcher{
+											pos:        position{line: 578, col: 23, offset: 19728},
+											val:        "\"",
+											ignoreCase: false,
+											want:       "\"\\\"\"",
+										},
+										&litMatcher{
+											pos:        position{line: 578, col: 29, offset: 19734},
+											val:        "\\",
+											ignoreCase: false,
+											want:       "\"\\\\\"",
+										},
+									},
+								},
+							},
+							&anyMatcher{
+								line: 578, col: 35, offset: 19740,
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 578, col: 39, offset: 19744},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 578, col: 39, offset: 19744},
+								val:        "\\",
+								ignoreCase: false,
+								want:       "\"\\\\\"",
+							},
+							&ruleRefExpr{
+								pos:  position{line: 578, col: 44, offset: 19749},
+								name: "EscapeSequence",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "EscapeSequence",
+			pos:  position{line: 581, col: 1, offset: 19835},
+			expr: &choiceExpr{
+				pos: position{line: 581, col: 19, offset: 19853},
+				alternatives: []interface{}{
+					&charClassMatcher{
+						pos:        position{line: 581, col: 19, offset: 19853},
+						val:        "[\"\\\\abfnrtv]",
+						chars:      []rune{'"', '\\', 'a', 'b', 'f', 'n', 'r', 't', 'v'},
+						ignoreCase: false,
+						inverted:   false,
+					},
+					&seqExpr{
+						pos: position{line: 581, col: 34, offset: 19868},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 581, col: 34, offset: 19868},
+								val:        "u",
+								ignoreCase: false,
+								want:       "\"u\"",
+							},
+							&ruleRefExpr{
+								pos:  position{line: 581, col: 38, offset: 19872},
+								name: "HexDigit",
+							},
+							&ruleRefExpr{
+								pos:  position{line: 581, col: 47, offset: 19881},
+								name: "HexDigit",
+							},
+							&ruleRefExpr{
+								pos:  position{line: 581, col: 56, offset: 19890},
+								name: "HexDigit",
+							},
+							&ruleRefExpr{
+								pos:  position{line: 581, col: 65, offset: 19899},
+								name: "HexDigit",
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 581, col: 76, offset: 19910},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 581, col: 76, offset: 19910},
+								val:        "x",
+								ignoreCase: false,
+								want:       "\"x\"",
+							},
+							&ruleRefExpr{
+								pos:  position{line: 581, col: 80, offset: 19914},
+								name: "HexDigit",
+							},
+							&ruleRefExpr{
+								pos:  position{line: 581, col: 89, offset: 19923},
+								name: "HexDigit",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "HexDigit",
+			pos:  position{line: 582, col: 1, offset: 19932},
+			expr: &charClassMatcher{
+				pos:        position{line: 582, col: 13, offset: 19944},
+				val:        "[0-9a-fA-F]",
+				ranges:     []rune{'0', '9', 'a', 'f', 'A', 'F'},
+				ignoreCase: false,
+				inverted:   false,
+			},
+		},
+		{
+			name:        "_",
+			displayName: "\"whitespace\"",
+			pos:         position{line: 586, col: 1, offset: 20042},
+			expr: &oneOrMoreExpr{
+				pos: position{line: 586, col: 19, offset: 20060},
+				expr: &choiceExpr{
+					pos: position{line: 586, col: 20, offset: 20061},
+					alternatives: []interface{}{
+						&oneOrMoreExpr{
+							pos: position{line: 586, col: 20, offset: 20061},
+							expr: &charClassMatcher{
+								pos:        position{line: 586, col: 20, offset: 20061},
+								val:        "[ \\t\\r\\n]",
+								chars:      []rune{' ', '\t', '\r', '\n'},
+								ignoreCase: false,
+								inverted:   false,
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 586, col: 33, offset: 20074},
+							name: "Comment",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "Comment",
+			displayName: "\"comment\"",
+			pos:         position{line: 588, col: 1, offset: 20085},
+			expr: &choiceExpr{
+				pos: position{line: 588, col: 22, offset: 20106},
+				alternatives: []interface{}{
+					&seqExpr{
+						pos: position{line: 588, col: 22, offset: 20106},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 588, col: 22, offset: 20106},
+								val:        "#",
+								ignoreCase: false,
+								want:       "\"#\"",
+							},
+							&zeroOrMoreExpr{
+								pos: position{line: 588, col: 26, offset: 20110},
+								expr: &charClassMatcher{
+									pos:        position{line: 588, col: 26, offset: 20110},
+									val:        "[^\\n]",
+									chars:      []rune{'\n'},
+									ignoreCase: false,
+									inverted:   true,
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 588, col: 35, offset: 20119},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 588, col: 35, offset: 20119},
+								val:        "/*",
+								ignoreCase: false,
+								want:       "\"/*\"",
+							},
+							&zeroOrMoreExpr{
+								pos: position{line: 588, col: 40, offset: 20124},
+								expr: &seqExpr{
+									pos: position{line: 588, col: 41, offset: 20125},
+									exprs: []interface{}{
+										&notExpr{
+											pos: position{line: 588, col: 41, offset: 20125},
+											expr: &litMatcher{
+												pos:        position{line: 588, col: 42, offset: 20126},
+												val:        "*/",
+												ignoreCase: false,
+												want:       "\"*/\"",
+											},
+										},
+										&anyMatcher{
+											line: 588, col: 47, offset: 20131,
+										},
+									},
+								},
+							},
+							&litMatcher{
+								pos:        position{line: 588, col: 51, offset: 20135},
+								val:        "*/",
+								ignoreCase: false,
+								want:       "\"*/\"",
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 588, col: 58, offset: 20142},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 588, col: 58, offset: 20142},
+								val:        "/*",
+								ignoreCase: false,
+								want:       "\"/*\"",
+							},
+							&zeroOrMoreExpr{
+								pos: position{line: 588, col: 63, offset: 20147},
+								expr: &seqExpr{
+									pos: position{line: 588, col: 64, offset: 20148},
+									exprs: []interface{}{
+										&notExpr{
+											pos: position{line: 588, col: 64, offset: 20148},
+											expr: &litMatcher{
+												pos:        position{line: 588, col: 65, offset: 20149},
+												val:        "*/",
+												ignoreCase: false,
+												want:       "\"*/\"",
+											},
+										},
+										&anyMatcher{
+											line: 588, col: 70, offset: 20154,
+										},
+									},
+								},
+							},
+							&ruleRefExpr{
+								pos:  position{line: 588, col: 74, offset: 20158},
+								name: "EOF",
+							},
+							&andCodeExpr{
+								pos: position{line: 588, col: 78, offset: 20162},
+								run: (*parser).callonComment22,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "EOF",
+			pos:  position{line: 592, col: 1, offset: 20219},
+			expr: &notExpr{
+				pos: position{line: 592, col: 8, offset: 20226},
+				expr: &anyMatcher{
+					line: 592, col: 9, offset: 20227,
+				},
+			},
+		},
+	},
+}
+
+func (c *current) onInput2(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonInput2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onInput2(stack["expr"])
+}
+
+func (c *current) onInput17(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonInput17() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onInput17(stack["expr"])
+}
+
+func (c *current) onInput28() (bool, error) {
+	return c.globalStore[errorRecoveryKey] == true, nil
+}
+
+func (p *parser) callonInput28() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onInput28()
+}
+
+func (c *current) onInput26(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonInput26() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onInput26(stack["expr"])
+}
+
+func (c *current) onCommaExpression8() (bool, error) {
+	return c.globalStore[commaAndKey] == true, nil
+}
+
+func (p *parser) callonCommaExpression8() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onCommaExpression8()
+}
+
+func (c *current) onCommaExpression1(first, rest interface{}) (interface{}, error) {
+	if len(rest.([]interface{})) == 0 {
+		return first, nil
+	}
+
+	exprs := []Expression{first.(Expression)}
+	for _, v := range rest.([]interface{}) {
+		exprs = append(exprs, v.([]interface{})[4].(Expression))
+	}
+
+	// nest to the right in the same way as and
+	expr := exprs[len(exprs)-1]
+	for i := len(exprs) - 2; i >= 0; i-- {
+		expr = &BinaryExpression{
+			Operator: BinaryOpAnd,
+			Left:     exprs[i],
+			Right:    expr,
+		}
+	}
+	return expr, nil
+}
+
+func (p *parser) callonCommaExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onCommaExpression1(stack["first"], stack["rest"])
+}
+
+func (c *current) onTrailingText1() (interface{}, error) {
+	return nil, errors.New("Unexpected text after expression")
+}
+
+func (p *parser) callonTrailingText1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onTrailingText1()
+}
+
+func (c *current) onSelectorInput1(selector interface{}) (interface{}, error) {
+	return selector, nil
+}
+
+func (p *parser) callonSelectorInput1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onSelectorInput1(stack["selector"])
+}
+
+func (c *current) onOrExpression2(left, right interface{}) (interface{}, error) {
+	return &BinaryExpression{
+		Operator: BinaryOpOr,
+		Left:     left.(Expression),
+		Right:    right.(Expression),
+	}, nil
+}
+
+func (p *parser) callonOrExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onOrExpression2(stack["left"], stack["right"])
+}
+
+func (c *current) onOrExpression11(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonOrExpression11() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onOrExpression11(stack["expr"])
+}
+
+func (c *current) onXorExpression1(left, right interface{}) (interface{}, error) {
+	// the optional tail avoids parsing the left hand side twice when there is no xor
+	if right == nil {
+		return left, nil
+	}
+	return &BinaryExpression{
+		Operator: BinaryOpXor,
+		Left:     left.(Expression),
+		Right:    right.([]interface{})[3].(Expression),
+	}, nil
+}
+
+func (p *parser) callonXorExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onXorExpression1(stack["left"], stack["right"])
+}
+
+func (c *current) onAndExpression2(left, right interface{}) (interface{}, error) {
+	return &BinaryExpression{
+		Operator: BinaryOpAnd,
+		Left:     left.(Expression),
+		Right:    right.(Expression),
+	}, nil
+}
+
+func (p *parser) callonAndExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onAndExpression2(stack["left"], stack["right"])
+}
+
+func (c *current) onAndExpression11(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonAndExpression11() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onAndExpression11(stack["expr"])
+}
+
+func (c *current) onNotExpression2(expr interface{}) (interface{}, error) {
+	if unary, ok := expr.(*UnaryExpression); ok && unary.Operator == UnaryOpNot {
+		// small optimization to get rid unnecessary levels of AST nodes
+		// for things like:  not not foo == 3  which is equivalent to foo == 3
+		return unary.Operand, nil
+	}
+
+	if constant, ok := expr.(*ConstantExpression); ok {
+		return &ConstantExpression{Value: !constant.Value}, nil
+	}
+
+	return &UnaryExpression{
+		Operator: UnaryOpNot,
+		Operand:  expr.(Expression),
+	}, nil
+}
+
+func (p *parser) callonNotExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNotExpression2(stack["expr"])
+}
+
+func (c *current) onNotExpression8(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonNotExpression8() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNotExpression8(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression2(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression2(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression12(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression12() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression12(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression15(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression15() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression15(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression18(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression18() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression18(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression21(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression21() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression21(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression24(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression24() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression24(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression27(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression27() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression27(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression39() (bool, error) {
+	return false, errors.New("Unmatched parentheses")
+}
+
+func (p *parser) callonParenthesizedExpression39() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression39()
+}
+
+func (c *current) onParenthesizedExpression40(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression40() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression40(stack["expr"])
+}
+
+func (c *current) onRecoveredClause3() (bool, error) {
+	return c.globalStore[errorRecoveryKey] == true, nil
+}
+
+func (p *parser) callonRecoveredClause3() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onRecoveredClause3()
+}
+
+func (c *current) onRecoveredClause1() (interface{}, error) {
+	return &ConstantExpression{Value: false}, fmt.Errorf("Invalid expression %q", strings.TrimSpace(string(c.text)))
+}
+
+func (p *parser) callonRecoveredClause1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onRecoveredClause1()
+}
+
+func (c *current) onClauseEnd16() (bool, error) {
+	return c.globalStore[commaAndKey] == true, nil
+}
+
+func (p *parser) callonClauseEnd16() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onClauseEnd16()
+}
+
+func (c *current) onQuantifierExpression2(quantifier, selector, variable, expr interface{}) (interface{}, error) {
+	q := QuantifierAny
+	if string(quantifier.([]byte)) == "all" {
+		q = QuantifierAll
+	}
+	return &QuantifierExpression{Quantifier: q, Selector: selector.(Selector), Variable: variable.(string), Expression: expr.(Expression)}, nil
+}
+
+func (p *parser) callonQuantifierExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onQuantifierExpression2(stack["quantifier"], stack["selector"], stack["variable"], stack["expr"])
+}
+
+func (c *current) onQuantifierExpression58() (bool, error) {
+	return false, errors.New("Unclosed quantifier")
+}
+
+func (p *parser) callonQuantifierExpression58() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onQuantifierExpression58()
+}
+
+func (c *current) onFunctionExpression1(name, args interface{}) (interface{}, error) {
+	expr := &FunctionExpression{Name: name.(string)}
+	if args != nil {
+		expr.Args = args.([]*FunctionArgument)
+	}
+	return expr, nil
+}
+
+func (p *parser) callonFunctionExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionExpression1(stack["name"], stack["args"])
+}
+
+func (c *current) onFunctionArguments1(first, rest interface{}) (interface{}, error) {
+	args := []*FunctionArgument{first.(*FunctionArgument)}
+	for _, v := range rest.([]interface{}) {
+		args = append(args, v.([]interface{})[3].(*FunctionArgument))
+	}
+	return args, nil
+}
+
+func (p *parser) callonFunctionArguments1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionArguments1(stack["first"], stack["rest"])
+}
+
+func (c *current) onFunctionArgument2(selector interface{}) (interface{}, error) {
+	return &FunctionArgument{Selector: selector.(Selector)}, nil
+}
+
+func (p *parser) callonFunctionArgument2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionArgument2(stack["selector"])
+}
+
+func (c *current) onFunctionArgument5(value interface{}) (interface{}, error) {
+	return &FunctionArgument{Value: value.(*MatchValue)}, nil
+}
+
+func (p *parser) callonFunctionArgument5() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionArgument5(stack["value"])
+}
+
+func (c *current) onComparisonExpression19(left, operator, right interface{}) (bool, error) {
+	// comparisons without any arithmetic are left to the match rules
+	return left.(*Operand).Arithmetic != nil || right.(*Operand).Arithmetic != nil, nil
+}
+
+func (p *parser) callonComparisonExpression19() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonExpression19(stack["left"], stack["operator"], stack["right"])
+}
+
+func (c *current) onComparisonExpression1(left, operator, right interface{}) (interface{}, error) {
+	return &ComparisonExpression{Left: left.(*Operand), Operator: operator.(MatchOperator), Right: right.(*Operand)}, nil
+}
+
+func (p *parser) callonComparisonExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonExpression1(stack["left"], stack["operator"], stack["right"])
+}
+
+func (c *current) onArithmeticSum1(first, rest interface{}) (interface{}, error) {
+	return foldArithmetic(first, rest), nil
+}
+
+func (p *parser) callonArithmeticSum1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticSum1(stack["first"], stack["rest"])
+}
+
+func (c *current) onArithmeticProduct1(first, rest interface{}) (interface{}, error) {
+	return foldArithmetic(first, rest), nil
+}
+
+func (p *parser) callonArithmeticProduct1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticProduct1(stack["first"], stack["rest"])
+}
+
+func (c *current) onArithmeticSumOp2() (interface{}, error) {
+	return ArithmeticAdd, nil
+}
+
+func (p *parser) callonArithmeticSumOp2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticSumOp2()
+}
+
+func (c *current) onArithmeticSumOp4() (interface{}, error) {
+	return ArithmeticSubtract, nil
+}
+
+func (p *parser) callonArithmeticSumOp4() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticSumOp4()
+}
+
+func (c *current) onArithmeticProductOp2() (interface{}, error) {
+	return ArithmeticMultiply, nil
+}
+
+func (p *parser) callonArithmeticProductOp2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticProductOp2()
+}
+
+func (c *current) onArithmeticProductOp4() (interface{}, error) {
+	return ArithmeticDivide, nil
+}
+
+func (p *parser) callonArithmeticProductOp4() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticProductOp4()
+}
+
+func (c *current) onArithmeticProductOp9() (interface{}, error) {
+	return ArithmeticModulo, nil
+}
+
+func (p *parser) callonArithmeticProductOp9() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticProductOp9()
+}
+
+func (c *current) onArithmeticOperand2(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonArithmeticOperand2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticOperand2(stack["expr"])
+}
+
+func (c *current) onArithmeticOperand12(value interface{}) (interface{}, error) {
+	return &Operand{Value: &MatchValue{Raw: value.(string)}}, nil
+}
+
+func (p *parser) callonArithmeticOperand12() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticOperand12(stack["value"])
+}
+
+func (c *current) onArithmeticOperand20(selector interface{}) (interface{}, error) {
+	// relative times such as now-1h are values rather than arithmetic
+	return &Operand{Selector: selector.(Selector)}, nil
+}
+
+func (p *parser) callonArithmeticOperand20() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onArithmeticOperand20(stack["selector"])
+}
+
+func (c *current) onConstantExpression1(value interface{}) (interface{}, error) {
+	return &ConstantExpression{Value: string(value.([]byte)) == "true"}, nil
+}
+
+func (p *parser) callonConstantExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onConstantExpression1(stack["value"])
+}
+
+func (c *current) onScopedExpression2(selector, expr interface{}) (interface{}, error) {
+	return scopeExpression(expr.(Expression), selector.(Selector)), nil
+}
+
+func (p *parser) callonScopedExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onScopedExpression2(stack["selector"], stack["expr"])
+}
+
+func (c *current) onScopedExpression28() (bool, error) {
+	return false, errors.New("Unclosed scope block")
+}
+
+func (p *parser) callonScopedExpression28() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onScopedExpression28()
+}
+
+func (c *current) onMatchBareSelector1(selector interface{}) (interface{}, error) {
+	// a bare selector is shorthand for: selector == true
+	return &MatchExpression{Selector: selector.(Selector), Operator: MatchEqual, Value: &MatchValue{Raw: "true", Converted: true}}, nil
+}
+
+func (p *parser) callonMatchBareSelector1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchBareSelector1(stack["selector"])
+}
+
+func (c *current) onMatchChainedComparison2(low, lowOp, selector, highOp, high interface{}) (interface{}, error) {
+	// low < selector < high is sugar for: selector > low and selector < high
+	return &BinaryExpression{
+		Operator: BinaryOpAnd,
+		Left:     &MatchExpression{Selector: selector.(Selector), Operator: lowOp.(MatchOperator).reverse(), Value: low.(*MatchValue)},
+		Right:    &MatchExpression{Selector: selector.(Selector), Operator: highOp.(MatchOperator), Value: high.(*MatchValue)},
+	}, nil
+}
+
+func (p *parser) callonMatchChainedComparison2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchChainedComparison2(stack["low"], stack["lowOp"], stack["selector"], stack["highOp"], stack["high"])
+}
+
+func (c *current) onMatchChainedComparison18(high, highOp, selector, lowOp, low interface{}) (interface{}, error) {
+	// high > selector > low is sugar for: selector < high and selector > low
+	return &BinaryExpression{
+		Operator: BinaryOpAnd,
+		Left:     &MatchExpression{Selector: selector.(Selector), Operator: highOp.(MatchOperator).reverse(), Value: high.(*MatchValue)},
+		Right:    &MatchExpression{Selector: selector.(Selector), Operator: lowOp.(MatchOperator), Value: low.(*MatchValue)},
+	}, nil
+}
+
+func (p *parser) callonMatchChainedComparison18() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchChainedComparison18(stack["high"], stack["highOp"], stack["selector"], stack["lowOp"], stack["low"])
+}
+
+func (c *current) onMatchChainedComparison44() (bool, error) {
+	return false, errors.New("Chained comparisons must use operators of the same direction")
+}
+
+func (p *parser) callonMatchChainedComparison44() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchChainedComparison44()
+}
+
+func (c *current) onMatchChainedComparison55() (bool, error) {
+	return false, errors.New("Chained comparisons must use operators of the same direction")
+}
+
+func (p *parser) callonMatchChainedComparison55() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchChainedComparison55()
+}
+
+func (c *current) onMatchSelectorInSet1(selector, operator, set interface{}) (interface{}, error) {
+	op := MatchInSet
+	if operator.(MatchOperator) == MatchNotIn {
+		op = MatchNotInSet
+	}
+	expr := &MatchExpression{Selector: selector.(Selector), Operator: op}
+	switch set := set.(type) {
+	case string:
+		expr.Value = &MatchValue{Raw: set}
+	case []*MatchValue:
+		expr.Values = set
+	}
+	return expr, nil
+}
+
+func (p *parser) callonMatchSelectorInSet1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorInSet1(stack["selector"], stack["operator"], stack["set"])
+}
+
+func (c *current) onMatchSelectorContainsList1(selector, operator, list interface{}) (interface{}, error) {
+	return &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Values: list.([]*MatchValue)}, nil
+}
+
+func (p *parser) callonMatchSelectorContainsList1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorContainsList1(stack["selector"], stack["operator"], stack["list"])
+}
+
+func (c *current) onMatchSelectorBetween1(selector, operator, low, high interface{}) (interface{}, error) {
+	return &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Values: []*MatchValue{low.(*MatchValue), high.(*MatchValue)}}, nil
+}
+
+func (p *parser) callonMatchSelectorBetween1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorBetween1(stack["selector"], stack["operator"], stack["low"], stack["high"])
+}
+
+func (c *current) onMatchSelectorOpValue1(selector, operator, value interface{}) (interface{}, error) {
+	expr := &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Value: value.(*MatchValue)}
+	if err := expr.ConvertValue(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+func (p *parser) callonMatchSelectorOpValue1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorOpValue1(stack["selector"], stack["operator"], stack["value"])
+}
+
+func (c *current) onMatchLengthOpValue1(selector, operator, value interface{}) (interface{}, error) {
+	return &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Value: value.(*MatchValue), Length: true}, nil
+}
+
+func (p *parser) callonMatchLengthOpValue1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchLengthOpValue1(stack["selector"], stack["operator"], stack["value"])
+}
+
+func (c *current) onMatchSelectorOp1(selector, operator interface{}) (interface{}, error) {
+	return &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Value: nil}, nil
+}
+
+func (p *parser) callonMatchSelectorOp1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorOp1(stack["selector"], stack["operator"])
+}
+
+func (c *current) onMatchSelectorCustomOp3() (bool, error) {
+	return hasCustomOperators(), nil
+}
+
+func (p *parser) callonMatchSelectorCustomOp3() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorCustomOp3()
+}
+
+func (c *current) onMatchSelectorCustomOp9(selector, token interface{}) (bool, error) {
+	_, ok := lookupCustomOperator(token.(string))
+	return ok, nil
+}
+
+func (p *parser) callonMatchSelectorCustomOp9() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorCustomOp9(stack["selector"], stack["token"])
+}
+
+func (c *current) onMatchSelectorCustomOp1(selector, token, value interface{}) (interface{}, error) {
+	op, _ := lookupCustomOperator(token.(string))
+	expr := &MatchExpression{Selector: selector.(Selector), Operator: op, Value: value.(*MatchValue)}
+	if err := expr.ConvertValue(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+func (p *parser) callonMatchSelectorCustomOp1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorCustomOp1(stack["selector"], stack["token"], stack["value"])
+}
+
+func (c *current) onMatchValueOpSelector2(value, operator, selector interface{}) (interface{}, error) {
+	return &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Value: value.(*MatchValue)}, nil
+}
+
+func (p *parser) callonMatchValueOpSelector2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchValueOpSelector2(stack["value"], stack["operator"], stack["selector"])
+}
+
+func (c *current) onMatchValueOpSelector22(operator interface{}) (bool, error) {
+	return false, errors.New("Invalid selector")
+}
+
+func (p *parser) callonMatchValueOpSelector22() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchValueOpSelector22(stack["operator"])
+}
+
+func (c *current) onMatchEqualFold2() (interface{}, error) {
+	return MatchEqualFold, nil
+}
+
+func (p *parser) callonMatchEqualFold2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchEqualFold2()
+}
+
+func (c *current) onMatchEqualFold8() (interface{}, error) {
+	return MatchEqualFold, nil
+}
+
+func (p *parser) callonMatchEqualFold8() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchEqualFold8()
+}
+
+func (c *current) onMatchNotEqualFold2() (interface{}, error) {
+	return MatchNotEqualFold, nil
+}
+
+func (p *parser) callonMatchNotEqualFold2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchNotEqualFold2()
+}
+
+func (c *current) onMatchNotEqualFold8() (interface{}, error) {
+	return MatchNotEqualFold, nil
+}
+
+func (p *parser) callonMatchNotEqualFold8() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchNotEqualFold8()
+}
+
+func (c *current) onMatchEqual2() (interface{}, error) {
+	return MatchEqual, nil
+}
+
+func (p *parser) callonMatchEqual2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchEqual2()
+}
+
+func (c *current) onMatchEqual9() (interface{}, error) {
+	// keyword forms need no escaping within URL query parameters
+	return MatchEqual, nil
+}
+
+func (p *parser) callonMatchEqual9() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchEqual9()
+}
+
+func (c *current) onMatchNotEqual2() (interface{}, error) {
+	return MatchNotEqual, nil
+}
+
+func (p *parser) callonMatchNotEqual2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchNotEqual2()
 }
 
-func (c *current) onInput2(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchNotEqual9() (interface{}, error) {
+	return MatchNotEqual, nil
 }
 
-func (p *parser) callonInput2() (interface{}, error) {
+func (p *parser) callonMatchNotEqual9() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onInput2(stack["expr"])
+	return p.cur.onMatchNotEqual9()
 }
 
-func (c *current) onInput17(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchLike1() (interface{}, error) {
+	return MatchLike, nil
 }
 
-func (p *parser) callonInput17() (interface{}, error) {
+func (p *parser) callonMatchLike1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onInput17(stack["expr"])
+	return p.cur.onMatchLike1()
 }
 
-func (c *current) onOrExpression2(left, right interface{}) (interface{}, error) {
-	return &BinaryExpression{
-		Operator: BinaryOpOr,
-		Left:     left.(Expression),
-		Right:    right.(Expression),
-	}, nil
+func (c *current) onMatchNotLike1() (interface{}, error) {
+	return MatchNotLike, nil
 }
 
-func (p *parser) callonOrExpression2() (interface{}, error) {
+func (p *parser) callonMatchNotLike1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onOrExpression2(stack["left"], stack["right"])
+	return p.cur.onMatchNotLike1()
 }
 
-func (c *current) onOrExpression11(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchPrefix1() (interface{}, error) {
+	return MatchPrefix, nil
 }
 
-func (p *parser) callonOrExpression11() (interface{}, error) {
+func (p *parser) callonMatchPrefix1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onOrExpression11(stack["expr"])
+	return p.cur.onMatchPrefix1()
 }
 
-func (c *current) onAndExpression2(left, right interface{}) (interface{}, error) {
-	return &BinaryExpression{
-		Operator: BinaryOpAnd,
-		Left:     left.(Expression),
-		Right:    right.(Expression),
-	}, nil
+func (c *current) onMatchNotPrefix1() (interface{}, error) {
+	return MatchNotPrefix, nil
 }
 
-func (p *parser) callonAndExpression2() (interface{}, error) {
+func (p *parser) callonMatchNotPrefix1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onAndExpression2(stack["left"], stack["right"])
+	return p.cur.onMatchNotPrefix1()
 }
 
-func (c *current) onAndExpression11(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchSuffix1() (interface{}, error) {
+	return MatchSuffix, nil
 }
 
-func (p *parser) callonAndExpression11() (interface{}, error) {
+func (p *parser) callonMatchSuffix1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onAndExpression11(stack["expr"])
+	return p.cur.onMatchSuffix1()
 }
 
-func (c *current) onNotExpression2(expr interface{}) (interface{}, error) {
-	if unary, ok := expr.(*UnaryExpression); ok && unary.Operator == UnaryOpNot {
-		// small optimization to get rid unnecessary levels of AST nodes
-		// for things like:  not not foo == 3  which is equivalent to foo == 3
-		return unary.Operand, nil
-	}
-
-	return &UnaryExpression{
-		Operator: UnaryOpNot,
-		Operand:  expr.(Expression),
-	}, nil
+func (c *current) onMatchNotSuffix1() (interface{}, error) {
+	return MatchNotSuffix, nil
 }
 
-func (p *parser) callonNotExpression2() (interface{}, error) {
+func (p *parser) callonMatchNotSuffix1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onNotExpression2(stack["expr"])
+	return p.cur.onMatchNotSuffix1()
 }
 
-func (c *current) onNotExpression8(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchBetween1() (interface{}, error) {
+	return MatchBetween, nil
 }
 
-func (p *parser) callonNotExpression8() (interface{}, error) {
+func (p *parser) callonMatchBetween1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onNotExpression8(stack["expr"])
+	return p.cur.onMatchBetween1()
 }
 
-func (c *current) onParenthesizedExpression2(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchNotBetween1() (interface{}, error) {
+	return MatchNotBetween, nil
 }
 
-func (p *parser) callonParenthesizedExpression2() (interface{}, error) {
+func (p *parser) callonMatchNotBetween1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onParenthesizedExpression2(stack["expr"])
+	return p.cur.onMatchNotBetween1()
 }
 
-func (c *current) onParenthesizedExpression12(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchLessThan2() (interface{}, error) {
+	return MatchLessThan, nil
 }
 
-func (p *parser) callonParenthesizedExpression12() (interface{}, error) {
+func (p *parser) callonMatchLessThan2() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onParenthesizedExpression12(stack["expr"])
+	return p.cur.onMatchLessThan2()
 }
 
-func (c *current) onParenthesizedExpression24() (bool, error) {
-	return false, errors.New("Unmatched parentheses")
+func (c *current) onMatchLessThan9() (interface{}, error) {
+	return MatchLessThan, nil
 }
 
-func (p *parser) callonParenthesizedExpression24() (bool, error) {
+func (p *parser) callonMatchLessThan9() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onParenthesizedExpression24()
+	return p.cur.onMatchLessThan9()
 }
 
-func (c *current) onMatchSelectorOpValue1(selector, operator, value interface{}) (interface{}, error) {
-	return &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Value: value.(*MatchValue)}, nil
+func (c *current) onMatchLessThanOrEqual2() (interface{}, error) {
+	return MatchLessThanOrEqual, nil
 }
 
-func (p *parser) callonMatchSelectorOpValue1() (interface{}, error) {
+func (p *parser) callonMatchLessThanOrEqual2() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchSelectorOpValue1(stack["selector"], stack["operator"], stack["value"])
+	return p.cur.onMatchLessThanOrEqual2()
 }
 
-func (c *current) onMatchSelectorOp1(selector, operator interface{}) (interface{}, error) {
-	return &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Value: nil}, nil
+func (c *current) onMatchLessThanOrEqual9() (interface{}, error) {
+	return MatchLessThanOrEqual, nil
 }
 
-func (p *parser) callonMatchSelectorOp1() (interface{}, error) {
+func (p *parser) callonMatchLessThanOrEqual9() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchSelectorOp1(stack["selector"], stack["operator"])
+	return p.cur.onMatchLessThanOrEqual9()
 }
 
-func (c *current) onMatchValueOpSelector2(value, operator, selector interface{}) (interface{}, error) {
-	return &MatchExpression{Selector: selector.(Selector), Operator: operator.(MatchOperator), Value: value.(*MatchValue)}, nil
+func (c *current) onMatchGreaterThan2() (interface{}, error) {
+	return MatchGreaterThan, nil
 }
 
-func (p *parser) callonMatchValueOpSelector2() (interface{}, error) {
+func (p *parser) callonMatchGreaterThan2() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchValueOpSelector2(stack["value"], stack["operator"], stack["selector"])
+	return p.cur.onMatchGreaterThan2()
 }
 
-func (c *current) onMatchValueOpSelector20(operator interface{}) (bool, error) {
-	return false, errors.New("Invalid selector")
+func (c *current) onMatchGreaterThan9() (interface{}, error) {
+	return MatchGreaterThan, nil
 }
 
-func (p *parser) callonMatchValueOpSelector20() (bool, error) {
+func (p *parser) callonMatchGreaterThan9() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchValueOpSelector20(stack["operator"])
+	return p.cur.onMatchGreaterThan9()
 }
 
-func (c *current) onMatchEqual1() (interface{}, error) {
-	return MatchEqual, nil
+func (c *current) onMatchGreaterThanOrEqual2() (interface{}, error) {
+	return MatchGreaterThanOrEqual, nil
 }
 
-func (p *parser) callonMatchEqual1() (interface{}, error) {
+func (p *parser) callonMatchGreaterThanOrEqual2() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchEqual1()
+	return p.cur.onMatchGreaterThanOrEqual2()
 }
 
-func (c *current) onMatchNotEqual1() (interface{}, error) {
-	return MatchNotEqual, nil
+func (c *current) onMatchGreaterThanOrEqual9() (interface{}, error) {
+	return MatchGreaterThanOrEqual, nil
 }
 
-func (p *parser) callonMatchNotEqual1() (interface{}, error) {
+func (p *parser) callonMatchGreaterThanOrEqual9() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchNotEqual1()
+	return p.cur.onMatchGreaterThanOrEqual9()
 }
 
 func (c *current) onMatchIsEmpty1() (interface{}, error) {
@@ -1884,6 +7435,46 @@ func (p *parser) callonMatchIsNotEmpty1() (interface{}, error) {
 	return p.cur.onMatchIsNotEmpty1()
 }
 
+func (c *current) onMatchIsNull1() (interface{}, error) {
+	return MatchIsNull, nil
+}
+
+func (p *parser) callonMatchIsNull1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchIsNull1()
+}
+
+func (c *current) onMatchIsNotNull1() (interface{}, error) {
+	return MatchIsNotNull, nil
+}
+
+func (p *parser) callonMatchIsNotNull1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchIsNotNull1()
+}
+
+func (c *current) onMatchInCIDR1() (interface{}, error) {
+	return MatchInCIDR, nil
+}
+
+func (p *parser) callonMatchInCIDR1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchInCIDR1()
+}
+
+func (c *current) onMatchNotInCIDR1() (interface{}, error) {
+	return MatchNotInCIDR, nil
+}
+
+func (p *parser) callonMatchNotInCIDR1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchNotInCIDR1()
+}
+
 func (c *current) onMatchIn1() (interface{}, error) {
 	return MatchIn, nil
 }
@@ -1904,6 +7495,46 @@ func (p *parser) callonMatchNotIn1() (interface{}, error) {
 	return p.cur.onMatchNotIn1()
 }
 
+func (c *current) onMatchContainsAny1() (interface{}, error) {
+	return MatchContainsAny, nil
+}
+
+func (p *parser) callonMatchContainsAny1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchContainsAny1()
+}
+
+func (c *current) onMatchNotContainsAny1() (interface{}, error) {
+	return MatchNotContainsAny, nil
+}
+
+func (p *parser) callonMatchNotContainsAny1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchNotContainsAny1()
+}
+
+func (c *current) onMatchContainsAll1() (interface{}, error) {
+	return MatchContainsAll, nil
+}
+
+func (p *parser) callonMatchContainsAll1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchContainsAll1()
+}
+
+func (c *current) onMatchNotContainsAll1() (interface{}, error) {
+	return MatchNotContainsAll, nil
+}
+
+func (p *parser) callonMatchNotContainsAll1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchNotContainsAll1()
+}
+
 func (c *current) onMatchContains1() (interface{}, error) {
 	return MatchIn, nil
 }
@@ -1924,24 +7555,44 @@ func (p *parser) callonMatchNotContains1() (interface{}, error) {
 	return p.cur.onMatchNotContains1()
 }
 
-func (c *current) onMatchMatches1() (interface{}, error) {
+func (c *current) onMatchMatches2() (interface{}, error) {
+	return MatchMatches, nil
+}
+
+func (p *parser) callonMatchMatches2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchMatches2()
+}
+
+func (c *current) onMatchMatches7() (interface{}, error) {
 	return MatchMatches, nil
 }
 
-func (p *parser) callonMatchMatches1() (interface{}, error) {
+func (p *parser) callonMatchMatches7() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchMatches7()
+}
+
+func (c *current) onMatchNotMatches2() (interface{}, error) {
+	return MatchNotMatches, nil
+}
+
+func (p *parser) callonMatchNotMatches2() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchMatches1()
+	return p.cur.onMatchNotMatches2()
 }
 
-func (c *current) onMatchNotMatches1() (interface{}, error) {
+func (c *current) onMatchNotMatches9() (interface{}, error) {
 	return MatchNotMatches, nil
 }
 
-func (p *parser) callonMatchNotMatches1() (interface{}, error) {
+func (p *parser) callonMatchNotMatches9() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchNotMatches1()
+	return p.cur.onMatchNotMatches9()
 }
 
 func (c *current) onSelector2(first, rest interface{}) (interface{}, error) {
@@ -1951,6 +7602,10 @@ func (c *current) onSelector2(first, rest interface{}) (interface{}, error) {
 	}
 	if rest != nil {
 		for _, v := range rest.([]interface{}) {
+			if _, ok := v.(wildcardSegment); ok {
+				sel.Wildcards = append(sel.Wildcards, len(sel.Path))
+				v = "*"
+			}
 			sel.Path = append(sel.Path, v.(string))
 		}
 	}
@@ -2000,6 +7655,62 @@ func (p *parser) callonJsonPointerSegment1() (interface{}, error) {
 	return p.cur.onJsonPointerSegment1(stack["ident"])
 }
 
+func (c *current) onNamedSet1(name interface{}) (interface{}, error) {
+	return name, nil
+}
+
+func (p *parser) callonNamedSet1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNamedSet1(stack["name"])
+}
+
+func (c *current) onListLiteral2(first, rest interface{}) (interface{}, error) {
+	values := []*MatchValue{first.(*MatchValue)}
+	if rest != nil {
+		for _, v := range rest.([]interface{}) {
+			values = append(values, v.(*MatchValue))
+		}
+	}
+	return values, nil
+}
+
+func (p *parser) callonListLiteral2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onListLiteral2(stack["first"], stack["rest"])
+}
+
+func (c *current) onListLiteral15() (interface{}, error) {
+	return []*MatchValue{}, nil
+}
+
+func (p *parser) callonListLiteral15() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onListLiteral15()
+}
+
+func (c *current) onListLiteral34() (bool, error) {
+	return false, errors.New("Unclosed list literal")
+}
+
+func (p *parser) callonListLiteral34() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onListLiteral34()
+}
+
+func (c *current) onListLiteralItem1(value interface{}) (interface{}, error) {
+	return value, nil
+}
+
+func (p *parser) callonListLiteralItem1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onListLiteralItem1(stack["value"])
+}
+
 func (c *current) onIdentifier1() (interface{}, error) {
 	return string(c.text), nil
 }
@@ -2020,24 +7731,45 @@ func (p *parser) callonSelectorOrIndex2() (interface{}, error) {
 	return p.cur.onSelectorOrIndex2(stack["ident"])
 }
 
-func (c *current) onSelectorOrIndex7(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onSelectorOrIndex7() (interface{}, error) {
+	return wildcardSegment{}, nil
 }
 
 func (p *parser) callonSelectorOrIndex7() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onSelectorOrIndex7(stack["expr"])
+	return p.cur.onSelectorOrIndex7()
+}
+
+func (c *current) onSelectorOrIndex11(lit interface{}) (interface{}, error) {
+	// quoted segments may contain dots and spaces such as: meta.`consul.io/version`
+	return lit, nil
+}
+
+func (p *parser) callonSelectorOrIndex11() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onSelectorOrIndex11(stack["lit"])
+}
+
+func (c *current) onSelectorOrIndex16(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonSelectorOrIndex16() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onSelectorOrIndex16(stack["expr"])
 }
 
-func (c *current) onSelectorOrIndex10(idx interface{}) (interface{}, error) {
+func (c *current) onSelectorOrIndex19(idx interface{}) (interface{}, error) {
 	return string(c.text)[1:], nil
 }
 
-func (p *parser) callonSelectorOrIndex10() (interface{}, error) {
+func (p *parser) callonSelectorOrIndex19() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onSelectorOrIndex10(stack["idx"])
+	return p.cur.onSelectorOrIndex19(stack["idx"])
 }
 
 func (c *current) onIndexExpression2(lit interface{}) (interface{}, error) {
@@ -2050,54 +7782,114 @@ func (p *parser) callonIndexExpression2() (interface{}, error) {
 	return p.cur.onIndexExpression2(stack["lit"])
 }
 
-func (c *current) onIndexExpression18() (bool, error) {
+func (c *current) onIndexExpression12(idx interface{}) (interface{}, error) {
+	return idx, nil
+}
+
+func (p *parser) callonIndexExpression12() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onIndexExpression12(stack["idx"])
+}
+
+func (c *current) onIndexExpression22() (interface{}, error) {
+	return wildcardSegment{}, nil
+}
+
+func (p *parser) callonIndexExpression22() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onIndexExpression22()
+}
+
+func (c *current) onIndexExpression41() (bool, error) {
 	return false, errors.New("Invalid index")
 }
 
-func (p *parser) callonIndexExpression18() (bool, error) {
+func (p *parser) callonIndexExpression41() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onIndexExpression18()
+	return p.cur.onIndexExpression41()
 }
 
-func (c *current) onIndexExpression28() (bool, error) {
+func (c *current) onIndexExpression54() (bool, error) {
 	return false, errors.New("Unclosed index expression")
 }
 
-func (p *parser) callonIndexExpression28() (bool, error) {
+func (p *parser) callonIndexExpression54() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onIndexExpression28()
+	return p.cur.onIndexExpression54()
 }
 
-func (c *current) onValue2(selector interface{}) (interface{}, error) {
-	return &MatchValue{Raw: selector.(Selector).String()}, nil
+func (c *current) onIndexNumber1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonIndexNumber1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onIndexNumber1()
+}
+
+func (c *current) onValue2(t interface{}) (interface{}, error) {
+	return &MatchValue{Raw: t.(string)}, nil
 }
 
 func (p *parser) callonValue2() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue2(stack["selector"])
+	return p.cur.onValue2(stack["t"])
 }
 
-func (c *current) onValue5(n interface{}) (interface{}, error) {
-	return &MatchValue{Raw: n.(string)}, nil
+func (c *current) onValue5(selector interface{}) (interface{}, error) {
+	return &MatchValue{Raw: selector.(Selector).String()}, nil
 }
 
 func (p *parser) callonValue5() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue5(stack["n"])
+	return p.cur.onValue5(stack["selector"])
 }
 
-func (c *current) onValue8(s interface{}) (interface{}, error) {
-	return &MatchValue{Raw: s.(string)}, nil
+func (c *current) onValue8(name interface{}) (interface{}, error) {
+	return &MatchValue{Raw: string(c.text), Parameter: name.(string)}, nil
 }
 
 func (p *parser) callonValue8() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue8(stack["s"])
+	return p.cur.onValue8(stack["name"])
+}
+
+func (c *current) onValue13(d interface{}) (interface{}, error) {
+	return &MatchValue{Raw: d.(string)}, nil
+}
+
+func (p *parser) callonValue13() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onValue13(stack["d"])
+}
+
+func (c *current) onValue16(n interface{}) (interface{}, error) {
+	return &MatchValue{Raw: n.(string)}, nil
+}
+
+func (p *parser) callonValue16() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onValue16(stack["n"])
+}
+
+func (c *current) onValue19(s interface{}) (interface{}, error) {
+	return &MatchValue{Raw: s.(string), Quoted: true}, nil
+}
+
+func (p *parser) callonValue19() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onValue19(stack["s"])
 }
 
 func (c *current) onNumberLiteral2() (interface{}, error) {
@@ -2110,14 +7902,34 @@ func (p *parser) callonNumberLiteral2() (interface{}, error) {
 	return p.cur.onNumberLiteral2()
 }
 
-func (c *current) onNumberLiteral15() (bool, error) {
+func (c *current) onNumberLiteral19() (bool, error) {
 	return false, errors.New("Invalid number literal")
 }
 
-func (p *parser) callonNumberLiteral15() (bool, error) {
+func (p *parser) callonNumberLiteral19() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNumberLiteral19()
+}
+
+func (c *current) onTimeLiteral1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonTimeLiteral1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onTimeLiteral1()
+}
+
+func (c *current) onDurationLiteral1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonDurationLiteral1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onNumberLiteral15()
+	return p.cur.onDurationLiteral1()
 }
 
 func (c *current) onStringLiteral2() (interface{}, error) {
@@ -2130,14 +7942,34 @@ func (p *parser) callonStringLiteral2() (interface{}, error) {
 	return p.cur.onStringLiteral2()
 }
 
-func (c *current) onStringLiteral25() (bool, error) {
+func (c *current) onStringLiteral21() (bool, error) {
+	return false, errors.New("Invalid escape sequence in string literal")
+}
+
+func (p *parser) callonStringLiteral21() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onStringLiteral21()
+}
+
+func (c *current) onStringLiteral33() (bool, error) {
 	return false, errors.New("Unterminated string literal")
 }
 
-func (p *parser) callonStringLiteral25() (bool, error) {
+func (p *parser) callonStringLiteral33() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onStringLiteral33()
+}
+
+func (c *current) onComment22() (bool, error) {
+	return false, errors.New("Unterminated comment")
+}
+
+func (p *parser) callonComment22() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onStringLiteral25()
+	return p.cur.onComment22()
 }
 
 var (
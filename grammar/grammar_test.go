@@ -1,6 +1,12 @@
 package grammar
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -33,13 +39,36 @@ func TestExpressionParsing(t *testing.T) {
 		"Match Equality, JSON Pointer, with punctuation, trailing slash": {
 			input:    `"/hy-phen/under_score/pi|pe/do.t/ti~lde/" == 3`,
 			expected: nil,
-			err:      "1:43 (42): no match found, expected: \"in\", \"not\" or [ \\t\\r\\n]",
+			err:      "1:43 (42): no match found, expected: \"#\", \"/*\", \"<\", \"<=\", \">\", \">=\", \"ge\", \"gt\", \"in\", \"le\", \"lt\", \"not\" or [ \\t\\r\\n]",
 		},
 		"Match Inequality": {
 			input:    "foo != xyz",
 			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotEqual, Value: &MatchValue{Raw: "xyz"}},
 			err:      "",
 		},
+		"Match Keyword Equality": {
+			input:    "Status eq passing",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Status"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "passing"}},
+			err:      "",
+		},
+		"Match Keyword Inequality": {
+			input: "Status ne passing and Port ge 8000",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Status"}}, Operator: MatchNotEqual, Value: &MatchValue{Raw: "passing"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Port"}}, Operator: MatchGreaterThanOrEqual, Value: &MatchValue{Raw: "8000"}},
+			},
+			err: "",
+		},
+		"Match Keyword Chained Comparison": {
+			input: "1 le foo lt 3",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchGreaterThanOrEqual, Value: &MatchValue{Raw: "1"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchLessThan, Value: &MatchValue{Raw: "3"}},
+			},
+			err: "",
+		},
 		"Match Is Empty": {
 			input:    "list is empty",
 			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}, Operator: MatchIsEmpty, Value: nil},
@@ -72,12 +101,434 @@ func TestExpressionParsing(t *testing.T) {
 		},
 		"Match Matches": {
 			input:    "foo matches bar",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchMatches, Value: &MatchValue{Raw: "bar"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchMatches, Value: &MatchValue{Raw: "bar", Converted: regexp.MustCompile("bar")}},
 			err:      "",
 		},
 		"Match Not Matches": {
 			input:    "foo not matches bar",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotMatches, Value: &MatchValue{Raw: "bar"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotMatches, Value: &MatchValue{Raw: "bar", Converted: regexp.MustCompile("bar")}},
+			err:      "",
+		},
+		"Match Matches Operator": {
+			input:    "foo =~ `^b.r$`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchMatches, Value: &MatchValue{Raw: "^b.r$", Quoted: true, Converted: regexp.MustCompile("^b.r$")}},
+			err:      "",
+		},
+		"Match Not Matches Operator": {
+			input:    "foo!~bar",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotMatches, Value: &MatchValue{Raw: "bar", Converted: regexp.MustCompile("bar")}},
+			err:      "",
+		},
+		"Match Matches Invalid Pattern": {
+			input:    "foo matches `[a-`",
+			expected: nil,
+			err:      "1:1 (0): rule \"match\": Invalid regular expression \"[a-\": error parsing regexp: missing closing ]: `[a-`",
+		},
+		"Match Like": {
+			input:    "foo like `web-*`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchLike, Value: &MatchValue{Raw: "web-*", Quoted: true, Converted: regexp.MustCompile(`^web-(?s:.*)$`)}},
+			err:      "",
+		},
+		"Match Not Like": {
+			input:    "foo not like bar",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotLike, Value: &MatchValue{Raw: "bar", Converted: regexp.MustCompile(`^bar$`)}},
+			err:      "",
+		},
+		"Placeholder": {
+			input:    "meta.env == $env",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"meta", "env"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "$env", Parameter: "env"}},
+			err:      "",
+		},
+		"Placeholder Not Converted": {
+			input:    "name matches $pattern",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}, Operator: MatchMatches, Value: &MatchValue{Raw: "$pattern", Parameter: "pattern"}},
+			err:      "",
+		},
+		"Match In CIDR": {
+			input:    `addr in cidr "10.0.0.0/8"`,
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"addr"}}, Operator: MatchInCIDR, Value: &MatchValue{Raw: "10.0.0.0/8", Quoted: true, Converted: &net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)}}},
+			err:      "",
+		},
+		"Match Not In CIDR": {
+			input:    "addr not in cidr `fd00::/8`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"addr"}}, Operator: MatchNotInCIDR, Value: &MatchValue{Raw: "fd00::/8", Quoted: true, Converted: &net.IPNet{IP: net.ParseIP("fd00::"), Mask: net.CIDRMask(8, 128)}}},
+			err:      "",
+		},
+		"Match In CIDR Invalid": {
+			input:    `addr in cidr "10.0.0.0/33"`,
+			expected: nil,
+			err:      "1:1 (0): rule \"match\": Invalid CIDR \"10.0.0.0/33\": invalid CIDR address: 10.0.0.0/33",
+		},
+		"Match In Selector Named cidr": {
+			input:    "addr in cidr",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"cidr"}}, Operator: MatchIn, Value: &MatchValue{Raw: "addr"}},
+			err:      "",
+		},
+		"Match Like Invalid Pattern": {
+			input:    "foo like `web-[a`",
+			expected: nil,
+			err:      "1:1 (0): rule \"match\": Invalid glob pattern \"web-[a\": unclosed character class",
+		},
+		"Match In List": {
+			input:    `status in ["passing", warning, 3, -1.5]`,
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"status"}}, Operator: MatchInSet, Values: []*MatchValue{{Raw: "passing", Quoted: true}, {Raw: "warning"}, {Raw: "3"}, {Raw: "-1.5"}}},
+			err:      "",
+		},
+		"Match Not In List": {
+			input:    "status not in [1,2]",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"status"}}, Operator: MatchNotInSet, Values: []*MatchValue{{Raw: "1"}, {Raw: "2"}}},
+			err:      "",
+		},
+		"Match In Empty List": {
+			input:    "status in [ ]",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"status"}}, Operator: MatchInSet, Values: []*MatchValue{}},
+			err:      "",
+		},
+		"Match In Unclosed List": {
+			input:    "status in [1, 2",
+			expected: nil,
+			err:      "1:16 (15): rule \"list\": Unclosed list literal",
+		},
+		"Match Contains Any": {
+			input:    "tags contains any [`web`, db]",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"tags"}}, Operator: MatchContainsAny, Values: []*MatchValue{{Raw: "web", Quoted: true}, {Raw: "db"}}},
+			err:      "",
+		},
+		"Match Not Contains All": {
+			input:    "tags not contains all[1, 2]",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"tags"}}, Operator: MatchNotContainsAll, Values: []*MatchValue{{Raw: "1"}, {Raw: "2"}}},
+			err:      "",
+		},
+		"Match Contains Selector Named all": {
+			input:    "tags contains all",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"tags"}}, Operator: MatchIn, Value: &MatchValue{Raw: "all"}},
+			err:      "",
+		},
+		"Match Between": {
+			input: "port between 8000 and 9000 and x == 1",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}, Operator: MatchBetween, Values: []*MatchValue{{Raw: "8000"}, {Raw: "9000"}}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "1"}},
+			},
+			err: "",
+		},
+		"Match Not Between": {
+			input:    "name not between `a` and `m`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}, Operator: MatchNotBetween, Values: []*MatchValue{{Raw: "a", Quoted: true}, {Raw: "m", Quoted: true}}},
+			err:      "",
+		},
+		"Length": {
+			input:    "len(Tags) >= 3",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Tags"}}, Operator: MatchGreaterThanOrEqual, Value: &MatchValue{Raw: "3"}, Length: true},
+			err:      "",
+		},
+		"Length Count": {
+			input:    "count( Meta.keys ) != 0",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Meta", "keys"}}, Operator: MatchNotEqual, Value: &MatchValue{Raw: "0"}, Length: true},
+			err:      "",
+		},
+		"Quantifier": {
+			input: "all(Checks, check -> check.Status == passing and Node == web)",
+			expected: &QuantifierExpression{
+				Quantifier: QuantifierAll,
+				Selector:   Selector{Type: SelectorTypeBexpr, Path: []string{"Checks"}},
+				Variable:   "check",
+				Expression: &BinaryExpression{
+					Operator: BinaryOpAnd,
+					Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"check", "Status"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "passing"}},
+					Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Node"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "web"}},
+				},
+			},
+			err: "",
+		},
+		"Quantifier Scoped": {
+			input: "service { any(Checks, c -> c.Status == passing and Name == web) }",
+			expected: &QuantifierExpression{
+				Quantifier: QuantifierAny,
+				Selector:   Selector{Type: SelectorTypeBexpr, Path: []string{"service", "Checks"}},
+				Variable:   "c",
+				Expression: &BinaryExpression{
+					Operator: BinaryOpAnd,
+					Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"c", "Status"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "passing"}},
+					Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"service", "Name"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "web"}},
+				},
+			},
+			err: "",
+		},
+		"Quantifier Unclosed": {
+			input:    "any(Checks, c -> c.Status == passing",
+			expected: nil,
+			err:      "1:37 (36): rule \"quantifier\": Unclosed quantifier",
+		},
+		"Constant True": {
+			input: "true and (foo == 3)",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &ConstantExpression{Value: true},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "3"}},
+			},
+			err: "",
+		},
+		"Constant Not False": {
+			input:    "not (false)",
+			expected: &ConstantExpression{Value: true},
+			err:      "",
+		},
+		"Constant Prefix Selector": {
+			input:    "trueish == 1",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"trueish"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "1"}},
+			err:      "",
+		},
+		"Comments": {
+			input: "# only production nodes\nfoo == 3 /* and the\n right region */ and bar == 4 # trailing",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "3"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "4"}},
+			},
+			err: "",
+		},
+		"Comment Inside String": {
+			input:    `foo == "# not a /* comment"`,
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "# not a /* comment", Quoted: true}},
+			err:      "",
+		},
+		"Comment As Separator": {
+			input:    "foo/* between */in/**/bar",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchIn, Value: &MatchValue{Raw: "foo"}},
+			err:      "",
+		},
+		"Unterminated Comment": {
+			input:    "foo == 3 /* never closed",
+			expected: nil,
+			err:      "1:25 (24): rule \"comment\": Unterminated comment",
+		},
+		"Arithmetic Comparison": {
+			input: "Used / Total > 0.9",
+			expected: &ComparisonExpression{
+				Left: &Operand{Arithmetic: &ArithmeticExpression{
+					Left:     &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Used"}}},
+					Operator: ArithmeticDivide,
+					Right:    &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Total"}}},
+				}},
+				Operator: MatchGreaterThan,
+				Right:    &Operand{Value: &MatchValue{Raw: "0.9"}},
+			},
+			err: "",
+		},
+		"Arithmetic Precedence": {
+			input: "a - b * (c + 1m) == 0",
+			expected: &ComparisonExpression{
+				Left: &Operand{Arithmetic: &ArithmeticExpression{
+					Left:     &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"a"}}},
+					Operator: ArithmeticSubtract,
+					Right: &Operand{Arithmetic: &ArithmeticExpression{
+						Left:     &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"b"}}},
+						Operator: ArithmeticMultiply,
+						Right: &Operand{Arithmetic: &ArithmeticExpression{
+							Left:     &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"c"}}},
+							Operator: ArithmeticAdd,
+							Right:    &Operand{Value: &MatchValue{Raw: "1m"}},
+						}},
+					}},
+				}},
+				Operator: MatchEqual,
+				Right:    &Operand{Value: &MatchValue{Raw: "0"}},
+			},
+			err: "",
+		},
+		"Arithmetic Scoped": {
+			input: "Port + 1000 == 9080 and foo { a % 2 == b }",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left: &ComparisonExpression{
+					Left: &Operand{Arithmetic: &ArithmeticExpression{
+						Left:     &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Port"}}},
+						Operator: ArithmeticAdd,
+						Right:    &Operand{Value: &MatchValue{Raw: "1000"}},
+					}},
+					Operator: MatchEqual,
+					Right:    &Operand{Value: &MatchValue{Raw: "9080"}},
+				},
+				Right: &ComparisonExpression{
+					Left: &Operand{Arithmetic: &ArithmeticExpression{
+						Left:     &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "a"}}},
+						Operator: ArithmeticModulo,
+						Right:    &Operand{Value: &MatchValue{Raw: "2"}},
+					}},
+					Operator: MatchEqual,
+					Right:    &Operand{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "b"}}},
+				},
+			},
+			err: "",
+		},
+		"Function Call": {
+			input: `hasPrefix(Node, "web-") and foo == 3`,
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left: &FunctionExpression{Name: "hasPrefix", Args: []*FunctionArgument{
+					{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Node"}}},
+					{Value: &MatchValue{Raw: "web-", Quoted: true}},
+				}},
+				Right: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "3"}},
+			},
+			err: "",
+		},
+		"Function Call No Arguments": {
+			input:    "not isWeekend( )",
+			expected: &UnaryExpression{Operator: UnaryOpNot, Operand: &FunctionExpression{Name: "isWeekend"}},
+			err:      "",
+		},
+		"Function Call Parameter": {
+			input: "within(Meta.zone, $zone, 5)",
+			expected: &FunctionExpression{Name: "within", Args: []*FunctionArgument{
+				{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Meta", "zone"}}},
+				{Value: &MatchValue{Raw: "$zone", Parameter: "zone"}},
+				{Value: &MatchValue{Raw: "5"}},
+			}},
+			err: "",
+		},
+		"Bare Boolean Selector": {
+			input: "Enabled and not Deprecated",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Enabled"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "true", Converted: true}},
+				Right: &UnaryExpression{
+					Operator: UnaryOpNot,
+					Operand:  &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Deprecated"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "true", Converted: true}},
+				},
+			},
+			err: "",
+		},
+		"Bare Boolean Selector Scoped": {
+			input:    "(node { Healthy })",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"node", "Healthy"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "true", Converted: true}},
+			err:      "",
+		},
+		"Bare Selector Junk": {
+			input:    "foo bar",
+			expected: nil,
+			err:      "1:5 (4): no match found, expected: \"!=\", \"!=i\", \"!~\", \"#\", \"%\", \"(\", \")\", \"*\", \"+\", \"-\", \"/\", \"/*\", \"<\", \"<=\", \"==\", \"==i\", \"=~\", \">\", \">=\", \"and\", \"between\", \"contains\", \"ends\", \"eq\", \"ge\", \"gt\", \"iequals\", \"in\", \"is\", \"le\", \"like\", \"lt\", \"matches\", \"ne\", \"not\", \"or\", \"starts\", \"xor\", \"{\", \"}\", [ \\t\\r\\n] or EOF",
+		},
+		"Match Is Null": {
+			input:    "owner is null",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"owner"}}, Operator: MatchIsNull, Value: nil},
+			err:      "",
+		},
+		"Match Is Not Nil": {
+			input:    "owner is not nil",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"owner"}}, Operator: MatchIsNotNull, Value: nil},
+			err:      "",
+		},
+		"Match Equal Fold": {
+			input:    "name ==i `Web`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}, Operator: MatchEqualFold, Value: &MatchValue{Raw: "Web", Quoted: true}},
+			err:      "",
+		},
+		"Match Not Equal Fold Keyword": {
+			input:    "name not iequals web",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}, Operator: MatchNotEqualFold, Value: &MatchValue{Raw: "web"}},
+			err:      "",
+		},
+		"Match Starts With": {
+			input:    "node starts with `web-`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"node"}}, Operator: MatchPrefix, Value: &MatchValue{Raw: "web-", Quoted: true}},
+			err:      "",
+		},
+		"Match Not Starts With": {
+			input:    "node not starts with web",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"node"}}, Operator: MatchNotPrefix, Value: &MatchValue{Raw: "web"}},
+			err:      "",
+		},
+		"Match Ends With": {
+			input:    "node ends with `.local`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"node"}}, Operator: MatchSuffix, Value: &MatchValue{Raw: ".local", Quoted: true}},
+			err:      "",
+		},
+		"Match Not Ends With": {
+			input:    "node not ends with local",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"node"}}, Operator: MatchNotSuffix, Value: &MatchValue{Raw: "local"}},
+			err:      "",
+		},
+		"Match Less Than": {
+			input:    "foo < 3",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchLessThan, Value: &MatchValue{Raw: "3"}},
+			err:      "",
+		},
+		"Match Less Than Or Equal": {
+			input:    "foo <= 3",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchLessThanOrEqual, Value: &MatchValue{Raw: "3"}},
+			err:      "",
+		},
+		"Match Greater Than": {
+			input:    "foo > 3",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchGreaterThan, Value: &MatchValue{Raw: "3"}},
+			err:      "",
+		},
+		"Match Greater Than Or Equal": {
+			input:    "foo >= 3",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchGreaterThanOrEqual, Value: &MatchValue{Raw: "3"}},
+			err:      "",
+		},
+		"Chained Comparison Ascending": {
+			input: "10 <= port < 1024",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}, Operator: MatchGreaterThanOrEqual, Value: &MatchValue{Raw: "10"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}, Operator: MatchLessThan, Value: &MatchValue{Raw: "1024"}},
+			},
+			err: "",
+		},
+		"Chained Comparison Descending": {
+			input: "1024 > port >= 10",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}, Operator: MatchLessThan, Value: &MatchValue{Raw: "1024"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}, Operator: MatchGreaterThanOrEqual, Value: &MatchValue{Raw: "10"}},
+			},
+			err: "",
+		},
+		"Chained Comparison Mixed Directions": {
+			input:    "10 < port > 20",
+			expected: nil,
+			err:      "1:15 (14): rule \"match\": Chained comparisons must use operators of the same direction",
+		},
+		"Scoped Expression": {
+			input: "service.meta { port == 80 and http in tags }",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"service", "meta", "port"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "80"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"service", "meta", "tags"}}, Operator: MatchIn, Value: &MatchValue{Raw: "http"}},
+			},
+			err: "",
+		},
+		"Scoped Expression Nested": {
+			input: "service{not meta{ port == 80 }}",
+			expected: &UnaryExpression{
+				Operator: UnaryOpNot,
+				Operand:  &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"service", "meta", "port"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "80"}},
+			},
+			err: "",
+		},
+		"Scoped Expression Compact": {
+			input:    "svc{port==80}",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"svc", "port"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "80"}},
+			err:      "",
+		},
+		"Scoped Expression Unclosed": {
+			input:    "service { port == 80",
+			expected: nil,
+			err:      "1:21 (20): rule \"scope\": Unclosed scope block",
+		},
+		"Match In Set": {
+			input:    "owner in @admins",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"owner"}}, Operator: MatchInSet, Value: &MatchValue{Raw: "admins"}},
+			err:      "",
+		},
+		"Match Not In Set": {
+			input:    "owner not in @admins",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"owner"}}, Operator: MatchNotInSet, Value: &MatchValue{Raw: "admins"}},
 			err:      "",
 		},
 		"Logical Not": {
@@ -108,42 +559,42 @@ func TestExpressionParsing(t *testing.T) {
 		},
 		"Double Quoted Value (Equal)": {
 			input:    "foo == \"bar\"",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "bar"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "bar", Quoted: true}},
 			err:      "",
 		},
 		"Double Quoted Value (Not Equal)": {
 			input:    "foo != \"bar\"",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotEqual, Value: &MatchValue{Raw: "bar"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotEqual, Value: &MatchValue{Raw: "bar", Quoted: true}},
 			err:      "",
 		},
 		"Double Quoted Value (In)": {
 			input:    "\"foo\" in bar",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchIn, Value: &MatchValue{Raw: "foo"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchIn, Value: &MatchValue{Raw: "foo", Quoted: true}},
 			err:      "",
 		},
 		"Double Quoted Value (Not In)": {
 			input:    "\"foo\" not in bar",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchNotIn, Value: &MatchValue{Raw: "foo"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchNotIn, Value: &MatchValue{Raw: "foo", Quoted: true}},
 			err:      "",
 		},
 		"Backtick Quoted Value (Equal)": {
 			input:    "foo == `bar`",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "bar"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "bar", Quoted: true}},
 			err:      "",
 		},
 		"Backtick Quoted Value (Not Equal)": {
 			input:    "foo != `bar`",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotEqual, Value: &MatchValue{Raw: "bar"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchNotEqual, Value: &MatchValue{Raw: "bar", Quoted: true}},
 			err:      "",
 		},
 		"Backtick Quoted Value (In)": {
 			input:    "`foo` in bar",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchIn, Value: &MatchValue{Raw: "foo"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchIn, Value: &MatchValue{Raw: "foo", Quoted: true}},
 			err:      "",
 		},
 		"Backtick Quoted Value (Not In)": {
 			input:    "`foo` not in bar",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchNotIn, Value: &MatchValue{Raw: "foo"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}, Operator: MatchNotIn, Value: &MatchValue{Raw: "foo", Quoted: true}},
 			err:      "",
 		},
 		// This is standard boolean expression precedence
@@ -151,6 +602,23 @@ func TestExpressionParsing(t *testing.T) {
 		// 1 - not
 		// 2 - and
 		// 3 - or
+		"Logical Xor Precedence": {
+			input: "a == 1 or b == 2 xor c == 3 and d == 4",
+			expected: &BinaryExpression{
+				Operator: BinaryOpOr,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"a"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "1"}},
+				Right: &BinaryExpression{
+					Operator: BinaryOpXor,
+					Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"b"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "2"}},
+					Right: &BinaryExpression{
+						Operator: BinaryOpAnd,
+						Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"c"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "3"}},
+						Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"d"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "4"}},
+					},
+				},
+			},
+			err: "",
+		},
 		"Logical Operator Precedence": {
 			input: "x in foo and not str == something or list is empty",
 			expected: &BinaryExpression{
@@ -251,7 +719,12 @@ func TestExpressionParsing(t *testing.T) {
 		},
 		"Selector Path": {
 			input:    "`environment` in foo.bar[\"meta\"].tags[\t`ENV` ]",
-			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar", "meta", "tags", "ENV"}}, Operator: MatchIn, Value: &MatchValue{Raw: "environment"}},
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar", "meta", "tags", "ENV"}}, Operator: MatchIn, Value: &MatchValue{Raw: "environment", Quoted: true}},
+			err:      "",
+		},
+		"Selector Path, Quoted Segments": {
+			input:    "meta.`consul.io/version`.\"with space\" == `1.9`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"meta", "consul.io/version", "with space"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "1.9", Quoted: true}},
 			err:      "",
 		},
 		"Selector Path, JSON Pointer": {
@@ -286,13 +759,62 @@ func TestExpressionParsing(t *testing.T) {
 			expected: nil,
 			err:      "1:12 (11): rule \"string\": Unterminated string literal",
 		},
+		"String Escapes": {
+			input:    `foo == "say \"hi\"\\n\t\u00e9\\"`,
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "say \"hi\"\\n\t\u00e9\\", Quoted: true}},
+			err:      "",
+		},
+		"Raw String No Escapes": {
+			input:    "foo == `a\\nb`",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "a\\nb", Quoted: true}},
+			err:      "",
+		},
+		"Invalid String Escape": {
+			input:    `foo == "a\qb"`,
+			expected: nil,
+			err:      "1:11 (10): rule \"string\": Invalid escape sequence in string literal",
+		},
+		"Unterminated Escaped Quote": {
+			input:    `foo == "abc\"`,
+			expected: nil,
+			err:      "1:14 (13): rule \"string\": Unterminated string literal",
+		},
 		"Invalid Number": {
 			input:    "foo == 3x",
 			expected: nil,
 			err:      "1:9 (8): rule \"number\": Invalid number literal",
 		},
+		"Slice Index": {
+			input:    "tags[ 0 ] == abc",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"tags", "0"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "abc"}},
+			err:      "",
+		},
+		"Relative Slice Index": {
+			input: "checks[-1].status == passing or checks[last].status == warning",
+			expected: &BinaryExpression{
+				Operator: BinaryOpOr,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"checks", "-1", "status"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "passing"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"checks", "last", "status"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "warning"}},
+			},
+			err: "",
+		},
+		"Wildcard Segments": {
+			input:    "services.*.ports[*] == 80",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"services", "*", "ports", "*"}, Wildcards: []int{1, 3}}, Operator: MatchEqual, Value: &MatchValue{Raw: "80"}},
+			err:      "",
+		},
+		"Quoted Star Key": {
+			input:    "meta[\"*\"] == star",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"meta", "*"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "star"}},
+			err:      "",
+		},
+		"Unclosed Slice Index": {
+			input:    "tags[0 == abc",
+			expected: nil,
+			err:      "1:8 (7): rule \"index\": Unclosed index expression",
+		},
 		"Invalid Index Key": {
-			input:    "foo[3] == abc",
+			input:    "foo[abc] == abc",
 			expected: nil,
 			err:      "1:5 (4): rule \"index\": Invalid index",
 		},
@@ -314,32 +836,32 @@ func TestExpressionParsing(t *testing.T) {
 		"Invalid Selector 2": {
 			input:    "32 == 32",
 			expected: nil,
-			err:      `1:4 (3): no match found, expected: "in", "not" or [ \t\r\n]`,
+			err:      `1:9 (8): no match found, expected: "#", "%", "*", "+", "-", ".", "/", "/*", "h", "m", "ms", "ns", "s", "us", "µs", [ \t\r\n], [0-9] or [eE]`,
 		},
 		"Invalid Selector 3": {
 			input:    "32 is empty",
 			expected: nil,
-			err:      `1:4 (3): no match found, expected: "in", "not" or [ \t\r\n]`,
+			err:      `1:4 (3): no match found, expected: "!=", "#", "%", "*", "+", "-", "/", "/*", "<", "<=", "==", ">", ">=", "eq", "ge", "gt", "in", "le", "lt", "ne", "not" or [ \t\r\n]`,
 		},
 		"Junk at the end 1": {
 			input:    "x in foo abc",
 			expected: nil,
-			err:      `1:10 (9): no match found, expected: "and", "or", [ \t\r\n] or EOF`,
+			err:      `1:10 (9): no match found, expected: "#", "/*", "and", "or", "xor", [ \t\r\n] or EOF`,
 		},
 		"Junk at the end 2": {
 			input:    "x in foo and ",
 			expected: nil,
-			err:      "1:14 (13): no match found, expected: \"(\", \"-\", \"0\", \"\\\"\", \"`\", \"not\", [ \\t\\r\\n], [1-9] or [a-zA-Z]",
+			err:      "1:14 (13): no match found, expected: \"#\", \"$\", \"(\", \"-\", \"/*\", \"0\", \"\\\"\", \"`\", \"all\", \"any\", \"count\", \"false\", \"len\", \"not\", \"now\", \"true\", [ \\t\\r\\n], [0-9], [1-9] or [a-zA-Z]",
 		},
 		"Junk at the end 3": {
 			input:    "x in foo or ",
 			expected: nil,
-			err:      "1:13 (12): no match found, expected: \"(\", \"-\", \"0\", \"\\\"\", \"`\", \"not\", [ \\t\\r\\n], [1-9] or [a-zA-Z]",
+			err:      "1:13 (12): no match found, expected: \"#\", \"$\", \"(\", \"-\", \"/*\", \"0\", \"\\\"\", \"`\", \"all\", \"any\", \"count\", \"false\", \"len\", \"not\", \"now\", \"true\", [ \\t\\r\\n], [0-9], [1-9] or [a-zA-Z]",
 		},
 		"Junk at the end 4": {
 			input:    "x in foo or not ",
 			expected: nil,
-			err:      "1:17 (16): no match found, expected: \"!=\", \"(\", \"-\", \"0\", \"==\", \"\\\"\", \"`\", \"contains\", \"in\", \"is\", \"matches\", \"not\", [ \\t\\r\\n], [1-9] or [a-zA-Z]",
+			err:      "1:17 (16): no match found, expected: \"!=\", \"!=i\", \"!~\", \"#\", \"$\", \"(\", \"-\", \"/*\", \"0\", \"<\", \"<=\", \"==\", \"==i\", \"=~\", \">\", \">=\", \"\\\"\", \"`\", \"all\", \"any\", \"between\", \"contains\", \"count\", \"ends\", \"eq\", \"false\", \"ge\", \"gt\", \"iequals\", \"in\", \"is\", \"le\", \"len\", \"like\", \"lt\", \"matches\", \"ne\", \"not\", \"now\", \"starts\", \"true\", \"{\", [ \\t\\r\\n], [0-9], [1-9] or [a-zA-Z]",
 		},
 		"Float Literal 1": {
 			input:    "foo == 0.2",
@@ -351,6 +873,64 @@ func TestExpressionParsing(t *testing.T) {
 			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "11.11"}},
 			err:      "",
 		},
+		"Time Literal": {
+			input: "created > 2023-01-01T00:00:00Z and updated <= 2023-06-01T12:30:00.5+02:00",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"created"}}, Operator: MatchGreaterThan, Value: &MatchValue{Raw: "2023-01-01T00:00:00Z"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"updated"}}, Operator: MatchLessThanOrEqual, Value: &MatchValue{Raw: "2023-06-01T12:30:00.5+02:00"}},
+			},
+			err: "",
+		},
+		"Date Literal": {
+			input:    "created between 2023-01-01 and now-24h",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"created"}}, Operator: MatchBetween, Values: []*MatchValue{{Raw: "2023-01-01"}, {Raw: "now-24h"}}},
+			err:      "",
+		},
+		"Relative Time Literal": {
+			input: "created > now-1h and expires < now+1h",
+			expected: &BinaryExpression{
+				Operator: BinaryOpAnd,
+				Left:     &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"created"}}, Operator: MatchGreaterThan, Value: &MatchValue{Raw: "now-1h"}},
+				Right:    &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"expires"}}, Operator: MatchLessThan, Value: &MatchValue{Raw: "now+1h"}},
+			},
+			err: "",
+		},
+		"Now Selector Value": {
+			input:    "now in times",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"times"}}, Operator: MatchIn, Value: &MatchValue{Raw: "now"}},
+			err:      "",
+		},
+		"Duration Literal": {
+			input:    "ttl > 2h30m",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"ttl"}}, Operator: MatchGreaterThan, Value: &MatchValue{Raw: "2h30m"}},
+			err:      "",
+		},
+		"Fractional Duration Literal": {
+			input:    "ttl between -1.5s and 300ms",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"ttl"}}, Operator: MatchBetween, Values: []*MatchValue{{Raw: "-1.5s"}, {Raw: "300ms"}}},
+			err:      "",
+		},
+		"Hex Literal": {
+			input:    "foo == 0x1F",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "0x1F"}},
+			err:      "",
+		},
+		"Octal Literal": {
+			input:    "foo == 0o755",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "0o755"}},
+			err:      "",
+		},
+		"Scientific Literal": {
+			input:    "foo < -1.5e+6",
+			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchLessThan, Value: &MatchValue{Raw: "-1.5e+6"}},
+			err:      "",
+		},
+		"Invalid Octal Literal": {
+			input:    "foo == 0o8",
+			expected: nil,
+			err:      "1:9 (8): rule \"number\": Invalid number literal",
+		},
 		"Negative Float": {
 			input:    "foo == -0.2",
 			expected: &MatchExpression{Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}, Operator: MatchEqual, Value: &MatchValue{Raw: "-0.2"}},
@@ -437,6 +1017,9 @@ func BenchmarkExpressionParsing(b *testing.B) {
 		"Not Equals And Equals": "not (foo == 3 and bar == 4)",
 		"Matches":               "foo matches bar",
 		"Not Matches":           "foo not matches bar",
+		"Less Than":             "foo < 3",
+		"Greater Than Or Equal": "foo >= 3",
+		"Chained Comparison":    "1 < foo <= 3",
 		"Big Selectors":         "abcdefghijklmnopqrstuvwxyz.foo.bar.baz.one.two.three.four.five.six.seven.eight.nine.ten == 42",
 		"Many Ors":              "foo == 3 or bar in baz or one != two or next is empty or other is not empty or name == \"\"",
 		"Lots of Ops":           "foo == 3 and not bar in baz and not one != two or next is empty and not foo is not empty and bar not in foo",
@@ -454,3 +1037,310 @@ func BenchmarkExpressionParsing(b *testing.B) {
 		})
 	}
 }
+
+func TestParseSelector(t *testing.T) {
+	t.Parallel()
+	type testCase struct {
+		input    string
+		expected Selector
+		err      string
+	}
+
+	tests := map[string]testCase{
+		"Dotted":       {input: "foo.bar", expected: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar"}}},
+		"Index":        {input: ` foo["b ar"] `, expected: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "b ar"}}},
+		"Quoted":       {input: "foo.`b.ar`", expected: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "b.ar"}}},
+		"JSON Pointer": {input: `"/foo/bar"`, expected: Selector{Type: SelectorTypeJsonPointer, Path: []string{"foo", "bar"}}},
+		"Trailing":     {input: "foo bar", err: "1:5 (4): no match found, expected: \"#\", \"/*\", [ \\t\\r\\n] or EOF"},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sel, err := ParseSelector(tcase.input)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tcase.expected, sel)
+			}
+		})
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	t.Parallel()
+	type testCase struct {
+		input string
+		errs  []string
+	}
+
+	tests := map[string]testCase{
+		"Valid": {
+			input: "foo == 3 and (bar in baz or not x is empty)",
+		},
+		"Multiple Clauses": {
+			input: "foo = 3 and bar == 3 or baz is emtpy and x y",
+			errs: []string{
+				"1:1 (0): rule \"clause\": Invalid expression \"foo = 3\"",
+				"1:25 (24): rule \"clause\": Invalid expression \"baz is emtpy\"",
+				"1:42 (41): rule \"clause\": Invalid expression \"x y\"",
+			},
+		},
+		"Unmatched Parentheses": {
+			input: "(foo ==",
+			errs: []string{
+				"1:2 (1): rule \"clause\": Invalid expression \"foo ==\"",
+				"1:8 (7): rule \"grouping\": Unmatched parentheses",
+			},
+		},
+		"Trailing Text": {
+			input: "foo == 3 )",
+			errs: []string{
+				"1:10 (9): rule TrailingText: Unexpected text after expression",
+			},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse("", []byte(tcase.input), ErrorRecovery(true))
+			var msgs []string
+			for _, err := range Errors(err) {
+				msgs = append(msgs, err.Error())
+			}
+			require.Equal(t, tcase.errs, msgs)
+		})
+	}
+}
+
+func TestCommaAnd(t *testing.T) {
+	t.Parallel()
+
+	expr, err := Parse("", []byte("Status==passing,Meta.env == prod , x is empty or y is empty"), CommaAnd(true))
+	require.NoError(t, err)
+	require.Equal(t, &BinaryExpression{
+		Operator: BinaryOpAnd,
+		Left: &MatchExpression{
+			Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Status"}},
+			Operator: MatchEqual,
+			Value:    &MatchValue{Raw: "passing"},
+		},
+		Right: &BinaryExpression{
+			Operator: BinaryOpAnd,
+			Left: &MatchExpression{
+				Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Meta", "env"}},
+				Operator: MatchEqual,
+				Value:    &MatchValue{Raw: "prod"},
+			},
+			Right: &BinaryExpression{
+				Operator: BinaryOpOr,
+				Left: &MatchExpression{
+					Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}},
+					Operator: MatchIsEmpty,
+				},
+				Right: &MatchExpression{
+					Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"y"}},
+					Operator: MatchIsEmpty,
+				},
+			},
+		},
+	}, expr)
+
+	// list literals keep their own commas
+	_, err = Parse("", []byte("x in [1, 2], y == 3"), CommaAnd(true))
+	require.NoError(t, err)
+
+	// commas are only accepted when enabled
+	_, err = Parse("", []byte("x == 1, y == 2"))
+	require.Error(t, err)
+
+	_, err = Parse("", []byte("x == 1, y = 2"), CommaAnd(true), ErrorRecovery(true))
+	var msgs []string
+	for _, err := range Errors(err) {
+		msgs = append(msgs, err.Error())
+	}
+	require.Equal(t, []string{"1:9 (8): rule \"clause\": Invalid expression \"y = 2\""}, msgs)
+}
+
+func TestExpressionJSON(t *testing.T) {
+	t.Parallel()
+
+	inputs := []string{
+		"foo == 3 and not (bar != `x` or baz is empty)",
+		"foo xor bar",
+		`"/foo/bar" matches "^a+$" and "/x" like "web-*" and ip in cidr "10.0.0.0/8"`,
+		"foo in [1, 2] and bar not in [] and baz in @set and tags contains all [a, b]",
+		"x between 1 and 10 and len(name) > 3 and y == $param",
+		"any(Checks, c -> c.Status == passing) and all(Nodes, n -> n.Port != 80)",
+		"hasPrefix(Node, \"web-\", 3) and Used / Total * 100 > 90 - Reserved",
+		"true or Enabled",
+		"meta.* == a and meta[\"*\"] == b",
+	}
+
+	for _, input := range inputs {
+		input := input
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			expected, err := Parse("", []byte(input))
+			require.NoError(t, err)
+
+			data, err := json.Marshal(expected)
+			require.NoError(t, err)
+
+			actual, err := UnmarshalExpression(data)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestExpressionJSON_Encoding(t *testing.T) {
+	t.Parallel()
+
+	expr, err := Parse("", []byte(`not foo.bar == 3`))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(expr)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "unary",
+		"operator": "Not",
+		"operand": {
+			"type": "match",
+			"selector": {"path": ["foo", "bar"]},
+			"operator": "Equal",
+			"value": {"raw": "3"}
+		}
+	}`, string(data))
+
+	_, err = UnmarshalExpression([]byte(`{"type": "match", "selector": {"path": ["foo"]}, "operator": "Equals", "value": {"raw": "3"}}`))
+	require.EqualError(t, err, `unknown match operator "Equals"`)
+
+	_, err = UnmarshalExpression([]byte(`{"type": "match", "selector": {"path": ["foo"]}, "operator": "Matches", "value": {"raw": "[a-"}}`))
+	require.EqualError(t, err, "Invalid regular expression \"[a-\": error parsing regexp: missing closing ]: `[a-`")
+
+	_, err = UnmarshalExpression([]byte(`{"type": "match", "selector": {"path": ["foo", "bar"], "wildcards": [1]}, "operator": "Equal", "value": {"raw": "3"}}`))
+	require.EqualError(t, err, `invalid wildcard 1 of selector "foo.bar"`)
+
+	// operators must have the values they take
+	for match, expected := range map[string]string{
+		`"operator": "Equal"`:                               `match operator "Equal" requires a value`,
+		`"operator": "Less Than"`:                           `match operator "Less Than" requires a value`,
+		`"operator": "Equal", "values": [{"raw": "1"}]`:     `match operator "Equal" requires a value`,
+		`"operator": "Is Empty", "value": {"raw": "1"}`:     `match operator "Is Empty" takes no value`,
+		`"operator": "In Set"`:                              `match operator "In Set" requires either a value set or a list of values`,
+		`"operator": "Contains All", "value": {"raw": "1"}`: `match operator "Contains All" requires a list of values`,
+		`"operator": "Between", "values": [{"raw": "1"}]`:   `match operator "Between" requires two values`,
+	} {
+		_, err = UnmarshalExpression([]byte(`{"type": "match", "selector": {"path": ["a"]}, ` + match + `}`))
+		require.EqualError(t, err, expected, match)
+	}
+
+	_, err = UnmarshalExpression([]byte(`{"type": "ternary"}`))
+	require.EqualError(t, err, `unknown expression type "ternary"`)
+
+	// deeply nested expressions are decoded in a single pass, and fail as
+	// soon as they exceed the maximum depth
+	deep := []byte(strings.Repeat(`{"type":"unary","operator":"Not","operand":`, 9000) +
+		`{"type":"constant","value":true}` + strings.Repeat(`}`, 9000))
+	decoded, err := UnmarshalExpression(deep)
+	require.NoError(t, err)
+	data, err = json.Marshal(decoded)
+	require.NoError(t, err)
+	require.Equal(t, string(deep), string(data))
+	_, err = UnmarshalExpressionMaxDepth(deep, 128)
+	require.True(t, errors.Is(err, ErrExpressionTooDeep))
+	require.EqualError(t, err, "expression exceeds the maximum depth of 128")
+	_, err = UnmarshalExpressionMaxDepth(deep, 9001)
+	require.NoError(t, err)
+
+	var match MatchExpression
+	require.EqualError(t, json.Unmarshal(deep, &match), `cannot decode expression of type "unary" into *grammar.MatchExpression`)
+}
+
+func TestExpressionString(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		input    string
+		expected string
+	}
+
+	tests := []testCase{
+		{"foo==3", "foo == 3"},
+		{"(a == 1 and b == 2) and c == 3", "(a == 1 and b == 2) and c == 3"},
+		{"a == 1 and (b == 2 and c == 3)", "a == 1 and b == 2 and c == 3"},
+		{"(a == 1 or b == 2) and not (c == 3 xor d == 4)", "(a == 1 or b == 2) and not (c == 3 xor d == 4)"},
+		{"a == 1 or b == 2 and c == 3", "a == 1 or b == 2 and c == 3"},
+		{"not a is empty", "not a is empty"},
+		{"Enabled and foo.bar.baz is not null", "Enabled and foo.bar.baz is not null"},
+		{"foo == `hello world` and bar != \"a\\\"b\" and baz == and_more", "foo == \"hello world\" and bar != \"a\\\"b\" and baz == and_more"},
+		{"foo == \"and\" and x == -1.5e3 and y == 0x1F", "foo == \"and\" and x == -1.5e3 and y == 0x1F"},
+		{`"/foo/a~1b/c~0d" == 1 and "/x" like "web-*"`, `"/foo/a~1b/c~0d" == 1 and "/x" like "web-*"`},
+		{"items[0].tags.`a.b`[\"c d\"].* contains all [a, \"b c\"]", "items[0].tags[\"a.b\"][\"c d\"].* contains all [a, \"b c\"]"},
+		{"items[-1].x == 1 and items.last.y == 2", "items[-1].x == 1 and items.last.y == 2"},
+		{"meta[*] == a and meta[\"*\"] == b and meta.`*`.c == d", "meta.* == a and meta[\"*\"] == b and meta[\"*\"].c == d"},
+		{"x in [1, 2] and y not in [] and z in @set and w not in @other", "x in [1, 2] and y not in [] and z in @set and w not in @other"},
+		{"v in tags and v not contains w and ip not in cidr \"10.0.0.0/8\"", "v in tags and w not in v and ip not in cidr \"10.0.0.0/8\""},
+		{"x between 1 and 10 and y not between 5m and 1h", "x between 1 and 10 and y not between 5m and 1h"},
+		{"len(name) >= 3 and name ==i Web and name !~ `^db` and name not ends with x", "len(name) >= 3 and name ==i Web and name not matches \"^db\" and name not ends with x"},
+		{"Used / (Total - Reserved) * 100 > 90 - (a - b)", "Used / (Total - Reserved) * 100 > 90 - (a - b)"},
+		{"any(Checks, c -> c.Status == passing and all(c.Notes, n -> n is empty))", "any(Checks, c -> c.Status == passing and all(c.Notes, n -> n is empty))"},
+		{"hasPrefix(Node, web, 3, $p) or false", "hasPrefix(Node, web, 3, $p) or false"},
+		{"x == $min and not (true)", "x == $min and false"},
+		{"Delim == \"5\" and x in [`a`, b, 5] and t > 2024-01-02T03:04:05Z", "Delim == \"5\" and x in [\"a\", b, 5] and t > 2024-01-02T03:04:05Z"},
+		{"x == \"a\U000E0001b\"", "x == `a\U000E0001b`"},
+		{"x == \"a`\U000E0001\\n\"", "x == \"a`\U000E0001\\n\""},
+	}
+
+	for _, tcase := range tests {
+		tcase := tcase
+		t.Run(tcase.input, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse("", []byte(tcase.input))
+			require.NoError(t, err)
+
+			formatted := expr.(fmt.Stringer).String()
+			require.Equal(t, tcase.expected, formatted)
+
+			reparsed, err := Parse("", []byte(formatted))
+			require.NoError(t, err)
+			require.Equal(t, expr, reparsed)
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseExpression([]byte("foo == 3 and\n  bar ==i baz } or x == 1"))
+	require.EqualError(t, err, "2:15 (27): no match found, expected: \"#\", \"/*\", \"and\", \"or\", \"xor\", [ \\t\\r\\n] or EOF")
+
+	var perr *ParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, 27, perr.Offset)
+	require.Equal(t, 2, perr.Line)
+	require.Equal(t, 15, perr.Column)
+	require.Equal(t, "}", perr.Token)
+	require.Equal(t, "  bar ==i baz } or x == 1", perr.Snippet)
+	require.Equal(t, []string{"\"#\"", "\"/*\"", "\"and\"", "\"or\"", "\"xor\"", "[ \\t\\r\\n]", "EOF"}, perr.Expected)
+
+	_, err = ParseExpression([]byte("foo matches `[a`"))
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, 0, perr.Offset)
+	require.Equal(t, "foo", perr.Token)
+	require.Empty(t, perr.Expected)
+	require.EqualError(t, perr.Unwrap(), "Invalid regular expression \"[a\": error parsing regexp: missing closing ]: `[a`")
+
+	_, err = ParseSelector("foo bar")
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, "bar", perr.Token)
+}
@@ -0,0 +1,611 @@
+package grammar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The JSON form of an expression is a tree of objects, one per node, whose
+// "type" field identifies the kind of node. Operators are encoded by their
+// names, such as "Not Equal", so that the encoding does not depend on the
+// numbering of the operator constants. For example foo == 3 is encoded as:
+//
+//	{"type":"match","selector":{"path":["foo"]},"operator":"Equal","value":{"raw":"3"}}
+
+type jsonUnary struct {
+	Type     string      `json:"type"`
+	Operator string      `json:"operator"`
+	Operand  interface{} `json:"operand"`
+}
+
+type jsonBinary struct {
+	Type     string      `json:"type"`
+	Operator string      `json:"operator"`
+	Left     interface{} `json:"left"`
+	Right    interface{} `json:"right"`
+}
+
+type jsonQuantifier struct {
+	Type       string       `json:"type"`
+	Quantifier string       `json:"quantifier"`
+	Selector   jsonSelector `json:"selector"`
+	Variable   string       `json:"variable"`
+	Expression interface{}  `json:"expression"`
+}
+
+type jsonFunction struct {
+	Type string         `json:"type"`
+	Name string         `json:"name"`
+	Args []jsonArgument `json:"args"`
+}
+
+type jsonArgument struct {
+	Selector *jsonSelector `json:"selector,omitempty"`
+	Value    *jsonValue    `json:"value,omitempty"`
+}
+
+type jsonComparison struct {
+	Type     string       `json:"type"`
+	Left     *jsonOperand `json:"left"`
+	Operator string       `json:"operator"`
+	Right    *jsonOperand `json:"right"`
+}
+
+type jsonOperand struct {
+	Selector   *jsonSelector   `json:"selector,omitempty"`
+	Value      *jsonValue      `json:"value,omitempty"`
+	Arithmetic *jsonArithmetic `json:"arithmetic,omitempty"`
+}
+
+type jsonArithmetic struct {
+	Left     *jsonOperand `json:"left"`
+	Operator string       `json:"operator"`
+	Right    *jsonOperand `json:"right"`
+}
+
+type jsonConstant struct {
+	Type  string `json:"type"`
+	Value bool   `json:"value"`
+}
+
+type jsonMatch struct {
+	Type     string       `json:"type"`
+	Selector jsonSelector `json:"selector"`
+	Operator string       `json:"operator"`
+	Value    *jsonValue   `json:"value,omitempty"`
+	// Values is a pointer so that an empty list literal is kept distinct
+	// from the absent list of a named value set
+	Values *[]jsonValue `json:"values,omitempty"`
+	Length bool         `json:"length,omitempty"`
+	// Bare is set for a bare selector, which is shorthand for: selector == true
+	Bare bool `json:"bare,omitempty"`
+}
+
+type jsonSelector struct {
+	Pointer   bool     `json:"pointer,omitempty"`
+	Path      []string `json:"path"`
+	Wildcards []int    `json:"wildcards,omitempty"`
+}
+
+type jsonValue struct {
+	Raw       string `json:"raw"`
+	Parameter string `json:"parameter,omitempty"`
+	Quoted    bool   `json:"quoted,omitempty"`
+}
+
+// jsonNode holds the fields of any of the nodes of the JSON form, along
+// with those of operands, so that a whole document is decoded in a single
+// pass rather than decoding the children of each node again. The value is
+// kept raw as it is a bool for constants and an object otherwise.
+type jsonNode struct {
+	Type       string          `json:"type"`
+	Operator   string          `json:"operator"`
+	Operand    *jsonNode       `json:"operand"`
+	Left       *jsonNode       `json:"left"`
+	Right      *jsonNode       `json:"right"`
+	Quantifier string          `json:"quantifier"`
+	Selector   *jsonSelector   `json:"selector"`
+	Variable   string          `json:"variable"`
+	Expression *jsonNode       `json:"expression"`
+	Name       string          `json:"name"`
+	Args       []jsonArgument  `json:"args"`
+	Arithmetic *jsonNode       `json:"arithmetic"`
+	Value      json.RawMessage `json:"value"`
+	Values     *[]jsonValue    `json:"values"`
+	Length     bool            `json:"length"`
+	Bare       bool            `json:"bare"`
+}
+
+// ErrExpressionTooDeep is returned by UnmarshalExpressionMaxDepth for
+// expressions nested more deeply than the maximum depth
+var ErrExpressionTooDeep = errors.New("expression exceeds the maximum depth")
+
+// UnmarshalExpression decodes an expression from the JSON produced by
+// json.Marshal for any of the expression types. The values of the decoded
+// expression are converted in the same way as by the parser.
+func UnmarshalExpression(data []byte) (Expression, error) {
+	return UnmarshalExpressionMaxDepth(data, 0)
+}
+
+// UnmarshalExpressionMaxDepth is like UnmarshalExpression but fails with an
+// error wrapping ErrExpressionTooDeep as soon as it finds the expression
+// nested more deeply than maxDepth, where each not, and, or, xor and
+// quantifier adds a level. A maxDepth of zero is no limit.
+func UnmarshalExpressionMaxDepth(data []byte, maxDepth uint64) (Expression, error) {
+	var j jsonNode
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	return j.expression(1, maxDepth)
+}
+
+// unmarshalNode decodes the JSON form of a node into expr, which must be of
+// the type the JSON gives
+func unmarshalNode(data []byte, expr Expression) error {
+	var j jsonNode
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	decoded, err := j.expression(1, 0)
+	if err != nil {
+		return err
+	}
+
+	switch expr := expr.(type) {
+	case *UnaryExpression:
+		if node, ok := decoded.(*UnaryExpression); ok {
+			*expr = *node
+			return nil
+		}
+	case *BinaryExpression:
+		if node, ok := decoded.(*BinaryExpression); ok {
+			*expr = *node
+			return nil
+		}
+	case *QuantifierExpression:
+		if node, ok := decoded.(*QuantifierExpression); ok {
+			*expr = *node
+			return nil
+		}
+	case *FunctionExpression:
+		if node, ok := decoded.(*FunctionExpression); ok {
+			*expr = *node
+			return nil
+		}
+	case *ComparisonExpression:
+		if node, ok := decoded.(*ComparisonExpression); ok {
+			*expr = *node
+			return nil
+		}
+	case *ConstantExpression:
+		if node, ok := decoded.(*ConstantExpression); ok {
+			*expr = *node
+			return nil
+		}
+	case *MatchExpression:
+		if node, ok := decoded.(*MatchExpression); ok {
+			*expr = *node
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot decode expression of type %q into %T", j.Type, expr)
+}
+
+// expression returns the expression of the node at the given depth
+func (j *jsonNode) expression(depth, maxDepth uint64) (Expression, error) {
+	if j == nil {
+		return nil, fmt.Errorf("expression is missing")
+	}
+	if maxDepth != 0 && depth > maxDepth {
+		return nil, fmt.Errorf("%w of %d", ErrExpressionTooDeep, maxDepth)
+	}
+
+	switch j.Type {
+	case "unary":
+		op, err := lookupUnaryOperator(j.Operator)
+		if err != nil {
+			return nil, err
+		}
+		operand, err := j.Operand.expression(depth+1, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpression{Operator: op, Operand: operand}, nil
+	case "binary":
+		op, err := lookupBinaryOperator(j.Operator)
+		if err != nil {
+			return nil, err
+		}
+		left, err := j.Left.expression(depth+1, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		right, err := j.Right.expression(depth+1, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		return &BinaryExpression{Left: left, Operator: op, Right: right}, nil
+	case "quantifier":
+		q, err := lookupQuantifier(j.Quantifier)
+		if err != nil {
+			return nil, err
+		}
+		sel, err := j.Selector.selector()
+		if err != nil {
+			return nil, err
+		}
+		body, err := j.Expression.expression(depth+1, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		return &QuantifierExpression{Quantifier: q, Selector: sel, Variable: j.Variable, Expression: body}, nil
+	case "function":
+		return j.function()
+	case "comparison":
+		op, err := lookupMatchOperator(j.Operator)
+		if err != nil {
+			return nil, err
+		}
+		left, err := j.Left.operand()
+		if err != nil {
+			return nil, err
+		}
+		right, err := j.Right.operand()
+		if err != nil {
+			return nil, err
+		}
+		return &ComparisonExpression{Left: left, Operator: op, Right: right}, nil
+	case "constant":
+		var value bool
+		if err := json.Unmarshal(j.Value, &value); err != nil {
+			return nil, fmt.Errorf("invalid value of constant: %w", err)
+		}
+		return &ConstantExpression{Value: value}, nil
+	case "match":
+		return j.match()
+	default:
+		return nil, fmt.Errorf("unknown expression type %q", j.Type)
+	}
+}
+
+func (j *jsonNode) function() (Expression, error) {
+	expr := &FunctionExpression{Name: j.Name, Args: make([]*FunctionArgument, 0, len(j.Args))}
+	for _, arg := range j.Args {
+		switch {
+		case arg.Value != nil:
+			expr.Args = append(expr.Args, &FunctionArgument{Value: arg.Value.value()})
+		case arg.Selector != nil:
+			sel, err := arg.Selector.selector()
+			if err != nil {
+				return nil, err
+			}
+			expr.Args = append(expr.Args, &FunctionArgument{Selector: sel})
+		default:
+			return nil, fmt.Errorf("argument of function %q has neither a selector nor a value", j.Name)
+		}
+	}
+	return expr, nil
+}
+
+func (j *jsonNode) match() (Expression, error) {
+	op, err := lookupMatchOperator(j.Operator)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := j.Selector.selector()
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &MatchExpression{Selector: sel, Operator: op, Length: j.Length}
+	if value, err := j.value(); err != nil {
+		return nil, err
+	} else if value != nil {
+		expr.Value = value.value()
+		if j.Bare {
+			expr.Value.Converted = true
+		}
+	}
+	if j.Values != nil {
+		expr.Values = make([]*MatchValue, 0, len(*j.Values))
+		for _, value := range *j.Values {
+			expr.Values = append(expr.Values, value.value())
+		}
+	}
+	if err := checkMatchValues(expr); err != nil {
+		return nil, err
+	}
+	if err := expr.ConvertValue(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// checkMatchValues checks that the match has the value or values its
+// operator takes, as the parser would have given it
+func checkMatchValues(expr *MatchExpression) error {
+	hasValue, hasValues := expr.Value != nil, expr.Values != nil
+	switch expr.Operator {
+	case MatchIsEmpty, MatchIsNotEmpty, MatchIsNull, MatchIsNotNull:
+		if hasValue || hasValues {
+			return fmt.Errorf("match operator %q takes no value", expr.Operator)
+		}
+	case MatchInSet, MatchNotInSet:
+		if hasValue == hasValues {
+			return fmt.Errorf("match operator %q requires either a value set or a list of values", expr.Operator)
+		}
+	case MatchContainsAny, MatchNotContainsAny, MatchContainsAll, MatchNotContainsAll:
+		if hasValue || !hasValues {
+			return fmt.Errorf("match operator %q requires a list of values", expr.Operator)
+		}
+	case MatchBetween, MatchNotBetween:
+		if hasValue || len(expr.Values) != 2 {
+			return fmt.Errorf("match operator %q requires two values", expr.Operator)
+		}
+	default:
+		if !hasValue || hasValues {
+			return fmt.Errorf("match operator %q requires a value", expr.Operator)
+		}
+	}
+	return nil
+}
+
+// value decodes the value of a match or operand, which is nil if absent
+func (j *jsonNode) value() (*jsonValue, error) {
+	if len(j.Value) == 0 || string(j.Value) == "null" {
+		return nil, nil
+	}
+	var value jsonValue
+	if err := json.Unmarshal(j.Value, &value); err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	return &value, nil
+}
+
+func (j *jsonNode) operand() (*Operand, error) {
+	if j == nil {
+		return nil, fmt.Errorf("comparison is missing an operand")
+	}
+	value, err := j.value()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case j.Arithmetic != nil:
+		op, err := lookupArithmeticOperator(j.Arithmetic.Operator)
+		if err != nil {
+			return nil, err
+		}
+		left, err := j.Arithmetic.Left.operand()
+		if err != nil {
+			return nil, err
+		}
+		right, err := j.Arithmetic.Right.operand()
+		if err != nil {
+			return nil, err
+		}
+		return &Operand{Arithmetic: &ArithmeticExpression{Left: left, Operator: op, Right: right}}, nil
+	case value != nil:
+		return &Operand{Value: value.value()}, nil
+	case j.Selector != nil:
+		sel, err := j.Selector.selector()
+		if err != nil {
+			return nil, err
+		}
+		return &Operand{Selector: sel}, nil
+	default:
+		return nil, fmt.Errorf("operand has neither a selector, a value nor an arithmetic expression")
+	}
+}
+
+// expressionToJSON returns the JSON form of the expression, made of values
+// without MarshalJSON methods so that it is encoded in a single pass
+func expressionToJSON(expr Expression) interface{} {
+	switch expr := expr.(type) {
+	case *UnaryExpression:
+		return jsonUnary{Type: "unary", Operator: expr.Operator.String(), Operand: expressionToJSON(expr.Operand)}
+	case *BinaryExpression:
+		return jsonBinary{Type: "binary", Operator: expr.Operator.String(), Left: expressionToJSON(expr.Left), Right: expressionToJSON(expr.Right)}
+	case *QuantifierExpression:
+		return jsonQuantifier{
+			Type:       "quantifier",
+			Quantifier: expr.Quantifier.String(),
+			Selector:   selectorToJSON(expr.Selector),
+			Variable:   expr.Variable,
+			Expression: expressionToJSON(expr.Expression),
+		}
+	case *FunctionExpression:
+		j := jsonFunction{Type: "function", Name: expr.Name, Args: make([]jsonArgument, 0, len(expr.Args))}
+		for _, arg := range expr.Args {
+			if arg.Value != nil {
+				j.Args = append(j.Args, jsonArgument{Value: valueToJSON(arg.Value)})
+			} else {
+				sel := selectorToJSON(arg.Selector)
+				j.Args = append(j.Args, jsonArgument{Selector: &sel})
+			}
+		}
+		return j
+	case *ComparisonExpression:
+		return jsonComparison{
+			Type:     "comparison",
+			Left:     operandToJSON(expr.Left),
+			Operator: expr.Operator.String(),
+			Right:    operandToJSON(expr.Right),
+		}
+	case *ConstantExpression:
+		return jsonConstant{Type: "constant", Value: expr.Value}
+	case *MatchExpression:
+		j := jsonMatch{
+			Type:     "match",
+			Selector: selectorToJSON(expr.Selector),
+			Operator: expr.Operator.String(),
+			Length:   expr.Length,
+		}
+		if expr.Value != nil {
+			j.Value = valueToJSON(expr.Value)
+			_, j.Bare = expr.Value.Converted.(bool)
+		}
+		if expr.Values != nil {
+			values := make([]jsonValue, 0, len(expr.Values))
+			for _, value := range expr.Values {
+				values = append(values, *valueToJSON(value))
+			}
+			j.Values = &values
+		}
+		return j
+	default:
+		// expressions of other packages encode themselves
+		return expr
+	}
+}
+
+func (expr *UnaryExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(expressionToJSON(expr))
+}
+
+func (expr *UnaryExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, expr)
+}
+
+func (expr *BinaryExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(expressionToJSON(expr))
+}
+
+func (expr *BinaryExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, expr)
+}
+
+func (expr *QuantifierExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(expressionToJSON(expr))
+}
+
+func (expr *QuantifierExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, expr)
+}
+
+func (expr *FunctionExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(expressionToJSON(expr))
+}
+
+func (expr *FunctionExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, expr)
+}
+
+func (expr *ComparisonExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(expressionToJSON(expr))
+}
+
+func (expr *ComparisonExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, expr)
+}
+
+func (expr *ConstantExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(expressionToJSON(expr))
+}
+
+func (expr *ConstantExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, expr)
+}
+
+func (expr *MatchExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(expressionToJSON(expr))
+}
+
+func (expr *MatchExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(data, expr)
+}
+
+func selectorToJSON(sel Selector) jsonSelector {
+	return jsonSelector{Pointer: sel.Type == SelectorTypeJsonPointer, Path: sel.Path, Wildcards: sel.Wildcards}
+}
+
+func (j *jsonSelector) selector() (Selector, error) {
+	if j == nil {
+		return Selector{}, fmt.Errorf("selector is missing")
+	}
+	sel := Selector{Type: SelectorTypeBexpr, Path: j.Path, Wildcards: j.Wildcards}
+	if j.Pointer {
+		sel.Type = SelectorTypeJsonPointer
+	}
+	for i, wildcard := range j.Wildcards {
+		if wildcard < 0 || wildcard >= len(j.Path) || j.Path[wildcard] != "*" || (i > 0 && wildcard <= j.Wildcards[i-1]) {
+			return Selector{}, fmt.Errorf("invalid wildcard %d of selector %q", wildcard, sel)
+		}
+	}
+	return sel, nil
+}
+
+func valueToJSON(value *MatchValue) *jsonValue {
+	return &jsonValue{Raw: value.Raw, Parameter: value.Parameter, Quoted: value.Quoted}
+}
+
+func (j *jsonValue) value() *MatchValue {
+	return &MatchValue{Raw: j.Raw, Parameter: j.Parameter, Quoted: j.Quoted}
+}
+
+func operandToJSON(o *Operand) *jsonOperand {
+	switch {
+	case o.Arithmetic != nil:
+		return &jsonOperand{Arithmetic: &jsonArithmetic{
+			Left:     operandToJSON(o.Arithmetic.Left),
+			Operator: o.Arithmetic.Operator.String(),
+			Right:    operandToJSON(o.Arithmetic.Right),
+		}}
+	case o.Value != nil:
+		return &jsonOperand{Value: valueToJSON(o.Value)}
+	default:
+		sel := selectorToJSON(o.Selector)
+		return &jsonOperand{Selector: &sel}
+	}
+}
+
+// The lookup functions return the operator with the given name as returned
+// by its String method. Every operator type numbers its constants from zero
+// and returns "UNKNOWN" past the last one.
+
+func lookupUnaryOperator(name string) (UnaryOperator, error) {
+	for op := UnaryOperator(0); op.String() != "UNKNOWN"; op++ {
+		if op.String() == name {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown unary operator %q", name)
+}
+
+func lookupBinaryOperator(name string) (BinaryOperator, error) {
+	for op := BinaryOperator(0); op.String() != "UNKNOWN"; op++ {
+		if op.String() == name {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown binary operator %q", name)
+}
+
+func lookupQuantifier(name string) (Quantifier, error) {
+	for q := Quantifier(0); q.String() != "UNKNOWN"; q++ {
+		if q.String() == name {
+			return q, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown quantifier %q", name)
+}
+
+func lookupArithmeticOperator(name string) (ArithmeticOperator, error) {
+	for op := ArithmeticOperator(0); op.String() != "UNKNOWN"; op++ {
+		if op.String() == name {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown arithmetic operator %q", name)
+}
+
+func lookupMatchOperator(name string) (MatchOperator, error) {
+	for op := MatchOperator(0); op.String() != "UNKNOWN"; op++ {
+		if op.String() == name {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown match operator %q", name)
+}
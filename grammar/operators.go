@@ -0,0 +1,116 @@
+package grammar
+
+import (
+	"fmt"
+	"sync"
+)
+
+// customOperator is a match operator registered with RegisterMatchOperator
+type customOperator struct {
+	token   string
+	convert func(raw string) (interface{}, error)
+}
+
+// customOperators holds the registered match operators in the order they
+// were registered. They are numbered after the built in operators so that
+// the operator with index i is MatchNotContainsAll + 1 + i.
+var customOperators struct {
+	lock      sync.RWMutex
+	operators []*customOperator
+}
+
+// operatorKeywords are the words which cannot be used as the token of a
+// custom operator as the built in syntax already gives them a meaning
+var operatorKeywords = map[string]struct{}{
+	"and": {}, "or": {}, "xor": {}, "not": {}, "in": {}, "is": {},
+	"contains": {}, "matches": {}, "like": {}, "starts": {}, "ends": {},
+	"between": {}, "iequals": {}, "eq": {}, "ne": {}, "lt": {}, "le": {},
+	"gt": {}, "ge": {}, "any": {}, "all": {}, "len": {}, "count": {},
+	"true": {}, "false": {},
+}
+
+// RegisterMatchOperator adds a match operator to the syntax which is
+// applied by the token in expressions of the form: selector <token> value
+// The token must be a single word, such as near, that is not already part
+// of the syntax. The returned operator has the token as its name.
+//
+// When convert is not nil it is called with the raw value of each match
+// using the operator whenever the value is converted, as by ConvertValue,
+// so that invalid values are reported when the expression is parsed. Its
+// result is stored as the converted value.
+//
+// Registering a token again replaces its convert function and returns the
+// same operator. Operators should be registered before any expressions
+// using them are parsed, such as from an init function.
+func RegisterMatchOperator(token string, convert func(raw string) (interface{}, error)) (MatchOperator, error) {
+	if !identifierRegexp.MatchString(token) {
+		return 0, fmt.Errorf("invalid match operator token %q: must be a single word", token)
+	}
+	if _, ok := operatorKeywords[token]; ok {
+		return 0, fmt.Errorf("invalid match operator token %q: already part of the syntax", token)
+	}
+
+	customOperators.lock.Lock()
+	defer customOperators.lock.Unlock()
+	for i, custom := range customOperators.operators {
+		if custom.token == token {
+			customOperators.operators[i] = &customOperator{token: token, convert: convert}
+			return customOperatorAt(i), nil
+		}
+	}
+	customOperators.operators = append(customOperators.operators, &customOperator{token: token, convert: convert})
+	return customOperatorAt(len(customOperators.operators) - 1), nil
+}
+
+// CustomMatchOperators returns the operators registered with
+// RegisterMatchOperator in the order they were registered
+func CustomMatchOperators() []MatchOperator {
+	customOperators.lock.RLock()
+	defer customOperators.lock.RUnlock()
+	ops := make([]MatchOperator, 0, len(customOperators.operators))
+	for i := range customOperators.operators {
+		ops = append(ops, customOperatorAt(i))
+	}
+	return ops
+}
+
+// IsCustom reports whether the operator was registered with
+// RegisterMatchOperator rather than being built in
+func (op MatchOperator) IsCustom() bool {
+	return op > MatchNotContainsAll
+}
+
+func customOperatorAt(index int) MatchOperator {
+	return MatchNotContainsAll + 1 + MatchOperator(index)
+}
+
+// getCustomOperator returns the registration of the custom operator, or nil
+// if it is not one
+func getCustomOperator(op MatchOperator) *customOperator {
+	if !op.IsCustom() {
+		return nil
+	}
+	customOperators.lock.RLock()
+	defer customOperators.lock.RUnlock()
+	if index := int(op - MatchNotContainsAll - 1); index < len(customOperators.operators) {
+		return customOperators.operators[index]
+	}
+	return nil
+}
+
+func hasCustomOperators() bool {
+	customOperators.lock.RLock()
+	defer customOperators.lock.RUnlock()
+	return len(customOperators.operators) > 0
+}
+
+func lookupCustomOperator(token string) (MatchOperator, bool) {
+	customOperators.lock.RLock()
+	defer customOperators.lock.RUnlock()
+	for i, custom := range customOperators.operators {
+		if custom.token == token {
+			return customOperatorAt(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,24 @@
+package grammar
+
+// the global store keys of the parse options below
+const (
+	errorRecoveryKey = "errorRecovery"
+	commaAndKey      = "commaAnd"
+)
+
+// ErrorRecovery creates an Option to make the parser skip over clauses it
+// cannot parse rather than stopping at the first syntax error. Each skipped
+// clause is reported as an error and parsing continues with the next one.
+// Use Errors to get the individual errors from the error Parse returns.
+func ErrorRecovery(enabled bool) Option {
+	return GlobalStore(errorRecoveryKey, enabled)
+}
+
+// CommaAnd creates an Option to accept commas between the top level clauses
+// of an expression as shorthand for and, in the style of label selectors
+// such as: Status == passing, Meta.env == prod
+// The comma binds more loosely than any other operator so that
+// a or b, c is equivalent to (a or b) and c.
+func CommaAnd(enabled bool) Option {
+	return GlobalStore(commaAndKey, enabled)
+}
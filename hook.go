@@ -0,0 +1,29 @@
+package bexpr
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Hook is notified of the evaluation of each match expression, such as
+// Name == web, when registered with WithHook. This allows applications to
+// collect metrics or audit logs about the fields filtered on. The methods
+// are called from the goroutine evaluating the expression so they must be
+// safe for concurrent use when evaluations run concurrently.
+type Hook interface {
+	// OnMatchStart is called before the match expression is evaluated
+	OnMatchStart(selector grammar.Selector, operator grammar.MatchOperator)
+	// OnMatchEnd is called once the match expression has been evaluated
+	OnMatchEnd(event MatchEvent)
+}
+
+// MatchEvent describes the evaluation of a match expression
+type MatchEvent struct {
+	Selector grammar.Selector
+	Operator grammar.MatchOperator
+	Result   bool
+	// Err is the error the evaluation failed with, if any
+	Err      error
+	Duration time.Duration
+}
@@ -0,0 +1,101 @@
+package bexpr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strconv"
+)
+
+// EvaluateJSON evaluates the expression against a JSON document without
+// decoding all of it, such as to filter encoded events or log entries. Only
+// the values that selectors refer to are decoded, with the rest of the
+// document skipped over, so syntax errors in the parts that are skipped may
+// go unreported. Numbers are compared as integers where they have no
+// fraction and as floats otherwise.
+func (eval *Evaluator) EvaluateJSON(data []byte) (bool, error) {
+	return eval.Evaluate(jsonDocument{data: data})
+}
+
+// EvaluateReader reads a JSON document from the reader and evaluates the
+// expression against it as by EvaluateJSON
+func (eval *Evaluator) EvaluateReader(r io.Reader) (bool, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return false, err
+	}
+	return eval.EvaluateJSON(data)
+}
+
+// jsonDocument resolves selectors by walking through the tokens of an
+// encoded JSON document
+type jsonDocument struct {
+	data []byte
+}
+
+func (doc jsonDocument) Resolve(path []string) (interface{}, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc.data))
+	dec.UseNumber()
+
+	for _, part := range path {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			found, err := seekKey(dec, part)
+			if err != nil || !found {
+				return nil, false, err
+			}
+		case json.Delim('['):
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 {
+				return nil, false, nil
+			}
+			for i := 0; i < index && dec.More(); i++ {
+				if err := skipValue(dec); err != nil {
+					return nil, false, err
+				}
+			}
+			if !dec.More() {
+				return nil, false, nil
+			}
+		default:
+			// there is nothing within strings, numbers, booleans and null
+			return nil, false, nil
+		}
+	}
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// seekKey skips over the members of the object that the decoder is within
+// until the one with the key, leaving the decoder before its value
+func seekKey(dec *json.Decoder, key string) (bool, error) {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return false, err
+		}
+		if tok == key {
+			return true, nil
+		}
+		if err := skipValue(dec); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// skipValue reads past the next value without decoding it
+func skipValue(dec *json.Decoder) error {
+	var skipped json.RawMessage
+	return dec.Decode(&skipped)
+}
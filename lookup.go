@@ -0,0 +1,486 @@
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+	"github.com/mitchellh/pointerstructure"
+)
+
+// structField is an exported field of a struct type as selectors see it
+type structField struct {
+	// name is what selectors call the field
+	name string
+	// index is the index sequence of the field within the struct, which is
+	// longer than one for fields promoted from embedded structs
+	index []int
+	typ   reflect.Type
+
+	// operators are the match operators allowed for the field by the ops
+	// option of its tag, or nil when all of them are allowed
+	operators map[grammar.MatchOperator]struct{}
+
+	// fold is set by the fold option of its tag to compare strings case
+	// insensitively, as for selectors given to WithCaseInsensitive
+	fold bool
+
+	// duration is set by the duration option of its tag to compare the
+	// integer values of the field as time.Duration nanoseconds
+	duration bool
+
+	// err reports an invalid tag when the field is selected
+	err error
+}
+
+// structInfo holds the selectable fields of a struct type
+type structInfo struct {
+	fields []*structField
+	// ignored holds the Go names of the fields tagged with "-"
+	ignored map[string]struct{}
+}
+
+// structInfos caches the parsed tags of each struct type by structKey
+var structInfos sync.Map
+
+// structKey identifies the fields of a struct type as named with the tags
+// that the options fall back to
+type structKey struct {
+	rtype reflect.Type
+	tags  string
+}
+
+// errNoStructField is returned when no field of a struct has the name and
+// errIgnoredField when the field with the name is tagged "-"
+var (
+	errNoStructField = errors.New("couldn't find struct field")
+	errIgnoredField  = errors.New("ignored and cannot be used")
+)
+
+// getStructInfo returns the fields of the struct type that selectors can
+// refer to. Fields are selected by the name given in their bexpr tag, or by
+// their Go name when the tag gives none, and fields tagged "-" are ignored.
+// Options follow the name after commas, such as: bexpr:"status,ops=eq|ne"
+// or bexpr:"name,fold" or bexpr:"timeout,duration"
+//
+// When the bexpr tag gives no name the name tags of the options, such as
+// json, are tried in turn before the Go name. A field whose first such tag
+// is "-" is ignored unless it has a bexpr tag.
+//
+// The fields of embedded structs that are not given a name by a tag are
+// promoted as Go promotes them, so they can be selected by their own names
+// as well as through the name of the embedded type. A name given to fields
+// at a shallower depth hides those deeper within, and a name given to more
+// than one field at the same depth of embedding selects none of them.
+func getStructInfo(rtype reflect.Type, opts *options) *structInfo {
+	key := structKey{rtype: rtype, tags: strings.Join(opts.withNameTags, ",")}
+	if info, ok := structInfos.Load(key); ok {
+		return info.(*structInfo)
+	}
+
+	info := &structInfo{ignored: make(map[string]struct{})}
+	taken := make(map[string]struct{})
+	level := []embeddedStruct{{typ: rtype}}
+	visited := map[reflect.Type]bool{rtype: true}
+	for depth := 0; len(level) > 0; depth++ {
+		var next []embeddedStruct
+		var fields []*structField
+		count := make(map[string]int)
+		for _, embedded := range level {
+			for i := 0; i < embedded.typ.NumField(); i++ {
+				field := embedded.typ.Field(i)
+				index := append(embedded.index[:len(embedded.index):len(embedded.index)], i)
+				elem := derefType(field.Type)
+				promoted := field.Anonymous && elem.Kind() == reflect.Struct
+				if field.PkgPath != "" && !promoted {
+					continue
+				}
+
+				sf, named := parseStructField(field, index, opts)
+				if sf == nil {
+					if _, ok := taken[field.Name]; !ok {
+						info.ignored[field.Name] = struct{}{}
+					}
+					continue
+				}
+				if promoted && !named && !visited[elem] {
+					visited[elem] = true
+					next = append(next, embeddedStruct{typ: elem, index: index})
+				}
+				// embedded structs of unexported types only promote
+				if field.PkgPath == "" {
+					fields = append(fields, sf)
+					count[sf.name]++
+				}
+			}
+		}
+
+		for _, sf := range fields {
+			if _, ok := taken[sf.name]; ok && depth > 0 {
+				continue
+			}
+			if depth > 0 && count[sf.name] > 1 {
+				// ambiguous, but still hiding any deeper fields
+				continue
+			}
+			info.fields = append(info.fields, sf)
+		}
+		for _, sf := range fields {
+			taken[sf.name] = struct{}{}
+		}
+		level = next
+	}
+
+	actual, _ := structInfos.LoadOrStore(key, info)
+	return actual.(*structInfo)
+}
+
+// embeddedStruct is a struct type embedded at the index within the struct
+// type whose fields are being collected
+type embeddedStruct struct {
+	typ   reflect.Type
+	index []int
+}
+
+// parseStructField returns the field as selectors see it, or nil if it is
+// ignored, and whether a tag gave it its name
+func parseStructField(field reflect.StructField, index []int, opts *options) (*structField, bool) {
+	raw, tagged := field.Tag.Lookup("bexpr")
+	tag := strings.Split(raw, ",")
+	if tag[0] == "-" && len(tag) == 1 {
+		return nil, false
+	}
+
+	sf := &structField{name: tag[0], index: index, typ: field.Type}
+	if sf.name == "" {
+		name, ignored := tagName(field, opts.withNameTags)
+		if ignored && !tagged {
+			return nil, false
+		}
+		sf.name = name
+	}
+	for _, option := range tag[1:] {
+		switch {
+		case option == "fold":
+			sf.fold = true
+		case option == "duration":
+			sf.duration = true
+			if kind := derefType(field.Type).Kind(); primitiveCompareFn(kind) == nil || kind == reflect.String || kind == reflect.Float32 || kind == reflect.Float64 {
+				sf.err = fmt.Errorf("the duration option in the bexpr tag of field %s requires an integer field", field.Name)
+			}
+		case strings.HasPrefix(option, "ops="):
+			sf.operators = make(map[grammar.MatchOperator]struct{})
+			for _, name := range strings.Split(strings.TrimPrefix(option, "ops="), "|") {
+				op, ok := lookupTagOperator(name)
+				if !ok {
+					sf.err = fmt.Errorf("invalid operator %q in the bexpr tag of field %s", name, field.Name)
+					break
+				}
+				sf.operators[op] = struct{}{}
+			}
+		default:
+			sf.err = fmt.Errorf("invalid option %q in the bexpr tag of field %s", option, field.Name)
+		}
+	}
+	return sf, sf.name != field.Name
+}
+
+// value returns the value of the field within the struct value, which is
+// invalid when a nil pointer to an embedded struct holds the field
+func (field *structField) value(current reflect.Value) reflect.Value {
+	for i, index := range field.index {
+		if i > 0 && current.Kind() == reflect.Ptr {
+			if current.IsNil() {
+				return reflect.Value{}
+			}
+			current = current.Elem()
+		}
+		current = current.Field(index)
+	}
+	return current
+}
+
+// the name tags standing for the names given by the protobuf tags of the
+// fields of generated protobuf messages
+const (
+	protobufTag     = "protobuf"
+	protobufJSONTag = "protobuf_json"
+)
+
+// tagName returns the name of the field given by the first of the tags that
+// names it, or its Go name when none do. The field is reported as ignored
+// when the first of the tags it has is "-".
+func tagName(field reflect.StructField, tags []string) (string, bool) {
+	for _, tag := range tags {
+		if tag == protobufTag || tag == protobufJSONTag {
+			if name, ignored, ok := protobufName(field, tag == protobufJSONTag); ok {
+				return name, ignored
+			}
+			continue
+		}
+
+		raw, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		if raw == "-" {
+			return field.Name, true
+		}
+		if name := strings.Split(raw, ",")[0]; name != "" {
+			return name, false
+		}
+	}
+	return field.Name, false
+}
+
+// protobufName returns the name of the field of a generated protobuf message
+// given by its protobuf tag, or its JSON name when json is set. Oneof fields
+// are named by their protobuf_oneof tag and the XXX_ fields of messages
+// generated by older versions of protoc-gen-go are ignored. It returns false
+// for fields without these tags.
+func protobufName(field reflect.StructField, json bool) (string, bool, bool) {
+	if strings.HasPrefix(field.Name, "XXX_") {
+		return field.Name, true, true
+	}
+	if oneof, ok := field.Tag.Lookup("protobuf_oneof"); ok {
+		return oneof, false, true
+	}
+	raw, ok := field.Tag.Lookup("protobuf")
+	if !ok {
+		return "", false, false
+	}
+
+	var name, jsonName string
+	for _, option := range strings.Split(raw, ",") {
+		switch {
+		case strings.HasPrefix(option, "name="):
+			name = strings.TrimPrefix(option, "name=")
+		case strings.HasPrefix(option, "json="):
+			jsonName = strings.TrimPrefix(option, "json=")
+		}
+	}
+	// the JSON name is only given when it differs from the name
+	if json && jsonName != "" {
+		return jsonName, false, true
+	}
+	return name, false, name != ""
+}
+
+// field returns the field that the selector path segment refers to. As
+// with pointerstructure, fields named by their tag take precedence over
+// those named by their Go name.
+func (info *structInfo) field(name string) (*structField, error) {
+	var found *structField
+	for _, field := range info.fields {
+		if field.name == name {
+			found = field
+			break
+		}
+	}
+	if found == nil {
+		if _, ok := info.ignored[name]; ok {
+			return nil, fmt.Errorf("struct field %q is %w", name, errIgnoredField)
+		}
+		return nil, fmt.Errorf("%w with name %q", errNoStructField, name)
+	}
+	if found.err != nil {
+		return nil, found.err
+	}
+	return found, nil
+}
+
+// names returns the names that the fields can be selected by
+func (info *structInfo) names() map[string]struct{} {
+	names := make(map[string]struct{}, len(info.fields))
+	for _, field := range info.fields {
+		names[field.name] = struct{}{}
+	}
+	return names
+}
+
+// allows reports whether the tag of the field allows the match operator
+func (field *structField) allows(op grammar.MatchOperator) bool {
+	if field == nil || field.operators == nil {
+		return true
+	}
+	_, ok := field.operators[op]
+	return ok
+}
+
+// durationValue returns the integer value of a field tagged with the
+// duration option as a time.Duration, and any other value as is
+func (field *structField) durationValue(val interface{}) interface{} {
+	if field == nil || !field.duration {
+		return val
+	}
+	rvalue := reflect.Indirect(reflect.ValueOf(val))
+	switch rvalue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(rvalue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(rvalue.Uint())
+	}
+	return val
+}
+
+// folds reports whether the tag of the field asks for case insensitive
+// comparisons
+func (field *structField) folds() bool {
+	return field != nil && field.fold
+}
+
+// checkOperator returns an error if the tag of the field does not allow the
+// operator of the match. Matches on the length of the field are allowed
+// when it allows is empty.
+func (field *structField) checkOperator(match *grammar.MatchExpression) error {
+	if match.Length {
+		if !field.allows(grammar.MatchIsEmpty) {
+			return &UnsupportedOperatorError{
+				Operator: match.Operator,
+				Selector: match.Selector,
+				Err:      fmt.Errorf("match operator %q is not supported for the length of selector: %q", match.Operator, match.Selector),
+			}
+		}
+		return nil
+	}
+	if !field.allows(match.Operator) {
+		return &UnsupportedOperatorError{
+			Operator: match.Operator,
+			Selector: match.Selector,
+			Err:      fmt.Errorf("match operator %q is not supported for selector: %q", match.Operator, match.Selector),
+		}
+	}
+	return nil
+}
+
+// tagOperators are the short names of the match operators within the ops
+// option of bexpr tags. Every operator can also be given by its name in
+// lower case without spaces, such as notin or startswith, and registered
+// operators by their token.
+var tagOperators = map[string]grammar.MatchOperator{
+	"eq": grammar.MatchEqual,
+	"ne": grammar.MatchNotEqual,
+	"lt": grammar.MatchLessThan,
+	"le": grammar.MatchLessThanOrEqual,
+	"gt": grammar.MatchGreaterThan,
+	"ge": grammar.MatchGreaterThanOrEqual,
+}
+
+func lookupTagOperator(name string) (grammar.MatchOperator, bool) {
+	if op, ok := tagOperators[name]; ok {
+		return op, true
+	}
+	for _, op := range allOperators() {
+		if op.IsCustom() && op.String() == name {
+			return op, true
+		}
+		if strings.ToLower(strings.ReplaceAll(op.String(), " ", "")) == name {
+			return op, true
+		}
+	}
+	return 0, false
+}
+
+// FieldResolver is implemented by values which look up the values that
+// selectors refer to themselves rather than by reflection, such as data
+// models which are loaded lazily, backed by a database or only virtual.
+// Resolve is called with the rest of the selector path once it reaches the
+// value, so the datum or any value within it can be a FieldResolver. It
+// returns false when there is no value at the path, which is treated the
+// same as a missing map key.
+type FieldResolver interface {
+	Resolve(path []string) (value interface{}, ok bool, err error)
+}
+
+// ResolverFunc is a function implementing FieldResolver, such as to wrap a
+// value to be evaluated
+type ResolverFunc func(path []string) (interface{}, bool, error)
+
+// Resolve calls the function
+func (fn ResolverFunc) Resolve(path []string) (interface{}, bool, error) {
+	return fn(path)
+}
+
+var fieldResolverType = reflect.TypeOf((*FieldResolver)(nil)).Elem()
+
+// getValue returns the value at the pointer path within the datum
+func getValue(ptr *pointerstructure.Pointer, datum interface{}, opts *options) (interface{}, error) {
+	val, _, err := lookupValue(ptr, datum, opts, false)
+	return val, err
+}
+
+// lookupValue returns the value at the pointer path within the datum along
+// with the struct field holding it, if the last segment of the path named
+// one. Maps, slices and arrays are indexed by pointerstructure while struct
+// fields are found by getStructInfo, and errors are reported in the same
+// form as by pointerstructure. The rest of the path is resolved by the first
+// FieldResolver reached. When delegate is set the first MatchEvaluator
+// reached is returned as a *delegatedMatch instead.
+func lookupValue(ptr *pointerstructure.Pointer, datum interface{}, opts *options, delegate bool) (interface{}, *structField, error) {
+	var field *structField
+	current := reflect.ValueOf(datum)
+	for i, part := range ptr.Parts {
+		for current.Kind() == reflect.Interface {
+			current = current.Elem()
+		}
+		if delegate {
+			if evaluator, ok := asMatchEvaluator(current); ok {
+				return &delegatedMatch{evaluator: evaluator, path: ptr.Parts[i:]}, nil, nil
+			}
+		}
+		if current.IsValid() && current.Type().Implements(fieldResolverType) && current.CanInterface() {
+			val, ok, err := current.Interface().(FieldResolver).Resolve(ptr.Parts[i:])
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s at part %d: %w", ptr, i, err)
+			}
+			if !ok {
+				return nil, nil, fmt.Errorf("%s at part %d: %w", ptr, i, pointerstructure.ErrNotFound)
+			}
+			return val, nil, nil
+		}
+		for current.Kind() == reflect.Ptr {
+			current = reflect.Indirect(current)
+		}
+		if !current.IsValid() {
+			return nil, nil, fmt.Errorf("%s at part %d: %w", ptr, i, ErrNilValue)
+		}
+
+		field = nil
+		switch current.Kind() {
+		case reflect.Struct:
+			found, err := getStructInfo(current.Type(), opts).field(part)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s at part %d: %w", ptr, i, err)
+			}
+			field = found
+			current = found.value(current)
+		case reflect.Map, reflect.Slice, reflect.Array:
+			step := pointerstructure.Pointer{Parts: []string{part}}
+			val, err := step.Get(current.Interface())
+			if err != nil {
+				// report the error of the step against the whole path
+				if inner := errors.Unwrap(err); inner != nil {
+					err = inner
+				}
+				return nil, nil, fmt.Errorf("%s at part %d: %w", ptr, i, err)
+			}
+			current = reflect.ValueOf(val)
+		default:
+			return nil, nil, fmt.Errorf("%s: at part %d, %w: %s", ptr, i, pointerstructure.ErrInvalidKind, current.Kind())
+		}
+	}
+
+	if !current.IsValid() {
+		return nil, field, nil
+	}
+	if delegate {
+		if evaluator, ok := asMatchEvaluator(current); ok {
+			return &delegatedMatch{evaluator: evaluator}, field, nil
+		}
+	}
+	return current.Interface(), field, nil
+}
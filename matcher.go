@@ -0,0 +1,75 @@
+package bexpr
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// MatchEvaluator is implemented by values which evaluate the match
+// expressions on them and the values within them themselves, such as
+// values backed by a search index or a remote service. EvaluateMatch is
+// called with the rest of the selector path once it reaches the value, so
+// the datum or any value within it can be a MatchEvaluator, along with the
+// operator of the match and its values. The values hold both the raw value
+// of the expression and its converted form, if any, such as the compiled
+// regular expression of matches. They are the elements of the list for in
+// and the bounds for between, while operators such as is empty have none.
+//
+// The context is that of EvaluateContext, so that long running evaluations
+// can be cancelled, and is otherwise the background context. Errors should
+// be an UnsupportedOperatorError for operators the value does not support
+// and a CoercionError for values it cannot compare against, which are
+// handled as for built in values such as by WithDynamicTypes.
+//
+// Matches on the length of a MatchEvaluator with len() are evaluated
+// against the value itself rather than by EvaluateMatch.
+type MatchEvaluator interface {
+	EvaluateMatch(ctx context.Context, selector []string, op grammar.MatchOperator, values []*grammar.MatchValue) (bool, error)
+}
+
+// MatchEvaluatorFunc is a function implementing MatchEvaluator, such as to
+// wrap a value to be evaluated
+type MatchEvaluatorFunc func(ctx context.Context, selector []string, op grammar.MatchOperator, values []*grammar.MatchValue) (bool, error)
+
+// EvaluateMatch calls the function
+func (fn MatchEvaluatorFunc) EvaluateMatch(ctx context.Context, selector []string, op grammar.MatchOperator, values []*grammar.MatchValue) (bool, error) {
+	return fn(ctx, selector, op, values)
+}
+
+var matchEvaluatorType = reflect.TypeOf((*MatchEvaluator)(nil)).Elem()
+
+// delegatedMatch is the MatchEvaluator that a selector reached along with
+// the rest of its path
+type delegatedMatch struct {
+	evaluator MatchEvaluator
+	path      []string
+}
+
+// asMatchEvaluator returns the value as a MatchEvaluator if it is one. Nil
+// pointers are not, so that they are null as any other.
+func asMatchEvaluator(value reflect.Value) (MatchEvaluator, bool) {
+	if !value.IsValid() || !value.Type().Implements(matchEvaluatorType) || !value.CanInterface() {
+		return nil, false
+	}
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil, false
+	}
+	return value.Interface().(MatchEvaluator), true
+}
+
+func (d *delegatedMatch) evaluate(expression *grammar.MatchExpression, opts *options) (bool, error) {
+	ctx := opts.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	values := expression.Values
+	if expression.Value != nil {
+		values = []*grammar.MatchValue{expression.Value}
+	}
+
+	result, err := d.evaluator.EvaluateMatch(ctx, d.path, expression.Operator, values)
+	return dynamicTypeResult(expression, result, err, opts)
+}
@@ -0,0 +1,109 @@
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Operator defines a match operator registered with RegisterOperator
+type Operator struct {
+	// Parse, if set, checks and converts the value given to the operator
+	// when an expression using it is parsed or its parameters are bound, so
+	// that invalid values are reported up front. Values are otherwise
+	// passed to the evaluation functions as strings.
+	Parse func(value string) (interface{}, error)
+
+	// Evaluate holds the function applying the operator to selected values
+	// of each kind, such as reflect.String. Applying the operator to values
+	// of other kinds is an error.
+	Evaluate map[reflect.Kind]OperatorFunc
+}
+
+// OperatorFunc reports whether the selected value matches the value given
+// to the operator in the expression, as returned by Parse if set
+type OperatorFunc func(selected reflect.Value, value interface{}) (bool, error)
+
+// operators holds the Operator of each registered grammar.MatchOperator
+var operators sync.Map
+
+// RegisterOperator adds a match operator applied by the token in
+// expressions of the form: selector <token> value
+// such as: Location near "52.52,13.40"
+// The token must be a single word that is not already part of the syntax.
+//
+// The returned operator can be used wherever the built in ones can, such
+// as with WithMissingResult and the ops option of bexpr tags, and Fields
+// lists it for the values of the kinds it supports. Registering a token
+// again replaces its definition. Operators should be registered before any
+// expressions using them are created, such as from an init function.
+func RegisterOperator(token string, op Operator) (grammar.MatchOperator, error) {
+	if len(op.Evaluate) == 0 {
+		return 0, fmt.Errorf("operator %q has no evaluation functions", token)
+	}
+	matchOp, err := grammar.RegisterMatchOperator(token, op.Parse)
+	if err != nil {
+		return 0, err
+	}
+	operators.Store(matchOp, &op)
+	return matchOp, nil
+}
+
+// getOperator returns the definition of a registered operator
+func getOperator(op grammar.MatchOperator) (*Operator, bool) {
+	def, ok := operators.Load(op)
+	if !ok {
+		return nil, false
+	}
+	return def.(*Operator), true
+}
+
+// builtinOperators returns the built in match operators in the order they
+// are declared
+func builtinOperators() []grammar.MatchOperator {
+	var ops []grammar.MatchOperator
+	for op := grammar.MatchEqual; op <= grammar.MatchNotContainsAll; op++ {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// allOperators returns the built in match operators followed by the
+// registered ones
+func allOperators() []grammar.MatchOperator {
+	return append(builtinOperators(), grammar.CustomMatchOperators()...)
+}
+
+// customOperators returns the registered operators which support values of
+// the kind
+func customOperators(kind reflect.Kind) []grammar.MatchOperator {
+	var ops []grammar.MatchOperator
+	for _, op := range grammar.CustomMatchOperators() {
+		if def, ok := getOperator(op); ok && def.Evaluate[kind] != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+func doMatchCustom(expression *grammar.MatchExpression, value reflect.Value) (bool, error) {
+	def, ok := getOperator(expression.Operator)
+	if !ok {
+		return false, fmt.Errorf("Invalid match operation: %d", expression.Operator)
+	}
+	fn := def.Evaluate[value.Kind()]
+	if fn == nil {
+		return false, operatorError(expression, "Cannot perform %s operations on type %s", expression.Operator, value.Kind())
+	}
+
+	var operand interface{}
+	if expression.Value != nil {
+		operand = expression.Value.Raw
+		if def.Parse != nil {
+			operand = expression.Value.Converted
+		}
+	}
+	return fn(value, operand)
+}
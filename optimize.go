@@ -0,0 +1,174 @@
+package bexpr
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Optimize returns a copy of the evaluator with a simplified expression
+// that gives the same results with less work, which mostly benefits machine
+// generated expressions. Constants are folded, double negations removed,
+// repeated clauses such as `X == 1 and X == 1` evaluated once, and clauses
+// contradicting or completing each other such as `X == 1 and not X == 1`
+// replaced by a constant.
+//
+// Clauses which are optimized away are no longer evaluated so errors they
+// would have returned, such as for a selector that does not exist within
+// the datum, are no longer reported.
+func (eval *Evaluator) Optimize() *Evaluator {
+	if eval.operands != nil {
+		// the operands of combined evaluators are optimized separately as
+		// they may be evaluated with different options
+		optimized, _ := eval.mapOperands(func(operand *Evaluator) (*Evaluator, error) {
+			return operand.Optimize(), nil
+		})
+		return optimized
+	}
+
+	ast := optimizeExpression(eval.ast)
+
+	opts := eval.opts
+	opts.listSets = newListSets(ast)
+
+	return &Evaluator{
+		ast:     ast,
+		opts:    opts,
+		unbound: unboundParameters(ast),
+	}
+}
+
+// optimizeExpression returns the simplified form of the expression. Nodes
+// that change are copied rather than modified as the original expression
+// may be in use by concurrent evaluations.
+func optimizeExpression(ast grammar.Expression) grammar.Expression {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return optimizeNot(optimizeExpression(node.Operand))
+	case *grammar.BinaryExpression:
+		if node.Operator == grammar.BinaryOpXor {
+			return optimizeXor(optimizeExpression(node.Left), optimizeExpression(node.Right))
+		}
+		return optimizeChain(node)
+	case *grammar.QuantifierExpression:
+		body := optimizeExpression(node.Expression)
+		if body == node.Expression {
+			return node
+		}
+		copied := *node
+		copied.Expression = body
+		return &copied
+	default:
+		return ast
+	}
+}
+
+func optimizeNot(operand grammar.Expression) grammar.Expression {
+	switch node := operand.(type) {
+	case *grammar.ConstantExpression:
+		return &grammar.ConstantExpression{Value: !node.Value}
+	case *grammar.UnaryExpression:
+		if node.Operator == grammar.UnaryOpNot {
+			return node.Operand
+		}
+	}
+	return &grammar.UnaryExpression{Operator: grammar.UnaryOpNot, Operand: operand}
+}
+
+func optimizeXor(left, right grammar.Expression) grammar.Expression {
+	lconst, lok := left.(*grammar.ConstantExpression)
+	rconst, rok := right.(*grammar.ConstantExpression)
+	switch {
+	case lok && rok:
+		return &grammar.ConstantExpression{Value: lconst.Value != rconst.Value}
+	case lok && lconst.Value:
+		return optimizeNot(right)
+	case lok:
+		return right
+	case rok && rconst.Value:
+		return optimizeNot(left)
+	case rok:
+		return left
+	}
+
+	switch {
+	case expressionKey(left) == expressionKey(right):
+		return &grammar.ConstantExpression{Value: false}
+	case isNegationOf(left, right) || isNegationOf(right, left):
+		return &grammar.ConstantExpression{Value: true}
+	}
+	return &grammar.BinaryExpression{Left: left, Operator: grammar.BinaryOpXor, Right: right}
+}
+
+// optimizeChain simplifies a chain of and or of or operations, such as
+// a and b and c, as a whole so that repeated and contradicting clauses are
+// found wherever they are within the chain.
+func optimizeChain(node *grammar.BinaryExpression) grammar.Expression {
+	op := node.Operator
+	// the constant which decides the result of the chain on its own, such
+	// as false for and, and the one that has no effect on it
+	absorbing := op == grammar.BinaryOpOr
+
+	var terms []grammar.Expression
+	seen := make(map[string]struct{})
+	var collect func(expr grammar.Expression)
+	collect = func(expr grammar.Expression) {
+		if binary, ok := expr.(*grammar.BinaryExpression); ok && binary.Operator == op {
+			collect(binary.Left)
+			collect(binary.Right)
+			return
+		}
+
+		expr = optimizeExpression(expr)
+		// the optimized term may itself be a chain of the same operator
+		if binary, ok := expr.(*grammar.BinaryExpression); ok && binary.Operator == op {
+			collect(binary.Left)
+			collect(binary.Right)
+			return
+		}
+
+		if constant, ok := expr.(*grammar.ConstantExpression); ok && constant.Value != absorbing {
+			return
+		}
+		key := expressionKey(expr)
+		if _, found := seen[key]; found {
+			return
+		}
+		seen[key] = struct{}{}
+		terms = append(terms, expr)
+	}
+	collect(node)
+
+	for _, term := range terms {
+		if constant, ok := term.(*grammar.ConstantExpression); ok && constant.Value == absorbing {
+			return &grammar.ConstantExpression{Value: absorbing}
+		}
+		// a and not a is false while a or not a is true
+		if unary, ok := term.(*grammar.UnaryExpression); ok && unary.Operator == grammar.UnaryOpNot {
+			if _, found := seen[expressionKey(unary.Operand)]; found {
+				return &grammar.ConstantExpression{Value: absorbing}
+			}
+		}
+	}
+
+	if len(terms) == 0 {
+		return &grammar.ConstantExpression{Value: !absorbing}
+	}
+
+	// rebuild the chain nested to the right as the parser does
+	result := terms[len(terms)-1]
+	for i := len(terms) - 2; i >= 0; i-- {
+		result = &grammar.BinaryExpression{Left: terms[i], Operator: op, Right: result}
+	}
+	return result
+}
+
+func isNegationOf(expr, other grammar.Expression) bool {
+	unary, ok := expr.(*grammar.UnaryExpression)
+	return ok && unary.Operator == grammar.UnaryOpNot && expressionKey(unary.Operand) == expressionKey(other)
+}
+
+// expressionKey identifies equivalent expressions by their canonical form
+func expressionKey(expr grammar.Expression) string {
+	return expr.(fmt.Stringer).String()
+}
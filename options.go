@@ -1,5 +1,12 @@
 package bexpr
 
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
 // getOpts - iterate the inbound Options and return a struct
 func getOpts(opt ...Option) options {
 	opts := getDefaultOptions()
@@ -16,7 +23,51 @@ type Option func(*options)
 
 // options = how options are represented
 type options struct {
-	withMaxExpressions uint64
+	withMaxExpressions      uint64
+	withMaxExpressionLength uint64
+	withMaxSelectorDepth    uint64
+	withMaxExpressionDepth  uint64
+	withMaxMatchExpressions uint64
+	withMaxEvaluationSteps  uint64
+	withMaxEvalDuration     time.Duration
+	withDeniedOperators     map[grammar.MatchOperator]struct{}
+	withDefaultValues       map[string]interface{}
+	withMissingResults      map[grammar.MatchOperator]bool
+	withNilTraversal        NilTraversal
+	withCaseInsensitive     map[string]struct{}
+	withValueSets           map[string][]string
+	withFunctions           map[string]Function
+	withHooks               []Hook
+	withErrorRecovery       bool
+	withCommaAnd            bool
+	withDynamicTypes        bool
+	withStringers           bool
+	withMapKeySelector      string
+	withParallelism         int
+	withFields              []Field
+	withAllowedSelectors    []string
+	withDeniedSelectors     []string
+	withNameTags            []string
+
+	// valueSets holds the named value sets of the Evaluator and listSets
+	// the coerced values of list literals. These are set up by
+	// CreateEvaluator rather than an Option
+	valueSets *valueSets
+	listSets  map[*grammar.MatchExpression]*valueSet
+
+	// scopes holds the evaluators that And, Or and Not combined, keyed by
+	// their expressions, whose options those expressions are evaluated with
+	scopes map[grammar.Expression]*Evaluator
+
+	// ctx is the context of the evaluation in progress when it was started
+	// by EvaluateContext and is nil otherwise, while budget tracks its use
+	// of the evaluation limits if any are set
+	ctx    context.Context
+	budget *budget
+
+	// unknown is set during evaluations by EvaluateTruth, making match
+	// expressions on missing and nil values fail with errUnknown
+	unknown bool
 }
 
 func WithMaxExpressions(maxExprCnt uint64) Option {
@@ -25,6 +76,367 @@ func WithMaxExpressions(maxExprCnt uint64) Option {
 	}
 }
 
+// WithMaxExpressionLength limits the length in bytes of the expression text.
+// Longer expressions are rejected before any parsing takes place. Given to
+// CreateEvaluatorFromJSON it limits the length of the JSON instead.
+func WithMaxExpressionLength(length uint64) Option {
+	return func(o *options) {
+		o.withMaxExpressionLength = length
+	}
+}
+
+// WithMaxSelectorDepth limits the number of path segments in each of the
+// expressions selectors, including those of quantifiers, functions and
+// comparisons.
+func WithMaxSelectorDepth(depth uint64) Option {
+	return func(o *options) {
+		o.withMaxSelectorDepth = depth
+	}
+}
+
+// WithMaxExpressionDepth limits how deeply the expression may nest, where
+// each not, and, or, xor and quantifier adds a level. For example
+// `a == 1 and not (b == 2 or c == 3)` has a depth of 4. A chain of clauses
+// such as `a == 1 and b == 2 and c == 3` adds a level for each operator.
+// Evaluation recurses through the levels so this bounds the stack used.
+func WithMaxExpressionDepth(depth uint64) Option {
+	return func(o *options) {
+		o.withMaxExpressionDepth = depth
+	}
+}
+
+// WithMaxMatchExpressions limits the number of match expressions, such as
+// foo == 3, that the expression may contain.
+func WithMaxMatchExpressions(count uint64) Option {
+	return func(o *options) {
+		o.withMaxMatchExpressions = count
+	}
+}
+
+// WithMaxEvaluationSteps limits the work done by each evaluation to the
+// given number of steps, where evaluating each node of the expression and
+// each value a wildcard selector expands to is a step. Evaluations which
+// exceed it fail with a *BudgetExceededError. This guards against
+// expressions with nested quantifiers over large values.
+func WithMaxEvaluationSteps(steps uint64) Option {
+	return func(o *options) {
+		o.withMaxEvaluationSteps = steps
+	}
+}
+
+// WithMaxEvaluationDuration limits the time each evaluation may take.
+// Evaluations which exceed it fail with a *BudgetExceededError. The time is
+// checked at every step so evaluations overrun by at most one step, which
+// may be long when it calls a slow function.
+func WithMaxEvaluationDuration(d time.Duration) Option {
+	return func(o *options) {
+		o.withMaxEvalDuration = d
+	}
+}
+
+// WithDeniedOperators rejects expressions using any of the given match
+// operators. This can be used to disable features that are expensive to
+// evaluate such as regular expression matching.
+func WithDeniedOperators(ops ...grammar.MatchOperator) Option {
+	return func(o *options) {
+		if o.withDeniedOperators == nil {
+			o.withDeniedOperators = make(map[grammar.MatchOperator]struct{})
+		}
+		for _, op := range ops {
+			o.withDeniedOperators[op] = struct{}{}
+		}
+	}
+}
+
+// WithUntrustedInputLimits applies conservative limits suitable for
+// evaluating expressions received from untrusted sources such as the
+// query parameters of a public API. Options passed after this one will
+// override the individual limits. It sets:
+//
+//	WithMaxExpressionLength(4096)
+//	WithMaxExpressions(250000)
+//	WithMaxSelectorDepth(16)
+//	WithMaxExpressionDepth(128)
+//	WithMaxMatchExpressions(64)
+//	WithMaxEvaluationSteps(100000)
+//	WithMaxEvaluationDuration(100 * time.Millisecond)
+//	WithDeniedOperators(grammar.MatchMatches, grammar.MatchNotMatches)
+func WithUntrustedInputLimits() Option {
+	return func(o *options) {
+		o.withMaxExpressionLength = 4096
+		o.withMaxExpressions = 250000
+		o.withMaxSelectorDepth = 16
+		o.withMaxExpressionDepth = 128
+		o.withMaxMatchExpressions = 64
+		o.withMaxEvaluationSteps = 100000
+		o.withMaxEvalDuration = 100 * time.Millisecond
+		WithDeniedOperators(grammar.MatchMatches, grammar.MatchNotMatches)(o)
+	}
+}
+
+// WithAllowedSelectors rejects expressions using any selector that is not
+// one of the given selectors or within one of them, such as to only expose
+// some fields to the users of a multi-tenant API. The selectors may use
+// either the dotted bexpr syntax or the quoted JSON Pointer syntax and a *
+// segment allows any map key or slice index, as in: Meta.*.name
+// Selectors within quantifiers are checked as the path of the collection
+// iterated followed by a * segment, which only a * segment allows.
+func WithAllowedSelectors(selectors ...string) Option {
+	return func(o *options) {
+		o.withAllowedSelectors = append(o.withAllowedSelectors, selectors...)
+	}
+}
+
+// WithDeniedSelectors rejects expressions using any of the given selectors
+// or a selector within one of them, for example denying Secrets rejects
+// Secrets.token. Selectors are given and checked in the same way as for
+// WithAllowedSelectors except that wildcards within expressions are denied
+// if they could select a denied value. Denied selectors take precedence
+// over allowed ones.
+func WithDeniedSelectors(selectors ...string) Option {
+	return func(o *options) {
+		o.withDeniedSelectors = append(o.withDeniedSelectors, selectors...)
+	}
+}
+
+// WithDefaultValue sets the value to use in place of the value at the
+// given selector when it is missing from the datum being evaluated. A value
+// is considered missing when a map key along the selector path does not
+// exist or when a pointer along the path is nil. The selector may use either
+// the dotted bexpr syntax or the quoted JSON Pointer syntax.
+func WithDefaultValue(selector string, value interface{}) Option {
+	return func(o *options) {
+		if o.withDefaultValues == nil {
+			o.withDefaultValues = make(map[string]interface{})
+		}
+		o.withDefaultValues[selector] = value
+	}
+}
+
+// WithMissingResult sets the result of matches using the given operators
+// when the value at their selector is missing from the datum, rather than
+// failing the evaluation with an error. Values are missing in the same cases
+// as for WithDefaultValue, which takes precedence for its selector. Without
+// any operators the result applies to all of them. By default only the is
+// null and is not null operators match missing values, as null.
+func WithMissingResult(result bool, ops ...grammar.MatchOperator) Option {
+	return func(o *options) {
+		if o.withMissingResults == nil {
+			o.withMissingResults = make(map[grammar.MatchOperator]bool)
+		}
+		if len(ops) == 0 {
+			ops = allOperators()
+		}
+		for _, op := range ops {
+			o.withMissingResults[op] = result
+		}
+	}
+}
+
+// NilTraversal is how WithNilTraversal evaluates matches whose selectors
+// pass through a nil pointer, map or interface, such as Spec.Owner.Name when
+// Spec or Owner is a nil pointer
+type NilTraversal int
+
+const (
+	// NilTraversalError fails the evaluation with an error wrapping
+	// ErrNilValue, which is the default
+	NilTraversalError NilTraversal = iota
+
+	// NilTraversalFalse makes the matches false
+	NilTraversalFalse
+
+	// NilTraversalNegated makes the matches false, other than those with
+	// negated operators such as != and not in which are true, as values
+	// behind nil pointers equal nothing
+	NilTraversalNegated
+)
+
+// WithNilTraversal sets how matches whose selectors pass through a nil
+// pointer, map or interface are evaluated, at any depth of the selector.
+// WithDefaultValue and WithMissingResult take precedence, and the is null
+// and is not null operators treat such values as null regardless.
+func WithNilTraversal(mode NilTraversal) Option {
+	return func(o *options) {
+		o.withNilTraversal = mode
+	}
+}
+
+// WithValueSet registers a named set of values that expressions can
+// reference with the @name syntax, for example: Owner in @admins
+// The set can later be replaced with Evaluator.SetValueSet.
+func WithValueSet(name string, values []string) Option {
+	return func(o *options) {
+		if o.withValueSets == nil {
+			o.withValueSets = make(map[string][]string)
+		}
+		o.withValueSets[name] = values
+	}
+}
+
+// WithFunction registers a function that expressions can call by name,
+// for example: hasPrefix(Node, "web-") Creating an evaluator for an
+// expression that calls an unregistered function fails.
+func WithFunction(name string, fn Function) Option {
+	return func(o *options) {
+		if o.withFunctions == nil {
+			o.withFunctions = make(map[string]Function)
+		}
+		o.withFunctions[name] = fn
+	}
+}
+
+// WithHook registers a hook that is notified of the evaluation of each
+// match expression. Several hooks can be registered and are called in the
+// order they were given.
+func WithHook(hook Hook) Option {
+	return func(o *options) {
+		o.withHooks = append(o.withHooks, hook)
+	}
+}
+
+// WithErrorRecovery makes CreateEvaluator continue parsing past syntax
+// errors so that all of them can be reported at once, such as by a user
+// interface for writing expressions. When there are syntax errors the
+// returned error is a Diagnostics holding each of them.
+func WithErrorRecovery() Option {
+	return func(o *options) {
+		o.withErrorRecovery = true
+	}
+}
+
+// WithCommaAnd makes CreateEvaluator accept commas between the top level
+// clauses of an expression as shorthand for and, as in label selectors:
+//
+//	Status == passing, Meta.env == prod
+//
+// The comma binds more loosely than or so that each comma separated clause
+// must match on its own.
+func WithCommaAnd() Option {
+	return func(o *options) {
+		o.withCommaAnd = true
+	}
+}
+
+// WithDynamicTypes suits data whose types are only known during evaluation
+// and vary between values, such as JSON documents decoded into
+// map[string]interface{}. Rather than failing the evaluation, a match whose
+// value cannot be converted to the type of the selected value, or whose
+// operator cannot be applied to it, does not match, while its negated form
+// such as != does. Validate does not report these mismatches either.
+func WithDynamicTypes() Option {
+	return func(o *options) {
+		o.withDynamicTypes = true
+	}
+}
+
+// WithStringers makes selected structs whose types implement fmt.Stringer,
+// and that are otherwise only null or not, compare as the output of their
+// String method, such as for version or semantic types. They support the
+// same operators as strings, such as:
+//
+//	Version == "1.2.3"
+//	"beta" in Version
+//
+// Fields and JSONSchema describe them as strings.
+func WithStringers() Option {
+	return func(o *options) {
+		o.withStringers = true
+	}
+}
+
+// WithMapKeySelector sets the name of the virtual selector that refers to
+// the key of each entry filtered by Evaluator.FilterMap. The default is
+// "key". The name shadows any field or map key of the same name within the
+// filtered values.
+func WithMapKeySelector(name string) Option {
+	return func(o *options) {
+		o.withMapKeySelector = name
+	}
+}
+
+// WithParallelism makes Evaluator.FilterSlice split slices and arrays into
+// as many shards as there are workers and evaluate the shards concurrently,
+// such as to use every CPU to filter millions of elements. The matching
+// elements are still returned in their original order. Any hooks and
+// functions used by the expression must be safe for concurrent use.
+func WithParallelism(workers int) Option {
+	return func(o *options) {
+		o.withParallelism = workers
+	}
+}
+
+// WithFields restricts expressions to selecting the given fields, such as
+// those listed by Fields for a type or read by LoadFields, and to using the
+// operators of each field. Where the type of a field is given the values of
+// the expression are checked to be convertible to it. Selectors within
+// quantifiers are checked as the path of the collection iterated followed
+// by FieldAny. Aliases of the fields are replaced by their paths.
+func WithFields(fields ...Field) Option {
+	return func(o *options) {
+		o.withFields = append(o.withFields, fields...)
+	}
+}
+
+// WithJSONTagNames selects struct fields without a name in their bexpr tag
+// by the name in their json tag, so that expressions use the same names as
+// the JSON encoding of the values. Fields tagged json:"-" cannot be
+// selected unless they have a bexpr tag.
+func WithJSONTagNames() Option {
+	return func(o *options) {
+		o.withNameTags = append(o.withNameTags, "json")
+	}
+}
+
+// WithMapstructureTagNames selects struct fields without a name in their
+// bexpr tag by the name in their mapstructure tag, such as for values
+// decoded from configuration files. It can be combined with
+// WithJSONTagNames, in which case the tags are tried in the order the
+// options are given.
+func WithMapstructureTagNames() Option {
+	return func(o *options) {
+		o.withNameTags = append(o.withNameTags, "mapstructure")
+	}
+}
+
+// WithProtobufTagNames selects the struct fields of messages generated by
+// protoc-gen-go by the names of the fields in the .proto file, such as
+// display_name, as given by their protobuf tags. The fields of a oneof are
+// selected within the name of the oneof. Repeated and map fields are
+// selected as slices and maps. The well known Timestamp and Duration types
+// compare as times and durations, such as: created > "2024-01-01T00:00:00Z",
+// while their own fields can still be selected: created.seconds > 1700000000
+func WithProtobufTagNames() Option {
+	return func(o *options) {
+		o.withNameTags = append(o.withNameTags, protobufTag)
+	}
+}
+
+// WithProtobufJSONNames selects the struct fields of messages generated by
+// protoc-gen-go by their JSON names, such as displayName, as used by the
+// JSON encoding of the messages. It otherwise works as WithProtobufTagNames.
+func WithProtobufJSONNames() Option {
+	return func(o *options) {
+		o.withNameTags = append(o.withNameTags, protobufJSONTag)
+	}
+}
+
+// WithCaseInsensitive makes the equality and in/contains operators compare
+// the string values at the given selectors case insensitively. The selectors
+// may use either the dotted bexpr syntax or the quoted JSON Pointer syntax.
+// Struct fields can instead be marked by the fold option of their bexpr
+// tag, such as: bexpr:"name,fold"
+func WithCaseInsensitive(selectors ...string) Option {
+	return func(o *options) {
+		if o.withCaseInsensitive == nil {
+			o.withCaseInsensitive = make(map[string]struct{})
+		}
+		for _, selector := range selectors {
+			o.withCaseInsensitive[selector] = struct{}{}
+		}
+	}
+}
+
 func getDefaultOptions() options {
 	return options{
 		withMaxExpressions: 0,
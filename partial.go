@@ -0,0 +1,110 @@
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+	"github.com/mitchellh/pointerstructure"
+)
+
+// errSelectorNotFound stops the search for selectors missing from the datum
+var errSelectorNotFound = errors.New("selector not found")
+
+// PartialEvaluate evaluates the clauses of the expression whose selectors
+// can all be found within the datum, which may hold only some of the values
+// the expression refers to. It returns an evaluator for the residual
+// expression made of the remaining clauses, simplified as by Optimize. When
+// the datum decides the result on its own the residual expression is the
+// constant true or false.
+//
+// This allows part of a filter to be applied by a datastore, which fetches
+// the candidates that the residual evaluator is then used on.
+func (eval *Evaluator) PartialEvaluate(datum interface{}) (*Evaluator, error) {
+	if len(eval.unbound) > 0 {
+		return nil, fmt.Errorf("parameter $%s is not bound", eval.unbound[0])
+	}
+	if eval.operands != nil {
+		return eval.mapOperands(func(operand *Evaluator) (*Evaluator, error) {
+			return operand.PartialEvaluate(datum)
+		})
+	}
+
+	ast, err := partialEvaluate(eval.ast, datum, &eval.opts)
+	if err != nil {
+		return nil, err
+	}
+	ast = optimizeExpression(ast)
+
+	opts := eval.opts
+	opts.listSets = newListSets(ast)
+
+	return &Evaluator{
+		ast:  ast,
+		opts: opts,
+	}, nil
+}
+
+// partialEvaluate replaces the clauses of the expression which can be
+// evaluated against the datum with their results. The original expression
+// is not modified.
+func partialEvaluate(ast grammar.Expression, datum interface{}, opts *options) (grammar.Expression, error) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand, err := partialEvaluate(node.Operand, datum, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: operand}, nil
+	case *grammar.BinaryExpression:
+		left, err := partialEvaluate(node.Left, datum, opts)
+		if err != nil {
+			return nil, err
+		}
+		right, err := partialEvaluate(node.Right, datum, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.BinaryExpression{Left: left, Operator: node.Operator, Right: right}, nil
+	case *grammar.ConstantExpression:
+		return node, nil
+	}
+
+	err := walkSelectors(ast, func(sel grammar.Selector, _ *grammar.MatchExpression) error {
+		if !selectorFound(sel, datum, opts) {
+			return errSelectorNotFound
+		}
+		return nil
+	})
+	if err == errSelectorNotFound {
+		return ast, nil
+	}
+
+	result, err := evaluate(ast, datum, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &grammar.ConstantExpression{Value: result}, nil
+}
+
+// selectorFound reports whether the value at the selector is present in
+// the datum. For wildcard selectors only the part before the first wildcard
+// has to be present.
+func selectorFound(sel grammar.Selector, datum interface{}, opts *options) bool {
+	path := sel.Path
+	if sel.HasWildcard() {
+		path = path[:sel.Wildcards[0]]
+	}
+
+	ptr := pointerstructure.Pointer{
+		Parts: path,
+	}
+	parts, err := resolveRelativeIndexes(&ptr, datum, opts)
+	if err != nil {
+		return false
+	}
+	ptr.Parts = parts
+
+	_, err = getValue(&ptr, datum, opts)
+	return err == nil
+}
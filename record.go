@@ -0,0 +1,109 @@
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RecordSchema names and types the columns of records given as rows of
+// strings, such as the lines of CSV or TSV files, so that expressions can
+// select the values of a row by the names of its columns. See Record.
+type RecordSchema struct {
+	columns []string
+	types   []reflect.Type
+	index   map[string]int
+}
+
+var stringType = reflect.TypeOf("")
+
+// NewRecordSchema creates a schema for records with the columns of the
+// header, in order, such as the first line of a CSV file. The values of a
+// column are strings unless types gives the type to convert them to, which
+// must be a boolean, number or string type. Empty values of the columns of
+// other types than strings are null.
+func NewRecordSchema(header []string, types map[string]reflect.Type) (*RecordSchema, error) {
+	schema := &RecordSchema{
+		columns: header,
+		types:   make([]reflect.Type, len(header)),
+		index:   make(map[string]int, len(header)),
+	}
+	for i, name := range header {
+		if _, ok := schema.index[name]; ok {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		schema.index[name] = i
+		schema.types[i] = stringType
+	}
+
+	for name, rtype := range types {
+		i, ok := schema.index[name]
+		if !ok {
+			return nil, fmt.Errorf("type given for column %q which is not in the header", name)
+		}
+		if rtype == nil {
+			return nil, fmt.Errorf("column %q has no type", name)
+		}
+		if _, _, ok := primitiveValue(reflect.Zero(rtype)); !ok {
+			return nil, fmt.Errorf("column %q cannot have type %s", name, rtype)
+		}
+		schema.types[i] = rtype
+	}
+	return schema, nil
+}
+
+// Fields returns a field for each column, such as to check the selectors
+// and values of expressions against the schema with WithFields
+func (s *RecordSchema) Fields() []Field {
+	fields := make([]Field, 0, len(s.columns))
+	for i, name := range s.columns {
+		rtype := s.types[i]
+		if rtype.Kind() != reflect.String {
+			// empty values are null
+			rtype = reflect.PtrTo(rtype)
+		}
+		fields = append(fields, Field{
+			Path:      []string{name},
+			Type:      rtype,
+			Operators: fieldOperators(rtype),
+		})
+	}
+	return fields
+}
+
+// Record returns the row as a value to evaluate expressions against. The
+// row must have a value for every column of the schema. Values are
+// converted to the types of their columns as they are selected.
+func (s *RecordSchema) Record(row []string) *Record {
+	return &Record{schema: s, row: row}
+}
+
+// Record is a row of values named by a RecordSchema
+type Record struct {
+	schema *RecordSchema
+	row    []string
+}
+
+// Resolve implements FieldResolver to select the values of the record by
+// the names of their columns
+func (r *Record) Resolve(path []string) (interface{}, bool, error) {
+	i, ok := r.schema.index[path[0]]
+	if !ok || len(path) > 1 {
+		return nil, false, nil
+	}
+	if i >= len(r.row) {
+		return nil, false, fmt.Errorf("record has %d values but the schema has %d columns", len(r.row), len(r.schema.columns))
+	}
+
+	raw, rtype := r.row[i], r.schema.types[i]
+	if rtype.Kind() == reflect.String {
+		return reflect.ValueOf(raw).Convert(rtype).Interface(), true, nil
+	}
+	if raw == "" {
+		return nil, true, nil
+	}
+	value, err := coerceValue(raw, rtype)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid value %q in column %q: %w", raw, path[0], err)
+	}
+	return value, true, nil
+}
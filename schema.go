@@ -0,0 +1,125 @@
+package bexpr
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// jsonSchemaDialect is the version of JSON Schema that JSONSchema produces
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema describes the values of the given type that expressions can
+// select as a JSON Schema, such as to generate a query builder for the type
+// in a front end. The schema of each field selectable by an expression has
+// an "x-bexpr-operators" keyword listing the symbols of the operators the
+// field supports, as listed by Fields, and integer fields holding durations
+// have an "x-bexpr-format" keyword of "duration". Fields of struct types that refer to
+// themselves are described only once along each path. Struct fields are
+// named according to the options as for Fields.
+func JSONSchema(dataType interface{}, opts ...Option) ([]byte, error) {
+	parsedOpts := getOpts(opts...)
+	schema := typeSchema(reflect.TypeOf(dataType), make(map[reflect.Type]bool), &parsedOpts)
+	schema["$schema"] = jsonSchemaDialect
+	return json.Marshal(schema)
+}
+
+func typeSchema(rtype reflect.Type, visiting map[reflect.Type]bool, opts *options) map[string]interface{} {
+	schema := make(map[string]interface{})
+	if rtype == nil {
+		return schema
+	}
+
+	elem := derefType(rtype)
+	if elem == ipTyp {
+		schema["type"] = "string"
+		schema["format"] = "ip"
+		return schema
+	}
+	if elem == timeTyp {
+		schema["type"] = "string"
+		schema["format"] = "date-time"
+		return schema
+	}
+	if isComparerType(elem) {
+		// the encoding of values which compare themselves is unknown
+		return schema
+	}
+	if elem == bigIntTyp {
+		// encoding/json writes big.Int values as numbers and big.Float
+		// values as strings
+		schema["type"] = "integer"
+		return schema
+	}
+	if elem == jsonNumberTyp {
+		schema["type"] = "number"
+		return schema
+	}
+	if isUUIDType(elem) {
+		schema["type"] = "string"
+		schema["format"] = "uuid"
+		return schema
+	}
+	if (isTextType(elem) && elem.Kind() == reflect.Struct) || (opts.withStringers && isStringerType(elem)) {
+		schema["type"] = "string"
+		return schema
+	}
+
+	switch elem.Kind() {
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Map:
+		schema["type"] = "object"
+		schema["additionalProperties"] = fieldSchema(elem.Elem(), nil, visiting, opts)
+	case reflect.Slice, reflect.Array:
+		if elem.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes byte slices as base64 strings
+			schema["type"] = "string"
+			schema["contentEncoding"] = "base64"
+			break
+		}
+		schema["type"] = "array"
+		schema["items"] = fieldSchema(elem.Elem(), nil, visiting, opts)
+	case reflect.Struct:
+		schema["type"] = "object"
+		if visiting[elem] {
+			break
+		}
+		visiting[elem] = true
+		defer delete(visiting, elem)
+
+		properties := make(map[string]interface{})
+		for _, field := range getStructInfo(elem, opts).fields {
+			if field.err == nil {
+				properties[field.name] = fieldSchema(field.typ, field, visiting, opts)
+			}
+		}
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+	}
+	return schema
+}
+
+// fieldSchema describes a value within the datum, held by the struct field
+// if any, along with the operators that expressions can apply to it
+func fieldSchema(rtype reflect.Type, field *structField, visiting map[reflect.Type]bool, opts *options) map[string]interface{} {
+	schema := typeSchema(rtype, visiting, opts)
+	if derefType(rtype) == durationTyp || (field != nil && field.duration) {
+		// values can be given as durations such as 1h30m
+		schema["x-bexpr-format"] = "duration"
+	}
+	if ops := allowedOperators(rtype, field, opts); len(ops) > 0 {
+		symbols := make([]string, 0, len(ops))
+		for _, op := range ops {
+			symbols = append(symbols, op.Symbol())
+		}
+		schema["x-bexpr-operators"] = symbols
+	}
+	return schema
+}
@@ -0,0 +1,38 @@
+package bexpr
+
+import (
+	"reflect"
+	"strings"
+)
+
+// sqlNullType returns the type of the value held by one of the Null types
+// of database/sql, such as sql.NullString or sql.Null[T], which hold a
+// value along with whether it is valid, such as when scanned from a
+// nullable column. It returns false for any other type.
+func sqlNullType(rtype reflect.Type) (reflect.Type, bool) {
+	if rtype.Kind() != reflect.Struct || rtype.PkgPath() != "database/sql" || !strings.HasPrefix(rtype.Name(), "Null") {
+		return nil, false
+	}
+	if rtype.NumField() != 2 || rtype.Field(1).Name != "Valid" || rtype.Field(1).Type.Kind() != reflect.Bool {
+		return nil, false
+	}
+	return rtype.Field(0).Type, true
+}
+
+// sqlNullValue returns the value held by a database/sql Null value, or a
+// pointer to one, when it is valid and nil when it is not, so that it is
+// compared as its value and is null when it is not valid. It returns any
+// other value as is.
+func sqlNullValue(val interface{}) interface{} {
+	rvalue := reflect.Indirect(reflect.ValueOf(val))
+	if !rvalue.IsValid() {
+		return val
+	}
+	if _, ok := sqlNullType(rvalue.Type()); !ok {
+		return val
+	}
+	if !rvalue.Field(1).Bool() {
+		return nil
+	}
+	return rvalue.Field(0).Interface()
+}
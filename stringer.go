@@ -0,0 +1,43 @@
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var stringerTyp = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// isStringerType reports whether values of the type, or pointers to them,
+// are compared as the output of their String method with WithStringers.
+// Only structs are, as other kinds are compared as they are, while
+// timestamps, types that unmarshal values of expressions and comparers are
+// compared as such.
+func isStringerType(rtype reflect.Type) bool {
+	elem := derefType(rtype)
+	return elem.Kind() == reflect.Struct && elem != timeTyp && !isTextType(elem) && !isComparerType(elem) &&
+		reflect.PtrTo(elem).Implements(stringerTyp)
+}
+
+// stringerMatchValue returns the output of the String method of a struct
+// value, or a pointer to one, for which isStringerType holds and any other
+// value as is
+func stringerMatchValue(val interface{}, opts *options) interface{} {
+	if !opts.withStringers {
+		return val
+	}
+	rvalue := reflect.ValueOf(val)
+	if !rvalue.IsValid() || !isStringerType(rvalue.Type()) {
+		return val
+	}
+	if rvalue.Kind() == reflect.Ptr {
+		if rvalue.IsNil() {
+			return val
+		}
+		rvalue = rvalue.Elem()
+	}
+
+	// a copy is addressable, so that String can have a pointer receiver
+	ptr := reflect.New(rvalue.Type())
+	ptr.Elem().Set(rvalue)
+	return ptr.Interface().(fmt.Stringer).String()
+}
@@ -0,0 +1,93 @@
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mitchellh/pointerstructure"
+)
+
+// selectorSuggestion looks for the struct field which could not be found
+// along the pointer path and returns a hint naming the closest matching
+// field, such as: , did you mean "Node"? When there is no struct field
+// that is similar enough the empty string is returned.
+func selectorSuggestion(ptr *pointerstructure.Pointer, datum interface{}, opts *options) string {
+	for i, part := range ptr.Parts {
+		parent := pointerstructure.Pointer{Parts: ptr.Parts[:i]}
+		val, err := getValue(&parent, datum, opts)
+		if err != nil {
+			return ""
+		}
+
+		rvalue := reflect.ValueOf(val)
+		for rvalue.Kind() == reflect.Ptr || rvalue.Kind() == reflect.Interface {
+			rvalue = rvalue.Elem()
+		}
+		if rvalue.Kind() != reflect.Struct {
+			continue
+		}
+
+		names := getStructInfo(rvalue.Type(), opts).names()
+		if _, found := names[part]; found {
+			continue
+		}
+
+		return nameSuggestion(part, names)
+	}
+	return ""
+}
+
+// nameSuggestion returns a hint naming the closest of the names to part or
+// the empty string when none of them is similar enough
+func nameSuggestion(part string, names map[string]struct{}) string {
+	best, bestDistance := "", -1
+	for name := range names {
+		distance := editDistance(part, name)
+		if bestDistance < 0 || distance < bestDistance || (distance == bestDistance && name < best) {
+			best, bestDistance = name, distance
+		}
+	}
+	// only suggest names that differ by a few edits relative to their length
+	if bestDistance < 0 || bestDistance >= len(part) || bestDistance > 1+len(part)/4 {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %q?", best)
+}
+
+// editDistance returns the number of single character insertions,
+// deletions, substitutions and transpositions of adjacent characters
+// needed to turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(first int, rest ...int) int {
+	for _, v := range rest {
+		if v < first {
+			first = v
+		}
+	}
+	return first
+}
@@ -0,0 +1,169 @@
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Truth is the result of EvaluateTruth, which may be unknown as well as
+// true or false
+type Truth int
+
+const (
+	TruthFalse Truth = iota
+	TruthTrue
+	TruthUnknown
+)
+
+func (t Truth) String() string {
+	switch t {
+	case TruthFalse:
+		return "false"
+	case TruthTrue:
+		return "true"
+	case TruthUnknown:
+		return "unknown"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func truthOf(result bool) Truth {
+	if result {
+		return TruthTrue
+	}
+	return TruthFalse
+}
+
+// errUnknown is returned by match expressions on missing and nil values
+// during evaluations by EvaluateTruth
+var errUnknown = errors.New("value is unknown")
+
+// EvaluateTruth evaluates the expression against the datum with the three
+// valued logic of SQL, as some policy engines require. Rather than being an
+// error, a match expression on a value that is missing or nil is unknown,
+// unless its operator tests for null or emptiness or WithDefaultValue or
+// WithMissingResult gives it a result. Unknown propagates as follows:
+//
+//	not unknown is unknown
+//	false and unknown is false, while true and unknown is unknown
+//	true or unknown is true, while false or unknown is unknown
+//	true xor unknown and false xor unknown are unknown
+//
+// Quantifiers are unknown when the collection they iterate is missing. Any
+// is true when the expression is true for some element and otherwise
+// unknown when it is unknown for some element, while all is false when it
+// is false for some element and otherwise unknown when it is unknown for
+// some element. Missing values passed to functions or used in arithmetic
+// comparisons are still errors.
+func (eval *Evaluator) EvaluateTruth(datum interface{}) (Truth, error) {
+	if len(eval.unbound) > 0 {
+		return TruthUnknown, fmt.Errorf("parameter $%s is not bound", eval.unbound[0])
+	}
+
+	scratch := scratchOptions.Get().(*options)
+	defer func() {
+		*scratch = options{}
+		scratchOptions.Put(scratch)
+	}()
+	*scratch = eval.opts
+	scratch.budget = newBudget(scratch)
+	scratch.unknown = true
+	return evaluateTruth(eval.ast, datum, scratch)
+}
+
+func evaluateTruth(ast grammar.Expression, datum interface{}, opts *options) (Truth, error) {
+	opts = scope(ast, opts)
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		if err := step(opts); err != nil {
+			return TruthUnknown, err
+		}
+		operand, err := evaluateTruth(node.Operand, datum, opts)
+		if err != nil || operand == TruthUnknown {
+			return operand, err
+		}
+		return truthOf(operand == TruthFalse), nil
+
+	case *grammar.BinaryExpression:
+		if err := step(opts); err != nil {
+			return TruthUnknown, err
+		}
+		left, err := evaluateTruth(node.Left, datum, opts)
+		if err != nil {
+			return TruthUnknown, err
+		}
+		switch {
+		case node.Operator == grammar.BinaryOpAnd && left == TruthFalse,
+			node.Operator == grammar.BinaryOpOr && left == TruthTrue:
+			return left, nil
+		}
+
+		right, err := evaluateTruth(node.Right, datum, opts)
+		if err != nil {
+			return TruthUnknown, err
+		}
+		switch {
+		case left == TruthUnknown && node.Operator == grammar.BinaryOpAnd && right == TruthFalse,
+			left == TruthUnknown && node.Operator == grammar.BinaryOpOr && right == TruthTrue:
+			return right, nil
+		case left == TruthUnknown || right == TruthUnknown:
+			return TruthUnknown, nil
+		case node.Operator == grammar.BinaryOpXor:
+			return truthOf(left != right), nil
+		}
+		return right, nil
+
+	case *grammar.QuantifierExpression:
+		if err := step(opts); err != nil {
+			return TruthUnknown, err
+		}
+		return evaluateQuantifierTruth(node, datum, opts)
+	}
+
+	result, err := evaluate(ast, datum, opts)
+	if errors.Is(err, errUnknown) {
+		return TruthUnknown, nil
+	}
+	if err != nil {
+		return TruthUnknown, err
+	}
+	return truthOf(result), nil
+}
+
+func evaluateQuantifierTruth(node *grammar.QuantifierExpression, datum interface{}, opts *options) (Truth, error) {
+	val, err := getSelectorValue(node.Selector, datum, opts)
+	if isMissingValue(err) {
+		return TruthUnknown, nil
+	}
+	if err != nil {
+		return TruthUnknown, err
+	}
+	elements, err := quantifierElements(node, val)
+	if err != nil {
+		return TruthUnknown, err
+	}
+
+	// any stops at the first element that is true and all at the first
+	// that is false, otherwise a single unknown element makes it unknown
+	decisive := truthOf(node.Quantifier == grammar.QuantifierAny)
+	result := truthOf(node.Quantifier == grammar.QuantifierAll)
+	for _, element := range elements {
+		if err := checkContext(opts); err != nil {
+			return TruthUnknown, err
+		}
+		truth, err := evaluateTruth(node.Expression, &binding{variable: node.Variable, value: element.Interface(), parent: datum}, opts)
+		if err != nil {
+			return TruthUnknown, err
+		}
+		if truth == decisive {
+			return truth, nil
+		}
+		if truth == TruthUnknown {
+			result = TruthUnknown
+		}
+	}
+	return result, nil
+}
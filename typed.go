@@ -0,0 +1,117 @@
+//go:build go1.21
+
+package bexpr
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// TypedEvaluator evaluates an expression against values of a single type.
+// The selectors of the expression are checked against the type when it is
+// created so that misspelled struct fields are reported up front rather
+// than on every evaluation.
+type TypedEvaluator[T any] struct {
+	eval *Evaluator
+}
+
+// CreateTypedEvaluator creates an evaluator for values of type T. It
+// returns an error if a selector names a struct field that T does not
+// have. Selectors into maps, slices and interfaces can only be checked
+// during evaluation.
+func CreateTypedEvaluator[T any](expression string, opts ...Option) (*TypedEvaluator[T], error) {
+	eval, err := CreateEvaluator(expression, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rtype := reflect.TypeOf((*T)(nil)).Elem()
+	err = walkSelectors(eval.ast, func(sel grammar.Selector, match *grammar.MatchExpression) error {
+		_, field, err := selectorType(sel, rtype, &eval.opts)
+		if err != nil || match == nil {
+			return err
+		}
+		return field.checkOperator(match)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TypedEvaluator[T]{eval: eval}, nil
+}
+
+// Evaluate evaluates the expression against the datum
+func (e *TypedEvaluator[T]) Evaluate(datum T) (bool, error) {
+	return e.eval.Evaluate(datum)
+}
+
+// EvaluateContext evaluates the expression against the datum, stopping early
+// once the context is done. See Evaluator.EvaluateContext.
+func (e *TypedEvaluator[T]) EvaluateContext(ctx context.Context, datum T) (bool, error) {
+	return e.eval.EvaluateContext(ctx, datum)
+}
+
+// Filter returns a new slice holding the elements of data that match the
+// expression, in their original order.
+func (e *TypedEvaluator[T]) Filter(data []T) ([]T, error) {
+	matching := make([]T, 0, len(data))
+	for _, datum := range data {
+		result, err := e.eval.Evaluate(datum)
+		if err != nil {
+			return nil, err
+		}
+		if result {
+			matching = append(matching, datum)
+		}
+	}
+	return matching, nil
+}
+
+// FilterChan sends the values received from in that match the expression to
+// the returned channel, in the order they were received, such as to filter
+// a stream of events. Values are only received once the previous match has
+// been sent so that slow consumers hold back the producer.
+//
+// Both returned channels are closed once in is closed, the context is done
+// or evaluating a value fails. The error channel then receives the error of
+// the context or the evaluation, if any, so it should be read after the
+// value channel is drained.
+func (e *TypedEvaluator[T]) FilterChan(ctx context.Context, in <-chan T) (<-chan T, <-chan error) {
+	out := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(out)
+		for {
+			var datum T
+			var ok bool
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case datum, ok = <-in:
+				if !ok {
+					return
+				}
+			}
+
+			result, err := e.eval.EvaluateContext(ctx, datum)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !result {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case out <- datum:
+			}
+		}
+	}()
+	return out, errs
+}
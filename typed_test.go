@@ -0,0 +1,134 @@
+//go:build go1.21
+
+package bexpr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type typedNode struct {
+	Name   string
+	Port   int `bexpr:"port"`
+	Meta   map[string]string
+	Checks []*typedCheck
+	Parent *typedNode
+}
+
+type typedCheck struct {
+	Status string
+}
+
+func TestCreateTypedEvaluator(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateTypedEvaluator[typedNode](`Name matches "^web-" and port > 1024`)
+	require.NoError(t, err)
+
+	nodes := []typedNode{
+		{Name: "web-01", Port: 8080},
+		{Name: "db-01", Port: 5432},
+		{Name: "web-02", Port: 80},
+	}
+
+	match, err := eval.Evaluate(nodes[0])
+	require.NoError(t, err)
+	require.True(t, match)
+
+	matching, err := eval.Filter(nodes)
+	require.NoError(t, err)
+	require.Equal(t, []typedNode{{Name: "web-01", Port: 8080}}, matching)
+
+	matching, err = eval.Filter(nil)
+	require.NoError(t, err)
+	require.Empty(t, matching)
+}
+
+func TestCreateTypedEvaluator_Selectors(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"Map Keys":          {expression: "Meta.anything == 1"},
+		"Quantifier":        {expression: "any(Checks, c -> c.Status == passing)"},
+		"Nested Pointer":    {expression: "Parent.Parent.port == 80"},
+		"Comparison":        {expression: "port * 2 > 1024"},
+		"Unknown Field":     {expression: "Nmae == web", err: `invalid selector "Nmae": type bexpr.typedNode has no field "Nmae", did you mean "Name"?`},
+		"Untagged Name":     {expression: "Port == 80", err: `invalid selector "Port": type bexpr.typedNode has no field "Port", did you mean "port"?`},
+		"Nested Unknown":    {expression: "Parent.Mta is empty", err: `invalid selector "Parent.Mta": type bexpr.typedNode has no field "Mta", did you mean "Meta"?`},
+		"Quantifier Source": {expression: "all(Chekcs, c -> c.Status == passing)", err: `invalid selector "Chekcs": type bexpr.typedNode has no field "Chekcs", did you mean "Checks"?`},
+		"Arithmetic":        {expression: "prt + 1 > 2", err: `invalid selector "prt": type bexpr.typedNode has no field "prt", did you mean "port"?`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateTypedEvaluator[*typedNode](tcase.expression)
+			if tcase.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tcase.err)
+		})
+	}
+}
+
+func TestCreateTypedEvaluator_FieldResolver(t *testing.T) {
+	t.Parallel()
+
+	// the selectors of resolvers are only known during evaluation
+	eval, err := CreateTypedEvaluator[*lazyRecord]("Name == web and Tags is not empty")
+	require.NoError(t, err)
+	result, err := eval.Evaluate(new(lazyRecord))
+	require.NoError(t, err)
+	require.True(t, result)
+}
+
+func TestTypedEvaluator_FilterChan(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateTypedEvaluator[typedNode](`Name matches "^web-"`)
+	require.NoError(t, err)
+
+	in := make(chan typedNode)
+	go func() {
+		defer close(in)
+		for _, name := range []string{"web-01", "db-01", "web-02"} {
+			in <- typedNode{Name: name}
+		}
+	}()
+
+	out, errs := eval.FilterChan(context.Background(), in)
+	var names []string
+	for node := range out {
+		names = append(names, node.Name)
+	}
+	require.Equal(t, []string{"web-01", "web-02"}, names)
+	require.NoError(t, <-errs)
+
+	// cancelling stops the filter while it waits for values
+	ctx, cancel := context.WithCancel(context.Background())
+	out, errs = eval.FilterChan(ctx, make(chan typedNode))
+	cancel()
+	_, ok := <-out
+	require.False(t, ok)
+	require.Equal(t, context.Canceled, <-errs)
+
+	// evaluation errors end the stream
+	eval, err = CreateTypedEvaluator[typedNode](`Parent.Name == web`)
+	require.NoError(t, err)
+	in = make(chan typedNode, 1)
+	in <- typedNode{}
+	out, errs = eval.FilterChan(context.Background(), in)
+	_, ok = <-out
+	require.False(t, ok)
+	require.Error(t, <-errs)
+}
@@ -0,0 +1,72 @@
+package bexpr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// uuidTypes holds the types registered with RegisterUUIDType
+var uuidTypes sync.Map
+
+// RegisterUUIDType makes values of the type, which must be an array of 16
+// bytes such as uuid.UUID of github.com/google/uuid, compare equal to the
+// string forms of the same UUID. Values of expressions may be written in
+// either case, with or without hyphens and with or without braces or a
+// urn:uuid: prefix, such as:
+//
+//	ID == "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+//	ID in ["{F47AC10B58CC4372A5670E02B2C3D479}", @known]
+//
+// Types should be registered before any expressions are evaluated against
+// them, such as from an init function.
+func RegisterUUIDType(rtype reflect.Type) error {
+	if rtype == nil || rtype.Kind() != reflect.Array || rtype.Len() != 16 || rtype.Elem().Kind() != reflect.Uint8 {
+		return fmt.Errorf("cannot register type %s as a UUID type: must be an array of 16 bytes", rtype)
+	}
+	uuidTypes.Store(rtype, struct{}{})
+	return nil
+}
+
+func isUUIDType(rtype reflect.Type) bool {
+	_, ok := uuidTypes.Load(rtype)
+	return ok
+}
+
+// uuidValue is the form that values of registered UUID types are compared
+// in, as the canonical lower case and hyphenated string
+type uuidValue string
+
+var uuidValueTyp = reflect.TypeOf(uuidValue(""))
+
+// uuidMatchValue returns a value of a registered UUID type, or a pointer to
+// one, as a uuidValue and any other value as is
+func uuidMatchValue(val interface{}) interface{} {
+	rvalue := reflect.Indirect(reflect.ValueOf(val))
+	if !rvalue.IsValid() || !isUUIDType(rvalue.Type()) {
+		return val
+	}
+
+	var id [16]byte
+	reflect.Copy(reflect.ValueOf(id[:]), rvalue)
+	return uuidValue(formatUUID(id[:]))
+}
+
+func coerceUUIDValue(value string) (interface{}, error) {
+	raw := strings.TrimPrefix(strings.ToLower(value), "urn:uuid:")
+	if strings.HasPrefix(raw, "{") && strings.HasSuffix(raw, "}") {
+		raw = raw[1 : len(raw)-1]
+	}
+	id, err := hex.DecodeString(strings.ReplaceAll(raw, "-", ""))
+	if err != nil || len(id) != 16 {
+		return nil, fmt.Errorf("invalid UUID %q", value)
+	}
+	return uuidValue(formatUUID(id)), nil
+}
+
+func formatUUID(id []byte) string {
+	encoded := hex.EncodeToString(id)
+	return encoded[0:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:]
+}
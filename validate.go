@@ -0,0 +1,488 @@
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/hashicorp/go-bexpr/grammar"
+)
+
+// Validate checks the expression without evaluating it and returns every
+// problem found rather than only the first, such as for a user interface
+// for writing expressions. The options are the same as for CreateEvaluator.
+//
+// When dataType is not nil the expression is also checked against the type
+// of the values it will be evaluated against. Selectors naming struct fields
+// that do not exist, operators which cannot be used with the type of the
+// selected value and values that cannot be converted to that type are all
+// reported. Values within maps, slices and interfaces whose types are not
+// known until evaluation are not checked.
+func Validate(expression string, dataType interface{}, opts ...Option) []error {
+	opts = append(opts[:len(opts):len(opts)], WithErrorRecovery())
+	eval, err := CreateEvaluator(expression, opts...)
+	if err != nil {
+		var diags Diagnostics
+		if errors.As(err, &diags) {
+			return diags
+		}
+		return []error{err}
+	}
+	if dataType == nil {
+		return nil
+	}
+
+	rtype := reflect.TypeOf(dataType)
+	var errs []error
+	walkSelectors(eval.ast, func(sel grammar.Selector, match *grammar.MatchExpression) error {
+		valueType, field, err := selectorType(sel, rtype, &eval.opts)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if match == nil {
+			return nil
+		}
+		if err := field.checkOperator(match); err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if valueType == nil {
+			return nil
+		}
+		if err := validateMatchType(match, valueType, field, &eval.opts); err != nil {
+			errs = append(errs, err)
+		}
+		return nil
+	})
+	return errs
+}
+
+// validateType checks the expression against values of the type as
+// Validate does, returning the first problem found
+func (eval *Evaluator) validateType(rtype reflect.Type) error {
+	for _, operand := range eval.operands {
+		if err := operand.validateType(rtype); err != nil {
+			return err
+		}
+	}
+	if eval.operands != nil {
+		return nil
+	}
+	return walkSelectors(eval.ast, func(sel grammar.Selector, match *grammar.MatchExpression) error {
+		valueType, field, err := selectorType(sel, rtype, &eval.opts)
+		if err != nil || match == nil {
+			return err
+		}
+		if err := field.checkOperator(match); err != nil {
+			return err
+		}
+		if valueType == nil {
+			return nil
+		}
+		return validateMatchType(match, valueType, field, &eval.opts)
+	})
+}
+
+// validateMatchType checks the match expression by applying it to a sample
+// value of the type, as operators and values that cannot be used with the
+// type result in the same errors regardless of the value. The struct field
+// holding the values is given if there is one.
+func validateMatchType(match *grammar.MatchExpression, rtype reflect.Type, field *structField, opts *options) error {
+	if match.Value != nil && match.Value.Parameter != "" {
+		// the value is not known until it is bound
+		return nil
+	}
+	if match.Values == nil && (match.Operator == grammar.MatchInSet || match.Operator == grammar.MatchNotInSet) {
+		// named value sets may be defined after the evaluator is created
+		return nil
+	}
+
+	if inner, ok := sqlNullType(rtype); ok {
+		// database/sql null values are checked as the values they hold
+		rtype = inner
+	}
+
+	sample := reflect.New(rtype).Elem()
+	switch rtype.Kind() {
+	case reflect.Interface:
+		return nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if derefType(rtype.Elem()).Kind() == reflect.Interface {
+			return nil
+		}
+		// the elements of a collection are only checked when there are some
+		if rtype.Kind() == reflect.Slice {
+			sample = reflect.MakeSlice(rtype, 1, 1)
+		} else if rtype.Kind() == reflect.Map {
+			sample = reflect.MakeMap(rtype)
+			sample.SetMapIndex(reflect.Zero(rtype.Key()), reflect.Zero(rtype.Elem()))
+		}
+	}
+
+	var val interface{} = sample.Interface()
+	if match.Length {
+		length, err := valueLength(match, val)
+		if err != nil {
+			return err
+		}
+		val = length
+	}
+
+	_, err := evaluateMatchValue(match, val, field, opts)
+	return err
+}
+
+// selectorType returns the type of the values the selector refers to within
+// values of the given type. The returned type is nil when it depends on the
+// value, such as when the selector passes through an interface, a
+// FieldResolver or a MatchEvaluator.
+// The struct field holding the values is returned as well when the last
+// segment of the selector names one.
+func selectorType(sel grammar.Selector, rtype reflect.Type, opts *options) (reflect.Type, *structField, error) {
+	var field *structField
+	for i, part := range sel.Path {
+		if rtype.Implements(fieldResolverType) || rtype.Implements(matchEvaluatorType) {
+			return nil, nil, nil
+		}
+		rtype = derefType(rtype)
+		field = nil
+		switch rtype.Kind() {
+		case reflect.Interface:
+			return nil, nil, nil
+		case reflect.Struct:
+			if sel.IsWildcard(i) {
+				return nil, nil, nil
+			}
+			info := getStructInfo(rtype, opts)
+			found, err := info.field(part)
+			if errors.Is(err, errNoStructField) {
+				return nil, nil, &UnknownSelectorError{
+					Selector: sel,
+					Err:      fmt.Errorf("invalid selector %q: type %s has no field %q%s", sel, rtype, part, nameSuggestion(part, info.names())),
+				}
+			}
+			if err != nil {
+				return nil, nil, selectorError(sel, fmt.Errorf("invalid selector %q: %w", sel, err))
+			}
+			field = found
+			rtype = found.typ
+		case reflect.Map:
+			if !sel.IsWildcard(i) && !isMapKey(part, rtype.Key()) {
+				return nil, nil, &UnknownSelectorError{
+					Selector: sel,
+					Err:      fmt.Errorf("invalid selector %q: cannot convert %q to key type %s", sel, part, rtype.Key()),
+				}
+			}
+			rtype = rtype.Elem()
+		case reflect.Slice, reflect.Array:
+			rtype = rtype.Elem()
+		default:
+			return nil, nil, &UnknownSelectorError{
+				Selector: sel,
+				Err:      fmt.Errorf("invalid selector %q: cannot select %q within type %s", sel, part, rtype),
+			}
+		}
+	}
+	if rtype.Implements(matchEvaluatorType) {
+		return nil, field, nil
+	}
+	return derefType(rtype), field, nil
+}
+
+// validate checks the parsed expression against the limits set in the options
+func validate(ast grammar.Expression, opts *options) error {
+	if max := opts.withMaxExpressionDepth; max != 0 && expressionDepth(ast) > max {
+		return &LimitError{Max: max, Err: fmt.Errorf("expression exceeds the maximum depth of %d", max)}
+	}
+
+	checkDepth := func(sel grammar.Selector) error {
+		if max := opts.withMaxSelectorDepth; max != 0 && uint64(len(sel.Path)) > max {
+			return &LimitError{Max: max, Selector: sel, Err: fmt.Errorf("selector %q exceeds the maximum depth of %d", sel, max)}
+		}
+		return nil
+	}
+
+	matches := uint64(0)
+	err := walkExpressions(ast, func(expr grammar.Expression) error {
+		switch node := expr.(type) {
+		case *grammar.FunctionExpression:
+			if _, found := opts.withFunctions[node.Name]; !found {
+				return fmt.Errorf("function %q is not defined", node.Name)
+			}
+			for _, arg := range node.Args {
+				if arg.Value == nil {
+					if err := checkDepth(arg.Selector); err != nil {
+						return err
+					}
+				}
+			}
+		case *grammar.ComparisonExpression:
+			if _, denied := opts.withDeniedOperators[node.Operator]; denied {
+				return &UnsupportedOperatorError{
+					Operator: node.Operator,
+					Err:      fmt.Errorf("match operator %q is not allowed for comparison: %q", node.Operator, node.Left),
+				}
+			}
+			if err := walkOperandSelectors(node.Left, checkDepth); err != nil {
+				return err
+			}
+			return walkOperandSelectors(node.Right, checkDepth)
+		case *grammar.QuantifierExpression:
+			return checkDepth(node.Selector)
+		case *grammar.MatchExpression:
+			matches++
+			if _, denied := opts.withDeniedOperators[node.Operator]; denied {
+				return &UnsupportedOperatorError{
+					Operator: node.Operator,
+					Selector: node.Selector,
+					Err:      fmt.Errorf("match operator %q is not allowed for selector: %q", node.Operator, node.Selector),
+				}
+			}
+			return checkDepth(node.Selector)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.withMaxMatchExpressions != 0 && matches > opts.withMaxMatchExpressions {
+		return &LimitError{
+			Max: opts.withMaxMatchExpressions,
+			Err: fmt.Errorf("expression contains %d match expressions which exceeds the maximum of %d", matches, opts.withMaxMatchExpressions),
+		}
+	}
+
+	if len(opts.withAllowedSelectors) > 0 || len(opts.withDeniedSelectors) > 0 {
+		return validateSelectorAccess(ast, opts)
+	}
+	return nil
+}
+
+// validateSelectorAccess checks the selectors of the expression against the
+// allowed and denied selectors
+func validateSelectorAccess(ast grammar.Expression, opts *options) error {
+	parse := func(selectors []string, kind string) ([]grammar.Selector, error) {
+		parsed := make([]grammar.Selector, 0, len(selectors))
+		for _, selector := range selectors {
+			sel, err := grammar.ParseSelector(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s selector %q: %w", kind, selector, err)
+			}
+			parsed = append(parsed, sel)
+		}
+		return parsed, nil
+	}
+	allowed, err := parse(opts.withAllowedSelectors, "allowed")
+	if err != nil {
+		return err
+	}
+	denied, err := parse(opts.withDeniedSelectors, "denied")
+	if err != nil {
+		return err
+	}
+
+	return walkResolvedSelectors(ast, func(sel grammar.Selector, _ *grammar.MatchExpression) error {
+		for _, prefix := range denied {
+			if selectorWithin(sel, prefix, true) {
+				return fmt.Errorf("selector %q is not allowed", sel)
+			}
+		}
+		if len(allowed) == 0 {
+			return nil
+		}
+		for _, prefix := range allowed {
+			if selectorWithin(sel, prefix, false) {
+				return nil
+			}
+		}
+		return fmt.Errorf("selector %q is not allowed", sel)
+	})
+}
+
+// selectorWithin reports whether the selector is the prefix or within it,
+// where a * wildcard of the prefix matches any segment. When wildcards is
+// set a * wildcard of the selector matches any segment of the prefix as
+// well.
+func selectorWithin(sel, prefix grammar.Selector, wildcards bool) bool {
+	if len(sel.Path) < len(prefix.Path) {
+		return false
+	}
+	for i, part := range prefix.Path {
+		if prefix.IsWildcard(i) || (wildcards && sel.IsWildcard(i)) {
+			continue
+		}
+		if part != sel.Path[i] || sel.IsWildcard(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkMatchExpressions calls fn for each match expression in the AST, stopping
+// at the first error returned.
+func walkMatchExpressions(ast grammar.Expression, fn func(*grammar.MatchExpression) error) error {
+	return walkExpressions(ast, func(node grammar.Expression) error {
+		if match, ok := node.(*grammar.MatchExpression); ok {
+			return fn(match)
+		}
+		return nil
+	})
+}
+
+// expressionDepth returns the number of levels of the expression, counting
+// each not, and, or, xor and quantifier as a level above its operands
+func expressionDepth(ast grammar.Expression) uint64 {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return 1 + expressionDepth(node.Operand)
+	case *grammar.BinaryExpression:
+		left, right := expressionDepth(node.Left), expressionDepth(node.Right)
+		if left > right {
+			return 1 + left
+		}
+		return 1 + right
+	case *grammar.QuantifierExpression:
+		return 1 + expressionDepth(node.Expression)
+	default:
+		return 1
+	}
+}
+
+// walkExpressions calls fn for each node in the AST, parents before their
+// children, stopping at the first error returned.
+func walkExpressions(ast grammar.Expression, fn func(grammar.Expression) error) error {
+	if err := fn(ast); err != nil {
+		return err
+	}
+
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return walkExpressions(node.Operand, fn)
+	case *grammar.BinaryExpression:
+		if err := walkExpressions(node.Left, fn); err != nil {
+			return err
+		}
+		return walkExpressions(node.Right, fn)
+	case *grammar.QuantifierExpression:
+		return walkExpressions(node.Expression, fn)
+	}
+	return nil
+}
+
+// walkSelectors calls fn for each selector in the AST that refers to the
+// datum, along with the match expression it is the selector of if any,
+// stopping at the first error returned. Selectors relative to the element
+// bound to a quantifier variable are skipped.
+func walkSelectors(ast grammar.Expression, fn func(grammar.Selector, *grammar.MatchExpression) error) error {
+	return walkSelectorsBound(ast, fn, nil, false)
+}
+
+// walkResolvedSelectors is like walkSelectors but includes the selectors
+// relative to quantifier variables, resolved to the path of the collection
+// iterated followed by a * wildcard standing for each of its elements.
+func walkResolvedSelectors(ast grammar.Expression, fn func(grammar.Selector, *grammar.MatchExpression) error) error {
+	return walkSelectorsBound(ast, fn, nil, true)
+}
+
+// walkSelectorsBound walks the selectors with bound mapping the variables of
+// the enclosing quantifiers to the resolved paths of their elements
+func walkSelectorsBound(ast grammar.Expression, fn func(grammar.Selector, *grammar.MatchExpression) error, bound map[string]grammar.Selector, resolve bool) error {
+	resolved := func(sel grammar.Selector) (grammar.Selector, bool) {
+		if len(sel.Path) == 0 {
+			return sel, false
+		}
+		prefix, ok := bound[sel.Path[0]]
+		if !ok {
+			return sel, false
+		}
+		resolved := prefix.Append(sel.Slice(1, len(sel.Path)))
+		resolved.Type = sel.Type
+		return resolved, true
+	}
+	visitMatch := func(sel grammar.Selector, match *grammar.MatchExpression) error {
+		sel, isBound := resolved(sel)
+		if isBound && !resolve {
+			return nil
+		}
+		return fn(sel, match)
+	}
+	visit := func(sel grammar.Selector) error {
+		return visitMatch(sel, nil)
+	}
+
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return walkSelectorsBound(node.Operand, fn, bound, resolve)
+	case *grammar.BinaryExpression:
+		if err := walkSelectorsBound(node.Left, fn, bound, resolve); err != nil {
+			return err
+		}
+		return walkSelectorsBound(node.Right, fn, bound, resolve)
+	case *grammar.QuantifierExpression:
+		if err := visit(node.Selector); err != nil {
+			return err
+		}
+		collection, _ := resolved(node.Selector)
+		inner := make(map[string]grammar.Selector, len(bound)+1)
+		for variable, sel := range bound {
+			inner[variable] = sel
+		}
+		inner[node.Variable] = collection.Append(grammar.Selector{Path: []string{"*"}, Wildcards: []int{0}})
+		return walkSelectorsBound(node.Expression, fn, inner, resolve)
+	case *grammar.FunctionExpression:
+		for _, arg := range node.Args {
+			if arg.Value != nil {
+				continue
+			}
+			if err := visit(arg.Selector); err != nil {
+				return err
+			}
+		}
+	case *grammar.ComparisonExpression:
+		if err := walkOperandSelectors(node.Left, visit); err != nil {
+			return err
+		}
+		return walkOperandSelectors(node.Right, visit)
+	case *grammar.MatchExpression:
+		return visitMatch(node.Selector, node)
+	}
+	return nil
+}
+
+func walkOperandSelectors(o *grammar.Operand, fn func(grammar.Selector) error) error {
+	switch {
+	case o.Arithmetic != nil:
+		if err := walkOperandSelectors(o.Arithmetic.Left, fn); err != nil {
+			return err
+		}
+		return walkOperandSelectors(o.Arithmetic.Right, fn)
+	case o.Value != nil:
+		return nil
+	default:
+		return fn(o.Selector)
+	}
+}
+
+// isMapKey reports whether the selector segment can be converted to the key
+// type of a map, as lookups do for keys of scalar types such as integers.
+// Keys of other types are left to the lookup.
+func isMapKey(part string, keyType reflect.Type) bool {
+	if part == "" {
+		return true
+	}
+	var err error
+	switch keyType.Kind() {
+	case reflect.Bool:
+		_, err = strconv.ParseBool(part)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(part, 0, keyType.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, err = strconv.ParseUint(part, 0, keyType.Bits())
+	case reflect.Float32, reflect.Float64:
+		_, err = strconv.ParseFloat(part, keyType.Bits())
+	}
+	return err == nil
+}
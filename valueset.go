@@ -0,0 +1,152 @@
+package bexpr
+
+import (
+	"reflect"
+	"sync"
+)
+
+// valueSet is a named set of values that expressions can reference with
+// the @name syntax, for example: Owner in @admins
+type valueSet struct {
+	// the set values coerced for each kind of value they may be compared
+	// against. Values which cannot be coerced to a kind are not included.
+	coerced map[reflect.Kind]map[interface{}]struct{}
+	// values are the raw values, which are coerced for each type that has
+	// a registered coercion when first compared against a value of the type
+	values []string
+	// typed holds the coerced values by the reflect.Type they were coerced
+	// for
+	typed sync.Map
+}
+
+// newValueSet creates the set of the values. Quoted holds whether each of
+// the values of a list literal was quoted, in which case a single character
+// is a character for runes and bytes. It is nil for named sets, whose values
+// are characters when they are not numbers.
+func newValueSet(values []string, quoted []bool) *valueSet {
+	set := &valueSet{
+		coerced: make(map[reflect.Kind]map[interface{}]struct{}),
+		values:  values,
+	}
+
+	// runes and bytes also match characters, so they have their own sets
+	for _, kind := range []reflect.Kind{reflect.Bool, reflect.Int64, reflect.Int32, reflect.Uint64, reflect.Uint8, reflect.Float32, reflect.Float64, reflect.String} {
+		members := make(map[interface{}]struct{}, len(values))
+		for i, value := range values {
+			coerced, err := getMatchValue(value, kind)
+			if character, ok := characterValue(value, kind); ok && (quoted == nil && err != nil || quoted != nil && quoted[i]) {
+				coerced, err = character, nil
+			}
+			if err != nil {
+				continue
+			}
+			members[coerced] = struct{}{}
+		}
+		set.coerced[kind] = members
+	}
+	return set
+}
+
+// contains reports whether the set contains the given value. The second
+// return value will be false if the value is of a kind that the set
+// cannot be compared against.
+func (s *valueSet) contains(value reflect.Value) (bool, bool) {
+	kind, key, ok := primitiveValue(value)
+	if !ok {
+		if eqFn := valueEqualityFn(value); eqFn != nil {
+			return s.containsCoerced(value, eqFn), true
+		}
+		return false, false
+	}
+
+	if _, ok := getCoercion(value.Type()); ok {
+		_, found := s.typedMembers(value.Type())[key]
+		return found, true
+	}
+	switch value.Kind() {
+	case reflect.Int32, reflect.Uint8:
+		kind = value.Kind()
+	}
+	_, found := s.coerced[kind][key]
+	return found, true
+}
+
+// containsCoerced reports whether the set contains a value of a type that
+// is compared in its own way, such as a type which values are unmarshaled
+// into, whose coerced values need not be usable as map keys
+func (s *valueSet) containsCoerced(value reflect.Value, eqFn func(first interface{}, second reflect.Value) bool) bool {
+	for _, raw := range s.values {
+		coerced, err := coerceValue(raw, value.Type())
+		if err == nil && eqFn(coerced, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// typedMembers returns the values of the set coerced for the type with a
+// registered coercion. Values which cannot be coerced are not included.
+func (s *valueSet) typedMembers(rtype reflect.Type) map[interface{}]struct{} {
+	if members, ok := s.typed.Load(rtype); ok {
+		return members.(map[interface{}]struct{})
+	}
+
+	members := make(map[interface{}]struct{}, len(s.values))
+	for _, value := range s.values {
+		coerced, err := coerceValue(value, rtype)
+		if err != nil {
+			continue
+		}
+		members[coerced] = struct{}{}
+	}
+	actual, _ := s.typed.LoadOrStore(rtype, members)
+	return actual.(map[interface{}]struct{})
+}
+
+// valueSets holds all of the named value sets of an Evaluator. The sets
+// can be replaced while evaluations are running.
+type valueSets struct {
+	lock sync.RWMutex
+	sets map[string]*valueSet
+}
+
+// copy returns a copy of the sets, which the sets can then be added to and
+// removed from independently
+func (v *valueSets) copy() *valueSets {
+	if v == nil {
+		return new(valueSets)
+	}
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	copied := &valueSets{}
+	if v.sets != nil {
+		copied.sets = make(map[string]*valueSet, len(v.sets))
+		for name, set := range v.sets {
+			copied.sets[name] = set
+		}
+	}
+	return copied
+}
+
+func (v *valueSets) get(name string) *valueSet {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.sets[name]
+}
+
+func (v *valueSets) set(name string, values []string) {
+	set := newValueSet(values, nil)
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if v.sets == nil {
+		v.sets = make(map[string]*valueSet)
+	}
+	v.sets[name] = set
+}
+
+func (v *valueSets) delete(name string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	delete(v.sets, name)
+}